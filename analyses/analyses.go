@@ -130,6 +130,7 @@ func (s VulnerabilityScore) MarshalJSON() ([]byte, error) {
 }
 
 // VulnerabilityExportData summarizes key details about a vulnerability to be included in a project's export data.
+// The VEX fields are set when a VEX statement about the vulnerability has been applied to the data.
 type VulnerabilityExportData struct {
 	ProjectID         string             `json:"project_id"`
 	ProjectName       string             `json:"project_name"`
@@ -140,6 +141,9 @@ type VulnerabilityExportData struct {
 	Score             VulnerabilityScore `json:"score"`
 	Dependency        string             `json:"dependency"`
 	DependencyVersion string             `json:"dependency_version"`
+	VEXStatus         string             `json:"vex_status,omitempty"`
+	VEXJustification  string             `json:"vex_justification,omitempty"`
+	VEXStatement      string             `json:"vex_statement,omitempty"`
 }
 
 // String returns a JSON formatted string of the analysis object
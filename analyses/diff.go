@@ -0,0 +1,576 @@
+package analyses
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/ion-channel/ionic/rulesets"
+	"github.com/ion-channel/ionic/scans"
+	"github.com/ion-channel/ionic/versioning"
+)
+
+// AnalysisDiff represents the differences between two analyses of a project,
+// compared scan type by scan type
+type AnalysisDiff struct {
+	OldAnalysisID          string                `json:"old_analysis_id"`
+	NewAnalysisID          string                `json:"new_analysis_id"`
+	OldPassed              bool                  `json:"old_passed"`
+	NewPassed              bool                  `json:"new_passed"`
+	VulnerabilitiesAdded   []VulnerabilityChange `json:"vulnerabilities_added"`
+	VulnerabilitiesRemoved []VulnerabilityChange `json:"vulnerabilities_removed"`
+	DependenciesAdded      []DependencyChange    `json:"dependencies_added"`
+	DependenciesRemoved    []DependencyChange    `json:"dependencies_removed"`
+	DependenciesUpgraded   []DependencyChange    `json:"dependencies_upgraded"`
+	DependenciesDowngraded []DependencyChange    `json:"dependencies_downgraded"`
+	LicensesAdded          []string              `json:"licenses_added"`
+	LicensesRemoved        []string              `json:"licenses_removed"`
+	SecretsAdded           []SecretChange        `json:"secrets_added"`
+	VirusesAdded           []VirusChange         `json:"viruses_added"`
+	Coverage               *CoverageChange       `json:"coverage,omitempty"`
+	RulesChanged           []RuleChange          `json:"rules_changed"`
+}
+
+// VulnerabilityChange identifies a vulnerability that was found in only one
+// of the compared analyses
+type VulnerabilityChange struct {
+	ExternalID string `json:"external_id"`
+	Title      string `json:"title"`
+	Severity   string `json:"severity,omitempty"`
+	Score      string `json:"score,omitempty"`
+	Dependency string `json:"dependency"`
+	Version    string `json:"version"`
+}
+
+// DependencyChange identifies a dependency that was added, removed, or
+// changed version between the compared analyses
+type DependencyChange struct {
+	Type       string `json:"type"`
+	Org        string `json:"org"`
+	Name       string `json:"name"`
+	OldVersion string `json:"old_version,omitempty"`
+	NewVersion string `json:"new_version,omitempty"`
+}
+
+// SecretChange identifies a secret that was found in the new analysis but not
+// in the old one. The match is masked.
+type SecretChange struct {
+	Rule  string `json:"rule"`
+	File  string `json:"file"`
+	Match string `json:"match"`
+}
+
+// VirusChange identifies a file the virus scan of the new analysis found
+// infected that was not found infected in the old one
+type VirusChange struct {
+	File  string   `json:"file"`
+	Notes []string `json:"notes"`
+}
+
+// CoverageChange represents the change of the code coverage between the
+// compared analyses
+type CoverageChange struct {
+	Old   float64 `json:"old"`
+	New   float64 `json:"new"`
+	Delta float64 `json:"delta"`
+}
+
+// RuleChange identifies a rule of the applied ruleset whose result changed
+// between the compared analyses
+type RuleChange struct {
+	RuleID    string `json:"rule_id"`
+	Name      string `json:"name"`
+	OldPassed bool   `json:"old_passed"`
+	NewPassed bool   `json:"new_passed"`
+	Summary   string `json:"summary"`
+}
+
+// Diff compares the scan summaries of two analyses of a project, and returns
+// the vulnerabilities, dependencies, licenses, secrets, viruses, and coverage
+// that changed between them. Either analysis may be nil, in which case it is
+// treated as having no results.
+func Diff(old, new *Analysis) *AnalysisDiff {
+	if old == nil {
+		old = &Analysis{}
+	}
+
+	if new == nil {
+		new = &Analysis{}
+	}
+
+	d := &AnalysisDiff{
+		OldAnalysisID: old.ID,
+		NewAnalysisID: new.ID,
+		OldPassed:     old.Passed,
+		NewPassed:     new.Passed,
+	}
+
+	d.VulnerabilitiesAdded, d.VulnerabilitiesRemoved = diffVulnerabilities(vulnerabilityChanges(old), vulnerabilityChanges(new))
+	d.diffDependencies(dependencyVersions(old), dependencyVersions(new))
+	d.LicensesAdded, d.LicensesRemoved = diffStrings(licenseNames(old), licenseNames(new))
+	d.SecretsAdded = diffSecrets(secretChanges(old), secretChanges(new))
+	d.VirusesAdded = diffViruses(infectedFiles(old), infectedFiles(new))
+
+	oldCoverage, oldOK := coverageValue(old)
+	newCoverage, newOK := coverageValue(new)
+	if oldOK || newOK {
+		d.Coverage = &CoverageChange{
+			Old:   oldCoverage,
+			New:   newCoverage,
+			Delta: newCoverage - oldCoverage,
+		}
+	}
+
+	d.RulesChanged = []RuleChange{}
+
+	return d
+}
+
+// DiffWithRulesets compares two analyses like Diff, and also compares the
+// rulesets applied to them to find the rules whose result changed.
+func DiffWithRulesets(old, new *Analysis, oldRuleset, newRuleset *rulesets.AppliedRulesetSummary) *AnalysisDiff {
+	d := Diff(old, new)
+
+	oldRules := ruleResults(oldRuleset)
+	for _, e := range ruleResults(newRuleset) {
+		previous, ok := oldRules[e.RuleID]
+		if !ok || previous.Passed == e.Passed {
+			continue
+		}
+
+		d.RulesChanged = append(d.RulesChanged, RuleChange{
+			RuleID:    e.RuleID,
+			Name:      e.Name,
+			OldPassed: previous.Passed,
+			NewPassed: e.Passed,
+			Summary:   e.Summary,
+		})
+	}
+
+	sort.Slice(d.RulesChanged, func(i, j int) bool {
+		return d.RulesChanged[i].Name < d.RulesChanged[j].Name
+	})
+
+	if oldRuleset != nil {
+		_, d.OldPassed = oldRuleset.SummarizeEvaluation()
+	}
+
+	if newRuleset != nil {
+		_, d.NewPassed = newRuleset.SummarizeEvaluation()
+	}
+
+	return d
+}
+
+// Empty returns whether the compared analyses had no differences
+func (d *AnalysisDiff) Empty() bool {
+	return len(d.VulnerabilitiesAdded) == 0 && len(d.VulnerabilitiesRemoved) == 0 &&
+		len(d.DependenciesAdded) == 0 && len(d.DependenciesRemoved) == 0 &&
+		len(d.DependenciesUpgraded) == 0 && len(d.DependenciesDowngraded) == 0 &&
+		len(d.LicensesAdded) == 0 && len(d.LicensesRemoved) == 0 &&
+		len(d.SecretsAdded) == 0 && len(d.VirusesAdded) == 0 &&
+		(d.Coverage == nil || d.Coverage.Delta == 0) && len(d.RulesChanged) == 0
+}
+
+// WriteJSON writes the diff to the writer as indented JSON
+func (d *AnalysisDiff) WriteJSON(w io.Writer) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+
+	return enc.Encode(d)
+}
+
+// WriteMarkdown writes the diff to the writer as a Markdown document
+func (d *AnalysisDiff) WriteMarkdown(w io.Writer) error {
+	return d.write(w, markdownDiffFormat)
+}
+
+// WriteText writes the diff to the writer as plain text
+func (d *AnalysisDiff) WriteText(w io.Writer) error {
+	return d.write(w, textDiffFormat)
+}
+
+type diffFormat struct {
+	title   func(string) string
+	section func(string) string
+	item    func(string) string
+}
+
+var markdownDiffFormat = diffFormat{
+	title:   func(s string) string { return fmt.Sprintf("## %v\n\n", s) },
+	section: func(s string) string { return fmt.Sprintf("\n### %v\n\n", s) },
+	item:    func(s string) string { return fmt.Sprintf("- %v\n", s) },
+}
+
+var textDiffFormat = diffFormat{
+	title:   func(s string) string { return fmt.Sprintf("%v\n", s) },
+	section: func(s string) string { return fmt.Sprintf("\n%v:\n", s) },
+	item:    func(s string) string { return fmt.Sprintf("  %v\n", s) },
+}
+
+func (d *AnalysisDiff) write(w io.Writer, f diffFormat) error {
+	var sb strings.Builder
+
+	sb.WriteString(f.title(fmt.Sprintf("Changes from analysis %v to %v", d.OldAnalysisID, d.NewAnalysisID)))
+	if d.OldPassed != d.NewPassed {
+		sb.WriteString(fmt.Sprintf("Status changed from %v to %v\n", passFail(d.OldPassed), passFail(d.NewPassed)))
+	}
+
+	if d.Empty() {
+		sb.WriteString("No changes found\n")
+	}
+
+	section := func(name string, items []string) {
+		if len(items) == 0 {
+			return
+		}
+
+		sb.WriteString(f.section(name))
+		for _, item := range items {
+			sb.WriteString(f.item(item))
+		}
+	}
+
+	var rules []string
+	for _, r := range d.RulesChanged {
+		rules = append(rules, fmt.Sprintf("%v: %v -> %v", r.Name, passFail(r.OldPassed), passFail(r.NewPassed)))
+	}
+	section("Rules changed", rules)
+
+	section("Vulnerabilities added", vulnerabilityLines(d.VulnerabilitiesAdded))
+	section("Vulnerabilities removed", vulnerabilityLines(d.VulnerabilitiesRemoved))
+	section("Dependencies added", dependencyLines(d.DependenciesAdded))
+	section("Dependencies removed", dependencyLines(d.DependenciesRemoved))
+	section("Dependencies upgraded", dependencyLines(d.DependenciesUpgraded))
+	section("Dependencies downgraded", dependencyLines(d.DependenciesDowngraded))
+	section("Licenses added", d.LicensesAdded)
+	section("Licenses removed", d.LicensesRemoved)
+
+	var secrets []string
+	for _, s := range d.SecretsAdded {
+		secrets = append(secrets, fmt.Sprintf("%v in %v: %v", s.Rule, s.File, s.Match))
+	}
+	section("Secrets added", secrets)
+
+	var viruses []string
+	for _, v := range d.VirusesAdded {
+		viruses = append(viruses, fmt.Sprintf("%v: %v", v.File, strings.Join(v.Notes, ", ")))
+	}
+	section("Viruses added", viruses)
+
+	if d.Coverage != nil && d.Coverage.Delta != 0 {
+		section("Coverage", []string{fmt.Sprintf("%.2f%% -> %.2f%% (%+.2f)", d.Coverage.Old, d.Coverage.New, d.Coverage.Delta)})
+	}
+
+	_, err := io.WriteString(w, sb.String())
+	return err
+}
+
+func vulnerabilityLines(vs []VulnerabilityChange) []string {
+	lines := make([]string, 0, len(vs))
+	for _, v := range vs {
+		line := fmt.Sprintf("%v %v (%v %v)", v.ExternalID, v.Title, v.Dependency, v.Version)
+		if v.Severity != "" {
+			line = fmt.Sprintf("%v [%v]", line, v.Severity)
+		}
+
+		lines = append(lines, line)
+	}
+
+	return lines
+}
+
+func dependencyLines(ds []DependencyChange) []string {
+	lines := make([]string, 0, len(ds))
+	for _, d := range ds {
+		name := d.Name
+		if d.Org != "" {
+			name = d.Org + "/" + d.Name
+		}
+
+		switch {
+		case d.OldVersion != "" && d.NewVersion != "":
+			lines = append(lines, fmt.Sprintf("%v %v -> %v", name, d.OldVersion, d.NewVersion))
+		case d.NewVersion != "":
+			lines = append(lines, fmt.Sprintf("%v %v", name, d.NewVersion))
+		default:
+			lines = append(lines, fmt.Sprintf("%v %v", name, d.OldVersion))
+		}
+	}
+
+	return lines
+}
+
+func passFail(passed bool) string {
+	if passed {
+		return "pass"
+	}
+
+	return "fail"
+}
+
+func vulnerabilityChanges(a *Analysis) map[string]VulnerabilityChange {
+	changes := make(map[string]VulnerabilityChange)
+
+	results, err := a.Vulnerabilities()
+	if err != nil {
+		return changes
+	}
+
+	for _, product := range results.Vulnerabilities {
+		for _, v := range product.Vulnerabilities {
+			if _, ok := changes[v.ExternalID]; ok {
+				continue
+			}
+
+			change := VulnerabilityChange{
+				ExternalID: v.ExternalID,
+				Title:      v.Title,
+				Score:      v.Score,
+				Dependency: product.Name,
+				Version:    product.Version,
+			}
+
+			switch {
+			case v.ScoreDetails.CVSSv3 != nil && v.ScoreDetails.CVSSv3.BaseSeverity != "":
+				change.Severity = strings.ToLower(v.ScoreDetails.CVSSv3.BaseSeverity)
+			case v.ScoreDetails.NPM != nil && v.ScoreDetails.NPM.BaseSeverity != "":
+				change.Severity = strings.ToLower(v.ScoreDetails.NPM.BaseSeverity)
+			}
+
+			changes[v.ExternalID] = change
+		}
+	}
+
+	return changes
+}
+
+func diffVulnerabilities(old, new map[string]VulnerabilityChange) ([]VulnerabilityChange, []VulnerabilityChange) {
+	added, removed := []VulnerabilityChange{}, []VulnerabilityChange{}
+	for id, v := range new {
+		if _, ok := old[id]; !ok {
+			added = append(added, v)
+		}
+	}
+
+	for id, v := range old {
+		if _, ok := new[id]; !ok {
+			removed = append(removed, v)
+		}
+	}
+
+	for _, vs := range [][]VulnerabilityChange{added, removed} {
+		sort.Slice(vs, func(i, j int) bool {
+			return vs[i].ExternalID < vs[j].ExternalID
+		})
+	}
+
+	return added, removed
+}
+
+// dependencyVersions returns the version of every dependency found in the
+// analysis, including transitive dependencies, keyed by type, org, and name
+func dependencyVersions(a *Analysis) map[string]DependencyChange {
+	deps := make(map[string]DependencyChange)
+
+	var walk func([]scans.Dependency)
+	walk = func(ds []scans.Dependency) {
+		for _, dep := range ds {
+			key := strings.ToLower(strings.Join([]string{dep.Type, dep.Org, dep.Name}, "|"))
+			if _, ok := deps[key]; !ok {
+				deps[key] = DependencyChange{
+					Type:       dep.Type,
+					Org:        dep.Org,
+					Name:       dep.Name,
+					NewVersion: dep.Version,
+				}
+			}
+
+			walk(dep.Dependencies)
+		}
+	}
+
+	if results, err := a.Dependencies(); err == nil {
+		walk(results.Dependencies)
+	}
+
+	return deps
+}
+
+func (d *AnalysisDiff) diffDependencies(old, new map[string]DependencyChange) {
+	d.DependenciesAdded = []DependencyChange{}
+	d.DependenciesRemoved = []DependencyChange{}
+	d.DependenciesUpgraded = []DependencyChange{}
+	d.DependenciesDowngraded = []DependencyChange{}
+
+	for key, dep := range new {
+		previous, ok := old[key]
+		if !ok {
+			d.DependenciesAdded = append(d.DependenciesAdded, dep)
+			continue
+		}
+
+		if previous.NewVersion == dep.NewVersion {
+			continue
+		}
+
+		dep.OldVersion = previous.NewVersion
+		if versionLess(dep.Type, dep.NewVersion, dep.OldVersion) {
+			d.DependenciesDowngraded = append(d.DependenciesDowngraded, dep)
+		} else {
+			d.DependenciesUpgraded = append(d.DependenciesUpgraded, dep)
+		}
+	}
+
+	for key, dep := range old {
+		if _, ok := new[key]; !ok {
+			dep.OldVersion, dep.NewVersion = dep.NewVersion, ""
+			d.DependenciesRemoved = append(d.DependenciesRemoved, dep)
+		}
+	}
+
+	for _, ds := range [][]DependencyChange{d.DependenciesAdded, d.DependenciesRemoved, d.DependenciesUpgraded, d.DependenciesDowngraded} {
+		sort.Slice(ds, func(i, j int) bool {
+			if ds[i].Org != ds[j].Org {
+				return ds[i].Org < ds[j].Org
+			}
+
+			return ds[i].Name < ds[j].Name
+		})
+	}
+}
+
+// versionLess returns whether version a is older than version b, comparing
+// the versions with the versioning scheme of the dependency type, or as
+// strings when they can not be parsed
+func versionLess(depType, a, b string) bool {
+	c, err := versioning.Compare(versioning.For(depType), a, b)
+	if err != nil {
+		return a < b
+	}
+
+	return c < 0
+}
+
+func licenseNames(a *Analysis) []string {
+	results, err := a.Licenses()
+	if err != nil || results.License == nil {
+		return nil
+	}
+
+	names := make([]string, 0, len(results.Type))
+	for _, t := range results.Type {
+		names = append(names, t.Name)
+	}
+
+	return names
+}
+
+func diffStrings(old, new []string) ([]string, []string) {
+	oldSet := make(map[string]bool)
+	for _, s := range old {
+		oldSet[s] = true
+	}
+
+	newSet := make(map[string]bool)
+	for _, s := range new {
+		newSet[s] = true
+	}
+
+	added, removed := []string{}, []string{}
+	for s := range newSet {
+		if !oldSet[s] {
+			added = append(added, s)
+		}
+	}
+
+	for s := range oldSet {
+		if !newSet[s] {
+			removed = append(removed, s)
+		}
+	}
+
+	sort.Strings(added)
+	sort.Strings(removed)
+
+	return added, removed
+}
+
+func secretChanges(a *Analysis) []SecretChange {
+	results, err := a.Secrets()
+	if err != nil {
+		return nil
+	}
+
+	changes := make([]SecretChange, 0, len(results.Secrets))
+	for _, s := range results.Secrets {
+		changes = append(changes, SecretChange{
+			Rule:  s.Rule,
+			File:  s.File,
+			Match: s.Match,
+		})
+	}
+
+	return changes
+}
+
+func diffSecrets(old, new []SecretChange) []SecretChange {
+	seen := make(map[SecretChange]bool)
+	for _, s := range old {
+		seen[s] = true
+	}
+
+	added := []SecretChange{}
+	for _, s := range new {
+		if !seen[s] {
+			seen[s] = true
+			added = append(added, s)
+		}
+	}
+
+	return added
+}
+
+func infectedFiles(a *Analysis) scans.FileNotes {
+	results, err := a.Viruses()
+	if err != nil {
+		return nil
+	}
+
+	return results.FileNotes
+}
+
+func diffViruses(old, new scans.FileNotes) []VirusChange {
+	added := []VirusChange{}
+	for file, notes := range new {
+		if _, ok := old[file]; !ok {
+			added = append(added, VirusChange{File: file, Notes: notes})
+		}
+	}
+
+	sort.Slice(added, func(i, j int) bool {
+		return added[i].File < added[j].File
+	})
+
+	return added
+}
+
+func coverageValue(a *Analysis) (float64, bool) {
+	results, err := a.Coverage()
+	if err != nil {
+		return 0, false
+	}
+
+	return results.Value, true
+}
+
+func ruleResults(ar *rulesets.AppliedRulesetSummary) map[string]scans.Evaluation {
+	results := make(map[string]scans.Evaluation)
+	for _, e := range ar.RuleResults() {
+		results[e.RuleID] = e
+	}
+
+	return results
+}
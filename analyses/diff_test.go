@@ -0,0 +1,134 @@
+package analyses
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/franela/goblin"
+	. "github.com/onsi/gomega"
+
+	"github.com/ion-channel/ionic/rulesets"
+)
+
+func TestDiff(t *testing.T) {
+	g := goblin.Goblin(t)
+	RegisterFailHandler(func(m string, _ ...int) { g.Fail(m) })
+
+	g.Describe("Diff", func() {
+		var old, new Analysis
+
+		g.BeforeEach(func() {
+			Expect(json.Unmarshal([]byte(sampleOldAnalysis), &old)).To(Succeed())
+			Expect(json.Unmarshal([]byte(sampleNewAnalysis), &new)).To(Succeed())
+		})
+
+		g.It("should find vulnerabilities added and removed by external id", func() {
+			d := Diff(&old, &new)
+			Expect(d.VulnerabilitiesAdded).To(HaveLen(1))
+			Expect(d.VulnerabilitiesAdded[0].ExternalID).To(Equal("CVE-2021-44228"))
+			Expect(d.VulnerabilitiesAdded[0].Severity).To(Equal("critical"))
+			Expect(d.VulnerabilitiesAdded[0].Dependency).To(Equal("log4j-core"))
+			Expect(d.VulnerabilitiesRemoved).To(HaveLen(1))
+			Expect(d.VulnerabilitiesRemoved[0].ExternalID).To(Equal("CVE-2020-0001"))
+		})
+
+		g.It("should find dependencies added, removed, and upgraded", func() {
+			d := Diff(&old, &new)
+			Expect(d.DependenciesAdded).To(Equal([]DependencyChange{{Type: "maven", Org: "apache", Name: "log4j-core", NewVersion: "2.14.1"}}))
+			Expect(d.DependenciesRemoved).To(Equal([]DependencyChange{{Type: "npm", Org: "", Name: "left-pad", OldVersion: "1.0.0"}}))
+			Expect(d.DependenciesUpgraded).To(Equal([]DependencyChange{{Type: "npm", Org: "", Name: "lodash", OldVersion: "4.17.9", NewVersion: "4.17.21"}}))
+			Expect(d.DependenciesDowngraded).To(BeEmpty())
+		})
+
+		g.It("should find license, secret, virus, and coverage changes", func() {
+			d := Diff(&old, &new)
+			Expect(d.LicensesAdded).To(Equal([]string{"GPL-3.0"}))
+			Expect(d.LicensesRemoved).To(BeEmpty())
+			Expect(d.SecretsAdded).To(HaveLen(1))
+			Expect(d.SecretsAdded[0].Rule).To(Equal("AWS Key"))
+			Expect(d.SecretsAdded[0].Match).To(Equal("AKIA****"))
+			Expect(d.VirusesAdded).To(Equal([]VirusChange{{File: "bin/evil.exe", Notes: []string{"Win.Trojan FOUND"}}}))
+			Expect(d.Coverage).To(Equal(&CoverageChange{Old: 80, New: 75.5, Delta: -4.5}))
+			Expect(d.Empty()).To(BeFalse())
+		})
+
+		g.It("should find no changes between the same analysis", func() {
+			d := Diff(&old, &old)
+			Expect(d.Empty()).To(BeTrue())
+		})
+
+		g.It("should find rules whose result changed", func() {
+			var oldRuleset, newRuleset rulesets.AppliedRulesetSummary
+			Expect(json.Unmarshal([]byte(`{"rule_evaluation_summary":{"summary":"pass","ruleresults":[
+				{"rule_id":"r1","name":"No critical vulnerabilities","passed":true,"results":{"type":"about_yml","data":{}}},
+				{"rule_id":"r2","name":"Coverage above 70%","passed":true,"results":{"type":"about_yml","data":{}}}]}}`), &oldRuleset)).To(Succeed())
+			Expect(json.Unmarshal([]byte(`{"rule_evaluation_summary":{"summary":"fail","ruleresults":[
+				{"rule_id":"r1","name":"No critical vulnerabilities","passed":false,"summary":"1 critical","results":{"type":"about_yml","data":{}}},
+				{"rule_id":"r2","name":"Coverage above 70%","passed":true,"results":{"type":"about_yml","data":{}}}]}}`), &newRuleset)).To(Succeed())
+
+			d := DiffWithRulesets(&old, &new, &oldRuleset, &newRuleset)
+			Expect(d.OldPassed).To(BeTrue())
+			Expect(d.NewPassed).To(BeFalse())
+			Expect(d.RulesChanged).To(Equal([]RuleChange{{RuleID: "r1", Name: "No critical vulnerabilities", OldPassed: true, NewPassed: false, Summary: "1 critical"}}))
+		})
+
+		g.It("should render as JSON, markdown, and text", func() {
+			d := Diff(&old, &new)
+
+			var b bytes.Buffer
+			Expect(d.WriteJSON(&b)).To(Succeed())
+			Expect(b.String()).To(ContainSubstring(`"external_id": "CVE-2021-44228"`))
+
+			b.Reset()
+			Expect(d.WriteMarkdown(&b)).To(Succeed())
+			Expect(b.String()).To(ContainSubstring("## Changes from analysis old to new"))
+			Expect(b.String()).To(ContainSubstring("### Dependencies upgraded\n\n- lodash 4.17.9 -> 4.17.21\n"))
+			Expect(b.String()).To(ContainSubstring("Status changed from pass to fail"))
+
+			b.Reset()
+			Expect(d.WriteText(&b)).To(Succeed())
+			Expect(b.String()).To(ContainSubstring("Coverage:\n  80.00% -> 75.50% (-4.50)\n"))
+		})
+	})
+}
+
+const sampleOldAnalysis = `{
+  "id": "old",
+  "passed": true,
+  "scan_summaries": [
+    {"id": "s1", "results": {"type": "vulnerability", "data": {"vulnerabilities": [
+      {"name": "busybox", "version": "1.31.0", "vulnerabilities": [{"external_id": "CVE-2020-0001", "title": "old bug"}]}
+    ]}}},
+    {"id": "s2", "results": {"type": "dependency", "data": {"dependencies": [
+      {"type": "npm", "name": "lodash", "version": "4.17.9", "dependencies": [{"type": "npm", "name": "left-pad", "version": "1.0.0"}]}
+    ]}}},
+    {"id": "s3", "results": {"type": "license", "data": {"license": {"name": "LICENSE", "type": [{"name": "MIT"}]}}}},
+    {"id": "s4", "results": {"type": "secrets", "data": [{"rule": "Slack Token", "match": "xoxb", "confidence": 1, "file": "a.txt"}]}},
+    {"id": "s5", "results": {"type": "virus", "data": {"file_notes": {}}}},
+    {"id": "s6", "results": {"type": "coverage", "data": {"value": 80}}}
+  ]
+}`
+
+const sampleNewAnalysis = `{
+  "id": "new",
+  "passed": false,
+  "scan_summaries": [
+    {"id": "s1", "results": {"type": "vulnerability", "data": {"vulnerabilities": [
+      {"name": "log4j-core", "version": "2.14.1", "vulnerabilities": [
+        {"external_id": "CVE-2021-44228", "title": "Log4Shell", "score": "10.0", "score_details": {"cvssv3": {"baseSeverity": "CRITICAL"}}}
+      ]}
+    ]}}},
+    {"id": "s2", "results": {"type": "dependency", "data": {"dependencies": [
+      {"type": "npm", "name": "lodash", "version": "4.17.21"},
+      {"type": "maven", "org": "apache", "name": "log4j-core", "version": "2.14.1"}
+    ]}}},
+    {"id": "s3", "results": {"type": "license", "data": {"license": {"name": "LICENSE", "type": [{"name": "MIT"}, {"name": "GPL-3.0"}]}}}},
+    {"id": "s4", "results": {"type": "secrets", "data": [
+      {"rule": "Slack Token", "match": "xoxb", "confidence": 1, "file": "a.txt"},
+      {"rule": "AWS Key", "match": "AKIAAKIA", "confidence": 1, "file": "config.yml"}
+    ]}},
+    {"id": "s5", "results": {"type": "virus", "data": {"file_notes": {"bin/evil.exe": ["Win.Trojan FOUND"]}}}},
+    {"id": "s6", "results": {"type": "coverage", "data": {"value": 75.5}}}
+  ]
+}`
@@ -0,0 +1,140 @@
+package analyses
+
+import (
+	"github.com/ion-channel/ionic/scans"
+)
+
+// findResults calls get with each scan of the analysis until it returns
+// without an error, and returns a not present error if no scan matched
+func (a *Analysis) findResults(resultType string, get func(s *scans.Scan) error) error {
+	if a != nil {
+		for i := range a.ScanSummaries {
+			if get(&a.ScanSummaries[i]) == nil {
+				return nil
+			}
+		}
+	}
+
+	return scans.NotPresentError(resultType)
+}
+
+// Vulnerabilities returns the results of the analysis' vulnerability scan, or
+// an error if the analysis has none
+func (a *Analysis) Vulnerabilities() (*scans.VulnerabilityResults, error) {
+	var r *scans.VulnerabilityResults
+	err := a.findResults("vulnerability", func(s *scans.Scan) (err error) {
+		r, err = s.Vulnerabilities()
+		return err
+	})
+
+	return r, err
+}
+
+// ExternalVulnerabilities returns the results of the analysis' external
+// vulnerability scan, or an error if the analysis has none
+func (a *Analysis) ExternalVulnerabilities() (*scans.ExternalVulnerabilitiesResults, error) {
+	var r *scans.ExternalVulnerabilitiesResults
+	err := a.findResults("external vulnerability", func(s *scans.Scan) (err error) {
+		r, err = s.ExternalVulnerabilities()
+		return err
+	})
+
+	return r, err
+}
+
+// Dependencies returns the results of the analysis' dependency scan, or an
+// error if the analysis has none
+func (a *Analysis) Dependencies() (*scans.DependencyResults, error) {
+	var r *scans.DependencyResults
+	err := a.findResults("dependency", func(s *scans.Scan) (err error) {
+		r, err = s.Dependencies()
+		return err
+	})
+
+	return r, err
+}
+
+// Licenses returns the results of the analysis' license scan, or an error if
+// the analysis has none
+func (a *Analysis) Licenses() (*scans.LicenseResults, error) {
+	var r *scans.LicenseResults
+	err := a.findResults("license", func(s *scans.Scan) (err error) {
+		r, err = s.Licenses()
+		return err
+	})
+
+	return r, err
+}
+
+// Secrets returns the results of the analysis' secrets scan, or an error if
+// the analysis has none
+func (a *Analysis) Secrets() (*scans.SecretResults, error) {
+	var r *scans.SecretResults
+	err := a.findResults("secrets", func(s *scans.Scan) (err error) {
+		r, err = s.Secrets()
+		return err
+	})
+
+	return r, err
+}
+
+// Viruses returns the results of the analysis' virus scan, or an error if the
+// analysis has none
+func (a *Analysis) Viruses() (*scans.VirusResults, error) {
+	var r *scans.VirusResults
+	err := a.findResults("virus", func(s *scans.Scan) (err error) {
+		r, err = s.Viruses()
+		return err
+	})
+
+	return r, err
+}
+
+// Coverage returns the results of the analysis' coverage scan, or an error if
+// the analysis has none
+func (a *Analysis) Coverage() (*scans.CoverageResults, error) {
+	var r *scans.CoverageResults
+	err := a.findResults("coverage", func(s *scans.Scan) (err error) {
+		r, err = s.Coverage()
+		return err
+	})
+
+	return r, err
+}
+
+// Community returns the results of the analysis' community scan, or an error
+// if the analysis has none
+func (a *Analysis) Community() (*scans.CommunityResults, error) {
+	var r *scans.CommunityResults
+	err := a.findResults("community", func(s *scans.Scan) (err error) {
+		r, err = s.Community()
+		return err
+	})
+
+	return r, err
+}
+
+// Ecosystems returns the results of the analysis' ecosystems scan, or an
+// error if the analysis has none
+func (a *Analysis) Ecosystems() (*scans.EcosystemResults, error) {
+	var r *scans.EcosystemResults
+	err := a.findResults("ecosystems", func(s *scans.Scan) (err error) {
+		r, err = s.Ecosystems()
+		return err
+	})
+
+	return r, err
+}
+
+// RiskResults returns the results of the analysis' risk scan, or an error if
+// the analysis has none. It is not named Risk because Analysis already has a
+// Risk field from its Summary.
+func (a *Analysis) RiskResults() (*scans.RiskResults, error) {
+	var r *scans.RiskResults
+	err := a.findResults("risk", func(s *scans.Scan) (err error) {
+		r, err = s.Risk()
+		return err
+	})
+
+	return r, err
+}
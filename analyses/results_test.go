@@ -0,0 +1,66 @@
+package analyses
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/franela/goblin"
+	. "github.com/onsi/gomega"
+
+	"github.com/ion-channel/ionic/scans"
+)
+
+func TestResults(t *testing.T) {
+	g := goblin.Goblin(t)
+	RegisterFailHandler(func(m string, _ ...int) { g.Fail(m) })
+
+	g.Describe("Analysis Results", func() {
+		var a Analysis
+
+		g.BeforeEach(func() {
+			Expect(json.Unmarshal([]byte(sampleNewAnalysis), &a)).To(Succeed())
+		})
+
+		g.It("should return the results of each scan type present", func() {
+			v, err := a.Vulnerabilities()
+			Expect(err).To(BeNil())
+			Expect(v.Vulnerabilities).To(HaveLen(1))
+
+			d, err := a.Dependencies()
+			Expect(err).To(BeNil())
+			Expect(d.Dependencies).To(HaveLen(2))
+
+			l, err := a.Licenses()
+			Expect(err).To(BeNil())
+			Expect(l.Type).To(HaveLen(2))
+
+			s, err := a.Secrets()
+			Expect(err).To(BeNil())
+			Expect(s.Secrets).To(HaveLen(2))
+
+			vr, err := a.Viruses()
+			Expect(err).To(BeNil())
+			Expect(vr.FileNotes).To(HaveKey("bin/evil.exe"))
+
+			c, err := a.Coverage()
+			Expect(err).To(BeNil())
+			Expect(c.Value).To(Equal(75.5))
+		})
+
+		g.It("should return a not present error for missing scan types", func() {
+			_, err := a.Community()
+			Expect(errors.Is(err, scans.ErrResultsNotPresent)).To(BeTrue())
+
+			_, err = a.Ecosystems()
+			Expect(errors.Is(err, scans.ErrResultsNotPresent)).To(BeTrue())
+
+			_, err = a.RiskResults()
+			Expect(errors.Is(err, scans.ErrResultsNotPresent)).To(BeTrue())
+
+			var nilAnalysis *Analysis
+			_, err = nilAnalysis.Vulnerabilities()
+			Expect(errors.Is(err, scans.ErrResultsNotPresent)).To(BeTrue())
+		})
+	})
+}
@@ -0,0 +1,334 @@
+package ionic
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ion-channel/ionic/pagination"
+	"github.com/ion-channel/ionic/projects"
+	"github.com/ion-channel/ionic/scanner"
+)
+
+const (
+	defaultTeamAnalysisConcurrency = 5
+	defaultTeamAnalysisRetries     = 2
+	defaultTeamAnalysisSlowest     = 5
+	defaultTeamAnalysisRetryDelay  = 10 * time.Second
+)
+
+// TeamAnalysisOptions represents the options available when analyzing the
+// projects of a team with AnalyzeTeam. All the options are optional and will be
+// replaced with working defaults if left empty.
+type TeamAnalysisOptions struct {
+	// Filter selects the projects to analyze. The team ID is always set to the
+	// team being analyzed.
+	Filter projects.Filter
+	// Tags limits the analyses to projects with at least one of the given tags,
+	// matched by tag name or ID.
+	Tags []string
+	// RulesetID limits the analyses to projects using the given ruleset.
+	RulesetID string
+	// Concurrency is the maximum number of analyses tracked at the same time.
+	Concurrency int
+	// Retries is how many times an analysis is restarted after its project's
+	// source was unreachable. A negative value disables retries.
+	Retries int
+	// RetryDelay is the delay before the first retry of an analysis. It
+	// doubles before each further retry.
+	RetryDelay time.Duration
+	// SlowestCount is how many of the slowest projects to include in the report.
+	SlowestCount int
+	// Wait is used when waiting for each analysis to complete.
+	Wait WaitOptions
+}
+
+// ProjectAnalysis represents the outcome of analyzing a single project as part
+// of a team analysis
+type ProjectAnalysis struct {
+	ProjectID   string          `json:"project_id"`
+	ProjectName string          `json:"project_name"`
+	AnalysisID  string          `json:"analysis_id"`
+	Attempts    int             `json:"attempts"`
+	Duration    time.Duration   `json:"duration"`
+	Result      *AnalysisResult `json:"-"`
+	// PreviouslyPassed is whether the project's previous analysis passed, or
+	// nil if the project had not been analyzed before.
+	PreviouslyPassed *bool  `json:"previously_passed,omitempty"`
+	Error            string `json:"error,omitempty"`
+}
+
+// Passed returns whether the project's analysis completed and passed its ruleset
+func (p *ProjectAnalysis) Passed() bool {
+	return p.Error == "" && p.Result.Passed()
+}
+
+// Errored returns whether the project's analysis could not be completed
+func (p *ProjectAnalysis) Errored() bool {
+	return p.Error != "" || p.Result == nil || p.Result.Status == nil ||
+		p.Result.Status.Status == scanner.AnalysisStatusErrored
+}
+
+// ProjectScanStatus represents the status of a scan within a team analysis,
+// tagged with the project it belongs to
+type ProjectScanStatus struct {
+	ProjectID   string             `json:"project_id"`
+	ProjectName string             `json:"project_name"`
+	Scan        scanner.ScanStatus `json:"scan"`
+}
+
+// TeamAnalysisReport represents the aggregated results of analyzing the
+// projects of a team
+type TeamAnalysisReport struct {
+	TeamID       string              `json:"team_id"`
+	Projects     []ProjectAnalysis   `json:"projects"`
+	Passed       int                 `json:"passed"`
+	Failed       int                 `json:"failed"`
+	Errored      int                 `json:"errored"`
+	ErroredScans []ProjectScanStatus `json:"errored_scans"`
+	Slowest      []ProjectAnalysis   `json:"slowest"`
+	NewlyFailing []ProjectAnalysis   `json:"newly_failing"`
+}
+
+// AnalyzeTeam takes a team ID, token, and options. It starts analyses for the
+// team's projects matching the options, tracks each analysis to completion
+// with at most the configured number running at once, and retries analyses of
+// projects whose source was unreachable, backing off between attempts. It
+// returns a report aggregating the results, or an error if the projects could
+// not be retrieved.
+func (ic *IonClient) AnalyzeTeam(ctx context.Context, teamID, token string, opts TeamAnalysisOptions) (*TeamAnalysisReport, error) {
+	opts = opts.withDefaults()
+
+	filter := opts.Filter
+	filter.TeamID = &teamID
+
+	client := ic.WithContext(ctx)
+
+	ps, err := client.GetProjects(filter, token, pagination.AllItems)
+	if err != nil {
+		return nil, fmt.Errorf("failed to analyze team: %v", err.Error())
+	}
+
+	ps = filterProjects(ps, opts.Tags, opts.RulesetID)
+
+	previous := make(map[string]bool)
+	if len(ps) > 0 {
+		ids := make([]string, 0, len(ps))
+		for i := range ps {
+			ids = append(ids, stringValue(ps[i].ID))
+		}
+
+		// projects without a previous analysis are left out of the comparison,
+		// so a failure here only loses the newly failing detection
+		summaries, err := client.GetLatestAnalysisSummaries(ids, token)
+		if err == nil {
+			for _, s := range summaries {
+				previous[s.ProjectID] = s.Passed
+			}
+		}
+	}
+
+	results := make([]ProjectAnalysis, len(ps))
+	sem := make(chan struct{}, opts.Concurrency)
+	var wg sync.WaitGroup
+
+	for i := range ps {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+
+			sem <- struct{}{}
+			defer func() { <-sem }()
+
+			results[i] = ic.analyzeTeamProject(ctx, teamID, token, ps[i], opts)
+			if passed, ok := previous[results[i].ProjectID]; ok {
+				results[i].PreviouslyPassed = &passed
+			}
+		}(i)
+	}
+
+	wg.Wait()
+
+	report := summarizeTeamAnalyses(results, opts.SlowestCount)
+	report.TeamID = teamID
+
+	return report, nil
+}
+
+func (ic *IonClient) analyzeTeamProject(ctx context.Context, teamID, token string, project projects.Project, opts TeamAnalysisOptions) ProjectAnalysis {
+	pa := ProjectAnalysis{
+		ProjectID:   stringValue(project.ID),
+		ProjectName: stringValue(project.Name),
+	}
+
+	branch := stringValue(project.Branch)
+	wait := opts.Wait
+	wait.Token = token
+	start := time.Now()
+
+	delay := opts.RetryDelay
+	for pa.Attempts = 1; pa.Attempts <= opts.Retries+1; pa.Attempts++ {
+		if pa.Attempts > 1 {
+			timer := time.NewTimer(delay)
+			select {
+			case <-ctx.Done():
+				timer.Stop()
+				pa.Error = fmt.Sprintf("stopped retrying analysis of project %v: %v", pa.ProjectID, ctx.Err())
+				pa.Attempts--
+				return pa.finish(start)
+			case <-timer.C:
+			}
+
+			delay *= 2
+		}
+
+		status, err := ic.WithContext(ctx).AnalyzeProject(pa.ProjectID, teamID, branch, token)
+		if err != nil {
+			pa.Error = err.Error()
+			break
+		}
+
+		pa.AnalysisID = status.ID
+
+		res, err := ic.WaitForAnalysis(ctx, status.ID, teamID, pa.ProjectID, wait)
+		pa.Result = res
+		pa.Error = ""
+		if err != nil {
+			pa.Error = err.Error()
+		}
+
+		if res == nil || res.Status == nil || !res.Status.UnreachableError {
+			break
+		}
+	}
+
+	if pa.Attempts > opts.Retries+1 {
+		pa.Attempts = opts.Retries + 1
+	}
+
+	return pa.finish(start)
+}
+
+// finish sets the duration of the project's analysis, preferring the time the
+// analysis itself took over the time since it was started
+func (pa ProjectAnalysis) finish(start time.Time) ProjectAnalysis {
+	pa.Duration = time.Since(start)
+	if pa.Result != nil && pa.Result.Status != nil && !pa.Result.Status.CreatedAt.IsZero() && pa.Result.Status.UpdatedAt.After(pa.Result.Status.CreatedAt) {
+		pa.Duration = pa.Result.Status.UpdatedAt.Sub(pa.Result.Status.CreatedAt)
+	}
+
+	return pa
+}
+
+func (o TeamAnalysisOptions) withDefaults() TeamAnalysisOptions {
+	if o.Concurrency <= 0 {
+		o.Concurrency = defaultTeamAnalysisConcurrency
+	}
+
+	switch {
+	case o.Retries == 0:
+		o.Retries = defaultTeamAnalysisRetries
+	case o.Retries < 0:
+		o.Retries = 0
+	}
+
+	if o.SlowestCount <= 0 {
+		o.SlowestCount = defaultTeamAnalysisSlowest
+	}
+
+	if o.RetryDelay <= 0 {
+		o.RetryDelay = defaultTeamAnalysisRetryDelay
+	}
+
+	return o
+}
+
+// filterProjects returns the projects that have at least one of the given tags
+// and use the given ruleset. Empty criteria match every project.
+func filterProjects(ps []projects.Project, tags []string, rulesetID string) []projects.Project {
+	filtered := make([]projects.Project, 0, len(ps))
+	for _, p := range ps {
+		if rulesetID != "" && stringValue(p.RulesetID) != rulesetID {
+			continue
+		}
+
+		if len(tags) > 0 && !projectHasTag(p, tags) {
+			continue
+		}
+
+		filtered = append(filtered, p)
+	}
+
+	return filtered
+}
+
+func projectHasTag(p projects.Project, tags []string) bool {
+	for _, tag := range p.Tags {
+		for _, want := range tags {
+			if tag.ID == want || strings.EqualFold(tag.Name, want) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// summarizeTeamAnalyses aggregates the outcomes of the analyses of a team's
+// projects into a report
+func summarizeTeamAnalyses(results []ProjectAnalysis, slowestCount int) *TeamAnalysisReport {
+	report := &TeamAnalysisReport{
+		Projects:     results,
+		ErroredScans: []ProjectScanStatus{},
+		NewlyFailing: []ProjectAnalysis{},
+	}
+
+	for _, pa := range results {
+		switch {
+		case pa.Errored():
+			report.Errored++
+		case pa.Passed():
+			report.Passed++
+		default:
+			report.Failed++
+			if pa.PreviouslyPassed != nil && *pa.PreviouslyPassed {
+				report.NewlyFailing = append(report.NewlyFailing, pa)
+			}
+		}
+
+		if pa.Result != nil {
+			for _, scan := range pa.Result.ErroredScans {
+				report.ErroredScans = append(report.ErroredScans, ProjectScanStatus{
+					ProjectID:   pa.ProjectID,
+					ProjectName: pa.ProjectName,
+					Scan:        scan,
+				})
+			}
+		}
+	}
+
+	slowest := make([]ProjectAnalysis, len(results))
+	copy(slowest, results)
+	sort.SliceStable(slowest, func(i, j int) bool {
+		return slowest[i].Duration > slowest[j].Duration
+	})
+
+	if len(slowest) > slowestCount {
+		slowest = slowest[:slowestCount]
+	}
+
+	report.Slowest = slowest
+
+	return report
+}
+
+func stringValue(s *string) string {
+	if s == nil {
+		return ""
+	}
+
+	return *s
+}
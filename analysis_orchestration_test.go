@@ -0,0 +1,207 @@
+package ionic
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/franela/goblin"
+	"github.com/gomicro/bogus"
+	. "github.com/onsi/gomega"
+
+	"github.com/ion-channel/ionic/analyses"
+	"github.com/ion-channel/ionic/projects"
+	"github.com/ion-channel/ionic/rulesets"
+	"github.com/ion-channel/ionic/scanner"
+	"github.com/ion-channel/ionic/tags"
+)
+
+func TestAnalysisOrchestration(t *testing.T) {
+	g := goblin.Goblin(t)
+	RegisterFailHandler(func(m string, _ ...int) { g.Fail(m) })
+
+	g.Describe("Filtering Projects", func() {
+		rulesetA, rulesetB := "ruleset-a", "ruleset-b"
+		ps := []projects.Project{
+			{Name: strPtr("one"), RulesetID: &rulesetA, Tags: []tags.Tag{{ID: "t1", Name: "Backend"}}},
+			{Name: strPtr("two"), RulesetID: &rulesetB, Tags: []tags.Tag{{ID: "t2", Name: "frontend"}}},
+			{Name: strPtr("three"), RulesetID: &rulesetA},
+		}
+
+		g.It("should keep every project without criteria", func() {
+			Expect(filterProjects(ps, nil, "")).To(HaveLen(3))
+		})
+
+		g.It("should filter by tag name or id", func() {
+			Expect(filterProjects(ps, []string{"backend"}, "")).To(HaveLen(1))
+			Expect(filterProjects(ps, []string{"t2", "backend"}, "")).To(HaveLen(2))
+		})
+
+		g.It("should filter by ruleset", func() {
+			filtered := filterProjects(ps, nil, rulesetA)
+			Expect(filtered).To(HaveLen(2))
+			Expect(*filtered[1].Name).To(Equal("three"))
+		})
+	})
+
+	g.Describe("Summarizing Team Analyses", func() {
+		g.It("should aggregate pass, fail, and error counts", func() {
+			yes, no := true, false
+			results := []ProjectAnalysis{
+				{ProjectID: "passing", Duration: time.Minute, Result: analysisResult(scanner.AnalysisStatusFinished, true), PreviouslyPassed: &no},
+				{ProjectID: "newly-failing", Duration: 3 * time.Minute, Result: analysisResult(scanner.AnalysisStatusFinished, false), PreviouslyPassed: &yes},
+				{ProjectID: "still-failing", Duration: 2 * time.Minute, Result: analysisResult(scanner.AnalysisStatusFinished, false), PreviouslyPassed: &no},
+				{ProjectID: "errored", Duration: time.Second, Result: analysisResult(scanner.AnalysisStatusErrored, false), Error: "analysis errored"},
+			}
+			results[3].Result.ErroredScans = []scanner.ScanStatus{{Name: "virus", Status: scanner.ScanStatusErrored, Message: "boom"}}
+
+			r := summarizeTeamAnalyses(results, 2)
+			Expect(r.Passed).To(Equal(1))
+			Expect(r.Failed).To(Equal(2))
+			Expect(r.Errored).To(Equal(1))
+			Expect(r.NewlyFailing).To(HaveLen(1))
+			Expect(r.NewlyFailing[0].ProjectID).To(Equal("newly-failing"))
+			Expect(r.ErroredScans).To(HaveLen(1))
+			Expect(r.ErroredScans[0].ProjectID).To(Equal("errored"))
+			Expect(r.Slowest).To(HaveLen(2))
+			Expect(r.Slowest[0].ProjectID).To(Equal("newly-failing"))
+			Expect(r.Slowest[1].ProjectID).To(Equal("still-failing"))
+		})
+	})
+}
+
+func TestAnalyzeTeam(t *testing.T) {
+	g := goblin.Goblin(t)
+	RegisterFailHandler(func(m string, _ ...int) { g.Fail(m) })
+
+	g.Describe("Analyzing a Team", func() {
+		var server *bogus.Bogus
+		var ic *IonClient
+		var opts TeamAnalysisOptions
+
+		g.BeforeEach(func() {
+			server = bogus.New()
+			host, port := server.HostPort()
+			ic, _ = New(fmt.Sprintf("http://%v:%v", host, port))
+
+			server.AddPath("/" + projects.GetProjectsEndpoint).
+				SetMethods("GET").
+				SetPayload([]byte(`{"data":[{"id":"p1","name":"one","branch":"main"},{"id":"p2","name":"two","branch":"main"}],"meta":{}}`))
+			server.AddPath("/" + analyses.AnalysisGetLatestAnalysisSummariesEndpoint).
+				SetMethods("POST").
+				SetPayload([]byte(`{"data":[{"project_id":"p1","passed":true}],"meta":{}}`))
+			server.AddPath("/" + analyses.AnalysisGetAnalysisEndpoint).
+				SetMethods("GET").
+				SetPayload([]byte(`{"data":{"id":"a1","scan_summaries":[]},"meta":{}}`))
+			server.AddPath("/" + rulesets.GetAppliedRuleSetEndpoint).
+				SetMethods("GET").
+				SetPayload([]byte(`{"data":{"analysis_id":"a1","rule_evaluation_summary":{"ruleset_name":"rs","passed":false,"ruleresults":[]}},"meta":{}}`))
+
+			opts = TeamAnalysisOptions{
+				Concurrency: 1,
+				RetryDelay:  time.Millisecond,
+				Wait:        WaitOptions{Interval: time.Millisecond},
+			}
+		})
+
+		g.AfterEach(func() {
+			server.Close()
+		})
+
+		analyzed := func(status string) {
+			server.AddPath("/" + scanner.ScannerAnalyzeProjectEndpoint).
+				SetMethods("POST").
+				SetPayload([]byte(`{"data":{"id":"a1","status":"queued"},"meta":{}}`))
+			server.AddPath("/" + scanner.ScannerGetAnalysisStatusEndpoint).
+				SetMethods("GET").
+				SetPayload([]byte(`{"data":` + status + `,"meta":{}}`))
+		}
+
+		g.It("should analyze every project and report the newly failing ones", func() {
+			analyzed(`{"id":"a1","status":"finished"}`)
+
+			r, err := ic.AnalyzeTeam(context.Background(), "team", "token", opts)
+			Expect(err).To(BeNil())
+			Expect(r.TeamID).To(Equal("team"))
+			Expect(r.Projects).To(HaveLen(2))
+			Expect(r.Failed).To(Equal(2))
+			Expect(r.Projects[0].Attempts).To(Equal(1))
+			Expect(r.NewlyFailing).To(HaveLen(1))
+			Expect(r.NewlyFailing[0].ProjectID).To(Equal("p1"))
+		})
+
+		g.It("should still report when previous analyses cannot be retrieved", func() {
+			analyzed(`{"id":"a1","status":"finished"}`)
+			server.AddPath("/" + analyses.AnalysisGetLatestAnalysisSummariesEndpoint).
+				SetStatus(http.StatusInternalServerError)
+
+			r, err := ic.AnalyzeTeam(context.Background(), "team", "token", opts)
+			Expect(err).To(BeNil())
+			Expect(r.Failed).To(Equal(2))
+			Expect(r.NewlyFailing).To(BeEmpty())
+			Expect(r.Projects[0].PreviouslyPassed).To(BeNil())
+		})
+
+		g.It("should retry unreachable projects twice by default", func() {
+			analyzed(`{"id":"a1","status":"errored","unreachable_error":true}`)
+
+			r, err := ic.AnalyzeTeam(context.Background(), "team", "token", opts)
+			Expect(err).To(BeNil())
+			Expect(r.Errored).To(Equal(2))
+			Expect(r.Projects[0].Attempts).To(Equal(3))
+			Expect(r.Projects[1].Attempts).To(Equal(3))
+			Expect(server.AddPath("/" + scanner.ScannerAnalyzeProjectEndpoint).Hits).To(Equal(6))
+		})
+
+		g.It("should not retry when retries are disabled", func() {
+			analyzed(`{"id":"a1","status":"errored","unreachable_error":true}`)
+			opts.Retries = -1
+
+			r, err := ic.AnalyzeTeam(context.Background(), "team", "token", opts)
+			Expect(err).To(BeNil())
+			Expect(r.Projects[0].Attempts).To(Equal(1))
+			Expect(server.AddPath("/" + scanner.ScannerAnalyzeProjectEndpoint).Hits).To(Equal(2))
+		})
+
+		g.It("should stop retrying when the context is cancelled", func() {
+			analyzed(`{"id":"a1","status":"errored","unreachable_error":true}`)
+			opts.RetryDelay = time.Hour
+
+			ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+			defer cancel()
+
+			r, err := ic.AnalyzeTeam(ctx, "team", "token", opts)
+			Expect(err).To(BeNil())
+			errs := []string{}
+			for _, pa := range r.Projects {
+				Expect(pa.Attempts).To(Equal(1))
+				errs = append(errs, pa.Error)
+			}
+			Expect(errs).To(ContainElement(ContainSubstring("stopped retrying")))
+		})
+
+		g.It("should return an error when the projects cannot be retrieved", func() {
+			server.AddPath("/" + projects.GetProjectsEndpoint).
+				SetStatus(http.StatusUnauthorized)
+
+			r, err := ic.AnalyzeTeam(context.Background(), "team", "token", opts)
+			Expect(err).NotTo(BeNil())
+			Expect(r).To(BeNil())
+		})
+	})
+}
+
+func analysisResult(status string, passed bool) *AnalysisResult {
+	return &AnalysisResult{
+		Status: &scanner.AnalysisStatus{Status: status},
+		AppliedRuleset: &rulesets.AppliedRulesetSummary{
+			RuleEvaluationSummary: &rulesets.RuleEvaluationSummary{Passed: passed},
+		},
+	}
+}
+
+func strPtr(s string) *string {
+	return &s
+}
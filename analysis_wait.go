@@ -0,0 +1,184 @@
+package ionic
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/ion-channel/ionic/analyses"
+	"github.com/ion-channel/ionic/rulesets"
+	"github.com/ion-channel/ionic/scanner"
+)
+
+const (
+	defaultWaitInterval    = 2 * time.Second
+	defaultWaitMaxInterval = 30 * time.Second
+	defaultWaitMultiplier  = 1.5
+)
+
+// WaitOptions represents the options available when waiting for an analysis
+// to complete. All the options are optional and will be replaced with working
+// defaults if left empty.
+type WaitOptions struct {
+	// Token is used to authenticate the requests. The client's session token
+	// is used if it is empty.
+	Token string
+	// Interval is the delay before the first status check.
+	Interval time.Duration
+	// MaxInterval is the upper bound the delay between checks backs off to.
+	MaxInterval time.Duration
+	// Multiplier is the factor the delay grows by after each check.
+	Multiplier float64
+	// OnScanStatusChange is called whenever a scan of the analysis changes
+	// state. The previous status is the zero value the first time a scan is
+	// seen.
+	OnScanStatusChange func(previous, current scanner.ScanStatus)
+}
+
+// AnalysisResult represents a completed analysis: its final status, the
+// analysis itself, and the result of evaluating it against its ruleset.
+type AnalysisResult struct {
+	Status         *scanner.AnalysisStatus
+	Analysis       *analyses.Analysis
+	AppliedRuleset *rulesets.AppliedRulesetSummary
+	ErroredScans   []scanner.ScanStatus
+}
+
+// Passed returns whether the analysis passed its ruleset
+func (r *AnalysisResult) Passed() bool {
+	if r == nil || r.AppliedRuleset == nil || r.AppliedRuleset.RuleEvaluationSummary == nil {
+		return false
+	}
+
+	return r.AppliedRuleset.RuleEvaluationSummary.Passed
+}
+
+// WaitForAnalysis takes an analysis ID, team ID, project ID, and options. It
+// polls the status of the analysis, backing off between checks, until the
+// analysis is done or the context is cancelled. Once the analysis is done it
+// returns its final status together with the analysis and its applied ruleset.
+// If the analysis errored, the result contains the status and errored scans,
+// and the returned error includes the messages of the errored scans.
+func (ic *IonClient) WaitForAnalysis(ctx context.Context, analysisID, teamID, projectID string, opts WaitOptions) (*AnalysisResult, error) {
+	opts = opts.withDefaults()
+
+	token := opts.Token
+	if token == "" {
+		token = ic.Session().BearerToken
+	}
+
+	client := ic.WithContext(ctx)
+	seen := make(map[string]scanner.ScanStatus)
+	interval := opts.Interval
+
+	var status *scanner.AnalysisStatus
+	for {
+		timer := time.NewTimer(interval)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return nil, fmt.Errorf("stopped waiting for analysis %v: %v", analysisID, ctx.Err())
+		case <-timer.C:
+		}
+
+		var err error
+		status, err = client.GetAnalysisStatus(analysisID, teamID, projectID, token)
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil, fmt.Errorf("stopped waiting for analysis %v: %v", analysisID, ctx.Err())
+			}
+
+			return nil, err
+		}
+
+		notifyScanStatusChanges(seen, status.ScanStatus, opts.OnScanStatusChange)
+
+		if status.Done() {
+			break
+		}
+
+		interval = time.Duration(float64(interval) * opts.Multiplier)
+		if interval > opts.MaxInterval {
+			interval = opts.MaxInterval
+		}
+	}
+
+	result := &AnalysisResult{
+		Status:       status,
+		ErroredScans: status.ErroredScans(),
+	}
+
+	if status.Status == scanner.AnalysisStatusErrored {
+		return result, newAnalysisErroredError(status, result.ErroredScans)
+	}
+
+	analysis, err := client.GetAnalysis(analysisID, teamID, projectID, token)
+	if err != nil {
+		return result, err
+	}
+
+	result.Analysis = analysis
+
+	appliedRuleset, err := client.GetAppliedRuleSet(projectID, teamID, analysisID, token)
+	if err != nil {
+		return result, err
+	}
+
+	result.AppliedRuleset = appliedRuleset
+
+	return result, nil
+}
+
+func (o WaitOptions) withDefaults() WaitOptions {
+	if o.Interval <= 0 {
+		o.Interval = defaultWaitInterval
+	}
+
+	if o.MaxInterval <= 0 {
+		o.MaxInterval = defaultWaitMaxInterval
+	}
+
+	if o.MaxInterval < o.Interval {
+		o.MaxInterval = o.Interval
+	}
+
+	if o.Multiplier < 1 {
+		o.Multiplier = defaultWaitMultiplier
+	}
+
+	return o
+}
+
+func notifyScanStatusChanges(seen map[string]scanner.ScanStatus, current []scanner.ScanStatus, callback func(previous, current scanner.ScanStatus)) {
+	for _, scan := range current {
+		key := scan.ID
+		if key == "" {
+			key = scan.Name
+		}
+
+		previous, ok := seen[key]
+		seen[key] = scan
+
+		if ok && strings.EqualFold(previous.Status, scan.Status) {
+			continue
+		}
+
+		if callback != nil {
+			callback(previous, scan)
+		}
+	}
+}
+
+func newAnalysisErroredError(status *scanner.AnalysisStatus, erroredScans []scanner.ScanStatus) error {
+	msgs := make([]string, 0, len(erroredScans))
+	for _, scan := range erroredScans {
+		msgs = append(msgs, fmt.Sprintf("%v: %v", scan.Name, scan.Message))
+	}
+
+	if len(msgs) == 0 {
+		return fmt.Errorf("analysis %v errored: %v", status.ID, status.Message)
+	}
+
+	return fmt.Errorf("analysis %v errored: %v (%v)", status.ID, status.Message, strings.Join(msgs, "; "))
+}
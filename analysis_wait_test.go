@@ -0,0 +1,117 @@
+package ionic
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/franela/goblin"
+	. "github.com/onsi/gomega"
+
+	"github.com/ion-channel/ionic/analyses"
+	"github.com/ion-channel/ionic/rulesets"
+	"github.com/ion-channel/ionic/scanner"
+)
+
+func TestWaitForAnalysis(t *testing.T) {
+	g := goblin.Goblin(t)
+	RegisterFailHandler(func(m string, _ ...int) { g.Fail(m) })
+
+	g.Describe("WaitForAnalysis", func() {
+		var server *httptest.Server
+		var statuses []string
+		var mu sync.Mutex
+		var calls int
+
+		g.BeforeEach(func() {
+			calls = 0
+			server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				mu.Lock()
+				defer mu.Unlock()
+
+				switch r.URL.Path {
+				case "/" + scanner.ScannerGetAnalysisStatusEndpoint:
+					status := statuses[calls]
+					if calls < len(statuses)-1 {
+						calls++
+					}
+					fmt.Fprintf(w, `{"data":%v,"meta":{}}`, status)
+				case "/" + analyses.AnalysisGetAnalysisEndpoint:
+					fmt.Fprint(w, `{"data":{"id":"analysis","scan_summaries":[]},"meta":{}}`)
+				case "/" + rulesets.GetAppliedRuleSetEndpoint:
+					fmt.Fprint(w, `{"data":{"analysis_id":"analysis","rule_evaluation_summary":{"ruleset_name":"rs","passed":true,"ruleresults":[]}},"meta":{}}`)
+				default:
+					w.WriteHeader(http.StatusNotFound)
+				}
+			}))
+		})
+
+		g.AfterEach(func() {
+			server.Close()
+		})
+
+		g.It("should poll until done and return the analysis and applied ruleset", func() {
+			statuses = []string{
+				`{"id":"analysis","status":"analyzing","scan_status":[{"id":"s1","name":"license","status":"started"}]}`,
+				`{"id":"analysis","status":"analyzing","scan_status":[{"id":"s1","name":"license","status":"finished"},{"id":"s2","name":"virus","status":"started"}]}`,
+				`{"id":"analysis","status":"finished","scan_status":[{"id":"s1","name":"license","status":"finished"},{"id":"s2","name":"virus","status":"finished"}]}`,
+			}
+
+			ic, _ := New(server.URL)
+
+			var changes []string
+			res, err := ic.WaitForAnalysis(context.Background(), "analysis", "team", "project", WaitOptions{
+				Token:    "token",
+				Interval: time.Millisecond,
+				OnScanStatusChange: func(previous, current scanner.ScanStatus) {
+					changes = append(changes, fmt.Sprintf("%v:%v->%v", current.Name, previous.Status, current.Status))
+				},
+			})
+			Expect(err).To(BeNil())
+			Expect(res.Status.Status).To(Equal(scanner.AnalysisStatusFinished))
+			Expect(res.Analysis.ID).To(Equal("analysis"))
+			Expect(res.Passed()).To(BeTrue())
+			Expect(changes).To(Equal([]string{
+				"license:->started",
+				"license:started->finished",
+				"virus:->started",
+				"virus:started->finished",
+			}))
+		})
+
+		g.It("should surface errored scans", func() {
+			statuses = []string{
+				`{"id":"analysis","status":"errored","message":"scans errored","scan_status":[{"id":"s1","name":"virus","status":"errored","message":"clamav unavailable"}]}`,
+			}
+
+			ic, _ := New(server.URL)
+
+			res, err := ic.WaitForAnalysis(context.Background(), "analysis", "team", "project", WaitOptions{
+				Interval: time.Millisecond,
+			})
+			Expect(err).NotTo(BeNil())
+			Expect(err.Error()).To(ContainSubstring("virus: clamav unavailable"))
+			Expect(res.ErroredScans).To(HaveLen(1))
+			Expect(res.Analysis).To(BeNil())
+		})
+
+		g.It("should stop waiting when the context is done", func() {
+			statuses = []string{`{"id":"analysis","status":"analyzing"}`}
+
+			ic, _ := New(server.URL)
+
+			ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+			defer cancel()
+
+			_, err := ic.WaitForAnalysis(ctx, "analysis", "team", "project", WaitOptions{
+				Interval: time.Millisecond,
+			})
+			Expect(err).NotTo(BeNil())
+			Expect(err.Error()).To(ContainSubstring("stopped waiting"))
+		})
+	})
+}
@@ -0,0 +1,103 @@
+// Command ionic-gate analyzes a project, waits for the analysis to finish, and
+// exits non-zero if the analysis does not pass the project's ruleset.
+//
+// Usage:
+//
+//	ionic-gate -team <team id> -project <project id> [-branch <branch>] [-timeout 30m]
+//	           [-junit report.xml] [-markdown summary.md]
+//
+// The API key is read from the IONCHANNEL_SECRET_KEY environment variable, and
+// the API URL from IONIC_BASE_URL. The branch, commit hash, and author default
+// to the values exposed by common CI systems.
+//
+// Exit codes: 0 when the ruleset passed, 1 when it failed, and 2 when the gate
+// could not be run.
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/ion-channel/ionic"
+	"github.com/ion-channel/ionic/gate"
+)
+
+const (
+	exitPassed  = 0
+	exitFailed  = 1
+	exitErrored = 2
+)
+
+func main() {
+	os.Exit(run())
+}
+
+func run() int {
+	opts := gate.OptionsFromEnv()
+
+	var teamID, projectID, baseURL, junitPath, markdownPath string
+	flag.StringVar(&teamID, "team", os.Getenv("IONCHANNEL_TEAM_ID"), "ID of the team the project belongs to")
+	flag.StringVar(&projectID, "project", os.Getenv("IONCHANNEL_PROJECT_ID"), "ID of the project to analyze")
+	flag.StringVar(&baseURL, "base-url", "", "base URL of the Ion Channel API")
+	flag.StringVar(&opts.Branch, "branch", opts.Branch, "branch to analyze")
+	flag.StringVar(&opts.CommitHash, "commit", opts.CommitHash, "commit hash being gated")
+	flag.StringVar(&opts.Author, "author", opts.Author, "author of the commit being gated")
+	flag.DurationVar(&opts.Timeout, "timeout", gate.DefaultTimeout, "maximum time to wait for the analysis")
+	flag.DurationVar(&opts.PollInterval, "max-interval", gate.DefaultPollInterval, "longest delay between analysis status checks")
+	flag.StringVar(&junitPath, "junit", "", "path to write a JUnit XML report to")
+	flag.StringVar(&markdownPath, "markdown", "", "path to write a Markdown summary to")
+	flag.Parse()
+
+	token := os.Getenv("IONCHANNEL_SECRET_KEY")
+	if token == "" {
+		fmt.Fprintln(os.Stderr, "ionic-gate: IONCHANNEL_SECRET_KEY must be set")
+		return exitErrored
+	}
+
+	if teamID == "" || projectID == "" {
+		fmt.Fprintln(os.Stderr, "ionic-gate: -team and -project are required")
+		flag.Usage()
+		return exitErrored
+	}
+
+	client, err := ionic.NewWithOptions(ionic.IonClientOptions{BaseURL: baseURL})
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "ionic-gate: %v\n", err.Error())
+		return exitErrored
+	}
+
+	result, err := client.RunGate(context.Background(), teamID, projectID, token, opts)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "ionic-gate: %v\n", err.Error())
+		return exitErrored
+	}
+
+	err = gate.WriteTable(os.Stdout, result)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "ionic-gate: %v\n", err.Error())
+	}
+
+	if junitPath != "" {
+		err = gate.WriteJUnitFile(junitPath, result)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "ionic-gate: %v\n", err.Error())
+			return exitErrored
+		}
+	}
+
+	if markdownPath != "" {
+		err = gate.WriteMarkdownFile(markdownPath, result)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "ionic-gate: %v\n", err.Error())
+			return exitErrored
+		}
+	}
+
+	if !result.Passed() {
+		return exitFailed
+	}
+
+	return exitPassed
+}
@@ -0,0 +1,62 @@
+package coverage
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"regexp"
+	"strconv"
+)
+
+var conditionCoverageRegex = regexp.MustCompile(`\((\d+)/(\d+)\)`)
+
+type coberturaReport struct {
+	Packages []struct {
+		Classes []struct {
+			Filename string `xml:"filename,attr"`
+			Lines    []struct {
+				Number            int    `xml:"number,attr"`
+				Hits              int    `xml:"hits,attr"`
+				Branch            bool   `xml:"branch,attr"`
+				ConditionCoverage string `xml:"condition-coverage,attr"`
+			} `xml:"lines>line"`
+		} `xml:"classes>class"`
+	} `xml:"packages>package"`
+}
+
+// ParseCobertura reads a Cobertura XML report. Branch coverage comes from the
+// condition coverage of the lines marked as branches.
+func ParseCobertura(r io.Reader) (*Report, error) {
+	var cr coberturaReport
+	err := xml.NewDecoder(r).Decode(&cr)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse Cobertura report: %v", err.Error())
+	}
+
+	b := reportBuilder{}
+	for _, p := range cr.Packages {
+		for _, c := range p.Classes {
+			f := b.file(c.Filename)
+			for _, l := range c.Lines {
+				f.line(l.Number, l.Hits > 0)
+
+				if !l.Branch {
+					continue
+				}
+
+				// condition-coverage looks like "50% (1/2)"
+				m := conditionCoverageRegex.FindStringSubmatch(l.ConditionCoverage)
+				if m == nil {
+					continue
+				}
+
+				covered, _ := strconv.Atoi(m[1])
+				total, _ := strconv.Atoi(m[2])
+				f.branchesCovered += covered
+				f.branchesTotal += total
+			}
+		}
+	}
+
+	return b.report(FormatCobertura), nil
+}
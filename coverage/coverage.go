@@ -0,0 +1,360 @@
+// Package coverage parses unit test coverage reports produced by common tools,
+// and converts them into external scans that can be submitted to Ion Channel.
+package coverage
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/ion-channel/ionic/scanner"
+)
+
+const (
+	// FormatGo is the format of profiles written by go test -coverprofile
+	FormatGo = "go"
+	// FormatCobertura is the Cobertura XML format
+	FormatCobertura = "cobertura"
+	// FormatLCOV is the LCOV tracefile format
+	FormatLCOV = "lcov"
+	// FormatJaCoCo is the JaCoCo XML format
+	FormatJaCoCo = "jacoco"
+)
+
+var formatNames = map[string]string{
+	FormatGo:        "Go cover profile",
+	FormatCobertura: "Cobertura",
+	FormatLCOV:      "LCOV",
+	FormatJaCoCo:    "JaCoCo",
+}
+
+// Report represents the coverage of each file found in a coverage report
+type Report struct {
+	Format string         `json:"format"`
+	Files  []FileCoverage `json:"files"`
+}
+
+// FileCoverage represents the line and branch coverage of a single file
+type FileCoverage struct {
+	Path            string `json:"path"`
+	LinesCovered    int    `json:"lines_covered"`
+	LinesTotal      int    `json:"lines_total"`
+	BranchesCovered int    `json:"branches_covered"`
+	BranchesTotal   int    `json:"branches_total"`
+}
+
+// LineCoverage returns the percent of lines in the file that are covered
+func (f FileCoverage) LineCoverage() float64 {
+	return percent(f.LinesCovered, f.LinesTotal)
+}
+
+// BranchCoverage returns the percent of branches in the file that are covered
+func (f FileCoverage) BranchCoverage() float64 {
+	return percent(f.BranchesCovered, f.BranchesTotal)
+}
+
+// Parse reads a coverage report from the given reader, detecting its format
+// from its contents
+func Parse(r io.Reader) (*Report, error) {
+	b, err := ioutil.ReadAll(r)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read coverage report: %v", err.Error())
+	}
+
+	format, err := detectFormat(b)
+	if err != nil {
+		return nil, err
+	}
+
+	return ParseFormat(format, bytes.NewReader(b))
+}
+
+// ParseFile reads a coverage report from the file at the given path,
+// detecting its format from its contents
+func ParseFile(path string) (*Report, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open coverage report: %v", err.Error())
+	}
+	defer f.Close()
+
+	return Parse(f)
+}
+
+// ParseFormat reads a coverage report of the given format from the reader
+func ParseFormat(format string, r io.Reader) (*Report, error) {
+	switch format {
+	case FormatGo:
+		return ParseGoProfile(r)
+	case FormatCobertura:
+		return ParseCobertura(r)
+	case FormatLCOV:
+		return ParseLCOV(r)
+	case FormatJaCoCo:
+		return ParseJaCoCo(r)
+	default:
+		return nil, fmt.Errorf("unsupported coverage format: %q", format)
+	}
+}
+
+func detectFormat(b []byte) (string, error) {
+	s := bufio.NewScanner(bytes.NewReader(b))
+	for s.Scan() {
+		line := strings.TrimSpace(s.Text())
+		switch {
+		case line == "":
+			continue
+		case strings.HasPrefix(line, "mode:"):
+			return FormatGo, nil
+		case strings.HasPrefix(line, "TN:"), strings.HasPrefix(line, "SF:"):
+			return FormatLCOV, nil
+		case strings.HasPrefix(line, "<"):
+			if bytes.Contains(b, []byte("<coverage")) {
+				return FormatCobertura, nil
+			}
+
+			if bytes.Contains(b, []byte("<report")) {
+				return FormatJaCoCo, nil
+			}
+		}
+
+		break
+	}
+
+	return "", fmt.Errorf("unrecognized coverage report format")
+}
+
+// LinesCovered returns the number of covered lines across every file
+func (r *Report) LinesCovered() int {
+	total := 0
+	for _, f := range r.Files {
+		total += f.LinesCovered
+	}
+
+	return total
+}
+
+// LinesTotal returns the number of coverable lines across every file
+func (r *Report) LinesTotal() int {
+	total := 0
+	for _, f := range r.Files {
+		total += f.LinesTotal
+	}
+
+	return total
+}
+
+// LineCoverage returns the percent of lines covered across every file
+func (r *Report) LineCoverage() float64 {
+	return percent(r.LinesCovered(), r.LinesTotal())
+}
+
+// BranchCoverage returns the percent of branches covered across every file
+func (r *Report) BranchCoverage() float64 {
+	covered, total := 0, 0
+	for _, f := range r.Files {
+		covered += f.BranchesCovered
+		total += f.BranchesTotal
+	}
+
+	return percent(covered, total)
+}
+
+// Filter returns a copy of the report with only the files whose path matches
+// at least one of the include globs, or every file if none are given, and none
+// of the exclude globs. Globs match path segments with * and any number of
+// directories with **.
+func (r *Report) Filter(include, exclude []string) (*Report, error) {
+	includes, err := compileGlobs(include)
+	if err != nil {
+		return nil, err
+	}
+
+	excludes, err := compileGlobs(exclude)
+	if err != nil {
+		return nil, err
+	}
+
+	filtered := &Report{
+		Format: r.Format,
+		Files:  []FileCoverage{},
+	}
+
+	for _, f := range r.Files {
+		if len(includes) > 0 && !matchAny(includes, f.Path) {
+			continue
+		}
+
+		if matchAny(excludes, f.Path) {
+			continue
+		}
+
+		filtered.Files = append(filtered.Files, f)
+	}
+
+	return filtered, nil
+}
+
+type externalCoverageDetail struct {
+	Format         string         `json:"format"`
+	LineCoverage   float64        `json:"line_coverage"`
+	BranchCoverage float64        `json:"branch_coverage"`
+	Files          []FileCoverage `json:"files"`
+}
+
+// ToExternalScan converts the report into an external scan whose coverage is
+// the report's line coverage, with the coverage of each file kept in its raw
+// data
+func (r *Report) ToExternalScan() (*scanner.ExternalScan, error) {
+	lineCoverage := r.LineCoverage()
+
+	b, err := json.Marshal(externalCoverageDetail{
+		Format:         r.Format,
+		LineCoverage:   lineCoverage,
+		BranchCoverage: r.BranchCoverage(),
+		Files:          r.Files,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal coverage detail: %v", err.Error())
+	}
+
+	raw := json.RawMessage(b)
+
+	return &scanner.ExternalScan{
+		Coverage: &scanner.ExternalCoverage{
+			Value: lineCoverage,
+		},
+		Source: scanner.Source{
+			Name: formatNames[r.Format],
+		},
+		Notes: fmt.Sprintf("%v of %v lines covered in %v files", r.LinesCovered(), r.LinesTotal(), len(r.Files)),
+		Raw:   &raw,
+	}, nil
+}
+
+// fileLines accumulates the coverage of the lines and branches of a file
+// while a report is being parsed
+type fileLines struct {
+	lines           map[int]bool
+	branchesCovered int
+	branchesTotal   int
+}
+
+type reportBuilder map[string]*fileLines
+
+func (b reportBuilder) file(path string) *fileLines {
+	f, ok := b[path]
+	if !ok {
+		f = &fileLines{lines: make(map[int]bool)}
+		b[path] = f
+	}
+
+	return f
+}
+
+// line records the coverage of a line. A line is covered if any record of it
+// is covered.
+func (f *fileLines) line(number int, covered bool) {
+	f.lines[number] = f.lines[number] || covered
+}
+
+func (b reportBuilder) report(format string) *Report {
+	r := &Report{
+		Format: format,
+		Files:  make([]FileCoverage, 0, len(b)),
+	}
+
+	for path, f := range b {
+		fc := FileCoverage{
+			Path:            path,
+			LinesTotal:      len(f.lines),
+			BranchesCovered: f.branchesCovered,
+			BranchesTotal:   f.branchesTotal,
+		}
+
+		for _, covered := range f.lines {
+			if covered {
+				fc.LinesCovered++
+			}
+		}
+
+		r.Files = append(r.Files, fc)
+	}
+
+	sort.Slice(r.Files, func(i, j int) bool {
+		return r.Files[i].Path < r.Files[j].Path
+	})
+
+	return r
+}
+
+func compileGlobs(globs []string) ([]*regexp.Regexp, error) {
+	res := make([]*regexp.Regexp, 0, len(globs))
+	for _, glob := range globs {
+		re, err := globToRegexp(glob)
+		if err != nil {
+			return nil, fmt.Errorf("invalid glob %q: %v", glob, err.Error())
+		}
+
+		res = append(res, re)
+	}
+
+	return res, nil
+}
+
+func globToRegexp(glob string) (*regexp.Regexp, error) {
+	var sb strings.Builder
+	sb.WriteString("^")
+
+	for i := 0; i < len(glob); i++ {
+		c := glob[i]
+		switch c {
+		case '*':
+			if i+1 < len(glob) && glob[i+1] == '*' {
+				i++
+				// **/ matches zero or more directories
+				if i+1 < len(glob) && glob[i+1] == '/' {
+					i++
+					sb.WriteString("(?:.*/)?")
+				} else {
+					sb.WriteString(".*")
+				}
+			} else {
+				sb.WriteString("[^/]*")
+			}
+		case '?':
+			sb.WriteString("[^/]")
+		default:
+			sb.WriteString(regexp.QuoteMeta(string(c)))
+		}
+	}
+
+	sb.WriteString("$")
+
+	return regexp.Compile(sb.String())
+}
+
+func matchAny(res []*regexp.Regexp, path string) bool {
+	for _, re := range res {
+		if re.MatchString(path) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func percent(covered, total int) float64 {
+	if total == 0 {
+		return 0
+	}
+
+	return float64(covered) / float64(total) * 100
+}
@@ -0,0 +1,185 @@
+package coverage
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/franela/goblin"
+	. "github.com/onsi/gomega"
+)
+
+func TestCoverage(t *testing.T) {
+	g := goblin.Goblin(t)
+	RegisterFailHandler(func(m string, _ ...int) { g.Fail(m) })
+
+	g.Describe("Parsing", func() {
+		g.It("should parse a go cover profile", func() {
+			r, err := Parse(strings.NewReader(sampleGoProfile))
+			Expect(err).To(BeNil())
+			Expect(r.Format).To(Equal(FormatGo))
+			Expect(r.Files).To(Equal([]FileCoverage{
+				{Path: "github.com/ion-channel/ionic/a.go", LinesCovered: 3, LinesTotal: 5},
+				{Path: "github.com/ion-channel/ionic/b.go", LinesCovered: 0, LinesTotal: 1},
+			}))
+			Expect(r.LineCoverage()).To(Equal(50.0))
+		})
+
+		g.It("should parse an LCOV tracefile", func() {
+			r, err := Parse(strings.NewReader(sampleLCOV))
+			Expect(err).To(BeNil())
+			Expect(r.Format).To(Equal(FormatLCOV))
+			Expect(r.Files).To(Equal([]FileCoverage{
+				{Path: "src/index.js", LinesCovered: 2, LinesTotal: 3, BranchesCovered: 1, BranchesTotal: 2},
+				{Path: "src/util.js", LinesCovered: 1, LinesTotal: 1, BranchesCovered: 3, BranchesTotal: 4},
+			}))
+			Expect(r.BranchCoverage()).To(Equal(66.66666666666666))
+		})
+
+		g.It("should parse a Cobertura report", func() {
+			r, err := Parse(strings.NewReader(sampleCobertura))
+			Expect(err).To(BeNil())
+			Expect(r.Format).To(Equal(FormatCobertura))
+			Expect(r.Files).To(Equal([]FileCoverage{
+				{Path: "app/models.py", LinesCovered: 2, LinesTotal: 3, BranchesCovered: 1, BranchesTotal: 2},
+			}))
+		})
+
+		g.It("should parse a JaCoCo report", func() {
+			r, err := Parse(strings.NewReader(sampleJaCoCo))
+			Expect(err).To(BeNil())
+			Expect(r.Format).To(Equal(FormatJaCoCo))
+			Expect(r.Files).To(Equal([]FileCoverage{
+				{Path: "com/example/App.java", LinesCovered: 1, LinesTotal: 2, BranchesCovered: 1, BranchesTotal: 2},
+			}))
+		})
+
+		g.It("should parse a JaCoCo report with nested groups", func() {
+			r, err := Parse(strings.NewReader(sampleJaCoCoAggregate))
+			Expect(err).To(BeNil())
+			Expect(r.Format).To(Equal(FormatJaCoCo))
+			Expect(r.Files).To(Equal([]FileCoverage{
+				{Path: "com/example/api/Api.java", LinesCovered: 1, LinesTotal: 1},
+				{Path: "com/example/core/Core.java", LinesCovered: 1, LinesTotal: 2, BranchesCovered: 1, BranchesTotal: 2},
+			}))
+		})
+
+		g.It("should reject unknown formats", func() {
+			_, err := Parse(strings.NewReader("not a coverage report"))
+			Expect(err).NotTo(BeNil())
+		})
+	})
+
+	g.Describe("Filtering", func() {
+		r := &Report{
+			Format: FormatGo,
+			Files: []FileCoverage{
+				{Path: "cmd/main.go"},
+				{Path: "pkg/a/a.go"},
+				{Path: "pkg/a/a_mock.go"},
+				{Path: "vendor/x/x.go"},
+			},
+		}
+
+		g.It("should include and exclude files by glob", func() {
+			filtered, err := r.Filter([]string{"pkg/**"}, []string{"**/*_mock.go"})
+			Expect(err).To(BeNil())
+			Expect(filtered.Files).To(HaveLen(1))
+			Expect(filtered.Files[0].Path).To(Equal("pkg/a/a.go"))
+		})
+
+		g.It("should keep every file without globs", func() {
+			filtered, err := r.Filter(nil, []string{"vendor/**"})
+			Expect(err).To(BeNil())
+			Expect(filtered.Files).To(HaveLen(3))
+		})
+	})
+
+	g.Describe("Converting to an External Scan", func() {
+		g.It("should set the coverage, source, and raw detail", func() {
+			r, _ := ParseLCOV(strings.NewReader(sampleLCOV))
+
+			scan, err := r.ToExternalScan()
+			Expect(err).To(BeNil())
+			Expect(scan.Coverage.Value).To(Equal(75.0))
+			Expect(scan.Source.Name).To(Equal("LCOV"))
+
+			var detail externalCoverageDetail
+			Expect(json.Unmarshal(*scan.Raw, &detail)).To(Succeed())
+			Expect(detail.Files).To(HaveLen(2))
+			Expect(detail.LineCoverage).To(Equal(75.0))
+		})
+	})
+}
+
+const sampleGoProfile = `mode: set
+github.com/ion-channel/ionic/a.go:3.14,5.2 1 1
+github.com/ion-channel/ionic/a.go:5.2,7.3 2 0
+github.com/ion-channel/ionic/b.go:10.1,10.20 1 0
+`
+
+const sampleLCOV = `TN:
+SF:src/index.js
+DA:1,1
+DA:2,0
+DA:3,5
+BRDA:3,0,0,1
+BRDA:3,0,1,-
+end_of_record
+SF:src/util.js
+DA:1,2
+BRF:4
+BRH:3
+end_of_record
+`
+
+const sampleCobertura = `<?xml version="1.0" ?>
+<coverage line-rate="0.66" branch-rate="0.5" version="6.5">
+  <sources><source>/src</source></sources>
+  <packages>
+    <package name="app">
+      <classes>
+        <class name="models.py" filename="app/models.py">
+          <lines>
+            <line number="1" hits="1"/>
+            <line number="2" hits="1" branch="true" condition-coverage="50% (1/2)"/>
+            <line number="3" hits="0"/>
+          </lines>
+        </class>
+      </classes>
+    </package>
+  </packages>
+</coverage>`
+
+const sampleJaCoCo = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<!DOCTYPE report PUBLIC "-//JACOCO//DTD Report 1.1//EN" "report.dtd">
+<report name="example">
+  <package name="com/example">
+    <sourcefile name="App.java">
+      <line nr="3" mi="0" ci="3" mb="1" cb="1"/>
+      <line nr="5" mi="2" ci="0" mb="0" cb="0"/>
+    </sourcefile>
+  </package>
+</report>`
+
+const sampleJaCoCoAggregate = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<!DOCTYPE report PUBLIC "-//JACOCO//DTD Report 1.1//EN" "report.dtd">
+<report name="aggregate">
+  <group name="services">
+    <group name="core">
+      <package name="com/example/core">
+        <sourcefile name="Core.java">
+          <line nr="3" mi="0" ci="3" mb="1" cb="1"/>
+          <line nr="5" mi="2" ci="0" mb="0" cb="0"/>
+        </sourcefile>
+      </package>
+    </group>
+  </group>
+  <group name="api">
+    <package name="com/example/api">
+      <sourcefile name="Api.java">
+        <line nr="7" mi="0" ci="1" mb="0" cb="0"/>
+      </sourcefile>
+    </package>
+  </group>
+</report>`
@@ -0,0 +1,69 @@
+package coverage
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// ParseGoProfile reads a profile written by go test -coverprofile. Go
+// profiles record statement blocks rather than lines, so every line spanned by
+// a block is counted, and a line is covered if any block spanning it ran.
+// Go profiles carry no branch coverage.
+func ParseGoProfile(r io.Reader) (*Report, error) {
+	b := reportBuilder{}
+
+	s := bufio.NewScanner(r)
+	lineNumber := 0
+	for s.Scan() {
+		lineNumber++
+		line := strings.TrimSpace(s.Text())
+		if line == "" || strings.HasPrefix(line, "mode:") {
+			continue
+		}
+
+		// name.go:line.column,line.column numberOfStatements count
+		colon := strings.LastIndex(line, ":")
+		if colon < 0 {
+			return nil, fmt.Errorf("malformed go cover profile line %v", lineNumber)
+		}
+
+		fields := strings.Fields(line[colon+1:])
+		if len(fields) != 3 {
+			return nil, fmt.Errorf("malformed go cover profile line %v", lineNumber)
+		}
+
+		span := strings.Split(fields[0], ",")
+		if len(span) != 2 {
+			return nil, fmt.Errorf("malformed go cover profile line %v", lineNumber)
+		}
+
+		start, err := strconv.Atoi(strings.Split(span[0], ".")[0])
+		if err != nil {
+			return nil, fmt.Errorf("malformed go cover profile line %v: %v", lineNumber, err.Error())
+		}
+
+		end, err := strconv.Atoi(strings.Split(span[1], ".")[0])
+		if err != nil {
+			return nil, fmt.Errorf("malformed go cover profile line %v: %v", lineNumber, err.Error())
+		}
+
+		count, err := strconv.Atoi(fields[2])
+		if err != nil {
+			return nil, fmt.Errorf("malformed go cover profile line %v: %v", lineNumber, err.Error())
+		}
+
+		f := b.file(line[:colon])
+		for l := start; l <= end; l++ {
+			f.line(l, count > 0)
+		}
+	}
+
+	if err := s.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read go cover profile: %v", err.Error())
+	}
+
+	return b.report(FormatGo), nil
+}
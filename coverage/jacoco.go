@@ -0,0 +1,59 @@
+package coverage
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"path"
+)
+
+// jacocoGroup is a report or one of its groups, which aggregate reports of
+// multiple modules wrap their packages in, and which may be nested
+type jacocoGroup struct {
+	Groups   []jacocoGroup `xml:"group"`
+	Packages []struct {
+		Name        string `xml:"name,attr"`
+		SourceFiles []struct {
+			Name  string `xml:"name,attr"`
+			Lines []struct {
+				Number          int `xml:"nr,attr"`
+				CoveredInstr    int `xml:"ci,attr"`
+				CoveredBranches int `xml:"cb,attr"`
+				MissedBranches  int `xml:"mb,attr"`
+			} `xml:"line"`
+		} `xml:"sourcefile"`
+	} `xml:"package"`
+}
+
+// ParseJaCoCo reads a JaCoCo XML report. Files are identified by their package
+// path and source file name, and a line is covered if any of its instructions
+// ran.
+func ParseJaCoCo(r io.Reader) (*Report, error) {
+	var jr jacocoGroup
+	err := xml.NewDecoder(r).Decode(&jr)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse JaCoCo report: %v", err.Error())
+	}
+
+	b := reportBuilder{}
+	jr.addTo(&b)
+
+	return b.report(FormatJaCoCo), nil
+}
+
+func (g *jacocoGroup) addTo(b *reportBuilder) {
+	for i := range g.Groups {
+		g.Groups[i].addTo(b)
+	}
+
+	for _, p := range g.Packages {
+		for _, sf := range p.SourceFiles {
+			f := b.file(path.Join(p.Name, sf.Name))
+			for _, l := range sf.Lines {
+				f.line(l.Number, l.CoveredInstr > 0)
+				f.branchesCovered += l.CoveredBranches
+				f.branchesTotal += l.CoveredBranches + l.MissedBranches
+			}
+		}
+	}
+}
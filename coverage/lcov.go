@@ -0,0 +1,96 @@
+package coverage
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// ParseLCOV reads an LCOV tracefile. Line coverage comes from the DA records
+// and branch coverage from the BRDA records, falling back to the BRF and BRH
+// summaries for files without branch detail.
+func ParseLCOV(r io.Reader) (*Report, error) {
+	b := reportBuilder{}
+
+	var current *fileLines
+	var hasBranchDetail bool
+	var summaryFound, summaryHit int
+
+	s := bufio.NewScanner(r)
+	lineNumber := 0
+	for s.Scan() {
+		lineNumber++
+		line := strings.TrimSpace(s.Text())
+
+		if line == "end_of_record" {
+			if current != nil && !hasBranchDetail {
+				current.branchesTotal += summaryFound
+				current.branchesCovered += summaryHit
+			}
+
+			current = nil
+			continue
+		}
+
+		parts := strings.SplitN(line, ":", 2)
+		if len(parts) != 2 {
+			continue
+		}
+
+		if parts[0] == "SF" {
+			current = b.file(parts[1])
+			hasBranchDetail = false
+			summaryFound, summaryHit = 0, 0
+			continue
+		}
+
+		if current == nil {
+			continue
+		}
+
+		values := strings.Split(parts[1], ",")
+
+		switch parts[0] {
+		case "DA":
+			if len(values) < 2 {
+				return nil, fmt.Errorf("malformed LCOV line %v", lineNumber)
+			}
+
+			number, err := strconv.Atoi(values[0])
+			if err != nil {
+				return nil, fmt.Errorf("malformed LCOV line %v: %v", lineNumber, err.Error())
+			}
+
+			hits, err := strconv.ParseFloat(values[1], 64)
+			if err != nil {
+				return nil, fmt.Errorf("malformed LCOV line %v: %v", lineNumber, err.Error())
+			}
+
+			current.line(number, hits > 0)
+		case "BRDA":
+			if len(values) != 4 {
+				return nil, fmt.Errorf("malformed LCOV line %v", lineNumber)
+			}
+
+			hasBranchDetail = true
+			current.branchesTotal++
+
+			// a taken count of - means the branch's block never ran
+			if taken, err := strconv.Atoi(values[3]); err == nil && taken > 0 {
+				current.branchesCovered++
+			}
+		case "BRF":
+			summaryFound, _ = strconv.Atoi(parts[1])
+		case "BRH":
+			summaryHit, _ = strconv.Atoi(parts[1])
+		}
+	}
+
+	if err := s.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read LCOV tracefile: %v", err.Error())
+	}
+
+	return b.report(FormatLCOV), nil
+}
@@ -0,0 +1,498 @@
+// Package cpe parses, formats, and matches Common Platform Enumeration (CPE)
+// names, as defined by NIST IR 7695 and NIST IR 7696. Names are bound to
+// either the CPE 2.2 URI form, such as "cpe:/a:apache:struts:2.5.10", or the
+// CPE 2.3 formatted string form, such as
+// "cpe:2.3:a:apache:struts:2.5.10:*:*:*:*:*:*:*".
+package cpe
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/ion-channel/ionic/aliases"
+)
+
+const (
+	// URIPrefix is the prefix of a CPE 2.2 URI
+	URIPrefix = "cpe:/"
+	// FormattedStringPrefix is the prefix of a CPE 2.3 formatted string
+	FormattedStringPrefix = "cpe:2.3:"
+
+	// PartApplication is the part of a CPE naming an application
+	PartApplication = "a"
+	// PartOperatingSystem is the part of a CPE naming an operating system
+	PartOperatingSystem = "o"
+	// PartHardware is the part of a CPE naming a hardware device
+	PartHardware = "h"
+)
+
+var (
+	// Any is the logical value of an attribute that may have any value
+	Any = Value{}
+	// NA is the logical value of an attribute that is not applicable
+	NA = Value{kind: naValue}
+)
+
+type valueKind int
+
+const (
+	anyValue valueKind = iota
+	naValue
+	stringValue
+)
+
+// Value is the value of an attribute of a CPE name: the logical value Any or
+// NA, or a string. The zero value is Any.
+type Value struct {
+	kind valueKind
+	// s is the string in its well-formed name form, where every character
+	// other than a letter, digit, or underscore is quoted with a backslash,
+	// and unquoted "*" and "?" are wildcards
+	s string
+}
+
+// Name is a CPE name, made of the attributes of a well-formed name. An
+// attribute that is not set has the value Any.
+type Name struct {
+	Part      Value
+	Vendor    Value
+	Product   Value
+	Version   Value
+	Update    Value
+	Edition   Value
+	Language  Value
+	SWEdition Value
+	TargetSW  Value
+	TargetHW  Value
+	Other     Value
+}
+
+// NewValue returns an attribute value of the given string, taken literally,
+// so that characters such as "*" are not wildcards. An empty string is Any.
+func NewValue(s string) Value {
+	if s == "" {
+		return Any
+	}
+
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		if !isPlain(s[i]) {
+			b.WriteByte('\\')
+		}
+
+		b.WriteByte(s[i])
+	}
+
+	return Value{kind: stringValue, s: b.String()}
+}
+
+// FromAlias returns the CPE name of the application named by an alias, with
+// the org as its vendor, the name as its product, and the version as its
+// version. Values are lower cased with spaces replaced by underscores, and
+// empty fields are Any.
+func FromAlias(a aliases.Alias) Name {
+	return Name{
+		Part:    NewValue(PartApplication),
+		Vendor:  normalized(a.Org),
+		Product: normalized(a.Name),
+		Version: normalized(a.Version),
+	}
+}
+
+func normalized(s string) Value {
+	return NewValue(strings.Join(strings.Fields(strings.ToLower(s)), "_"))
+}
+
+// Parse parses a CPE name bound to either a CPE 2.2 URI or a CPE 2.3
+// formatted string
+func Parse(s string) (*Name, error) {
+	if hasPrefix(s, FormattedStringPrefix) {
+		return ParseFormattedString(s)
+	}
+
+	if hasPrefix(s, URIPrefix) {
+		return ParseURI(s)
+	}
+
+	return nil, fmt.Errorf("invalid cpe: %q is not a cpe uri or formatted string", s)
+}
+
+// ParseFormattedString parses a CPE name bound to a CPE 2.3 formatted string,
+// such as "cpe:2.3:a:microsoft:internet_explorer:8.0.6001:beta:*:*:*:*:*:*"
+func ParseFormattedString(s string) (*Name, error) {
+	if !hasPrefix(s, FormattedStringPrefix) {
+		return nil, fmt.Errorf("invalid cpe: %q does not start with %v", s, FormattedStringPrefix)
+	}
+
+	fields := splitUnquoted(s[len(FormattedStringPrefix):], ':')
+	if len(fields) != 11 {
+		return nil, fmt.Errorf("invalid cpe: %q has %v attributes instead of 11", s, len(fields))
+	}
+
+	n := &Name{}
+	for i, attr := range n.attributes() {
+		v, err := unbindFS(fields[i])
+		if err != nil {
+			return nil, fmt.Errorf("invalid cpe %q: %v", s, err.Error())
+		}
+
+		*attr = v
+	}
+
+	err := n.validate()
+	if err != nil {
+		return nil, fmt.Errorf("invalid cpe %q: %v", s, err.Error())
+	}
+
+	return n, nil
+}
+
+// ParseURI parses a CPE name bound to a CPE 2.2 URI, such as
+// "cpe:/a:microsoft:internet_explorer:8.0.6001:beta". An edition packed with
+// the extended attributes of CPE 2.3, such as "~~online~win2003~x64~", is
+// unpacked into them.
+func ParseURI(s string) (*Name, error) {
+	if !hasPrefix(s, URIPrefix) {
+		return nil, fmt.Errorf("invalid cpe: %q does not start with %v", s, URIPrefix)
+	}
+
+	fields := strings.Split(s[len(URIPrefix):], ":")
+	if len(fields) > 7 {
+		return nil, fmt.Errorf("invalid cpe: %q has more than 7 components", s)
+	}
+
+	n := &Name{}
+	attrs := []*Value{&n.Part, &n.Vendor, &n.Product, &n.Version, &n.Update, &n.Edition, &n.Language}
+	for i, field := range fields {
+		if attrs[i] == &n.Edition && strings.HasPrefix(field, "~") {
+			packed := strings.Split(field[1:], "~")
+			if len(packed) != 5 {
+				return nil, fmt.Errorf("invalid cpe: %q has a malformed packed edition", s)
+			}
+
+			for j, attr := range []*Value{&n.Edition, &n.SWEdition, &n.TargetSW, &n.TargetHW, &n.Other} {
+				v, err := unbindURI(packed[j])
+				if err != nil {
+					return nil, fmt.Errorf("invalid cpe %q: %v", s, err.Error())
+				}
+
+				*attr = v
+			}
+
+			continue
+		}
+
+		v, err := unbindURI(field)
+		if err != nil {
+			return nil, fmt.Errorf("invalid cpe %q: %v", s, err.Error())
+		}
+
+		*attrs[i] = v
+	}
+
+	err := n.validate()
+	if err != nil {
+		return nil, fmt.Errorf("invalid cpe %q: %v", s, err.Error())
+	}
+
+	return n, nil
+}
+
+// String returns the name bound to a CPE 2.3 formatted string
+func (n Name) String() string {
+	return n.FormattedString()
+}
+
+// FormattedString returns the name bound to a CPE 2.3 formatted string
+func (n Name) FormattedString() string {
+	values := make([]string, 0, 11)
+	for _, attr := range n.attributes() {
+		values = append(values, attr.String())
+	}
+
+	return FormattedStringPrefix + strings.Join(values, ":")
+}
+
+// URI returns the name bound to a CPE 2.2 URI. The extended attributes of CPE
+// 2.3, such as the target software, are packed into the edition when any of
+// them is not Any.
+func (n Name) URI() string {
+	edition := n.Edition.uri()
+	if n.SWEdition != Any || n.TargetSW != Any || n.TargetHW != Any || n.Other != Any {
+		edition = "~" + strings.Join([]string{edition, n.SWEdition.uri(), n.TargetSW.uri(), n.TargetHW.uri(), n.Other.uri()}, "~")
+	}
+
+	fields := []string{n.Part.uri(), n.Vendor.uri(), n.Product.uri(), n.Version.uri(), n.Update.uri(), edition, n.Language.uri()}
+	for len(fields) > 0 && fields[len(fields)-1] == "" {
+		fields = fields[:len(fields)-1]
+	}
+
+	return URIPrefix + strings.Join(fields, ":")
+}
+
+// WFN returns the name as a well-formed name, such as
+// wfn:[part="a",vendor="microsoft",product="internet_explorer"], leaving out
+// attributes that are Any
+func (n Name) WFN() string {
+	names := []string{"part", "vendor", "product", "version", "update", "edition", "language", "sw_edition", "target_sw", "target_hw", "other"}
+
+	attrs := []string{}
+	for i, attr := range n.attributes() {
+		switch attr.kind {
+		case naValue:
+			attrs = append(attrs, names[i]+"=NA")
+		case stringValue:
+			attrs = append(attrs, fmt.Sprintf(`%v="%v"`, names[i], attr.s))
+		}
+	}
+
+	return "wfn:[" + strings.Join(attrs, ",") + "]"
+}
+
+func (n *Name) attributes() []*Value {
+	return []*Value{&n.Part, &n.Vendor, &n.Product, &n.Version, &n.Update, &n.Edition, &n.Language, &n.SWEdition, &n.TargetSW, &n.TargetHW, &n.Other}
+}
+
+func (n *Name) validate() error {
+	if n.Part.kind == naValue {
+		return fmt.Errorf("part can not be NA")
+	}
+
+	if n.Part.kind == stringValue {
+		switch strings.ToLower(n.Part.s) {
+		case PartApplication, PartOperatingSystem, PartHardware:
+		default:
+			return fmt.Errorf("invalid part: %q", n.Part.s)
+		}
+	}
+
+	return nil
+}
+
+// IsAny returns true if the value is the logical value Any
+func (v Value) IsAny() bool {
+	return v.kind == anyValue
+}
+
+// IsNA returns true if the value is the logical value NA
+func (v Value) IsNA() bool {
+	return v.kind == naValue
+}
+
+// Text returns the string of the value without quoting, or an empty string
+// if the value is Any or NA
+func (v Value) Text() string {
+	var b strings.Builder
+	for i := 0; i < len(v.s); i++ {
+		if v.s[i] == '\\' && i+1 < len(v.s) {
+			i++
+		}
+
+		b.WriteByte(v.s[i])
+	}
+
+	return b.String()
+}
+
+// String returns the value as bound to a CPE 2.3 formatted string, where Any
+// is "*" and NA is "-"
+func (v Value) String() string {
+	switch v.kind {
+	case anyValue:
+		return "*"
+	case naValue:
+		return "-"
+	}
+
+	var b strings.Builder
+	for i := 0; i < len(v.s); i++ {
+		c := v.s[i]
+		if c == '\\' && i+1 < len(v.s) {
+			i++
+			next := v.s[i]
+			if next != '.' && next != '-' && next != '_' {
+				b.WriteByte('\\')
+			}
+
+			b.WriteByte(next)
+			continue
+		}
+
+		b.WriteByte(c)
+	}
+
+	return b.String()
+}
+
+// uri returns the value as bound to a CPE 2.2 URI, where Any is empty, NA is
+// "-", quoted characters are percent encoded, and the wildcards "?" and "*"
+// are "%01" and "%02"
+func (v Value) uri() string {
+	switch v.kind {
+	case anyValue:
+		return ""
+	case naValue:
+		return "-"
+	}
+
+	var b strings.Builder
+	for i := 0; i < len(v.s); i++ {
+		c := v.s[i]
+		switch {
+		case c == '\\' && i+1 < len(v.s):
+			i++
+			next := v.s[i]
+			if isPlain(next) || next == '.' || next == '-' {
+				b.WriteByte(next)
+			} else {
+				fmt.Fprintf(&b, "%%%02x", next)
+			}
+		case c == '?':
+			b.WriteString("%01")
+		case c == '*':
+			b.WriteString("%02")
+		default:
+			b.WriteByte(c)
+		}
+	}
+
+	return b.String()
+}
+
+// unbindFS returns the value of an attribute of a CPE 2.3 formatted string,
+// quoting the characters that need it
+func unbindFS(s string) (Value, error) {
+	switch s {
+	case "*":
+		return Any, nil
+	case "-":
+		return NA, nil
+	case "":
+		return Value{}, fmt.Errorf("empty attribute")
+	}
+
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case isPlain(c):
+			b.WriteByte(c)
+		case c == '\\':
+			if i+1 >= len(s) {
+				return Value{}, fmt.Errorf("unterminated escape in %q", s)
+			}
+
+			b.WriteByte(c)
+			b.WriteByte(s[i+1])
+			i++
+		case c == '*' || c == '?':
+			if !wildcardAllowed(s, i) {
+				return Value{}, fmt.Errorf("embedded wildcard in %q", s)
+			}
+
+			b.WriteByte(c)
+		default:
+			b.WriteByte('\\')
+			b.WriteByte(c)
+		}
+	}
+
+	return Value{kind: stringValue, s: b.String()}, nil
+}
+
+// unbindURI returns the value of a component of a CPE 2.2 URI, decoding its
+// percent encoded characters
+func unbindURI(s string) (Value, error) {
+	switch s {
+	case "":
+		return Any, nil
+	case "-":
+		return NA, nil
+	}
+
+	// pattern is the decoded value with literal characters replaced, so that
+	// only the wildcards are checked for where they appear
+	var b, pattern strings.Builder
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if c == '%' {
+			if i+2 >= len(s) {
+				return Value{}, fmt.Errorf("truncated percent encoding in %q", s)
+			}
+
+			code, err := strconv.ParseUint(s[i+1:i+3], 16, 8)
+			if err != nil {
+				return Value{}, fmt.Errorf("invalid percent encoding in %q", s)
+			}
+
+			i += 2
+			switch code {
+			case 0x01:
+				b.WriteByte('?')
+				pattern.WriteByte('?')
+				continue
+			case 0x02:
+				b.WriteByte('*')
+				pattern.WriteByte('*')
+				continue
+			}
+
+			c = byte(code)
+		}
+
+		if !isPlain(c) {
+			b.WriteByte('\\')
+		}
+
+		b.WriteByte(c)
+		pattern.WriteByte('x')
+	}
+
+	p := pattern.String()
+	for i := 0; i < len(p); i++ {
+		if p[i] != 'x' && !wildcardAllowed(p, i) {
+			return Value{}, fmt.Errorf("embedded wildcard in %q", s)
+		}
+	}
+
+	return Value{kind: stringValue, s: b.String()}, nil
+}
+
+// wildcardAllowed returns true if the wildcard at the index of the unquoted
+// string is at its beginning or end, where "*" may appear once and "?" may
+// appear in a run
+func wildcardAllowed(s string, i int) bool {
+	if s[i] == '*' {
+		return i == 0 || i == len(s)-1
+	}
+
+	return strings.Trim(s[:i], "?") == "" || strings.Trim(s[i+1:], "?") == ""
+}
+
+// splitUnquoted splits the string on every separator that is not quoted with
+// a backslash
+func splitUnquoted(s string, sep byte) []string {
+	fields := []string{}
+	start := 0
+	for i := 0; i < len(s); i++ {
+		switch s[i] {
+		case '\\':
+			i++
+		case sep:
+			fields = append(fields, s[start:i])
+			start = i + 1
+		}
+	}
+
+	return append(fields, s[start:])
+}
+
+func hasPrefix(s, prefix string) bool {
+	return len(s) >= len(prefix) && strings.EqualFold(s[:len(prefix)], prefix)
+}
+
+// isPlain returns true if the character never needs to be quoted
+func isPlain(c byte) bool {
+	return (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z') || (c >= '0' && c <= '9') || c == '_'
+}
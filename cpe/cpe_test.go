@@ -0,0 +1,123 @@
+package cpe
+
+import (
+	"testing"
+
+	"github.com/franela/goblin"
+	. "github.com/onsi/gomega"
+
+	"github.com/ion-channel/ionic/aliases"
+)
+
+func TestCPE(t *testing.T) {
+	g := goblin.Goblin(t)
+	RegisterFailHandler(func(m string, _ ...int) { g.Fail(m) })
+
+	mustParse := func(s string) Name {
+		n, err := Parse(s)
+		Expect(err).To(BeNil(), s)
+		return *n
+	}
+
+	g.Describe("Binding", func() {
+		g.It("should convert between uris and formatted strings", func() {
+			for _, pair := range [][2]string{
+				{"cpe:/a:microsoft:internet_explorer:8.0.6001:beta", "cpe:2.3:a:microsoft:internet_explorer:8.0.6001:beta:*:*:*:*:*:*"},
+				{"cpe:/a:hp:insight_diagnostics:7.4.0.1570:-:~~online~win2003~x64~", "cpe:2.3:a:hp:insight_diagnostics:7.4.0.1570:-:*:*:online:win2003:x64:*"},
+				{"cpe:/a:hp:openview_network_manager:7.51::~~~linux~~", "cpe:2.3:a:hp:openview_network_manager:7.51:*:*:*:*:linux:*:*"},
+				{"cpe:/a:foo%5cbar:big%24money_2010:::~~special~ipod_touch~80gb~", `cpe:2.3:a:foo\\bar:big\$money_2010:*:*:*:*:special:ipod_touch:80gb:*`},
+				{"cpe:/a:microsoft:internet_explorer:8.%02:sp%01", "cpe:2.3:a:microsoft:internet_explorer:8.*:sp?:*:*:*:*:*:*"},
+				{"cpe:/o:redhat:enterprise_linux:7", "cpe:2.3:o:redhat:enterprise_linux:7:*:*:*:*:*:*:*"},
+			} {
+				uri := mustParse(pair[0])
+				Expect(uri.FormattedString()).To(Equal(pair[1]))
+
+				fs := mustParse(pair[1])
+				Expect(fs.URI()).To(Equal(pair[0]))
+				Expect(fs).To(Equal(uri))
+			}
+		})
+
+		g.It("should quote special characters", func() {
+			n := mustParse(`cpe:2.3:a:acme:big\:widget:1.0\*:*:*:*:*:*:*:*`)
+			Expect(n.Product.Text()).To(Equal("big:widget"))
+			Expect(n.Version.Text()).To(Equal("1.0*"))
+			Expect(n.WFN()).To(Equal(`wfn:[part="a",vendor="acme",product="big\:widget",version="1\.0\*"]`))
+			Expect(n.URI()).To(Equal("cpe:/a:acme:big%3awidget:1.0%2a"))
+
+			Expect(Name{Part: NewValue("a"), Vendor: NewValue("acme"), Product: NewValue("c++"), Version: NA}.String()).
+				To(Equal(`cpe:2.3:a:acme:c\+\+:-:*:*:*:*:*:*:*`))
+		})
+
+		g.It("should reject invalid names", func() {
+			for _, s := range []string{
+				"",
+				"cpe:2.3:a:acme:widget",
+				"cpe:2.3:x:acme:widget:1.0:*:*:*:*:*:*:*",
+				"cpe:2.3:a:acme:wid*get:1.0:*:*:*:*:*:*:*",
+				"cpe:2.3:a:acme:widget:1.0:*:*:*:*:*:*:*:*",
+				"cpe:2.3:a:acme::1.0:*:*:*:*:*:*:*",
+				"cpe:/a:acme:widget:1.0:sp1:~~~:en:extra",
+				"cpe:/a:acme:wid%zzget",
+				"cpe:/a:acme:widget:1.%02.0",
+				"cpe:/a:acme:widget:1.0:sp1:~a~b",
+				"pkg:npm/lodash@4.17.21",
+			} {
+				_, err := Parse(s)
+				Expect(err).NotTo(BeNil(), s)
+			}
+		})
+
+		g.It("should build names from aliases", func() {
+			n := FromAlias(aliases.Alias{Org: "Apache Software Foundation", Name: "Struts", Version: "2.5.10"})
+			Expect(n.String()).To(Equal("cpe:2.3:a:apache_software_foundation:struts:2.5.10:*:*:*:*:*:*:*"))
+			Expect(n.URI()).To(Equal("cpe:/a:apache_software_foundation:struts:2.5.10"))
+
+			n = FromAlias(aliases.Alias{Name: "widget"})
+			Expect(n.URI()).To(Equal("cpe:/a::widget"))
+		})
+	})
+
+	g.Describe("Matching", func() {
+		g.It("should compare attribute values", func() {
+			foo := NewValue("foo")
+			Expect(CompareValues(Any, Any)).To(Equal(RelationEqual))
+			Expect(CompareValues(Any, NA)).To(Equal(RelationSuperset))
+			Expect(CompareValues(Any, foo)).To(Equal(RelationSuperset))
+			Expect(CompareValues(NA, Any)).To(Equal(RelationSubset))
+			Expect(CompareValues(NA, NA)).To(Equal(RelationEqual))
+			Expect(CompareValues(NA, foo)).To(Equal(RelationDisjoint))
+			Expect(CompareValues(foo, Any)).To(Equal(RelationSubset))
+			Expect(CompareValues(foo, NA)).To(Equal(RelationDisjoint))
+			Expect(CompareValues(foo, NewValue("FOO"))).To(Equal(RelationEqual))
+			Expect(CompareValues(foo, NewValue("bar"))).To(Equal(RelationDisjoint))
+
+			wild := mustParse("cpe:2.3:a:*:*:8.0*:?p?:*:*:*:*:*:*")
+			Expect(CompareValues(wild.Version, NewValue("8.0.6001"))).To(Equal(RelationSuperset))
+			Expect(CompareValues(wild.Version, NewValue("9.0"))).To(Equal(RelationDisjoint))
+			Expect(CompareValues(wild.Update, NewValue("sp1"))).To(Equal(RelationSuperset))
+			Expect(CompareValues(wild.Update, NewValue("sp10"))).To(Equal(RelationDisjoint))
+			Expect(CompareValues(foo, wild.Version)).To(Equal(RelationUndefined))
+		})
+
+		g.It("should compare names", func() {
+			ie := mustParse("cpe:2.3:a:microsoft:internet_explorer:8.0.6001:beta:*:*:*:*:*:*")
+			ie8 := mustParse("cpe:2.3:a:microsoft:internet_explorer:8.*:*:*:*:*:*:*:*")
+			ie9 := mustParse("cpe:/a:microsoft:internet_explorer:9.0")
+			any := mustParse("cpe:/a:microsoft:internet_explorer")
+			na := mustParse("cpe:/a:microsoft:internet_explorer:8.0.6001:-")
+
+			Expect(ie.Compare(mustParse("cpe:/a:Microsoft:Internet_Explorer:8.0.6001:beta"))).To(Equal(RelationEqual))
+			Expect(ie8.Compare(ie)).To(Equal(RelationSuperset))
+			Expect(ie8.Matches(ie)).To(BeTrue())
+			Expect(ie8.Matches(ie9)).To(BeFalse())
+			Expect(ie.Compare(any)).To(Equal(RelationSubset))
+			Expect(ie.Subset(any)).To(BeTrue())
+			Expect(ie.Compare(ie9)).To(Equal(RelationDisjoint))
+			Expect(ie.Disjoint(na)).To(BeTrue())
+			Expect(ie.Compare(ie8)).To(Equal(RelationUndefined))
+			Expect(ie9.Compare(mustParse("cpe:/a:microsoft:internet_explorer::sp1"))).To(Equal(RelationUndefined))
+			Expect(RelationDisjoint.String()).To(Equal("disjoint"))
+		})
+	})
+}
@@ -0,0 +1,241 @@
+package cpe
+
+import (
+	"strings"
+)
+
+// Relation is the relation of a source CPE name, or attribute value, to a
+// target one, as defined by the CPE name matching specification
+type Relation int
+
+const (
+	// RelationUndefined is the relation to a target whose wildcards the
+	// source can not be compared with
+	RelationUndefined Relation = iota
+	// RelationDisjoint is the relation to a target that has nothing in
+	// common with the source
+	RelationDisjoint
+	// RelationSubset is the relation to a target that includes everything
+	// the source names, and more
+	RelationSubset
+	// RelationSuperset is the relation to a target that the source
+	// includes, such as a version matched by a wildcard
+	RelationSuperset
+	// RelationEqual is the relation to a target that names the same as the
+	// source
+	RelationEqual
+)
+
+// String returns the name of the relation
+func (r Relation) String() string {
+	switch r {
+	case RelationDisjoint:
+		return "disjoint"
+	case RelationSubset:
+		return "subset"
+	case RelationSuperset:
+		return "superset"
+	case RelationEqual:
+		return "equal"
+	}
+
+	return "undefined"
+}
+
+// CompareValues returns the relation of a source attribute value to a target
+// one. Strings are compared without regard to case, and the wildcards of the
+// source are matched against the target; a target with wildcards can only be
+// compared with Any and NA.
+func CompareValues(source, target Value) Relation {
+	src, tgt := strings.ToLower(source.s), strings.ToLower(target.s)
+
+	switch {
+	case target.kind == stringValue && hasWildcards(tgt):
+		return RelationUndefined
+	case source.kind == target.kind && src == tgt:
+		return RelationEqual
+	case source.kind == anyValue:
+		return RelationSuperset
+	case target.kind == anyValue:
+		return RelationSubset
+	case source.kind == naValue || target.kind == naValue:
+		return RelationDisjoint
+	}
+
+	return compareStrings(src, tgt)
+}
+
+// Compare returns the relation of the name to a target name: equal if every
+// attribute is equal, a superset or subset if every attribute is that or
+// equal, disjoint if any attribute is disjoint, and undefined otherwise
+func (n Name) Compare(target Name) Relation {
+	relations := n.relations(target)
+
+	all := func(allowed ...Relation) bool {
+		for _, r := range relations {
+			found := false
+			for _, a := range allowed {
+				if r == a {
+					found = true
+				}
+			}
+
+			if !found {
+				return false
+			}
+		}
+
+		return true
+	}
+
+	switch {
+	case all(RelationEqual):
+		return RelationEqual
+	case n.Disjoint(target):
+		return RelationDisjoint
+	case all(RelationSuperset, RelationEqual):
+		return RelationSuperset
+	case all(RelationSubset, RelationEqual):
+		return RelationSubset
+	}
+
+	return RelationUndefined
+}
+
+// Disjoint returns true if any attribute of the name is disjoint from the
+// target's
+func (n Name) Disjoint(target Name) bool {
+	for _, r := range n.relations(target) {
+		if r == RelationDisjoint {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Equal returns true if every attribute of the name is equal to the target's
+func (n Name) Equal(target Name) bool {
+	return n.Compare(target) == RelationEqual
+}
+
+// Superset returns true if the name includes the target, with every attribute
+// a superset of or equal to the target's
+func (n Name) Superset(target Name) bool {
+	r := n.Compare(target)
+	return r == RelationSuperset || r == RelationEqual
+}
+
+// Subset returns true if the name is included by the target, with every
+// attribute a subset of or equal to the target's
+func (n Name) Subset(target Name) bool {
+	r := n.Compare(target)
+	return r == RelationSubset || r == RelationEqual
+}
+
+// Matches returns true if the name, used as a pattern such as
+// "cpe:2.3:a:apache:struts:2.5.*:*:*:*:*:*:*:*", includes the target name
+func (n Name) Matches(target Name) bool {
+	return n.Superset(target)
+}
+
+func (n Name) relations(target Name) []Relation {
+	sources, targets := n.attributes(), target.attributes()
+
+	relations := make([]Relation, len(sources))
+	for i := range sources {
+		relations[i] = CompareValues(*sources[i], *targets[i])
+	}
+
+	return relations
+}
+
+// compareStrings matches the wildcards at the beginning and end of the source
+// string against the target string, where "*" matches any number of
+// characters and each "?" matches one, returning a superset if it matches and
+// disjoint otherwise
+func compareStrings(source, target string) Relation {
+	start, end := 0, len(source)
+
+	// begins and ends are the number of characters the wildcards at the
+	// beginning and end of the source match, where -1 is any number
+	begins, ends := 0, 0
+	if strings.HasPrefix(source, "*") {
+		start, begins = 1, -1
+	} else {
+		for start < len(source) && source[start] == '?' {
+			start++
+			begins++
+		}
+	}
+
+	if end > start && source[end-1] == '*' && !isQuoted(source, end-1) {
+		end, ends = end-1, -1
+	} else {
+		for end > start && source[end-1] == '?' && !isQuoted(source, end-1) {
+			end--
+			ends++
+		}
+	}
+
+	source = source[start:end]
+	index := -1
+	for leftover := len(target); leftover > 0; {
+		next := strings.Index(target[index+1:], source)
+		if next < 0 {
+			break
+		}
+
+		index += next + 1
+		if index > 0 && begins != -1 && begins < index-countQuotes(target[:index]) {
+			break
+		}
+
+		leftover = len(target) - index - countQuotes(target[index+1:]) - len(source)
+		if leftover > 0 && ends != -1 && leftover > ends {
+			continue
+		}
+
+		return RelationSuperset
+	}
+
+	return RelationDisjoint
+}
+
+// hasWildcards returns true if the string has an unquoted "*" or "?"
+func hasWildcards(s string) bool {
+	for i := 0; i < len(s); i++ {
+		switch s[i] {
+		case '\\':
+			i++
+		case '*', '?':
+			return true
+		}
+	}
+
+	return false
+}
+
+// isQuoted returns true if the character at the index is quoted by an odd
+// number of backslashes
+func isQuoted(s string, i int) bool {
+	n := 0
+	for j := i - 1; j >= 0 && s[j] == '\\'; j-- {
+		n++
+	}
+
+	return n%2 == 1
+}
+
+// countQuotes returns the number of quoting backslashes in the string
+func countQuotes(s string) int {
+	n := 0
+	for i := 0; i < len(s); i++ {
+		if s[i] == '\\' {
+			n++
+			i++
+		}
+	}
+
+	return n
+}
@@ -6,6 +6,7 @@ import (
 	"github.com/google/uuid"
 	"github.com/ion-channel/ionic/aliases"
 	"github.com/ion-channel/ionic/projects"
+	"github.com/ion-channel/ionic/purl"
 	"github.com/ion-channel/ionic/util"
 	"strings"
 )
@@ -75,7 +76,7 @@ func projectFromComponent(component cyclonedx.Component) projects.Project {
 		Active:  true,
 		Monitor: true,
 		Aliases: projectAliases,
-		PURL:    component.PackageURL,
+		PURL:    purl.Normalize(component.PackageURL),
 		CPE:     component.CPE,
 	}
 
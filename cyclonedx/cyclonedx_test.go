@@ -109,5 +109,37 @@ func TestSPDX(t *testing.T) {
 			Expect(*projects[1].Branch).To(Equal(""))
 			Expect(*projects[1].Source).To(Equal(""))
 		})
+
+		g.It("should return the licenses of the components", func() {
+			topLevelLicenses := cyclonedx.Licenses{{Expression: "MIT OR Apache-2.0"}}
+			topLevelComponent := cyclonedx.Component{
+				Name:     "my_app",
+				Version:  "1.2.3",
+				Licenses: &topLevelLicenses,
+			}
+
+			nestedLicenses := cyclonedx.Licenses{
+				{License: &cyclonedx.License{ID: "BSD-3-Clause"}},
+				{License: &cyclonedx.License{Name: "Some Custom (License)"}},
+			}
+			nested := []cyclonedx.Component{{Name: "nested", Licenses: &nestedLicenses}}
+			components := []cyclonedx.Component{{
+				Name:       "react",
+				Version:    "5.5.5",
+				Publisher:  "facebook",
+				Components: &nested,
+			}}
+
+			doc := cyclonedx.NewBOM()
+			doc.Metadata = &cyclonedx.Metadata{Component: &topLevelComponent}
+			doc.Components = &components
+
+			licenseComponents := LicenseComponents(doc)
+			Expect(len(licenseComponents)).To(Equal(3))
+			Expect(licenseComponents[0].License).To(Equal("MIT OR Apache-2.0"))
+			Expect(licenseComponents[1].String()).To(Equal("facebook/react@5.5.5"))
+			Expect(licenseComponents[1].License).To(Equal(""))
+			Expect(licenseComponents[2].License).To(Equal("(BSD-3-Clause) AND (LicenseRef-Some-Custom-License)"))
+		})
 	})
 }
@@ -0,0 +1,84 @@
+package cyclonedx
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/CycloneDX/cyclonedx-go"
+	"github.com/ion-channel/ionic/licenses"
+)
+
+var invalidLicenseRefChars = regexp.MustCompile(`[^A-Za-z0-9.\-]+`)
+
+// LicenseComponents returns the components of a CycloneDX SoftwareList, including
+// the top-level component and any nested components, with their licenses as
+// SPDX license expressions for use with a license policy.
+func LicenseComponents(sbom *cyclonedx.BOM) []licenses.Component {
+	var components []licenses.Component
+
+	var add func(c cyclonedx.Component)
+	add = func(c cyclonedx.Component) {
+		components = append(components, licenses.Component{
+			Org:     c.Publisher,
+			Name:    c.Name,
+			Version: c.Version,
+			License: licenseExpression(c.Licenses),
+		})
+
+		if c.Components != nil {
+			for _, nested := range *c.Components {
+				add(nested)
+			}
+		}
+	}
+
+	if sbom == nil {
+		return components
+	}
+
+	if sbom.Metadata != nil && sbom.Metadata.Component != nil {
+		add(*sbom.Metadata.Component)
+	}
+
+	if sbom.Components != nil {
+		for _, c := range *sbom.Components {
+			add(c)
+		}
+	}
+
+	return components
+}
+
+// licenseExpression returns the licenses of a component as a single SPDX
+// license expression. A component with more than one license must comply with
+// all of them, so they are combined with AND.
+func licenseExpression(choices *cyclonedx.Licenses) string {
+	if choices == nil {
+		return ""
+	}
+
+	var parts []string
+	for _, choice := range *choices {
+		var part string
+		switch {
+		case choice.Expression != "":
+			part = choice.Expression
+		case choice.License != nil && choice.License.ID != "":
+			part = choice.License.ID
+		case choice.License != nil && choice.License.Name != "":
+			// names are not SPDX identifiers, so they are kept as custom
+			// license references
+			part = "LicenseRef-" + strings.Trim(invalidLicenseRefChars.ReplaceAllString(choice.License.Name, "-"), "-")
+		default:
+			continue
+		}
+
+		if len(*choices) > 1 {
+			part = "(" + part + ")"
+		}
+
+		parts = append(parts, part)
+	}
+
+	return strings.Join(parts, " AND ")
+}
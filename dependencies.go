@@ -10,12 +10,13 @@ import (
 	"net/url"
 	"os"
 
-	"github.com/hashicorp/go-version"
 	"github.com/ion-channel/ionic/pagination"
 	"github.com/ion-channel/ionic/responses"
 	"github.com/ion-channel/ionic/scans"
+	"github.com/ion-channel/ionic/versioning"
 
 	"github.com/ion-channel/ionic/dependencies"
+	"github.com/ion-channel/ionic/dependencies/lockfiles"
 )
 
 const (
@@ -25,15 +26,55 @@ const (
 
 var (
 	ecosystems = map[string]bool{"maven": true}
+
+	// apiEcosystems are the ecosystems the API resolves files of
+	apiEcosystems = map[string]bool{
+		lockfiles.EcosystemGoMod:        true,
+		lockfiles.EcosystemPackageJSON:  true,
+		"package":                       true,
+		lockfiles.EcosystemPackageLock:  true,
+		lockfiles.EcosystemPipfile:      true,
+		lockfiles.EcosystemMaven:        true,
+		lockfiles.EcosystemRequirements: true,
+		lockfiles.EcosystemYarnLock:     true,
+		lockfiles.EcosystemNuGet:        true,
+		RubyEcosystem:                   true,
+	}
 )
 
 // ResolveDependenciesInFile takes a dependency file location and token to send
 // the specified file to the API. All dependencies that are able to be resolved will
 // be with their info returned, and a list of any errors encountered during the
-// process.
+// process. The ecosystem is detected from the file name when it is not given.
+// When the request is local, files that can be parsed locally are. The API is
+// only sent a detected ecosystem when it is one the API resolves files of.
 func (ic *IonClient) ResolveDependenciesInFile(o dependencies.DependencyResolutionRequest, token string) (*dependencies.DependencyResolutionResponse, error) {
+	ecosystem := o.Ecosystem
+	if ecosystem == "" {
+		ecosystem, _ = lockfiles.Detect(o.File)
+	}
+
+	if o.Local {
+		if _, ok := lockfiles.ParserFor(ecosystem); ok {
+			resp, err := lockfiles.ParseFile(o.File, ecosystem)
+			if err != nil {
+				return nil, fmt.Errorf("failed to resolve dependencies: %v", err.Error())
+			}
+
+			if o.Flatten {
+				resp.Dependencies = lockfiles.Flatten(resp.Dependencies)
+			}
+
+			return resp, nil
+		}
+	}
+
+	if o.Ecosystem == "" && !apiEcosystems[ecosystem] {
+		ecosystem = ""
+	}
+
 	params := url.Values{}
-	params.Set("type", o.Ecosystem)
+	params.Set("type", ecosystem)
 	if o.Flatten {
 		params.Set("flatten", "true")
 	}
@@ -50,6 +91,7 @@ func (ic *IonClient) ResolveDependenciesInFile(o dependencies.DependencyResoluti
 	if err != nil {
 		return nil, fmt.Errorf("failed to open file: %v", err.Error())
 	}
+	defer fh.Close()
 
 	_, err = io.Copy(fw, fh)
 	if err != nil {
@@ -58,20 +100,9 @@ func (ic *IonClient) ResolveDependenciesInFile(o dependencies.DependencyResoluti
 
 	w.Close()
 
-	var endpoint string
-	switch o.Ecosystem {
-	// case strings.Contains(path.Base(o.E), "Gemfile.lock") ||
-	// 	strings.Contains(path.Base(o.File), "go.mod") ||
-	// 	strings.Contains(path.Base(o.File), "package.json") ||
-	// 	strings.Contains(path.Base(o.File), "package-lock.json") ||
-	// 	strings.Contains(path.Base(o.File), "Pipfile") ||
-	// 	strings.Contains(path.Base(o.File), "requirements.txt") ||
-	// 	strings.Contains(path.Base(o.File), "yarn.lock") ||
-	// 	strings.Contains(path.Base(o.File), "nuget") ||
-	case "gomod", "package-json", "package", "package-lock", "pipfile", "maven", "requirements", "yarnlock", "nuget":
+	endpoint := dependencies.ResolveDependenciesInFileEndpoint
+	if apiEcosystems[ecosystem] && ecosystem != RubyEcosystem {
 		endpoint = dependencies.ResolveFromFileEndpoint
-	default:
-		endpoint = dependencies.ResolveDependenciesInFileEndpoint
 	}
 
 	h := http.Header{}
@@ -193,38 +224,29 @@ func (ic *IonClient) GetDependencyVersions(packageName, ecosystem, version, toke
 }
 
 // GetDifferenceBetweenVersions calculates the difference between two version strings, returning an OutdatedMeta
-// object, or an error.
+// object, or an error. The versions are compared with the generic versioning scheme; use
+// GetDependencyOutdatedMeta to compare them with the scheme of a dependency's ecosystem.
 func GetDifferenceBetweenVersions(newerVersion, olderVersion string) (outdatedMeta scans.OutdatedMeta, err error) {
-	ver, err := version.NewVersion(olderVersion)
-	if err != nil {
-		return outdatedMeta, err
-	}
+	return getOutdatedMeta(versioning.Generic, newerVersion, olderVersion)
+}
 
-	latestVersion, err := version.NewVersion(newerVersion)
-	if err != nil {
-		return outdatedMeta, err
-	}
-
-	// for major.minor.patch
-	var versionsBehind [3]int
-	// check if our version is out of date, and calculate its versions behind if so
-	if ver.LessThan(latestVersion) {
-		versions := ver.Segments()
-		latestVer := latestVersion.Segments()
-		for i := 0; i < len(versionsBehind); i++ {
-			if versions[i] <= latestVer[i] {
-				versionsBehind[i] = latestVer[i] - versions[i]
-			} else {
-				versionsBehind[i] = 0
-			}
-		}
+// GetDependencyOutdatedMeta calculates how far the version of a dependency is
+// behind its latest version, comparing them with the versioning scheme of the
+// dependency's type, such as Maven or PEP 440, and returns an OutdatedMeta
+// object, or an error.
+func GetDependencyOutdatedMeta(dep scans.Dependency) (scans.OutdatedMeta, error) {
+	return getOutdatedMeta(versioning.For(dep.Type), dep.LatestVersion, dep.Version)
+}
 
-		outdatedMeta = scans.OutdatedMeta{
-			MajorBehind: versionsBehind[0],
-			MinorBehind: versionsBehind[1],
-			PatchBehind: versionsBehind[2],
-		}
+func getOutdatedMeta(scheme versioning.Scheme, newerVersion, olderVersion string) (scans.OutdatedMeta, error) {
+	behind, err := versioning.Difference(scheme, newerVersion, olderVersion)
+	if err != nil {
+		return scans.OutdatedMeta{}, err
 	}
 
-	return outdatedMeta, err
+	return scans.OutdatedMeta{
+		MajorBehind: behind.Major,
+		MinorBehind: behind.Minor,
+		PatchBehind: behind.Patch,
+	}, nil
 }
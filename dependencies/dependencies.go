@@ -78,4 +78,7 @@ type DependencyResolutionRequest struct {
 	Ecosystem string
 	File      string
 	Flatten   bool
+	// Local resolves the dependencies of the file without the API when it is
+	// a lockfile or manifest that can be parsed locally
+	Local bool
 }
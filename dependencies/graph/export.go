@@ -0,0 +1,145 @@
+package graph
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// jsonGraph is the JSON Graph Format representation of a graph
+type jsonGraph struct {
+	Graph jsonGraphBody `json:"graph"`
+}
+
+type jsonGraphBody struct {
+	Directed bool                     `json:"directed"`
+	Nodes    map[string]jsonGraphNode `json:"nodes"`
+	Edges    []jsonGraphEdge          `json:"edges"`
+}
+
+type jsonGraphNode struct {
+	Label    string  `json:"label"`
+	Metadata Package `json:"metadata"`
+}
+
+type jsonGraphEdge struct {
+	Source string `json:"source"`
+	Target string `json:"target"`
+}
+
+// WriteDOT writes the graph to the given writer in the Graphviz DOT format.
+// Direct dependencies are drawn with a bold outline and outdated dependencies
+// are filled.
+func (g *Graph) WriteDOT(w io.Writer) error {
+	var sb strings.Builder
+	sb.WriteString("digraph dependencies {\n")
+	sb.WriteString("\tnode [shape=box];\n")
+
+	for _, p := range g.Flatten() {
+		var styles []string
+		if p.Direct {
+			styles = append(styles, "bold")
+		}
+
+		if p.Outdated {
+			styles = append(styles, "filled")
+		}
+
+		attrs := []string{"label=" + strconv.Quote(packageLabel(p))}
+		if len(styles) > 0 {
+			attrs = append(attrs, "style="+strconv.Quote(strings.Join(styles, ",")))
+		}
+
+		if p.Outdated {
+			attrs = append(attrs, `fillcolor="lightyellow"`)
+		}
+
+		fmt.Fprintf(&sb, "\t%v [%v];\n", strconv.Quote(packageID(p)), strings.Join(attrs, ", "))
+	}
+
+	for _, e := range g.edges() {
+		fmt.Fprintf(&sb, "\t%v -> %v;\n", strconv.Quote(e[0]), strconv.Quote(e[1]))
+	}
+
+	sb.WriteString("}\n")
+
+	_, err := io.WriteString(w, sb.String())
+	if err != nil {
+		return fmt.Errorf("failed to write graph: %v", err.Error())
+	}
+
+	return nil
+}
+
+// WriteMermaid writes the graph to the given writer as a Mermaid flowchart.
+// Outdated dependencies are given the outdated class.
+func (g *Graph) WriteMermaid(w io.Writer) error {
+	var sb strings.Builder
+	sb.WriteString("flowchart TD\n")
+
+	ids := make(map[string]string)
+	var outdated []string
+	for i, p := range g.Flatten() {
+		id := fmt.Sprintf("n%v", i)
+		ids[packageID(p)] = id
+
+		fmt.Fprintf(&sb, "    %v[\"%v\"]\n", id, mermaidEscape(packageLabel(p)))
+		if p.Outdated {
+			outdated = append(outdated, id)
+		}
+	}
+
+	for _, e := range g.edges() {
+		fmt.Fprintf(&sb, "    %v --> %v\n", ids[e[0]], ids[e[1]])
+	}
+
+	if len(outdated) > 0 {
+		sb.WriteString("    classDef outdated fill:#fff8dc\n")
+		fmt.Fprintf(&sb, "    class %v outdated\n", strings.Join(outdated, ","))
+	}
+
+	_, err := io.WriteString(w, sb.String())
+	if err != nil {
+		return fmt.Errorf("failed to write graph: %v", err.Error())
+	}
+
+	return nil
+}
+
+// WriteJSON writes the graph to the given writer in the JSON Graph Format,
+// with each node identified by its ID and described by its flattened package
+func (g *Graph) WriteJSON(w io.Writer) error {
+	jg := jsonGraph{Graph: jsonGraphBody{
+		Directed: true,
+		Nodes:    make(map[string]jsonGraphNode),
+		Edges:    []jsonGraphEdge{},
+	}}
+
+	for _, p := range g.Flatten() {
+		jg.Graph.Nodes[packageID(p)] = jsonGraphNode{Label: packageLabel(p), Metadata: p}
+	}
+
+	for _, e := range g.edges() {
+		jg.Graph.Edges = append(jg.Graph.Edges, jsonGraphEdge{Source: e[0], Target: e[1]})
+	}
+
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	err := enc.Encode(jg)
+	if err != nil {
+		return fmt.Errorf("failed to write graph: %v", err.Error())
+	}
+
+	return nil
+}
+
+func packageLabel(p Package) string {
+	n := Node{Org: p.Org, Name: p.Name, Version: p.Version}
+	return n.Label()
+}
+
+func mermaidEscape(s string) string {
+	return strings.ReplaceAll(s, `"`, "#quot;")
+}
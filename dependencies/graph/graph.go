@@ -0,0 +1,404 @@
+// Package graph provides utilities for the dependency trees returned by Ion
+// Channel: flattening and deduplicating them, finding why a package is
+// included, detecting duplicate versions, computing dependency counts, and
+// exporting the trees for visualization.
+package graph
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/ion-channel/ionic/dependencies"
+	"github.com/ion-channel/ionic/scans"
+)
+
+// Node is a dependency within a dependency tree
+type Node struct {
+	Org           string
+	Name          string
+	Version       string
+	Type          string
+	Scope         string
+	Requirement   string
+	LatestVersion string
+	// Outdated is whether a newer version of the dependency is available.
+	Outdated bool
+	Children []*Node
+}
+
+// Package is a unique dependency found in a graph
+type Package struct {
+	Org           string `json:"org,omitempty"`
+	Name          string `json:"name"`
+	Version       string `json:"version,omitempty"`
+	Type          string `json:"type,omitempty"`
+	Scope         string `json:"scope,omitempty"`
+	Requirement   string `json:"requirement,omitempty"`
+	LatestVersion string `json:"latest_version,omitempty"`
+	Outdated      bool   `json:"outdated"`
+	// Depth is the shortest distance of the package from the root of the
+	// graph, where direct dependencies have a depth of 1.
+	Depth int `json:"depth"`
+	// Direct is whether the package is a direct dependency. A package can
+	// also be required transitively by other packages.
+	Direct bool `json:"direct"`
+}
+
+// Duplicate is a package found in more than one version
+type Duplicate struct {
+	Org      string   `json:"org,omitempty"`
+	Name     string   `json:"name"`
+	Versions []string `json:"versions"`
+}
+
+// Graph is a dependency graph, rooted at the direct dependencies of a project
+type Graph struct {
+	Roots []*Node
+}
+
+// FromDependencies returns the graph of the dependency trees returned by the
+// dependency resolution endpoints
+func FromDependencies(deps []dependencies.Dependency) *Graph {
+	var convert func([]dependencies.Dependency) []*Node
+	convert = func(deps []dependencies.Dependency) []*Node {
+		nodes := make([]*Node, 0, len(deps))
+		for _, d := range deps {
+			o := d.OutdatedVersion
+			nodes = append(nodes, &Node{
+				Org:           d.Org,
+				Name:          d.Name,
+				Version:       d.Version,
+				Type:          d.Type,
+				Scope:         d.Scope,
+				Requirement:   d.Requirement,
+				LatestVersion: d.LatestVersion,
+				Outdated:      o.MajorBehind > 0 || o.MinorBehind > 0 || o.PatchBehind > 0 || newerAvailable(d.Version, d.LatestVersion),
+				Children:      convert(d.Dependencies),
+			})
+		}
+
+		return nodes
+	}
+
+	return &Graph{Roots: convert(deps)}
+}
+
+// FromScanDependencies returns the graph of the dependency trees found by a
+// dependency scan
+func FromScanDependencies(deps []scans.Dependency) *Graph {
+	var convert func([]scans.Dependency) []*Node
+	convert = func(deps []scans.Dependency) []*Node {
+		nodes := make([]*Node, 0, len(deps))
+		for _, d := range deps {
+			outdated := newerAvailable(d.Version, d.LatestVersion)
+			if o := d.OutdatedMeta; o != nil {
+				outdated = outdated || o.MajorBehind > 0 || o.MinorBehind > 0 || o.PatchBehind > 0
+			}
+
+			nodes = append(nodes, &Node{
+				Org:           d.Org,
+				Name:          d.Name,
+				Version:       d.Version,
+				Type:          d.Type,
+				Scope:         d.Scope,
+				Requirement:   d.Requirement,
+				LatestVersion: d.LatestVersion,
+				Outdated:      outdated,
+				Children:      convert(d.Dependencies),
+			})
+		}
+
+		return nodes
+	}
+
+	return &Graph{Roots: convert(deps)}
+}
+
+// ID returns the identifier of the node's package, made of its type, org,
+// name, and version
+func (n *Node) ID() string {
+	id := n.Name
+	if n.Org != "" {
+		id = n.Org + "/" + id
+	}
+
+	if n.Type != "" {
+		id = n.Type + ":" + id
+	}
+
+	if n.Version != "" {
+		id += "@" + n.Version
+	}
+
+	return id
+}
+
+// Label returns the human readable name of the node's package
+func (n *Node) Label() string {
+	label := n.Name
+	if n.Org != "" {
+		label = n.Org + "/" + label
+	}
+
+	if n.Version != "" {
+		label += " " + n.Version
+	}
+
+	return label
+}
+
+// Walk calls fn for every node of the graph, depth first, with the depth of
+// the node. Nodes found more than once in the graph are visited each time.
+func (g *Graph) Walk(fn func(n *Node, depth int)) {
+	var walk func(nodes []*Node, depth int, path map[*Node]bool)
+	walk = func(nodes []*Node, depth int, path map[*Node]bool) {
+		for _, n := range nodes {
+			if path[n] {
+				continue
+			}
+
+			fn(n, depth)
+
+			path[n] = true
+			walk(n.Children, depth+1, path)
+			delete(path, n)
+		}
+	}
+
+	if g != nil {
+		walk(g.Roots, 1, make(map[*Node]bool))
+	}
+}
+
+// Flatten returns every unique package of the graph, ordered by depth and
+// then by ID. A package found at more than one depth is reported at its
+// shallowest.
+func (g *Graph) Flatten() []Package {
+	packages := make(map[string]*Package)
+	var order []string
+
+	g.Walk(func(n *Node, depth int) {
+		id := n.ID()
+		p, ok := packages[id]
+		if !ok {
+			p = &Package{
+				Org:           n.Org,
+				Name:          n.Name,
+				Version:       n.Version,
+				Type:          n.Type,
+				Scope:         n.Scope,
+				Requirement:   n.Requirement,
+				LatestVersion: n.LatestVersion,
+				Outdated:      n.Outdated,
+				Depth:         depth,
+			}
+			packages[id] = p
+			order = append(order, id)
+		}
+
+		if depth < p.Depth {
+			p.Depth = depth
+		}
+
+		if depth == 1 {
+			p.Direct = true
+			if n.Scope != "" {
+				p.Scope = n.Scope
+			}
+		}
+	})
+
+	flat := make([]Package, 0, len(order))
+	for _, id := range order {
+		flat = append(flat, *packages[id])
+	}
+
+	sort.SliceStable(flat, func(i, j int) bool {
+		if flat[i].Depth != flat[j].Depth {
+			return flat[i].Depth < flat[j].Depth
+		}
+
+		return packageID(flat[i]) < packageID(flat[j])
+	})
+
+	return flat
+}
+
+// Depth returns the length of the longest path from the root of the graph,
+// where a graph of only direct dependencies has a depth of 1
+func (g *Graph) Depth() int {
+	max := 0
+	g.Walk(func(n *Node, depth int) {
+		if depth > max {
+			max = depth
+		}
+	})
+
+	return max
+}
+
+// PathsTo returns every path from a direct dependency to the packages with
+// the given name, answering why a package is included. The org and version
+// are only compared when they are not empty, and names are compared without
+// regard to case. Each path ends with the matching node.
+func (g *Graph) PathsTo(org, name, version string) [][]*Node {
+	matches := func(n *Node) bool {
+		return strings.EqualFold(n.Name, name) &&
+			(org == "" || strings.EqualFold(n.Org, org)) &&
+			(version == "" || n.Version == version)
+	}
+
+	var paths [][]*Node
+	var find func(nodes []*Node, path []*Node)
+	find = func(nodes []*Node, path []*Node) {
+		for _, n := range nodes {
+			if containsNode(path, n) {
+				continue
+			}
+
+			current := append(append([]*Node{}, path...), n)
+			if matches(n) {
+				paths = append(paths, current)
+			}
+
+			find(n.Children, current)
+		}
+	}
+
+	if g != nil {
+		find(g.Roots, nil)
+	}
+
+	return paths
+}
+
+// Duplicates returns the packages found in more than one version, ordered by
+// org and name, with their versions sorted
+func (g *Graph) Duplicates() []Duplicate {
+	versions := make(map[string]map[string]bool)
+	names := make(map[string]Duplicate)
+
+	g.Walk(func(n *Node, depth int) {
+		if n.Version == "" {
+			return
+		}
+
+		key := strings.ToLower(n.Type + ":" + n.Org + "/" + n.Name)
+		if _, ok := versions[key]; !ok {
+			versions[key] = make(map[string]bool)
+			names[key] = Duplicate{Org: n.Org, Name: n.Name}
+		}
+
+		versions[key][n.Version] = true
+	})
+
+	var dups []Duplicate
+	for key, vs := range versions {
+		if len(vs) < 2 {
+			continue
+		}
+
+		d := names[key]
+		for v := range vs {
+			d.Versions = append(d.Versions, v)
+		}
+
+		sort.Strings(d.Versions)
+		dups = append(dups, d)
+	}
+
+	sort.Slice(dups, func(i, j int) bool {
+		if dups[i].Org != dups[j].Org {
+			return dups[i].Org < dups[j].Org
+		}
+
+		return dups[i].Name < dups[j].Name
+	})
+
+	return dups
+}
+
+// Meta returns the dependency counts of the graph, computed the same way as a
+// dependency scan. The vulnerable count is not known locally and is left as
+// zero.
+func (g *Graph) Meta() scans.DependencyMeta {
+	var meta scans.DependencyMeta
+	for _, p := range g.Flatten() {
+		meta.TotalUniqueCount++
+
+		if p.Direct {
+			meta.FirstDegreeCount++
+		}
+
+		if p.Version == "" {
+			meta.NoVersionCount++
+		}
+
+		if p.Outdated {
+			meta.UpdateAvailableCount++
+		}
+	}
+
+	return meta
+}
+
+// edges returns every unique parent to child edge of the graph by node ID, in
+// the order they are first found, including the edges closing any cycles
+func (g *Graph) edges() [][2]string {
+	seen := make(map[[2]string]bool)
+	var edges [][2]string
+
+	var walk func(parent *Node, path map[*Node]bool)
+	walk = func(parent *Node, path map[*Node]bool) {
+		path[parent] = true
+		for _, child := range parent.Children {
+			e := [2]string{parent.ID(), child.ID()}
+			if !seen[e] {
+				seen[e] = true
+				edges = append(edges, e)
+			}
+
+			if !path[child] {
+				walk(child, path)
+			}
+		}
+		delete(path, parent)
+	}
+
+	if g != nil {
+		for _, root := range g.Roots {
+			walk(root, make(map[*Node]bool))
+		}
+	}
+
+	return edges
+}
+
+func newerAvailable(version, latest string) bool {
+	return version != "" && latest != "" && version != latest
+}
+
+func containsNode(path []*Node, n *Node) bool {
+	for _, p := range path {
+		if p == n {
+			return true
+		}
+	}
+
+	return false
+}
+
+func packageID(p Package) string {
+	n := Node{Org: p.Org, Name: p.Name, Version: p.Version, Type: p.Type}
+	return n.ID()
+}
+
+// PathString returns the path as the labels of its nodes joined by arrows
+func PathString(path []*Node) string {
+	labels := make([]string, 0, len(path))
+	for _, n := range path {
+		labels = append(labels, n.Label())
+	}
+
+	return strings.Join(labels, " -> ")
+}
@@ -0,0 +1,142 @@
+package graph
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/ion-channel/ionic/dependencies"
+	"github.com/ion-channel/ionic/scans"
+
+	"github.com/franela/goblin"
+	. "github.com/onsi/gomega"
+)
+
+func TestGraph(t *testing.T) {
+	g := goblin.Goblin(t)
+	RegisterFailHandler(func(m string, _ ...int) { g.Fail(m) })
+
+	g.Describe("Dependency Graph", func() {
+		var deps []scans.Dependency
+		Expect(json.Unmarshal([]byte(sampleScanDependencies), &deps)).To(Succeed())
+		graph := FromScanDependencies(deps)
+
+		g.It("should flatten and dedupe the tree", func() {
+			flat := graph.Flatten()
+			Expect(flat).To(HaveLen(5))
+
+			Expect(flat[0].Name).To(Equal("guava"))
+			Expect(flat[0].Direct).To(BeTrue())
+			Expect(flat[0].Outdated).To(BeTrue())
+			Expect(flat[1].Name).To(Equal("junit"))
+			Expect(flat[1].Scope).To(Equal("test"))
+
+			Expect(flat[2].Name).To(Equal("hamcrest"))
+			Expect(flat[2].Version).To(Equal("1.3"))
+			Expect(flat[2].Depth).To(Equal(2))
+			Expect(flat[2].Direct).To(BeFalse())
+		})
+
+		g.It("should compute the depth of the tree", func() {
+			Expect(graph.Depth()).To(Equal(3))
+			Expect((&Graph{}).Depth()).To(Equal(0))
+		})
+
+		g.It("should find every path to a package", func() {
+			paths := graph.PathsTo("", "Hamcrest", "")
+			Expect(paths).To(HaveLen(3))
+			Expect(PathString(paths[0])).To(Equal("google/guava 18.0 -> hamcrest 1.3"))
+			Expect(PathString(paths[1])).To(Equal("google/guava 18.0 -> jsr305 3.0 -> hamcrest 2.2"))
+			Expect(PathString(paths[2])).To(Equal("junit/junit 4.12 -> hamcrest 1.3"))
+
+			Expect(graph.PathsTo("", "hamcrest", "2.2")).To(HaveLen(1))
+			Expect(graph.PathsTo("nope", "hamcrest", "")).To(BeEmpty())
+		})
+
+		g.It("should find packages with more than one version", func() {
+			dups := graph.Duplicates()
+			Expect(dups).To(HaveLen(1))
+			Expect(dups[0].Name).To(Equal("hamcrest"))
+			Expect(dups[0].Versions).To(Equal([]string{"1.3", "2.2"}))
+		})
+
+		g.It("should compute the dependency counts", func() {
+			meta := graph.Meta()
+			Expect(meta.FirstDegreeCount).To(Equal(2))
+			Expect(meta.TotalUniqueCount).To(Equal(5))
+			Expect(meta.NoVersionCount).To(Equal(0))
+			Expect(meta.UpdateAvailableCount).To(Equal(2))
+		})
+
+		g.It("should build a graph from resolved dependencies", func() {
+			resolved := FromDependencies([]dependencies.Dependency{
+				{Name: "a", Version: "1.0", Dependencies: []dependencies.Dependency{
+					{Name: "b", LatestVersion: "2.0"},
+				}},
+			})
+
+			meta := resolved.Meta()
+			Expect(meta.TotalUniqueCount).To(Equal(2))
+			Expect(meta.FirstDegreeCount).To(Equal(1))
+			Expect(meta.NoVersionCount).To(Equal(1))
+			Expect(meta.UpdateAvailableCount).To(Equal(0))
+		})
+
+		g.It("should not loop on cycles", func() {
+			a := &Node{Name: "a"}
+			b := &Node{Name: "b", Children: []*Node{a}}
+			a.Children = []*Node{b}
+
+			cyclic := &Graph{Roots: []*Node{a}}
+			Expect(cyclic.Depth()).To(Equal(2))
+			Expect(cyclic.PathsTo("", "b", "")).To(HaveLen(1))
+			Expect(cyclic.edges()).To(HaveLen(2))
+		})
+
+		g.It("should write the graph as DOT", func() {
+			var buf bytes.Buffer
+			Expect(graph.WriteDOT(&buf)).To(Succeed())
+
+			dot := buf.String()
+			Expect(dot).To(HavePrefix("digraph dependencies {\n"))
+			Expect(dot).To(ContainSubstring(`"maven:google/guava@18.0" [label="google/guava 18.0", style="bold,filled", fillcolor="lightyellow"];`))
+			Expect(dot).To(ContainSubstring(`"maven:google/guava@18.0" -> "maven:hamcrest@1.3";`))
+			Expect(dot).To(HaveSuffix("}\n"))
+		})
+
+		g.It("should write the graph as Mermaid", func() {
+			var buf bytes.Buffer
+			Expect(graph.WriteMermaid(&buf)).To(Succeed())
+
+			mermaid := buf.String()
+			Expect(mermaid).To(HavePrefix("flowchart TD\n"))
+			Expect(mermaid).To(ContainSubstring(`    n0["google/guava 18.0"]`))
+			Expect(mermaid).To(ContainSubstring("    n0 --> n2\n"))
+			Expect(mermaid).To(ContainSubstring("    class n0,n3 outdated\n"))
+		})
+
+		g.It("should write the graph as JSON", func() {
+			var buf bytes.Buffer
+			Expect(graph.WriteJSON(&buf)).To(Succeed())
+
+			var jg jsonGraph
+			Expect(json.Unmarshal(buf.Bytes(), &jg)).To(Succeed())
+			Expect(jg.Graph.Directed).To(BeTrue())
+			Expect(jg.Graph.Nodes).To(HaveLen(5))
+			Expect(jg.Graph.Nodes["maven:hamcrest@2.2"].Metadata.Depth).To(Equal(3))
+			Expect(jg.Graph.Edges).To(ContainElement(jsonGraphEdge{Source: "maven:junit/junit@4.12", Target: "maven:hamcrest@1.3"}))
+		})
+	})
+}
+
+const sampleScanDependencies = `[
+{"org":"google","name":"guava","type":"maven","version":"18.0","latest_version":"31.0","scope":"compile","outdated_version":{"major_behind":13},"dependencies":[
+	{"name":"hamcrest","type":"maven","version":"1.3","latest_version":"1.3","outdated_version":{}},
+	{"name":"jsr305","type":"maven","version":"3.0","latest_version":"3.0.2","dependencies":[
+		{"name":"hamcrest","type":"maven","version":"2.2"}
+	]}
+]},
+{"org":"junit","name":"junit","type":"maven","version":"4.12","latest_version":"4.12","scope":"test","dependencies":[
+	{"name":"hamcrest","type":"maven","version":"1.3"}
+]}
+]`
@@ -0,0 +1,103 @@
+package lockfiles
+
+import (
+	"fmt"
+	"io"
+	"io/ioutil"
+	"strings"
+
+	"github.com/ion-channel/ionic/dependencies"
+	"github.com/ion-channel/ionic/purl"
+)
+
+// ParseCargoLock parses a Cargo.lock file. Packages without a source are the
+// crates of the project's workspace, and the packages they require are its
+// direct dependencies.
+func ParseCargoLock(r io.Reader) (*dependencies.DependencyResolutionResponse, error) {
+	b, err := ioutil.ReadAll(r)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read Cargo.lock: %v", err.Error())
+	}
+
+	lock, err := parseTOML(string(b))
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse Cargo.lock: %v", err.Error())
+	}
+
+	packages := lock.tables("package")
+
+	// dependencies name only the package when one version of it is locked,
+	// and the package and version otherwise
+	versions := map[string][]string{}
+	for _, pkg := range packages {
+		versions[pkg.str("name")] = append(versions[pkg.str("name")], pkg.str("name")+" "+pkg.str("version"))
+	}
+
+	resolve := func(dep string) (string, bool) {
+		fields := strings.Fields(dep)
+		if len(fields) == 0 {
+			return "", false
+		}
+
+		keys := versions[fields[0]]
+		if len(keys) == 0 {
+			return "", false
+		}
+
+		if len(fields) == 1 {
+			return keys[0], true
+		}
+
+		key := fields[0] + " " + fields[1]
+		for _, k := range keys {
+			if k == key {
+				return key, true
+			}
+		}
+
+		return "", false
+	}
+
+	nodes := map[string]*node{}
+	workspace := map[string]bool{}
+	for _, pkg := range packages {
+		key := pkg.str("name") + " " + pkg.str("version")
+
+		requires := []edge{}
+		deps, _ := pkg["dependencies"].([]interface{})
+		for _, d := range deps {
+			s, _ := d.(string)
+			if child, ok := resolve(s); ok {
+				requires = append(requires, edge{key: child})
+			}
+		}
+
+		nodes[key] = &node{
+			dep:      newDependency(purl.TypeCargo, pkg.str("name"), pkg.str("version")),
+			requires: requires,
+		}
+
+		if pkg.str("source") == "" {
+			workspace[key] = true
+		}
+	}
+
+	roots := []edge{}
+	required := map[string]bool{}
+	for _, key := range nodeKeys(nodes) {
+		if workspace[key] {
+			for _, e := range nodes[key].requires {
+				if !workspace[e.key] && !required[e.key] {
+					required[e.key] = true
+					roots = append(roots, e)
+				}
+			}
+		}
+	}
+
+	if len(workspace) == 0 {
+		roots = unrequired(nodes)
+	}
+
+	return newResponse(buildTree(nodes, roots)), nil
+}
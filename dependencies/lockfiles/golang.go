@@ -0,0 +1,175 @@
+package lockfiles
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/ion-channel/ionic/dependencies"
+	"github.com/ion-channel/ionic/purl"
+)
+
+type goRequirement struct {
+	path     string
+	version  string
+	indirect bool
+}
+
+// ParseGoMod parses the requirements of a go.mod file. Replaced modules are
+// listed as their replacements, unless they are replaced by a local directory,
+// in which case they keep their module path and version and have the
+// ScopeLocal scope. Modules required only by other modules have the
+// ScopeIndirect scope. go.mod files do not record which modules
+// require each other, so the tree is flat.
+func ParseGoMod(r io.Reader) (*dependencies.DependencyResolutionResponse, error) {
+	requirements := []goRequirement{}
+	replacements := map[string][2]string{}
+
+	block := ""
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		comment := ""
+		if i := strings.Index(line, "//"); i >= 0 {
+			line, comment = strings.TrimSpace(line[:i]), strings.TrimSpace(line[i+2:])
+		}
+
+		if line == "" {
+			continue
+		}
+
+		if line == ")" {
+			block = ""
+			continue
+		}
+
+		directive := block
+		if directive == "" {
+			fields := strings.SplitN(line, " ", 2)
+			directive = fields[0]
+			line = ""
+			if len(fields) > 1 {
+				line = strings.TrimSpace(fields[1])
+			}
+
+			if line == "(" {
+				block = directive
+				continue
+			}
+		}
+
+		fields := strings.Fields(line)
+		switch directive {
+		case "require":
+			if len(fields) < 2 {
+				return nil, fmt.Errorf("invalid go.mod require: %v", line)
+			}
+
+			requirements = append(requirements, goRequirement{
+				path:     unquote(fields[0]),
+				version:  fields[1],
+				indirect: strings.HasPrefix(comment, "indirect"),
+			})
+		case "replace":
+			parts := strings.SplitN(line, "=>", 2)
+			if len(parts) != 2 {
+				return nil, fmt.Errorf("invalid go.mod replace: %v", line)
+			}
+
+			from, to := strings.Fields(parts[0]), strings.Fields(parts[1])
+			if len(from) == 0 || len(to) == 0 {
+				return nil, fmt.Errorf("invalid go.mod replace: %v", line)
+			}
+
+			replacement := [2]string{unquote(to[0]), ""}
+			if len(to) > 1 {
+				replacement[1] = to[1]
+			}
+
+			key := unquote(from[0])
+			if len(from) > 1 {
+				key += "@" + from[1]
+			}
+
+			replacements[key] = replacement
+		}
+	}
+
+	err := scanner.Err()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read go.mod: %v", err.Error())
+	}
+
+	roots := []dependencies.Dependency{}
+	for _, req := range requirements {
+		path, version := req.path, req.version
+		replacement, ok := replacements[path+"@"+version]
+		if !ok {
+			replacement, ok = replacements[path]
+		}
+
+		local := ok && isLocalPath(replacement[0])
+		if ok && !local {
+			path, version = replacement[0], replacement[1]
+		}
+
+		dep := newDependency(purl.TypeGolang, path, version)
+		dep.Requirement = req.version
+		switch {
+		case req.indirect:
+			dep.Scope = ScopeIndirect
+		case local:
+			dep.Scope = ScopeLocal
+		}
+
+		roots = append(roots, dep)
+	}
+
+	return newResponse(roots), nil
+}
+
+// ParseGoSum parses the modules of a go.sum file. Modules whose go.mod files
+// are the only ones checksummed were not needed to build, and are left out.
+func ParseGoSum(r io.Reader) (*dependencies.DependencyResolutionResponse, error) {
+	roots := []dependencies.Dependency{}
+	seen := map[string]bool{}
+
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 0 {
+			continue
+		}
+
+		if len(fields) != 3 {
+			return nil, fmt.Errorf("invalid go.sum line: %v", scanner.Text())
+		}
+
+		path, version := fields[0], fields[1]
+		if strings.HasSuffix(version, "/go.mod") || seen[path+"@"+version] {
+			continue
+		}
+
+		seen[path+"@"+version] = true
+		roots = append(roots, newDependency(purl.TypeGolang, path, version))
+	}
+
+	err := scanner.Err()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read go.sum: %v", err.Error())
+	}
+
+	return newResponse(roots), nil
+}
+
+// isLocalPath returns whether the target of a go.mod replace is a directory on
+// disk rather than a module
+func isLocalPath(target string) bool {
+	return strings.HasPrefix(target, "./") || strings.HasPrefix(target, "../") || strings.HasPrefix(target, "/") ||
+		strings.HasPrefix(target, `.\`) || strings.HasPrefix(target, `..\`)
+}
+
+func unquote(s string) string {
+	return strings.Trim(s, "\"`")
+}
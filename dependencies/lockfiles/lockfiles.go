@@ -0,0 +1,348 @@
+// Package lockfiles parses dependency lockfiles and manifests, such as go.mod,
+// package-lock.json, or Gemfile.lock, into the dependency trees returned by
+// Ion Channel, without sending them to the API. The ecosystem of a file is
+// detected from its name.
+package lockfiles
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/ion-channel/ionic/dependencies"
+	"github.com/ion-channel/ionic/purl"
+)
+
+const (
+	// EcosystemGoMod is the ecosystem of go.mod files
+	EcosystemGoMod = "gomod"
+	// EcosystemGoSum is the ecosystem of go.sum files
+	EcosystemGoSum = "gosum"
+	// EcosystemPackageJSON is the ecosystem of package.json files, which are
+	// only resolved by the API
+	EcosystemPackageJSON = "package-json"
+	// EcosystemPackageLock is the ecosystem of package-lock.json and
+	// npm-shrinkwrap.json files
+	EcosystemPackageLock = "package-lock"
+	// EcosystemYarnLock is the ecosystem of yarn.lock files
+	EcosystemYarnLock = "yarnlock"
+	// EcosystemPNPMLock is the ecosystem of pnpm-lock.yaml files
+	EcosystemPNPMLock = "pnpm-lock"
+	// EcosystemRequirements is the ecosystem of pip requirements files
+	EcosystemRequirements = "requirements"
+	// EcosystemPipfile is the ecosystem of Pipfile files, which are only
+	// resolved by the API
+	EcosystemPipfile = "pipfile"
+	// EcosystemPipfileLock is the ecosystem of Pipfile.lock files
+	EcosystemPipfileLock = "pipfile-lock"
+	// EcosystemPoetryLock is the ecosystem of poetry.lock files
+	EcosystemPoetryLock = "poetry-lock"
+	// EcosystemGemfileLock is the ecosystem of Gemfile.lock files
+	EcosystemGemfileLock = "ruby"
+	// EcosystemMaven is the ecosystem of Maven pom.xml files
+	EcosystemMaven = "maven"
+	// EcosystemCargoLock is the ecosystem of Cargo.lock files
+	EcosystemCargoLock = "cargo-lock"
+	// EcosystemNuGet is the ecosystem of NuGet packages.config and project
+	// files, which are only resolved by the API
+	EcosystemNuGet = "nuget"
+	// EcosystemNuGetLock is the ecosystem of NuGet packages.lock.json files
+	EcosystemNuGetLock = "nuget-lock"
+)
+
+const (
+	// ScopeDevelopment is the scope of dependencies only needed to develop a
+	// project
+	ScopeDevelopment = "development"
+	// ScopeOptional is the scope of dependencies a project can be installed
+	// without
+	ScopeOptional = "optional"
+	// ScopePeer is the scope of npm dependencies expected to be provided by
+	// the project using a package
+	ScopePeer = "peer"
+	// ScopeIndirect is the scope of Go modules required only by other modules.
+	// They are listed with the direct dependencies, but not counted as first
+	// degree dependencies.
+	ScopeIndirect = "indirect"
+	// ScopeLocal is the scope of Go modules replaced by a directory on disk.
+	// They are listed as the module and version they replace.
+	ScopeLocal = "local"
+)
+
+// Parser parses a lockfile or manifest into a dependency tree
+type Parser func(r io.Reader) (*dependencies.DependencyResolutionResponse, error)
+
+var parsers = map[string]Parser{
+	EcosystemGoMod:        ParseGoMod,
+	EcosystemGoSum:        ParseGoSum,
+	EcosystemPackageLock:  ParsePackageLock,
+	EcosystemYarnLock:     ParseYarnLock,
+	EcosystemPNPMLock:     ParsePNPMLock,
+	EcosystemRequirements: ParseRequirements,
+	EcosystemPipfileLock:  ParsePipfileLock,
+	EcosystemPoetryLock:   ParsePoetryLock,
+	EcosystemGemfileLock:  ParseGemfileLock,
+	EcosystemMaven:        ParsePOM,
+	EcosystemCargoLock:    ParseCargoLock,
+	EcosystemNuGetLock:    ParseNuGetLock,
+}
+
+var filenames = map[string]string{
+	"go.mod":              EcosystemGoMod,
+	"go.sum":              EcosystemGoSum,
+	"package.json":        EcosystemPackageJSON,
+	"package-lock.json":   EcosystemPackageLock,
+	"npm-shrinkwrap.json": EcosystemPackageLock,
+	"yarn.lock":           EcosystemYarnLock,
+	"pnpm-lock.yaml":      EcosystemPNPMLock,
+	"requirements.txt":    EcosystemRequirements,
+	"pipfile":             EcosystemPipfile,
+	"pipfile.lock":        EcosystemPipfileLock,
+	"poetry.lock":         EcosystemPoetryLock,
+	"gemfile.lock":        EcosystemGemfileLock,
+	"pom.xml":             EcosystemMaven,
+	"cargo.lock":          EcosystemCargoLock,
+	"packages.config":     EcosystemNuGet,
+	"packages.lock.json":  EcosystemNuGetLock,
+}
+
+// Detect returns the ecosystem of a lockfile or manifest from its name, such
+// as EcosystemYarnLock for "web/yarn.lock", and false if it is not known.
+// Pip requirements files may be named like "requirements-dev.txt", and NuGet
+// project files like "App.csproj".
+func Detect(filename string) (string, bool) {
+	base := strings.ToLower(filepath.Base(filename))
+	if eco, ok := filenames[base]; ok {
+		return eco, true
+	}
+
+	switch ext := filepath.Ext(base); {
+	case ext == ".txt" && strings.Contains(base, "requirements"):
+		return EcosystemRequirements, true
+	case ext == ".csproj" || ext == ".fsproj" || ext == ".vbproj":
+		return EcosystemNuGet, true
+	}
+
+	return "", false
+}
+
+// ParserFor returns the parser of an ecosystem, and false if it can only be
+// resolved by the API
+func ParserFor(ecosystem string) (Parser, bool) {
+	p, ok := parsers[ecosystem]
+	return p, ok
+}
+
+// Parse parses a lockfile or manifest of the given ecosystem
+func Parse(ecosystem string, r io.Reader) (*dependencies.DependencyResolutionResponse, error) {
+	p, ok := ParserFor(ecosystem)
+	if !ok {
+		return nil, fmt.Errorf("no parser for ecosystem: %v", ecosystem)
+	}
+
+	return p(r)
+}
+
+// ParseFile parses the lockfile or manifest at the given path. The ecosystem
+// is detected from the name of the file when it is empty.
+func ParseFile(path, ecosystem string) (*dependencies.DependencyResolutionResponse, error) {
+	if ecosystem == "" {
+		eco, ok := Detect(path)
+		if !ok {
+			return nil, fmt.Errorf("failed to detect ecosystem of file: %v", path)
+		}
+
+		ecosystem = eco
+	}
+
+	p, ok := ParserFor(ecosystem)
+	if !ok {
+		return nil, fmt.Errorf("no parser for ecosystem: %v", ecosystem)
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open file: %v", err.Error())
+	}
+	defer f.Close()
+
+	return p(f)
+}
+
+// Flatten returns every dependency of a tree once, in the order they are
+// first found, without their own dependencies
+func Flatten(deps []dependencies.Dependency) []dependencies.Dependency {
+	flat := []dependencies.Dependency{}
+	seen := map[string]bool{}
+
+	var walk func(deps []dependencies.Dependency)
+	walk = func(deps []dependencies.Dependency) {
+		for _, dep := range deps {
+			key := dependencyKey(dep)
+			if !seen[key] {
+				seen[key] = true
+				children := dep.Dependencies
+				dep.Dependencies = nil
+				flat = append(flat, dep)
+				walk(children)
+			}
+		}
+	}
+
+	walk(deps)
+	return flat
+}
+
+// newResponse returns the response of a dependency tree, counting its first
+// degree and unique dependencies
+func newResponse(roots []dependencies.Dependency) *dependencies.DependencyResolutionResponse {
+	resp := &dependencies.DependencyResolutionResponse{Dependencies: roots}
+
+	for _, dep := range roots {
+		if dep.Scope != ScopeIndirect {
+			resp.Meta.FirstDegreeCount++
+		}
+	}
+
+	for _, dep := range Flatten(roots) {
+		resp.Meta.TotalUniqueCount++
+		if dep.Version == "" {
+			resp.Meta.NoVersionCount++
+		}
+	}
+
+	return resp
+}
+
+// node is a package of a lockfile, and the packages it requires
+type node struct {
+	dep      dependencies.Dependency
+	requires []edge
+}
+
+// edge is a requirement of a package, or of the project, on the node with the
+// given key
+type edge struct {
+	key         string
+	requirement string
+	scope       string
+}
+
+// buildTree returns the dependency trees of the nodes the project requires.
+// The dependencies of each node are only built once, and a node required by
+// one of its own dependencies is listed there without its dependencies.
+// Requirements on nodes that are not in the lockfile are left out.
+func buildTree(nodes map[string]*node, roots []edge) []dependencies.Dependency {
+	built := map[string][]dependencies.Dependency{}
+	ancestors := map[string]bool{}
+
+	var build func(e edge) (dependencies.Dependency, bool)
+	build = func(e edge) (dependencies.Dependency, bool) {
+		n, ok := nodes[e.key]
+		if !ok {
+			return dependencies.Dependency{}, false
+		}
+
+		dep := n.dep
+		if e.requirement != "" {
+			dep.Requirement = e.requirement
+		}
+
+		if e.scope != "" {
+			dep.Scope = e.scope
+		}
+
+		if children, ok := built[e.key]; ok {
+			dep.Dependencies = children
+			return dep, true
+		}
+
+		if ancestors[e.key] {
+			return dep, true
+		}
+
+		ancestors[e.key] = true
+		var children []dependencies.Dependency
+		for _, child := range n.requires {
+			if d, ok := build(child); ok {
+				children = append(children, d)
+			}
+		}
+		delete(ancestors, e.key)
+
+		built[e.key] = children
+		dep.Dependencies = children
+		return dep, true
+	}
+
+	deps := []dependencies.Dependency{}
+	for _, root := range roots {
+		if dep, ok := build(root); ok {
+			deps = append(deps, dep)
+		}
+	}
+
+	return deps
+}
+
+// unrequired returns edges to the nodes that no other node requires, sorted
+// by key, which are taken to be the direct dependencies of lockfiles that do
+// not list them
+func unrequired(nodes map[string]*node) []edge {
+	required := map[string]bool{}
+	for _, n := range nodes {
+		for _, e := range n.requires {
+			required[e.key] = true
+		}
+	}
+
+	roots := []edge{}
+	for _, key := range nodeKeys(nodes) {
+		if !required[key] {
+			roots = append(roots, edge{key: key})
+		}
+	}
+
+	return roots
+}
+
+func nodeKeys(nodes map[string]*node) []string {
+	keys := make([]string, 0, len(nodes))
+	for key := range nodes {
+		keys = append(keys, key)
+	}
+
+	sort.Strings(keys)
+	return keys
+}
+
+func sortedKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+
+	sort.Strings(keys)
+	return keys
+}
+
+// newDependency returns a dependency of the given package URL type, splitting
+// the name into an org and name the way the type does, such as the group and
+// artifact of a Maven "group:artifact" name
+func newDependency(typ, name, version string) dependencies.Dependency {
+	p, err := purl.FromOrgName(typ, "", name, version)
+	if err != nil {
+		return dependencies.Dependency{Type: typ, Name: name, Version: version}
+	}
+
+	dep := p.Dependency()
+	dep.Version = version
+	return dep
+}
+
+func dependencyKey(dep dependencies.Dependency) string {
+	return dep.Type + ":" + dep.Org + "/" + dep.Name + "@" + dep.Version
+}
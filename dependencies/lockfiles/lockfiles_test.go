@@ -0,0 +1,822 @@
+package lockfiles
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/franela/goblin"
+	. "github.com/onsi/gomega"
+
+	"github.com/ion-channel/ionic/dependencies"
+)
+
+func TestLockfiles(t *testing.T) {
+	g := goblin.Goblin(t)
+	RegisterFailHandler(func(m string, _ ...int) { g.Fail(m) })
+
+	parse := func(ecosystem, contents string) *dependencies.DependencyResolutionResponse {
+		resp, err := Parse(ecosystem, strings.NewReader(contents))
+		Expect(err).To(BeNil())
+		return resp
+	}
+
+	names := func(deps []dependencies.Dependency) []string {
+		ns := []string{}
+		for _, dep := range deps {
+			n := dep.Name + "@" + dep.Version
+			if dep.Org != "" && dep.Type != "golang" {
+				n = dep.Org + "/" + n
+			}
+
+			ns = append(ns, n)
+		}
+
+		return ns
+	}
+
+	g.Describe("Detect", func() {
+		g.It("should detect ecosystems from file names", func() {
+			for file, eco := range map[string]string{
+				"go.mod":                        EcosystemGoMod,
+				"/src/app/package-lock.json":    EcosystemPackageLock,
+				"web/yarn.lock":                 EcosystemYarnLock,
+				"pnpm-lock.yaml":                EcosystemPNPMLock,
+				"requirements-dev.txt":          EcosystemRequirements,
+				"Pipfile":                       EcosystemPipfile,
+				"Pipfile.lock":                  EcosystemPipfileLock,
+				"poetry.lock":                   EcosystemPoetryLock,
+				"Gemfile.lock":                  EcosystemGemfileLock,
+				"pom.xml":                       EcosystemMaven,
+				"Cargo.lock":                    EcosystemCargoLock,
+				"src/App/packages.lock.json":    EcosystemNuGetLock,
+				"src/App/App.csproj":            EcosystemNuGet,
+				"frontend/npm-shrinkwrap.json":  EcosystemPackageLock,
+				"frontend/node/package.json":    EcosystemPackageJSON,
+				"services/api/requirements.txt": EcosystemRequirements,
+			} {
+				detected, ok := Detect(file)
+				Expect(ok).To(BeTrue(), file)
+				Expect(detected).To(Equal(eco), file)
+			}
+
+			_, ok := Detect("README.md")
+			Expect(ok).To(BeFalse())
+
+			_, ok = ParserFor(EcosystemPackageJSON)
+			Expect(ok).To(BeFalse())
+
+			_, err := Parse(EcosystemPipfile, strings.NewReader(""))
+			Expect(err).NotTo(BeNil())
+		})
+
+		g.It("should parse files by their detected ecosystem", func() {
+			dir, err := ioutil.TempDir("", "lockfiles")
+			Expect(err).To(BeNil())
+			defer os.RemoveAll(dir)
+
+			path := filepath.Join(dir, "requirements.txt")
+			Expect(ioutil.WriteFile(path, []byte("flask==2.0.1\n"), 0600)).To(BeNil())
+
+			resp, err := ParseFile(path, "")
+			Expect(err).To(BeNil())
+			Expect(names(resp.Dependencies)).To(Equal([]string{"flask@2.0.1"}))
+
+			_, err = ParseFile(filepath.Join(dir, "notes.md"), "")
+			Expect(err).NotTo(BeNil())
+		})
+	})
+
+	g.Describe("Go", func() {
+		g.It("should parse go.mod requirements and replacements", func() {
+			resp := parse(EcosystemGoMod, goMod)
+			Expect(names(resp.Dependencies)).To(Equal([]string{
+				"github.com/stretchr/testify@v1.7.0",
+				"golang.org/x/text@v0.3.7",
+				"github.com/davecgh/go-spew@v1.1.1",
+				"github.com/example/fork@v0.2.0",
+			}))
+
+			Expect(resp.Dependencies[0].Org).To(Equal("github.com/stretchr"))
+			Expect(resp.Dependencies[0].Type).To(Equal("golang"))
+			Expect(resp.Dependencies[0].Scope).To(Equal(""))
+			Expect(resp.Dependencies[2].Scope).To(Equal(ScopeIndirect))
+			Expect(resp.Dependencies[3].Requirement).To(Equal("v0.1.0"))
+			Expect(resp.Meta.FirstDegreeCount).To(Equal(3))
+			Expect(resp.Meta.TotalUniqueCount).To(Equal(4))
+		})
+
+		g.It("should keep modules replaced by local directories", func() {
+			resp := parse(EcosystemGoMod, `module github.com/ion-channel/example
+
+require (
+	github.com/example/lib v0.1.0
+	github.com/example/tools v1.2.0
+	github.com/example/util v0.3.0 // indirect
+	github.com/example/api v2.0.0
+)
+
+replace (
+	github.com/example/lib => ../lib
+	github.com/example/tools v1.2.0 => ./tools
+	github.com/example/util => /src/util
+	github.com/example/api => github.com/example/api-fork v2.0.1
+)
+`)
+			Expect(names(resp.Dependencies)).To(Equal([]string{
+				"github.com/example/lib@v0.1.0",
+				"github.com/example/tools@v1.2.0",
+				"github.com/example/util@v0.3.0",
+				"github.com/example/api-fork@v2.0.1",
+			}))
+
+			Expect(resp.Dependencies[0].Scope).To(Equal(ScopeLocal))
+			Expect(resp.Dependencies[1].Scope).To(Equal(ScopeLocal))
+			Expect(resp.Dependencies[2].Scope).To(Equal(ScopeIndirect))
+			Expect(resp.Dependencies[3].Scope).To(Equal(""))
+		})
+
+		g.It("should parse go.sum modules", func() {
+			resp := parse(EcosystemGoSum, goSum)
+			Expect(names(resp.Dependencies)).To(Equal([]string{
+				"github.com/stretchr/testify@v1.7.0",
+				"gopkg.in/yaml.v3@v3.0.0",
+			}))
+		})
+	})
+
+	g.Describe("npm", func() {
+		g.It("should parse version 2 and 3 package locks", func() {
+			resp := parse(EcosystemPackageLock, packageLockV2)
+			Expect(names(resp.Dependencies)).To(Equal([]string{"express@4.17.1", "jest@27.0.6"}))
+
+			express := resp.Dependencies[0]
+			Expect(express.Requirement).To(Equal("^4.17.1"))
+			Expect(express.Scope).To(Equal(""))
+			Expect(names(express.Dependencies)).To(Equal([]string{"debug@2.6.9", "fsevents@2.3.2"}))
+			Expect(express.Dependencies[1].Scope).To(Equal(ScopeOptional))
+			Expect(names(express.Dependencies[0].Dependencies)).To(Equal([]string{"ms@2.0.0"}))
+
+			jest := resp.Dependencies[1]
+			Expect(jest.Scope).To(Equal(ScopeDevelopment))
+			Expect(names(jest.Dependencies)).To(Equal([]string{"types/node@16.4.0", "debug@4.3.2"}))
+			Expect(names(jest.Dependencies[1].Dependencies)).To(Equal([]string{"ms@2.1.2"}))
+
+			Expect(resp.Meta.FirstDegreeCount).To(Equal(2))
+			Expect(resp.Meta.TotalUniqueCount).To(Equal(8))
+		})
+
+		g.It("should parse version 1 package locks", func() {
+			resp := parse(EcosystemPackageLock, packageLockV1)
+			Expect(names(resp.Dependencies)).To(Equal([]string{"express@4.17.1"}))
+			Expect(names(resp.Dependencies[0].Dependencies)).To(Equal([]string{"debug@2.6.9"}))
+			Expect(resp.Dependencies[0].Dependencies[0].Requirement).To(Equal("2.6.9"))
+			Expect(names(resp.Dependencies[0].Dependencies[0].Dependencies)).To(Equal([]string{"ms@2.0.0"}))
+		})
+
+		g.It("should parse yarn locks", func() {
+			for _, lock := range []string{yarnLockClassic, yarnLockBerry} {
+				resp := parse(EcosystemYarnLock, lock)
+				Expect(names(resp.Dependencies)).To(Equal([]string{"babel/code-frame@7.12.13"}))
+
+				frame := resp.Dependencies[0]
+				Expect(names(frame.Dependencies)).To(Equal([]string{"babel/highlight@7.14.5"}))
+				Expect(frame.Dependencies[0].Requirement).To(Equal("^7.12.13"))
+				Expect(names(frame.Dependencies[0].Dependencies)).To(Equal([]string{"chalk@2.4.2", "js-tokens@4.0.0"}))
+			}
+		})
+
+		g.It("should parse pnpm locks", func() {
+			for _, lock := range []string{pnpmLockV5, pnpmLockV6, pnpmLockV9} {
+				resp := parse(EcosystemPNPMLock, lock)
+				Expect(names(resp.Dependencies)).To(Equal([]string{"express@4.17.1", "typescript@4.3.5"}))
+				Expect(resp.Dependencies[0].Requirement).To(Equal("^4.17.1"))
+				Expect(resp.Dependencies[1].Scope).To(Equal(ScopeDevelopment))
+				Expect(names(resp.Dependencies[0].Dependencies)).To(Equal([]string{"debug@2.6.9"}))
+				Expect(names(resp.Dependencies[0].Dependencies[0].Dependencies)).To(Equal([]string{"ms@2.0.0"}))
+			}
+		})
+	})
+
+	g.Describe("Python", func() {
+		g.It("should parse requirements files", func() {
+			resp := parse(EcosystemRequirements, requirementsTxt)
+			Expect(names(resp.Dependencies)).To(Equal([]string{
+				"requests@", "django-rest-framework@3.12.4", "flask@2.0.1", "numpy@", "mylib@",
+			}))
+			Expect(resp.Dependencies[0].Requirement).To(Equal(">=2.8.1,<3"))
+			Expect(resp.Dependencies[2].Requirement).To(Equal("==2.0.1"))
+			Expect(resp.Dependencies[4].Requirement).To(Equal("https://example.com/mylib-1.0.tar.gz"))
+			Expect(resp.Meta.NoVersionCount).To(Equal(3))
+		})
+
+		g.It("should parse Pipfile locks", func() {
+			resp := parse(EcosystemPipfileLock, pipfileLockJSON)
+			Expect(names(resp.Dependencies)).To(Equal([]string{"certifi@2021.5.30", "requests@2.26.0", "pytest@6.2.4"}))
+			Expect(resp.Dependencies[1].Requirement).To(Equal("==2.26.0"))
+			Expect(resp.Dependencies[2].Scope).To(Equal(ScopeDevelopment))
+		})
+
+		g.It("should parse poetry locks", func() {
+			resp := parse(EcosystemPoetryLock, poetryLock)
+			Expect(names(resp.Dependencies)).To(Equal([]string{"pytest@6.2.4", "requests@2.26.0"}))
+			Expect(resp.Dependencies[0].Scope).To(Equal(ScopeDevelopment))
+
+			requests := resp.Dependencies[1]
+			Expect(names(requests.Dependencies)).To(Equal([]string{"certifi@2021.5.30", "charset-normalizer@2.0.4", "pysocks@1.7.1"}))
+			Expect(requests.Dependencies[0].Requirement).To(Equal(">=2017.4.17"))
+			Expect(requests.Dependencies[2].Scope).To(Equal(ScopeOptional))
+		})
+	})
+
+	g.Describe("Ruby", func() {
+		g.It("should parse Gemfile locks", func() {
+			resp := parse(EcosystemGemfileLock, gemfileLock)
+			Expect(names(resp.Dependencies)).To(Equal([]string{"nokogiri@1.12.5", "rails@6.1.4", "rake@13.0.6"}))
+			Expect(resp.Dependencies[1].Requirement).To(Equal("~> 6.1"))
+			Expect(resp.Dependencies[1].Type).To(Equal("gem"))
+			Expect(names(resp.Dependencies[1].Dependencies)).To(Equal([]string{"actionpack@6.1.4", "rake@13.0.6"}))
+			Expect(resp.Dependencies[1].Dependencies[0].Requirement).To(Equal("= 6.1.4"))
+			Expect(names(resp.Dependencies[0].Dependencies)).To(Equal([]string{"racc@1.5.2"}))
+		})
+	})
+
+	g.Describe("Maven", func() {
+		g.It("should parse pom dependencies", func() {
+			resp := parse(EcosystemMaven, pomXML)
+			Expect(names(resp.Dependencies)).To(Equal([]string{
+				"org.apache.logging.log4j/log4j-core@2.17.1",
+				"com.example/widgets@1.0.0-SNAPSHOT",
+				"junit/junit@4.13.2",
+				"com.google.guava/guava@",
+				"org.slf4j/slf4j-api@1.7.32",
+			}))
+
+			Expect(resp.Dependencies[0].Scope).To(Equal("compile"))
+			Expect(resp.Dependencies[2].Scope).To(Equal("test"))
+			Expect(resp.Dependencies[3].Requirement).To(Equal("[30.0-jre,)"))
+			Expect(resp.Dependencies[4].Scope).To(Equal(ScopeOptional))
+		})
+	})
+
+	g.Describe("Rust", func() {
+		g.It("should parse Cargo locks", func() {
+			resp := parse(EcosystemCargoLock, cargoLock)
+			Expect(names(resp.Dependencies)).To(Equal([]string{"rand@0.8.4", "serde@1.0.130"}))
+			Expect(resp.Dependencies[0].Type).To(Equal("cargo"))
+			Expect(names(resp.Dependencies[0].Dependencies)).To(Equal([]string{"libc@0.2.101", "rand_core@0.6.3"}))
+			Expect(names(resp.Dependencies[0].Dependencies[1].Dependencies)).To(Equal([]string{"getrandom@0.2.3"}))
+		})
+	})
+
+	g.Describe("NuGet", func() {
+		g.It("should parse NuGet locks", func() {
+			resp := parse(EcosystemNuGetLock, nugetLockJSON)
+			Expect(names(resp.Dependencies)).To(Equal([]string{"Newtonsoft.Json@13.0.1", "Serilog@2.10.0"}))
+			Expect(resp.Dependencies[0].Requirement).To(Equal("[13.0.1, )"))
+			Expect(names(resp.Dependencies[1].Dependencies)).To(Equal([]string{"System.Memory@4.5.4"}))
+			Expect(resp.Dependencies[1].Dependencies[0].Requirement).To(Equal("4.5.0"))
+		})
+	})
+
+	g.Describe("Flatten", func() {
+		g.It("should list each dependency once", func() {
+			resp := parse(EcosystemPackageLock, packageLockV2)
+			Expect(names(Flatten(resp.Dependencies))).To(Equal([]string{
+				"express@4.17.1", "debug@2.6.9", "ms@2.0.0", "fsevents@2.3.2",
+				"jest@27.0.6", "types/node@16.4.0", "debug@4.3.2", "ms@2.1.2",
+			}))
+		})
+	})
+
+	g.Describe("TOML", func() {
+		g.It("should parse the values used by lockfiles", func() {
+			doc, err := parseTOML(tomlDocument)
+			Expect(err).To(BeNil())
+			Expect(doc.str("title")).To(Equal("a \"quoted\" é"))
+			Expect(doc.str("literal")).To(Equal(`C:\path`))
+			Expect(doc.str("multiline")).To(Equal("one\ntwo"))
+			Expect(doc["enabled"]).To(Equal(true))
+			Expect(doc.str("count")).To(Equal("3"))
+
+			tables := doc.tables("package")
+			Expect(len(tables)).To(Equal(2))
+			Expect(tables[0]["files"]).To(Equal([]interface{}{tomlTable{"file": "a.whl", "hash": "sha256:1"}, "b"}))
+			Expect(tables[1]["extras"].(tomlTable)["socks"]).To(Equal([]interface{}{"PySocks (>=1.5.6)"}))
+			Expect(doc["site"].(tomlTable)["owner"].(tomlTable).str("name")).To(Equal("Tom"))
+
+			for _, invalid := range []string{"key", "key = ", `key = "open`, "[table", "key = [1, 2", `key = "\q"`} {
+				_, err := parseTOML(invalid)
+				Expect(err).NotTo(BeNil(), invalid)
+			}
+		})
+	})
+}
+
+const goMod = `module github.com/ion-channel/example
+
+go 1.17
+
+require github.com/stretchr/testify v1.7.0
+
+require (
+	golang.org/x/text v0.3.7
+	github.com/davecgh/go-spew v1.1.1 // indirect
+	"github.com/example/lib" v0.1.0
+)
+
+replace github.com/example/lib v0.1.0 => github.com/example/fork v0.2.0
+`
+
+const goSum = `github.com/stretchr/testify v1.6.0/go.mod h1:abc=
+github.com/stretchr/testify v1.7.0 h1:def=
+github.com/stretchr/testify v1.7.0/go.mod h1:ghi=
+gopkg.in/yaml.v3 v3.0.0 h1:jkl=
+`
+
+const packageLockV2 = `{
+  "name": "example",
+  "lockfileVersion": 2,
+  "packages": {
+    "": {
+      "name": "example",
+      "dependencies": {"express": "^4.17.1"},
+      "devDependencies": {"jest": "^27.0.0"}
+    },
+    "node_modules/express": {
+      "version": "4.17.1",
+      "dependencies": {"debug": "2.6.9"},
+      "optionalDependencies": {"fsevents": "~2.3.2"}
+    },
+    "node_modules/debug": {"version": "2.6.9", "dependencies": {"ms": "2.0.0"}},
+    "node_modules/ms": {"version": "2.0.0"},
+    "node_modules/fsevents": {"version": "2.3.2", "optional": true},
+    "node_modules/jest": {
+      "version": "27.0.6",
+      "dev": true,
+      "dependencies": {"debug": "^4.3.1", "@types/node": "*"}
+    },
+    "node_modules/jest/node_modules/debug": {"version": "4.3.2", "dev": true, "dependencies": {"ms": "2.1.2"}},
+    "node_modules/jest/node_modules/ms": {"version": "2.1.2", "dev": true},
+    "node_modules/@types/node": {"version": "16.4.0", "dev": true}
+  }
+}`
+
+const packageLockV1 = `{
+  "name": "example",
+  "lockfileVersion": 1,
+  "dependencies": {
+    "express": {"version": "4.17.1", "requires": {"debug": "2.6.9"}},
+    "debug": {
+      "version": "2.6.9",
+      "requires": {"ms": "2.0.0"},
+      "dependencies": {"ms": {"version": "2.0.0"}}
+    }
+  }
+}`
+
+const yarnLockClassic = `# THIS IS AN AUTOGENERATED FILE. DO NOT EDIT THIS FILE DIRECTLY.
+# yarn lockfile v1
+
+
+"@babel/code-frame@^7.0.0":
+  version "7.12.13"
+  resolved "https://registry.yarnpkg.com/@babel/code-frame/-/code-frame-7.12.13.tgz"
+  dependencies:
+    "@babel/highlight" "^7.12.13"
+
+"@babel/highlight@^7.10.4", "@babel/highlight@^7.12.13":
+  version "7.14.5"
+  dependencies:
+    chalk "^2.0.0"
+    js-tokens "^4.0.0"
+
+chalk@^2.0.0:
+  version "2.4.2"
+
+js-tokens@^4.0.0:
+  version "4.0.0"
+`
+
+const yarnLockBerry = `__metadata:
+  version: 4
+  cacheKey: 8
+
+"@babel/code-frame@npm:^7.0.0":
+  version: 7.12.13
+  resolution: "@babel/code-frame@npm:7.12.13"
+  dependencies:
+    "@babel/highlight": ^7.12.13
+
+"@babel/highlight@npm:^7.10.4, @babel/highlight@npm:^7.12.13":
+  version: 7.14.5
+  resolution: "@babel/highlight@npm:7.14.5"
+  dependencies:
+    chalk: ^2.0.0
+    js-tokens: ^4.0.0
+
+"chalk@npm:^2.0.0":
+  version: 2.4.2
+  resolution: "chalk@npm:2.4.2"
+
+"js-tokens@npm:^4.0.0":
+  version: 4.0.0
+  resolution: "js-tokens@npm:4.0.0"
+
+"example@workspace:.":
+  version: 0.0.0-use.local
+  resolution: "example@workspace:."
+  dependencies:
+    "@babel/code-frame": ^7.0.0
+  languageName: unknown
+  linkType: soft
+`
+
+const pnpmLockV5 = `lockfileVersion: 5.3
+
+specifiers:
+  express: ^4.17.1
+  typescript: ^4.3.0
+
+dependencies:
+  express: 4.17.1
+
+devDependencies:
+  typescript: 4.3.5
+
+packages:
+
+  /express/4.17.1:
+    dependencies:
+      debug: 2.6.9
+    dev: false
+
+  /debug/2.6.9:
+    dependencies:
+      ms: 2.0.0
+    dev: false
+
+  /ms/2.0.0:
+    dev: false
+
+  /typescript/4.3.5:
+    dev: true
+`
+
+const pnpmLockV6 = `lockfileVersion: '6.0'
+
+dependencies:
+  express:
+    specifier: ^4.17.1
+    version: 4.17.1
+
+devDependencies:
+  typescript:
+    specifier: ^4.3.0
+    version: 4.3.5
+
+packages:
+
+  /express@4.17.1:
+    dependencies:
+      debug: 2.6.9
+    dev: false
+
+  /debug@2.6.9:
+    dependencies:
+      ms: 2.0.0
+    dev: false
+
+  /ms@2.0.0:
+    dev: false
+
+  /typescript@4.3.5:
+    dev: true
+`
+
+const pnpmLockV9 = `lockfileVersion: '9.0'
+
+importers:
+
+  .:
+    dependencies:
+      express:
+        specifier: ^4.17.1
+        version: 4.17.1
+    devDependencies:
+      typescript:
+        specifier: ^4.3.0
+        version: 4.3.5
+
+packages:
+
+  express@4.17.1:
+    resolution: {integrity: sha512-a}
+
+  debug@2.6.9:
+    resolution: {integrity: sha512-b}
+
+  ms@2.0.0:
+    resolution: {integrity: sha512-c}
+
+  typescript@4.3.5:
+    resolution: {integrity: sha512-d}
+
+snapshots:
+
+  express@4.17.1:
+    dependencies:
+      debug: 2.6.9
+
+  debug@2.6.9:
+    dependencies:
+      ms: 2.0.0
+
+  ms@2.0.0: {}
+
+  typescript@4.3.5: {}
+`
+
+const requirementsTxt = `# production requirements
+-r base.txt
+--index-url https://pypi.example.com/simple
+requests[security] >=2.8.1,<3 ; python_version > '3.6'
+Django_Rest.Framework==3.12.4 \
+    --hash=sha256:abc
+flask==2.0.1  # web
+numpy
+-e git+https://github.com/example/editable.git#egg=editable
+mylib @ https://example.com/mylib-1.0.tar.gz
+`
+
+const pipfileLockJSON = `{
+  "_meta": {"hash": {"sha256": "abc"}},
+  "default": {
+    "requests": {"hashes": ["sha256:a"], "version": "==2.26.0"},
+    "certifi": {"hashes": ["sha256:b"], "version": "==2021.5.30"}
+  },
+  "develop": {
+    "pytest": {"hashes": ["sha256:c"], "version": "==6.2.4"}
+  }
+}`
+
+const poetryLock = `[[package]]
+name = "certifi"
+version = "2021.5.30"
+description = "Python package for providing Mozilla's CA Bundle."
+category = "main"
+optional = false
+python-versions = "*"
+
+[[package]]
+name = "charset-normalizer"
+version = "2.0.4"
+category = "main"
+optional = false
+
+[[package]]
+name = "PySocks"
+version = "1.7.1"
+category = "main"
+optional = true
+
+[[package]]
+name = "pytest"
+version = "6.2.4"
+category = "dev"
+optional = false
+
+[[package]]
+name = "requests"
+version = "2.26.0"
+category = "main"
+optional = false
+
+[package.dependencies]
+certifi = ">=2017.4.17"
+charset-normalizer = {version = ">=2.0.0,<2.1.0", markers = "python_version >= \"3\""}
+PySocks = {version = ">=1.5.6, !=1.5.7", optional = true}
+
+[package.extras]
+socks = ["PySocks (>=1.5.6, !=1.5.7)"]
+
+[metadata]
+lock-version = "1.1"
+python-versions = "^3.8"
+content-hash = "abc"
+
+[metadata.files]
+certifi = [
+    {file = "certifi-2021.5.30-py2.py3-none-any.whl", hash = "sha256:a"},
+    {file = "certifi-2021.5.30.tar.gz", hash = "sha256:b"},
+]
+`
+
+const gemfileLock = `GIT
+  remote: https://github.com/rails/rails.git
+  revision: abc
+  specs:
+    actionpack (6.1.4)
+      rack (~> 2.0, >= 2.0.9)
+
+GEM
+  remote: https://rubygems.org/
+  specs:
+    nokogiri (1.12.5-x86_64-linux)
+      racc (~> 1.4)
+    nokogiri (1.12.5-x86_64-darwin)
+      racc (~> 1.4)
+    racc (1.5.2)
+    rack (2.2.3)
+    rails (6.1.4)
+      actionpack (= 6.1.4)
+      rake (>= 12.2)
+    rake (13.0.6)
+
+PLATFORMS
+  x86_64-linux
+
+DEPENDENCIES
+  nokogiri!
+  rails (~> 6.1)!
+  rake
+
+BUNDLED WITH
+   2.2.22
+`
+
+const pomXML = `<?xml version="1.0" encoding="UTF-8"?>
+<project xmlns="http://maven.apache.org/POM/4.0.0">
+  <modelVersion>4.0.0</modelVersion>
+  <parent>
+    <groupId>com.example</groupId>
+    <artifactId>parent</artifactId>
+    <version>1.0.0-SNAPSHOT</version>
+  </parent>
+  <artifactId>app</artifactId>
+  <properties>
+    <log4j.version>2.17.1</log4j.version>
+    <junit.version>4.13.2</junit.version>
+  </properties>
+  <dependencyManagement>
+    <dependencies>
+      <dependency>
+        <groupId>junit</groupId>
+        <artifactId>junit</artifactId>
+        <version>${junit.version}</version>
+      </dependency>
+    </dependencies>
+  </dependencyManagement>
+  <dependencies>
+    <dependency>
+      <groupId>org.apache.logging.log4j</groupId>
+      <artifactId>log4j-core</artifactId>
+      <version>${log4j.version}</version>
+    </dependency>
+    <dependency>
+      <groupId>${project.groupId}</groupId>
+      <artifactId>widgets</artifactId>
+      <version>${project.version}</version>
+    </dependency>
+    <dependency>
+      <groupId>junit</groupId>
+      <artifactId>junit</artifactId>
+      <scope>test</scope>
+    </dependency>
+    <dependency>
+      <groupId>com.google.guava</groupId>
+      <artifactId>guava</artifactId>
+      <version>[30.0-jre,)</version>
+    </dependency>
+    <dependency>
+      <groupId>org.slf4j</groupId>
+      <artifactId>slf4j-api</artifactId>
+      <version>1.7.32</version>
+      <optional>true</optional>
+    </dependency>
+  </dependencies>
+  <build>
+    <plugins>
+      <plugin>
+        <artifactId>maven-compiler-plugin</artifactId>
+        <dependencies>
+          <dependency>
+            <groupId>org.ow2.asm</groupId>
+            <artifactId>asm</artifactId>
+            <version>9.2</version>
+          </dependency>
+        </dependencies>
+      </plugin>
+    </plugins>
+  </build>
+</project>`
+
+const cargoLock = `# This file is automatically @generated by Cargo.
+# It is not intended for manual editing.
+version = 3
+
+[[package]]
+name = "example"
+version = "0.1.0"
+dependencies = [
+ "rand",
+ "serde",
+]
+
+[[package]]
+name = "getrandom"
+version = "0.2.3"
+source = "registry+https://github.com/rust-lang/crates.io-index"
+checksum = "abc"
+dependencies = [
+ "libc",
+]
+
+[[package]]
+name = "libc"
+version = "0.2.101"
+source = "registry+https://github.com/rust-lang/crates.io-index"
+
+[[package]]
+name = "rand"
+version = "0.8.4"
+source = "registry+https://github.com/rust-lang/crates.io-index"
+dependencies = [
+ "libc",
+ "rand_core 0.6.3",
+]
+
+[[package]]
+name = "rand_core"
+version = "0.5.1"
+source = "registry+https://github.com/rust-lang/crates.io-index"
+
+[[package]]
+name = "rand_core"
+version = "0.6.3"
+source = "registry+https://github.com/rust-lang/crates.io-index"
+dependencies = [
+ "getrandom",
+]
+
+[[package]]
+name = "serde"
+version = "1.0.130"
+source = "registry+https://github.com/rust-lang/crates.io-index"
+`
+
+const nugetLockJSON = `{
+  "version": 1,
+  "dependencies": {
+    "net5.0": {
+      "Newtonsoft.Json": {
+        "type": "Direct",
+        "requested": "[13.0.1, )",
+        "resolved": "13.0.1",
+        "contentHash": "a"
+      },
+      "Serilog": {
+        "type": "Direct",
+        "requested": "[2.10.0, )",
+        "resolved": "2.10.0",
+        "dependencies": {"System.Memory": "4.5.0"}
+      },
+      "System.Memory": {"type": "Transitive", "resolved": "4.5.4"},
+      "Example.Core": {"type": "Project"}
+    },
+    "net6.0": {
+      "Newtonsoft.Json": {
+        "type": "Direct",
+        "requested": "[13.0.1, )",
+        "resolved": "13.0.1"
+      }
+    }
+  }
+}`
+
+const tomlDocument = `# a document
+title = "a \"quoted\" \u00e9"
+literal = 'C:\path'
+multiline = """
+one
+two"""
+enabled = true
+count = 3
+
+[[package]]
+files = [
+  {file = "a.whl", hash = "sha256:1"}, # a wheel
+  "b",
+]
+
+[[package]]
+name = "requests"
+
+[package.extras]
+socks = ["PySocks (>=1.5.6)"]
+
+[site.owner]
+name = "Tom"
+`
@@ -0,0 +1,122 @@
+package lockfiles
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+
+	"github.com/ion-channel/ionic/dependencies"
+	"github.com/ion-channel/ionic/purl"
+)
+
+var pomProperty = regexp.MustCompile(`\$\{([^}]+)\}`)
+
+type pom struct {
+	GroupID    string `xml:"groupId"`
+	ArtifactID string `xml:"artifactId"`
+	Version    string `xml:"version"`
+	Parent     struct {
+		GroupID string `xml:"groupId"`
+		Version string `xml:"version"`
+	} `xml:"parent"`
+	Properties struct {
+		Entries []struct {
+			XMLName xml.Name
+			Value   string `xml:",chardata"`
+		} `xml:",any"`
+	} `xml:"properties"`
+	DependencyManagement []pomDependency `xml:"dependencyManagement>dependencies>dependency"`
+	Dependencies         []pomDependency `xml:"dependencies>dependency"`
+}
+
+type pomDependency struct {
+	GroupID    string `xml:"groupId"`
+	ArtifactID string `xml:"artifactId"`
+	Version    string `xml:"version"`
+	Scope      string `xml:"scope"`
+	Optional   string `xml:"optional"`
+}
+
+// ParsePOM parses the dependencies declared by a Maven pom.xml file. Versions
+// are taken from the properties and dependency management of the pom, but not
+// of its parent, and dependencies declared with a version range are listed
+// without a version. pom.xml files do not declare the dependencies of their
+// dependencies, so the tree is flat.
+func ParsePOM(r io.Reader) (*dependencies.DependencyResolutionResponse, error) {
+	var p pom
+	err := xml.NewDecoder(r).Decode(&p)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse pom: %v", err.Error())
+	}
+
+	props := map[string]string{
+		"project.groupId":        p.GroupID,
+		"project.artifactId":     p.ArtifactID,
+		"project.version":        p.Version,
+		"project.parent.groupId": p.Parent.GroupID,
+		"project.parent.version": p.Parent.Version,
+	}
+
+	if props["project.groupId"] == "" {
+		props["project.groupId"] = p.Parent.GroupID
+	}
+
+	if props["project.version"] == "" {
+		props["project.version"] = p.Parent.Version
+	}
+
+	for _, entry := range p.Properties.Entries {
+		props[entry.XMLName.Local] = strings.TrimSpace(entry.Value)
+	}
+
+	expand := func(s string) string {
+		// properties may refer to other properties, but not endlessly
+		for i := 0; i < 10 && strings.Contains(s, "${"); i++ {
+			s = pomProperty.ReplaceAllStringFunc(s, func(ref string) string {
+				if v, ok := props[ref[2:len(ref)-1]]; ok {
+					return v
+				}
+
+				return ref
+			})
+		}
+
+		return strings.TrimSpace(s)
+	}
+
+	managed := map[string]string{}
+	for _, d := range p.DependencyManagement {
+		managed[expand(d.GroupID)+":"+expand(d.ArtifactID)] = expand(d.Version)
+	}
+
+	roots := []dependencies.Dependency{}
+	for _, d := range p.Dependencies {
+		name := expand(d.GroupID) + ":" + expand(d.ArtifactID)
+		requirement := expand(d.Version)
+		if requirement == "" {
+			requirement = managed[name]
+		}
+
+		version := requirement
+		if strings.HasPrefix(version, "[") || strings.HasPrefix(version, "(") || strings.Contains(version, "${") {
+			version = ""
+		}
+
+		dep := newDependency(purl.TypeMaven, name, version)
+		dep.Requirement = requirement
+		dep.Scope = expand(d.Scope)
+		if dep.Scope == "" {
+			dep.Scope = "compile"
+		}
+
+		if expand(d.Optional) == "true" {
+			dep.Scope = ScopeOptional
+		}
+
+		roots = append(roots, dep)
+	}
+
+	return newResponse(roots), nil
+}
@@ -0,0 +1,224 @@
+package lockfiles
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/ion-channel/ionic/dependencies"
+	"github.com/ion-channel/ionic/purl"
+)
+
+type packageLock struct {
+	LockfileVersion int                           `json:"lockfileVersion"`
+	Packages        map[string]packageLockPackage `json:"packages"`
+	Dependencies    map[string]packageLockPackage `json:"dependencies"`
+}
+
+type packageLockPackage struct {
+	Name     string `json:"name"`
+	Version  string `json:"version"`
+	Resolved string `json:"resolved"`
+	Link     bool   `json:"link"`
+	Dev      bool   `json:"dev"`
+	Optional bool   `json:"optional"`
+	// Requires are the requirements of a version 1 lockfile package
+	Requires map[string]string `json:"requires"`
+	// Dependencies are the nested packages of a version 1 lockfile package,
+	// and the requirements of a version 2 or 3 lockfile package
+	Dependencies         json.RawMessage   `json:"dependencies"`
+	DevDependencies      map[string]string `json:"devDependencies"`
+	OptionalDependencies map[string]string `json:"optionalDependencies"`
+	PeerDependencies     map[string]string `json:"peerDependencies"`
+
+	requirements map[string]string
+}
+
+// ParsePackageLock parses a package-lock.json or npm-shrinkwrap.json file of
+// any lockfile version. The direct dependencies of version 1 lockfiles, which
+// do not list them, are taken to be the packages no other package requires.
+func ParsePackageLock(r io.Reader) (*dependencies.DependencyResolutionResponse, error) {
+	var lock packageLock
+	err := json.NewDecoder(r).Decode(&lock)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse package lock: %v", err.Error())
+	}
+
+	packages := lock.Packages
+	if packages == nil {
+		packages = map[string]packageLockPackage{}
+		err = flattenPackageLock("", lock.Dependencies, packages)
+		if err != nil {
+			return nil, err
+		}
+	} else {
+		for path, pkg := range packages {
+			if len(pkg.Dependencies) > 0 {
+				err := json.Unmarshal(pkg.Dependencies, &pkg.requirements)
+				if err != nil {
+					return nil, fmt.Errorf("failed to parse package lock dependencies: %v", err.Error())
+				}
+
+				packages[path] = pkg
+			}
+		}
+	}
+
+	nodes := map[string]*node{}
+	for path, pkg := range packages {
+		if path == "" || pkg.Link {
+			continue
+		}
+
+		name := pkg.Name
+		if i := strings.LastIndex(path, "node_modules/"); i >= 0 {
+			name = path[i+len("node_modules/"):]
+		}
+
+		if name == "" {
+			continue
+		}
+
+		dep := newDependency(purl.TypeNPM, name, pkg.Version)
+		dep.Scope = npmScope(pkg.Dev, pkg.Optional)
+
+		requires := []edge{}
+		for _, req := range npmRequirements(pkg, false) {
+			if key, ok := resolveNodeModule(packages, path, req.key); ok {
+				req.key = key
+				requires = append(requires, req)
+			}
+		}
+
+		nodes[path] = &node{dep: dep, requires: requires}
+	}
+
+	var roots []edge
+	if root, ok := packages[""]; ok {
+		for _, req := range npmRequirements(root, true) {
+			if key, ok := resolveNodeModule(packages, "", req.key); ok {
+				req.key = key
+				roots = append(roots, req)
+			}
+		}
+	} else {
+		roots = unrequired(nodes)
+	}
+
+	return newResponse(buildTree(nodes, roots)), nil
+}
+
+// flattenPackageLock adds the nested packages of a version 1 lockfile to the
+// given packages, keyed by their path in node_modules as they are in later
+// lockfile versions
+func flattenPackageLock(dir string, deps map[string]packageLockPackage, packages map[string]packageLockPackage) error {
+	for name, pkg := range deps {
+		path := dir + "node_modules/" + name
+		pkg.requirements = pkg.Requires
+		packages[path] = pkg
+
+		if len(pkg.Dependencies) > 0 {
+			var nested map[string]packageLockPackage
+			err := json.Unmarshal(pkg.Dependencies, &nested)
+			if err != nil {
+				return fmt.Errorf("failed to parse package lock dependencies: %v", err.Error())
+			}
+
+			err = flattenPackageLock(path+"/", nested, packages)
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// resolveNodeModule returns the path of the package that the package at the
+// given path requires by name, found the way Node.js finds it: in the
+// node_modules of the package, then of each of its parents. Links are
+// followed to the packages they resolve to.
+func resolveNodeModule(packages map[string]packageLockPackage, from, name string) (string, bool) {
+	dir := from
+	for {
+		path := "node_modules/" + name
+		if dir != "" {
+			path = dir + "/" + path
+		}
+
+		if pkg, ok := packages[path]; ok {
+			if pkg.Link {
+				_, ok := packages[pkg.Resolved]
+				return pkg.Resolved, ok
+			}
+
+			return path, true
+		}
+
+		if dir == "" {
+			return "", false
+		}
+
+		i := strings.LastIndex(dir, "node_modules/")
+		if i < 0 {
+			dir = ""
+			continue
+		}
+
+		dir = strings.TrimSuffix(dir[:i], "/")
+	}
+}
+
+// npmRequirements returns the requirements of a package by name, sorted by
+// name. Development dependencies are only installed for the project, and
+// peer dependencies are listed when they are not also regular dependencies.
+func npmRequirements(pkg packageLockPackage, root bool) []edge {
+	reqs := []edge{}
+	add := func(deps map[string]string, scope string) {
+		for _, name := range sortedKeys(deps) {
+			reqs = append(reqs, edge{key: name, requirement: deps[name], scope: scope})
+		}
+	}
+
+	add(pkg.requirements, "")
+	add(pkg.OptionalDependencies, ScopeOptional)
+	if root {
+		add(pkg.DevDependencies, ScopeDevelopment)
+	}
+
+	for _, name := range sortedKeys(pkg.PeerDependencies) {
+		if _, ok := pkg.requirements[name]; !ok {
+			reqs = append(reqs, edge{key: name, requirement: pkg.PeerDependencies[name], scope: ScopePeer})
+		}
+	}
+
+	return reqs
+}
+
+func npmScope(dev, optional bool) string {
+	switch {
+	case dev:
+		return ScopeDevelopment
+	case optional:
+		return ScopeOptional
+	}
+
+	return ""
+}
+
+// splitSpec splits a package specifier such as "@babel/core@^7.0.0" into its
+// name and range. The range may itself name a package, as in
+// "string-width@npm:string-width@^4.2.0".
+func splitSpec(spec string) (string, string) {
+	if spec == "" {
+		return "", ""
+	}
+
+	i := strings.Index(spec[1:], "@") + 1
+	if i == 0 {
+		return spec, ""
+	}
+
+	return spec[:i], spec[i+1:]
+}
@@ -0,0 +1,90 @@
+package lockfiles
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/ion-channel/ionic/dependencies"
+	"github.com/ion-channel/ionic/purl"
+)
+
+type nugetLock struct {
+	Dependencies map[string]map[string]nugetLockPackage `json:"dependencies"`
+}
+
+type nugetLockPackage struct {
+	Type         string            `json:"type"`
+	Requested    string            `json:"requested"`
+	Resolved     string            `json:"resolved"`
+	Dependencies map[string]string `json:"dependencies"`
+}
+
+// ParseNuGetLock parses a NuGet packages.lock.json file. The packages of
+// every target framework are listed together, and references to other
+// projects are left out.
+func ParseNuGetLock(r io.Reader) (*dependencies.DependencyResolutionResponse, error) {
+	var lock nugetLock
+	err := json.NewDecoder(r).Decode(&lock)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse NuGet lock: %v", err.Error())
+	}
+
+	frameworks := make([]string, 0, len(lock.Dependencies))
+	for framework := range lock.Dependencies {
+		frameworks = append(frameworks, framework)
+	}
+
+	sort.Strings(frameworks)
+
+	nodes := map[string]*node{}
+	roots := []edge{}
+	direct := map[string]bool{}
+	for _, framework := range frameworks {
+		packages := map[string]nugetLockPackage{}
+		for name, pkg := range lock.Dependencies[framework] {
+			packages[strings.ToLower(name)] = pkg
+		}
+
+		key := func(name string) string {
+			return strings.ToLower(name) + "@" + packages[strings.ToLower(name)].Resolved
+		}
+
+		names := make([]string, 0, len(lock.Dependencies[framework]))
+		for name := range lock.Dependencies[framework] {
+			names = append(names, name)
+		}
+
+		sort.Strings(names)
+		for _, name := range names {
+			pkg := lock.Dependencies[framework][name]
+			if strings.EqualFold(pkg.Type, "Project") {
+				continue
+			}
+
+			k := key(name)
+			if _, ok := nodes[k]; !ok {
+				requires := []edge{}
+				for _, child := range sortedKeys(pkg.Dependencies) {
+					if p, ok := packages[strings.ToLower(child)]; ok && !strings.EqualFold(p.Type, "Project") {
+						requires = append(requires, edge{key: key(child), requirement: pkg.Dependencies[child]})
+					}
+				}
+
+				nodes[k] = &node{
+					dep:      newDependency(purl.TypeNuGet, name, pkg.Resolved),
+					requires: requires,
+				}
+			}
+
+			if strings.EqualFold(pkg.Type, "Direct") && !direct[k] {
+				direct[k] = true
+				roots = append(roots, edge{key: k, requirement: pkg.Requested})
+			}
+		}
+	}
+
+	return newResponse(buildTree(nodes, roots)), nil
+}
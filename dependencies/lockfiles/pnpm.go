@@ -0,0 +1,213 @@
+package lockfiles
+
+import (
+	"fmt"
+	"io"
+	"io/ioutil"
+	"sort"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v2"
+
+	"github.com/ion-channel/ionic/dependencies"
+	"github.com/ion-channel/ionic/purl"
+)
+
+type pnpmLock struct {
+	LockfileVersion string                  `yaml:"lockfileVersion"`
+	Importers       map[string]pnpmImporter `yaml:"importers"`
+	Project         pnpmImporter            `yaml:",inline"`
+	Packages        map[string]pnpmPackage  `yaml:"packages"`
+	Snapshots       map[string]pnpmPackage  `yaml:"snapshots"`
+}
+
+// pnpmImporter is a project of a pnpm workspace
+type pnpmImporter struct {
+	Specifiers           map[string]string   `yaml:"specifiers"`
+	Dependencies         map[string]pnpmSpec `yaml:"dependencies"`
+	DevDependencies      map[string]pnpmSpec `yaml:"devDependencies"`
+	OptionalDependencies map[string]pnpmSpec `yaml:"optionalDependencies"`
+}
+
+type pnpmPackage struct {
+	Name                 string            `yaml:"name"`
+	Version              string            `yaml:"version"`
+	Dev                  bool              `yaml:"dev"`
+	Optional             bool              `yaml:"optional"`
+	Dependencies         map[string]string `yaml:"dependencies"`
+	OptionalDependencies map[string]string `yaml:"optionalDependencies"`
+}
+
+// pnpmSpec is a dependency of a project: its resolved version in lockfiles
+// before version 6, and its specifier and version after
+type pnpmSpec struct {
+	Specifier string `yaml:"specifier"`
+	Version   string `yaml:"version"`
+}
+
+// UnmarshalYAML unmarshals a dependency of either a version or a specifier
+// and version
+func (s *pnpmSpec) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	var version string
+	if err := unmarshal(&version); err == nil {
+		s.Version = version
+		return nil
+	}
+
+	type spec pnpmSpec
+	return unmarshal((*spec)(s))
+}
+
+// ParsePNPMLock parses a pnpm-lock.yaml file of lockfile version 5 or later.
+// The dependencies of every project of a workspace are listed together.
+func ParsePNPMLock(r io.Reader) (*dependencies.DependencyResolutionResponse, error) {
+	b, err := ioutil.ReadAll(r)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read pnpm lock: %v", err.Error())
+	}
+
+	var lock pnpmLock
+	err = yaml.Unmarshal(b, &lock)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse pnpm lock: %v", err.Error())
+	}
+
+	// lockfiles before version 6 key packages like "/name/1.0.0"
+	major, _ := strconv.Atoi(strings.SplitN(lock.LockfileVersion, ".", 2)[0])
+	slashed := major > 0 && major < 6
+
+	// lockfiles since version 9 list the dependencies of packages in
+	// snapshots, keyed by package and peer dependencies
+	packages := map[string]pnpmPackage{}
+	snapshotted := map[string]bool{}
+	for key, snapshot := range lock.Snapshots {
+		packages[key] = snapshot
+		snapshotted[strings.SplitN(key, "(", 2)[0]] = true
+	}
+
+	for key, pkg := range lock.Packages {
+		if snapshot, ok := packages[key]; ok {
+			snapshot.Name, snapshot.Version = pkg.Name, pkg.Version
+			packages[key] = snapshot
+		} else if !snapshotted[key] {
+			packages[key] = pkg
+		}
+	}
+
+	resolve := func(name, ref string) (string, bool) {
+		for _, key := range []string{ref, name + "@" + ref, "/" + name + "@" + ref, "/" + name + "/" + ref} {
+			if _, ok := packages[key]; ok {
+				return key, true
+			}
+		}
+
+		return "", false
+	}
+
+	nodes := map[string]*node{}
+	for key, pkg := range packages {
+		name, version := splitPNPMKey(key, slashed)
+		if pkg.Name != "" {
+			name = pkg.Name
+		}
+
+		if pkg.Version != "" {
+			version = pkg.Version
+		}
+
+		dep := newDependency(purl.TypeNPM, name, version)
+		dep.Scope = npmScope(pkg.Dev, pkg.Optional)
+
+		requires := []edge{}
+		for _, reqs := range []struct {
+			deps  map[string]string
+			scope string
+		}{{pkg.Dependencies, ""}, {pkg.OptionalDependencies, ScopeOptional}} {
+			for _, name := range sortedKeys(reqs.deps) {
+				if child, ok := resolve(name, reqs.deps[name]); ok {
+					requires = append(requires, edge{key: child, scope: reqs.scope})
+				}
+			}
+		}
+
+		nodes[key] = &node{dep: dep, requires: requires}
+	}
+
+	importers := lock.Importers
+	if len(importers) == 0 {
+		importers = map[string]pnpmImporter{".": lock.Project}
+	}
+
+	roots := []edge{}
+	for _, path := range importerPaths(importers) {
+		importer := importers[path]
+		for _, reqs := range []struct {
+			deps  map[string]pnpmSpec
+			scope string
+		}{
+			{importer.Dependencies, ""},
+			{importer.OptionalDependencies, ScopeOptional},
+			{importer.DevDependencies, ScopeDevelopment},
+		} {
+			for _, name := range pnpmNames(reqs.deps) {
+				spec := reqs.deps[name]
+				if spec.Specifier == "" {
+					spec.Specifier = importer.Specifiers[name]
+				}
+
+				if key, ok := resolve(name, spec.Version); ok {
+					roots = append(roots, edge{key: key, requirement: spec.Specifier, scope: reqs.scope})
+				}
+			}
+		}
+	}
+
+	return newResponse(buildTree(nodes, roots)), nil
+}
+
+// splitPNPMKey returns the name and version of a package key such as
+// "/@babel/core/7.15.0_supports-color@8.1.1" before lockfile version 6,
+// "/@babel/core@7.15.0(supports-color@8.1.1)" in version 6, or
+// "@babel/core@7.15.0" in version 9, without its peer dependencies
+func splitPNPMKey(key string, slashed bool) (string, string) {
+	key = strings.TrimPrefix(key, "/")
+	if i := strings.Index(key, "("); i >= 0 {
+		key = key[:i]
+	}
+
+	if !slashed {
+		return splitSpec(key)
+	}
+
+	if i := strings.Index(key, "_"); i >= 0 {
+		key = key[:i]
+	}
+
+	i := strings.LastIndex(key, "/")
+	if i < 0 {
+		return key, ""
+	}
+
+	return key[:i], key[i+1:]
+}
+
+func importerPaths(importers map[string]pnpmImporter) []string {
+	paths := make([]string, 0, len(importers))
+	for path := range importers {
+		paths = append(paths, path)
+	}
+
+	sort.Strings(paths)
+	return paths
+}
+
+func pnpmNames(deps map[string]pnpmSpec) []string {
+	names := make([]string, 0, len(deps))
+	for name := range deps {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+	return names
+}
@@ -0,0 +1,220 @@
+package lockfiles
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/ion-channel/ionic/dependencies"
+	"github.com/ion-channel/ionic/purl"
+)
+
+var pythonRequirement = regexp.MustCompile(`^([A-Za-z0-9][A-Za-z0-9._-]*)\s*(\[[^\]]*\])?\s*(.*)$`)
+
+type pipfileLock struct {
+	Default map[string]pipfileLockPackage `json:"default"`
+	Develop map[string]pipfileLockPackage `json:"develop"`
+}
+
+type pipfileLockPackage struct {
+	Version string `json:"version"`
+}
+
+// ParseRequirements parses the requirements of a pip requirements file, such
+// as "requests[security]>=2.8.1,<3 ; python_version > '3.6'". Requirements are
+// versioned when they pin an exact version. Options, such as other files to
+// include or editable installs, are ignored.
+func ParseRequirements(r io.Reader) (*dependencies.DependencyResolutionResponse, error) {
+	roots := []dependencies.Dependency{}
+
+	line := ""
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		text := scanner.Text()
+		if strings.HasSuffix(text, `\`) {
+			line += strings.TrimSuffix(text, `\`) + " "
+			continue
+		}
+
+		line, text = "", line+text
+		if strings.HasPrefix(strings.TrimSpace(text), "#") {
+			continue
+		}
+
+		if i := strings.Index(text, " #"); i >= 0 {
+			text = text[:i]
+		}
+
+		if i := strings.Index(text, " --"); i >= 0 {
+			text = text[:i]
+		}
+
+		if i := strings.Index(text, ";"); i >= 0 {
+			text = text[:i]
+		}
+
+		text = strings.TrimSpace(text)
+		if text == "" || strings.HasPrefix(text, "-") {
+			continue
+		}
+
+		m := pythonRequirement.FindStringSubmatch(text)
+		if m == nil {
+			return nil, fmt.Errorf("invalid requirement: %v", text)
+		}
+
+		requirement := strings.TrimSpace(strings.TrimPrefix(strings.TrimSpace(m[3]), "@"))
+		dep := newDependency(purl.TypePyPI, m[1], pinnedVersion(requirement))
+		dep.Requirement = requirement
+		roots = append(roots, dep)
+	}
+
+	err := scanner.Err()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read requirements: %v", err.Error())
+	}
+
+	return newResponse(roots), nil
+}
+
+// ParsePipfileLock parses the default and development packages of a
+// Pipfile.lock file. Pipfile.lock files do not record which packages require
+// each other, so the tree is flat.
+func ParsePipfileLock(r io.Reader) (*dependencies.DependencyResolutionResponse, error) {
+	var lock pipfileLock
+	err := json.NewDecoder(r).Decode(&lock)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse Pipfile lock: %v", err.Error())
+	}
+
+	roots := []dependencies.Dependency{}
+	for _, section := range []struct {
+		packages map[string]pipfileLockPackage
+		scope    string
+	}{{lock.Default, ""}, {lock.Develop, ScopeDevelopment}} {
+		names := make([]string, 0, len(section.packages))
+		for name := range section.packages {
+			names = append(names, name)
+		}
+
+		sort.Strings(names)
+		for _, name := range names {
+			pkg := section.packages[name]
+			dep := newDependency(purl.TypePyPI, name, pinnedVersion(pkg.Version))
+			dep.Requirement = pkg.Version
+			dep.Scope = section.scope
+			roots = append(roots, dep)
+		}
+	}
+
+	return newResponse(roots), nil
+}
+
+// ParsePoetryLock parses a poetry.lock file. The direct dependencies, which
+// poetry.lock files do not list, are taken to be the packages no other
+// package requires.
+func ParsePoetryLock(r io.Reader) (*dependencies.DependencyResolutionResponse, error) {
+	b, err := ioutil.ReadAll(r)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read poetry lock: %v", err.Error())
+	}
+
+	lock, err := parseTOML(string(b))
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse poetry lock: %v", err.Error())
+	}
+
+	nodes := map[string]*node{}
+	for _, pkg := range lock.tables("package") {
+		dep := newDependency(purl.TypePyPI, pkg.str("name"), pkg.str("version"))
+		if pkg.str("category") == "dev" {
+			dep.Scope = ScopeDevelopment
+		} else if optional, _ := pkg["optional"].(bool); optional {
+			dep.Scope = ScopeOptional
+		}
+
+		requires := []edge{}
+		deps, _ := pkg["dependencies"].(tomlTable)
+		for _, name := range tomlKeys(deps) {
+			requirement, optional := poetryRequirement(deps[name])
+			e := edge{key: normalizePythonName(name), requirement: requirement}
+			if optional {
+				e.scope = ScopeOptional
+			}
+
+			requires = append(requires, e)
+		}
+
+		nodes[normalizePythonName(pkg.str("name"))] = &node{dep: dep, requires: requires}
+	}
+
+	return newResponse(buildTree(nodes, unrequired(nodes))), nil
+}
+
+// poetryRequirement returns the version constraint of a poetry.lock
+// dependency, which is either a constraint, a table with a constraint, or an
+// array of those tables for different environments, and whether it is only
+// installed with an extra
+func poetryRequirement(v interface{}) (string, bool) {
+	switch v := v.(type) {
+	case string:
+		return v, false
+	case tomlTable:
+		optional, _ := v["optional"].(bool)
+		return v.str("version"), optional
+	case []interface{}:
+		constraints := []string{}
+		optional := len(v) > 0
+		for _, item := range v {
+			constraint, o := poetryRequirement(item)
+			if constraint != "" {
+				constraints = append(constraints, constraint)
+			}
+
+			optional = optional && o
+		}
+
+		return strings.Join(constraints, " || "), optional
+	}
+
+	return "", false
+}
+
+// pinnedVersion returns the version of a requirement that pins one, such as
+// "==2.25.1", or an empty string
+func pinnedVersion(requirement string) string {
+	requirement = strings.TrimSpace(requirement)
+	if !strings.HasPrefix(requirement, "==") || strings.ContainsAny(requirement, ",*") {
+		return ""
+	}
+
+	return strings.TrimSpace(strings.TrimLeft(requirement, "="))
+}
+
+// normalizePythonName normalizes a Python package name as PEP 503 does
+func normalizePythonName(name string) string {
+	p, err := purl.New(purl.TypePyPI, "", name, "", nil, "")
+	if err != nil {
+		return name
+	}
+
+	return p.Name
+}
+
+func tomlKeys(t tomlTable) []string {
+	keys := make([]string, 0, len(t))
+	for key := range t {
+		keys = append(keys, key)
+	}
+
+	sort.Slice(keys, func(i, j int) bool {
+		return strings.ToLower(keys[i]) < strings.ToLower(keys[j])
+	})
+
+	return keys
+}
@@ -0,0 +1,87 @@
+package lockfiles
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+
+	"github.com/ion-channel/ionic/dependencies"
+	"github.com/ion-channel/ionic/purl"
+)
+
+// gemSpec matches a gem and its version or requirements, such as
+// "actionpack (= 6.0.3)", and the "!" of gems from other sources
+var gemSpec = regexp.MustCompile(`^([^\s!(]+)(?: \(([^)]*)\))?(!)?$`)
+
+// ParseGemfileLock parses the gems of a Gemfile.lock file from any source,
+// whether a gem server, git repository, or path. The versions of gems built
+// for a platform, such as "1.13.1-x86_64-linux", are listed without it.
+func ParseGemfileLock(r io.Reader) (*dependencies.DependencyResolutionResponse, error) {
+	nodes := map[string]*node{}
+	roots := []edge{}
+
+	section := ""
+	specs := false
+	var current *node
+
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := strings.TrimRight(scanner.Text(), " \r")
+		trimmed := strings.TrimLeft(line, " ")
+		if trimmed == "" {
+			continue
+		}
+
+		indent := len(line) - len(trimmed)
+		if indent == 0 {
+			section, specs, current = line, false, nil
+			continue
+		}
+
+		switch section {
+		case "GEM", "GIT", "PATH":
+			if indent == 2 {
+				specs = trimmed == "specs:"
+				continue
+			}
+
+			if !specs {
+				continue
+			}
+
+			m := gemSpec.FindStringSubmatch(trimmed)
+			if m == nil {
+				return nil, fmt.Errorf("invalid Gemfile.lock spec: %v", trimmed)
+			}
+
+			if indent == 4 {
+				version := strings.SplitN(m[2], "-", 2)[0]
+				if _, ok := nodes[m[1]]; ok {
+					current = nil
+					continue
+				}
+
+				current = &node{dep: newDependency(purl.TypeGem, m[1], version)}
+				nodes[m[1]] = current
+			} else if current != nil {
+				current.requires = append(current.requires, edge{key: m[1], requirement: m[2]})
+			}
+		case "DEPENDENCIES":
+			m := gemSpec.FindStringSubmatch(trimmed)
+			if m == nil {
+				return nil, fmt.Errorf("invalid Gemfile.lock dependency: %v", trimmed)
+			}
+
+			roots = append(roots, edge{key: m[1], requirement: m[2]})
+		}
+	}
+
+	err := scanner.Err()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read Gemfile.lock: %v", err.Error())
+	}
+
+	return newResponse(buildTree(nodes, roots)), nil
+}
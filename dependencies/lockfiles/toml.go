@@ -0,0 +1,424 @@
+package lockfiles
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// tomlTable is a table of a TOML document. Values are strings, bools,
+// []interface{} arrays, or tomlTables; arrays of tables are []tomlTable.
+// Numbers and dates are kept as the strings they are written as.
+type tomlTable map[string]interface{}
+
+// tomlParser parses the subset of TOML used by lockfiles such as poetry.lock
+// and Cargo.lock
+type tomlParser struct {
+	s   string
+	pos int
+}
+
+// parseTOML parses a TOML document
+func parseTOML(s string) (tomlTable, error) {
+	p := &tomlParser{s: s}
+	root := tomlTable{}
+	current := root
+
+	for {
+		p.skip(true)
+		if p.pos >= len(p.s) {
+			return root, nil
+		}
+
+		var err error
+		switch {
+		case strings.HasPrefix(p.s[p.pos:], "[["):
+			p.pos += 2
+			current, err = p.header(root, "]]", true)
+		case p.s[p.pos] == '[':
+			p.pos++
+			current, err = p.header(root, "]", false)
+		default:
+			err = p.keyValue(current)
+		}
+
+		if err != nil {
+			return nil, err
+		}
+
+		p.skip(false)
+		if p.pos < len(p.s) && p.s[p.pos] != '\n' && p.s[p.pos] != '\r' {
+			return nil, p.errorf("expected end of line")
+		}
+	}
+}
+
+// header parses the key of a table header, returning the table it opens
+func (p *tomlParser) header(root tomlTable, end string, array bool) (tomlTable, error) {
+	keys, err := p.keys()
+	if err != nil {
+		return nil, err
+	}
+
+	if !strings.HasPrefix(p.s[p.pos:], end) {
+		return nil, p.errorf("unterminated table header")
+	}
+	p.pos += len(end)
+
+	t := root
+	for _, key := range keys[:len(keys)-1] {
+		t, err = p.child(t, key)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	last := keys[len(keys)-1]
+	if !array {
+		return p.child(t, last)
+	}
+
+	tables, ok := t[last].([]tomlTable)
+	if !ok && t[last] != nil {
+		return nil, p.errorf("%v is not an array of tables", last)
+	}
+
+	table := tomlTable{}
+	t[last] = append(tables, table)
+	return table, nil
+}
+
+// child returns the table of a key, creating it if needed. The table of an
+// array of tables is its last.
+func (p *tomlParser) child(t tomlTable, key string) (tomlTable, error) {
+	switch v := t[key].(type) {
+	case nil:
+		child := tomlTable{}
+		t[key] = child
+		return child, nil
+	case tomlTable:
+		return v, nil
+	case []tomlTable:
+		if len(v) > 0 {
+			return v[len(v)-1], nil
+		}
+	}
+
+	return nil, p.errorf("%v is not a table", key)
+}
+
+func (p *tomlParser) keyValue(t tomlTable) error {
+	keys, err := p.keys()
+	if err != nil {
+		return err
+	}
+
+	p.skip(false)
+	if p.pos >= len(p.s) || p.s[p.pos] != '=' {
+		return p.errorf("expected =")
+	}
+	p.pos++
+	p.skip(false)
+
+	value, err := p.value()
+	if err != nil {
+		return err
+	}
+
+	for _, key := range keys[:len(keys)-1] {
+		t, err = p.child(t, key)
+		if err != nil {
+			return err
+		}
+	}
+
+	t[keys[len(keys)-1]] = value
+	return nil
+}
+
+// keys parses a dotted key such as `package."name".version`
+func (p *tomlParser) keys() ([]string, error) {
+	keys := []string{}
+	for {
+		p.skip(false)
+		if p.pos >= len(p.s) {
+			return nil, p.errorf("expected key")
+		}
+
+		var key string
+		switch p.s[p.pos] {
+		case '"', '\'':
+			v, err := p.value()
+			if err != nil {
+				return nil, err
+			}
+
+			key = v.(string)
+		default:
+			start := p.pos
+			for p.pos < len(p.s) && isBareKey(p.s[p.pos]) {
+				p.pos++
+			}
+
+			if p.pos == start {
+				return nil, p.errorf("expected key")
+			}
+
+			key = p.s[start:p.pos]
+		}
+
+		keys = append(keys, key)
+		p.skip(false)
+		if p.pos >= len(p.s) || p.s[p.pos] != '.' {
+			return keys, nil
+		}
+		p.pos++
+	}
+}
+
+func (p *tomlParser) value() (interface{}, error) {
+	if p.pos >= len(p.s) {
+		return nil, p.errorf("expected value")
+	}
+
+	rest := p.s[p.pos:]
+	switch {
+	case strings.HasPrefix(rest, `"""`):
+		return p.multilineString(`"""`, true)
+	case strings.HasPrefix(rest, "'''"):
+		return p.multilineString("'''", false)
+	case rest[0] == '"':
+		return p.basicString()
+	case rest[0] == '\'':
+		end := strings.IndexAny(rest[1:], "'\n")
+		if end < 0 || rest[1+end] != '\'' {
+			return nil, p.errorf("unterminated string")
+		}
+
+		p.pos += end + 2
+		return rest[1 : end+1], nil
+	case rest[0] == '[':
+		return p.array()
+	case rest[0] == '{':
+		return p.inlineTable()
+	case strings.HasPrefix(rest, "true"):
+		p.pos += 4
+		return true, nil
+	case strings.HasPrefix(rest, "false"):
+		p.pos += 5
+		return false, nil
+	}
+
+	end := strings.IndexAny(rest, ",]}#\r\n")
+	if end < 0 {
+		end = len(rest)
+	}
+
+	v := strings.TrimSpace(rest[:end])
+	if v == "" {
+		return nil, p.errorf("expected value")
+	}
+
+	p.pos += end
+	return v, nil
+}
+
+func (p *tomlParser) basicString() (string, error) {
+	var b strings.Builder
+	for p.pos++; p.pos < len(p.s); p.pos++ {
+		c := p.s[p.pos]
+		switch c {
+		case '"':
+			p.pos++
+			return b.String(), nil
+		case '\n':
+			return "", p.errorf("unterminated string")
+		case '\\':
+			err := p.escape(&b)
+			if err != nil {
+				return "", err
+			}
+		default:
+			b.WriteByte(c)
+		}
+	}
+
+	return "", p.errorf("unterminated string")
+}
+
+func (p *tomlParser) multilineString(quotes string, escapes bool) (string, error) {
+	p.pos += len(quotes)
+	if strings.HasPrefix(p.s[p.pos:], "\r\n") {
+		p.pos += 2
+	} else if strings.HasPrefix(p.s[p.pos:], "\n") {
+		p.pos++
+	}
+
+	var b strings.Builder
+	for ; p.pos < len(p.s); p.pos++ {
+		if strings.HasPrefix(p.s[p.pos:], quotes) {
+			p.pos += len(quotes)
+			return b.String(), nil
+		}
+
+		if escapes && p.s[p.pos] == '\\' {
+			// a backslash at the end of a line trims the whitespace after it
+			rest := strings.TrimLeft(p.s[p.pos+1:], " \t")
+			if strings.HasPrefix(rest, "\n") || strings.HasPrefix(rest, "\r\n") {
+				p.pos = len(p.s) - len(strings.TrimLeft(rest, " \t\r\n")) - 1
+				continue
+			}
+
+			err := p.escape(&b)
+			if err != nil {
+				return "", err
+			}
+
+			continue
+		}
+
+		b.WriteByte(p.s[p.pos])
+	}
+
+	return "", p.errorf("unterminated string")
+}
+
+// escape writes the character escaped by the backslash at the position
+func (p *tomlParser) escape(b *strings.Builder) error {
+	p.pos++
+	if p.pos >= len(p.s) {
+		return p.errorf("unterminated string")
+	}
+
+	switch c := p.s[p.pos]; c {
+	case 'b':
+		b.WriteByte('\b')
+	case 't':
+		b.WriteByte('\t')
+	case 'n':
+		b.WriteByte('\n')
+	case 'f':
+		b.WriteByte('\f')
+	case 'r':
+		b.WriteByte('\r')
+	case '"', '\\':
+		b.WriteByte(c)
+	case 'u', 'U':
+		n := 4
+		if c == 'U' {
+			n = 8
+		}
+
+		if p.pos+n >= len(p.s) {
+			return p.errorf("invalid unicode escape")
+		}
+
+		r, err := strconv.ParseUint(p.s[p.pos+1:p.pos+1+n], 16, 32)
+		if err != nil || !utf8.ValidRune(rune(r)) {
+			return p.errorf("invalid unicode escape")
+		}
+
+		b.WriteRune(rune(r))
+		p.pos += n
+	default:
+		return p.errorf("invalid escape: \\%c", c)
+	}
+
+	return nil
+}
+
+func (p *tomlParser) array() ([]interface{}, error) {
+	values := []interface{}{}
+	p.pos++
+	for {
+		p.skip(true)
+		if p.pos >= len(p.s) {
+			return nil, p.errorf("unterminated array")
+		}
+
+		if p.s[p.pos] == ']' {
+			p.pos++
+			return values, nil
+		}
+
+		v, err := p.value()
+		if err != nil {
+			return nil, err
+		}
+
+		values = append(values, v)
+		p.skip(true)
+		if p.pos < len(p.s) && p.s[p.pos] == ',' {
+			p.pos++
+		}
+	}
+}
+
+func (p *tomlParser) inlineTable() (tomlTable, error) {
+	t := tomlTable{}
+	p.pos++
+	for {
+		p.skip(false)
+		if p.pos >= len(p.s) {
+			return nil, p.errorf("unterminated inline table")
+		}
+
+		switch p.s[p.pos] {
+		case '}':
+			p.pos++
+			return t, nil
+		case ',':
+			p.pos++
+			continue
+		}
+
+		err := p.keyValue(t)
+		if err != nil {
+			return nil, err
+		}
+	}
+}
+
+// skip skips whitespace and comments, and newlines if asked
+func (p *tomlParser) skip(newlines bool) {
+	for p.pos < len(p.s) {
+		switch p.s[p.pos] {
+		case ' ', '\t':
+			p.pos++
+		case '\r', '\n':
+			if !newlines {
+				return
+			}
+
+			p.pos++
+		case '#':
+			for p.pos < len(p.s) && p.s[p.pos] != '\n' {
+				p.pos++
+			}
+		default:
+			return
+		}
+	}
+}
+
+func (p *tomlParser) errorf(format string, args ...interface{}) error {
+	line := strings.Count(p.s[:p.pos], "\n") + 1
+	return fmt.Errorf("line %v: %v", line, fmt.Sprintf(format, args...))
+}
+
+func isBareKey(c byte) bool {
+	return (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z') || (c >= '0' && c <= '9') || c == '_' || c == '-'
+}
+
+// str returns the string of a key of a table, or an empty string
+func (t tomlTable) str(key string) string {
+	s, _ := t[key].(string)
+	return s
+}
+
+// tables returns the array of tables of a key of a table
+func (t tomlTable) tables(key string) []tomlTable {
+	tables, _ := t[key].([]tomlTable)
+	return tables
+}
@@ -0,0 +1,231 @@
+package lockfiles
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v2"
+
+	"github.com/ion-channel/ionic/dependencies"
+	"github.com/ion-channel/ionic/purl"
+)
+
+// yarnEntry is a resolved package of a yarn.lock file, and the specifiers
+// that resolve to it
+type yarnEntry struct {
+	specs                []string
+	Version              string            `yaml:"version"`
+	Resolution           string            `yaml:"resolution"`
+	Dependencies         map[string]string `yaml:"dependencies"`
+	OptionalDependencies map[string]string `yaml:"optionalDependencies"`
+}
+
+// ParseYarnLock parses a yarn.lock file of Yarn 1, or of Yarn 2 and later,
+// which are YAML. The direct dependencies of Yarn 1 lockfiles, which do not
+// list them, are taken to be the packages no other package requires.
+func ParseYarnLock(r io.Reader) (*dependencies.DependencyResolutionResponse, error) {
+	b, err := ioutil.ReadAll(r)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read yarn lock: %v", err.Error())
+	}
+
+	var entries []*yarnEntry
+	if bytes.Contains(b, []byte("\n__metadata:")) || bytes.HasPrefix(b, []byte("__metadata:")) {
+		entries, err = parseYarnBerry(b)
+	} else {
+		entries, err = parseYarnClassic(b)
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	keys := map[string]string{}
+	for _, e := range entries {
+		for _, spec := range e.specs {
+			keys[spec] = e.specs[0]
+		}
+	}
+
+	resolve := func(name, requirement string) (string, bool) {
+		if key, ok := keys[name+"@"+requirement]; ok {
+			return key, true
+		}
+
+		key, ok := keys[name+"@npm:"+requirement]
+		return key, ok
+	}
+
+	nodes := map[string]*node{}
+	roots := []edge{}
+	workspaces := false
+	for _, e := range entries {
+		requires := []edge{}
+		for _, reqs := range []struct {
+			deps  map[string]string
+			scope string
+		}{{e.Dependencies, ""}, {e.OptionalDependencies, ScopeOptional}} {
+			for _, name := range sortedKeys(reqs.deps) {
+				if key, ok := resolve(name, reqs.deps[name]); ok {
+					requires = append(requires, edge{key: key, requirement: reqs.deps[name], scope: reqs.scope})
+				}
+			}
+		}
+
+		// the workspaces of a project are entries of Yarn 2 lockfiles, and
+		// the packages they require are its direct dependencies
+		if strings.HasSuffix(e.Resolution, "@workspace:.") {
+			workspaces = true
+			roots = append(roots, requires...)
+			continue
+		}
+
+		if strings.Contains(e.Resolution, "@workspace:") {
+			continue
+		}
+
+		name, _ := splitSpec(e.specs[0])
+		nodes[e.specs[0]] = &node{
+			dep:      newDependency(purl.TypeNPM, name, e.Version),
+			requires: requires,
+		}
+	}
+
+	if !workspaces {
+		for _, root := range unrequired(nodes) {
+			_, root.requirement = splitSpec(root.key)
+			roots = append(roots, root)
+		}
+	}
+
+	return newResponse(buildTree(nodes, roots)), nil
+}
+
+// parseYarnClassic parses the entries of a Yarn 1 lockfile, such as
+//
+//	"@babel/code-frame@^7.0.0", "@babel/code-frame@^7.10.4":
+//	  version "7.12.13"
+//	  dependencies:
+//	    "@babel/highlight" "^7.12.13"
+func parseYarnClassic(b []byte) ([]*yarnEntry, error) {
+	entries := []*yarnEntry{}
+	var entry *yarnEntry
+	var section map[string]string
+
+	scanner := bufio.NewScanner(bytes.NewReader(b))
+	for scanner.Scan() {
+		line := strings.TrimRight(scanner.Text(), " \r")
+		trimmed := strings.TrimLeft(line, " ")
+		if trimmed == "" || strings.HasPrefix(trimmed, "#") {
+			continue
+		}
+
+		switch indent := len(line) - len(trimmed); {
+		case indent == 0:
+			if !strings.HasSuffix(line, ":") {
+				return nil, fmt.Errorf("invalid yarn lock entry: %v", line)
+			}
+
+			entry = &yarnEntry{}
+			for _, spec := range strings.Split(strings.TrimSuffix(line, ":"), ",") {
+				entry.specs = append(entry.specs, strings.Trim(strings.TrimSpace(spec), `"`))
+			}
+
+			entries = append(entries, entry)
+			section = nil
+		case entry == nil:
+			return nil, fmt.Errorf("invalid yarn lock line: %v", line)
+		case indent <= 2:
+			section = nil
+			if strings.HasSuffix(trimmed, ":") {
+				switch strings.TrimSuffix(trimmed, ":") {
+				case "dependencies":
+					entry.Dependencies = map[string]string{}
+					section = entry.Dependencies
+				case "optionalDependencies":
+					entry.OptionalDependencies = map[string]string{}
+					section = entry.OptionalDependencies
+				}
+
+				continue
+			}
+
+			key, value := splitYarnField(trimmed)
+			if key == "version" {
+				entry.Version = value
+			}
+		case section != nil:
+			key, value := splitYarnField(trimmed)
+			section[key] = value
+		}
+	}
+
+	err := scanner.Err()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read yarn lock: %v", err.Error())
+	}
+
+	return entries, nil
+}
+
+// parseYarnBerry parses the entries of a Yarn 2 or later lockfile
+func parseYarnBerry(b []byte) ([]*yarnEntry, error) {
+	var lock map[string]*yarnEntry
+	err := yaml.Unmarshal(b, &lock)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse yarn lock: %v", err.Error())
+	}
+
+	entries := []*yarnEntry{}
+	for _, key := range yarnKeys(lock) {
+		if key == "__metadata" || lock[key] == nil {
+			continue
+		}
+
+		e := lock[key]
+		for _, spec := range strings.Split(key, ",") {
+			e.specs = append(e.specs, strings.TrimSpace(spec))
+		}
+
+		entries = append(entries, e)
+	}
+
+	return entries, nil
+}
+
+// splitYarnField splits a Yarn 1 field such as `"@babel/highlight" "^7.12.13"`
+// into its unquoted key and value
+func splitYarnField(field string) (string, string) {
+	var key string
+	if strings.HasPrefix(field, `"`) {
+		i := strings.Index(field[1:], `"`) + 1
+		if i == 0 {
+			return strings.Trim(field, `"`), ""
+		}
+
+		key, field = field[1:i], field[i+1:]
+	} else {
+		parts := strings.SplitN(field, " ", 2)
+		key, field = parts[0], ""
+		if len(parts) > 1 {
+			field = parts[1]
+		}
+	}
+
+	return key, strings.Trim(strings.TrimSpace(field), `"`)
+}
+
+func yarnKeys(lock map[string]*yarnEntry) []string {
+	keys := make([]string, 0, len(lock))
+	for key := range lock {
+		keys = append(keys, key)
+	}
+
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,126 @@
+package ionic
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/franela/goblin"
+	. "github.com/onsi/gomega"
+
+	"github.com/ion-channel/ionic/dependencies"
+)
+
+func TestResolveDependenciesInFile(t *testing.T) {
+	g := goblin.Goblin(t)
+	RegisterFailHandler(func(m string, _ ...int) { g.Fail(m) })
+
+	g.Describe("ResolveDependenciesInFile", func() {
+		var server *httptest.Server
+		var dir string
+		var paths, types []string
+
+		g.BeforeEach(func() {
+			paths, types = nil, nil
+			server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				paths = append(paths, r.URL.Path)
+				types = append(types, r.URL.Query().Get("type"))
+				fmt.Fprint(w, `{"data":{"dependencies":[{"name":"remote","version":"1.0.0"}]},"meta":{}}`)
+			}))
+
+			var err error
+			dir, err = ioutil.TempDir("", "dependencies")
+			Expect(err).To(BeNil())
+		})
+
+		g.AfterEach(func() {
+			server.Close()
+			os.RemoveAll(dir)
+		})
+
+		write := func(name, content string) string {
+			path := filepath.Join(dir, name)
+			Expect(ioutil.WriteFile(path, []byte(content), 0644)).To(BeNil())
+			return path
+		}
+
+		g.It("should parse local files without the API", func() {
+			path := write("go.mod", "module example.com/app\n\ngo 1.17\n\nrequire golang.org/x/text v0.3.7\n")
+
+			ic, _ := New(server.URL)
+			resp, err := ic.ResolveDependenciesInFile(dependencies.DependencyResolutionRequest{File: path, Local: true}, "token")
+			Expect(err).To(BeNil())
+			Expect(resp.Dependencies).To(HaveLen(1))
+			Expect(resp.Dependencies[0].Name).To(Equal("golang.org/x/text"))
+			Expect(paths).To(BeEmpty())
+		})
+
+		g.It("should send files that cannot be parsed locally to the API", func() {
+			path := write("package.json", `{"dependencies": {"lodash": "^4.17.0"}}`)
+
+			ic, _ := New(server.URL)
+			resp, err := ic.ResolveDependenciesInFile(dependencies.DependencyResolutionRequest{File: path, Local: true}, "token")
+			Expect(err).To(BeNil())
+			Expect(resp.Dependencies[0].Name).To(Equal("remote"))
+			Expect(paths).To(Equal([]string{"/" + dependencies.ResolveFromFileEndpoint}))
+			Expect(types).To(Equal([]string{"package-json"}))
+		})
+
+		g.It("should only send the API detected ecosystems it accepts", func() {
+			path := write("go.sum", "golang.org/x/text v0.3.7 h1:abc=\n")
+
+			ic, _ := New(server.URL)
+			_, err := ic.ResolveDependenciesInFile(dependencies.DependencyResolutionRequest{File: path}, "token")
+			Expect(err).To(BeNil())
+			Expect(paths).To(Equal([]string{"/" + dependencies.ResolveDependenciesInFileEndpoint}))
+			Expect(types).To(Equal([]string{""}))
+		})
+
+		g.It("should send the API the ecosystem detected for remote files", func() {
+			path := write("yarn.lock", "lodash@^4.17.0:\n  version \"4.17.21\"\n")
+
+			ic, _ := New(server.URL)
+			_, err := ic.ResolveDependenciesInFile(dependencies.DependencyResolutionRequest{File: path}, "token")
+			Expect(err).To(BeNil())
+			Expect(paths).To(Equal([]string{"/" + dependencies.ResolveFromFileEndpoint}))
+			Expect(types).To(Equal([]string{"yarnlock"}))
+		})
+
+		g.It("should not send the API detected ecosystems it does not accept", func() {
+			ic, _ := New(server.URL)
+			for _, name := range []string{"pnpm-lock.yaml", "Pipfile.lock", "poetry.lock", "Cargo.lock", "packages.lock.json"} {
+				_, err := ic.ResolveDependenciesInFile(dependencies.DependencyResolutionRequest{File: write(name, "")}, "token")
+				Expect(err).To(BeNil())
+			}
+
+			Expect(types).To(Equal([]string{"", "", "", "", ""}))
+
+			path := write("Gemfile.lock", "")
+			_, err := ic.ResolveDependenciesInFile(dependencies.DependencyResolutionRequest{File: path}, "token")
+			Expect(err).To(BeNil())
+			Expect(paths[len(paths)-1]).To(Equal("/" + dependencies.ResolveDependenciesInFileEndpoint))
+			Expect(types[len(types)-1]).To(Equal("ruby"))
+		})
+
+		g.It("should select the endpoint by ecosystem", func() {
+			path := write("deps", "")
+
+			ic, _ := New(server.URL)
+			for _, ecosystem := range []string{"gomod", "yarnlock", "ruby"} {
+				_, err := ic.ResolveDependenciesInFile(dependencies.DependencyResolutionRequest{File: path, Ecosystem: ecosystem}, "token")
+				Expect(err).To(BeNil())
+			}
+
+			Expect(paths).To(Equal([]string{
+				"/" + dependencies.ResolveFromFileEndpoint,
+				"/" + dependencies.ResolveFromFileEndpoint,
+				"/" + dependencies.ResolveDependenciesInFileEndpoint,
+			}))
+			Expect(types).To(Equal([]string{"gomod", "yarnlock", "ruby"}))
+		})
+	})
+}
@@ -0,0 +1,44 @@
+package ionic
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/ion-channel/ionic/gate"
+)
+
+// RunGate takes a team ID, project ID, token, and gate options. It triggers an
+// analysis of the project, waits for it using WaitForAnalysis, and retrieves
+// the ruleset evaluation for it. It returns the result, which can be
+// checked with its Passed method, or an error if the analysis could not be
+// completed within the timeout given in the options.
+func (ic *IonClient) RunGate(ctx context.Context, teamID, projectID, token string, opts gate.Options) (*gate.Result, error) {
+	opts = opts.WithDefaults()
+
+	ctx, cancel := context.WithTimeout(ctx, opts.Timeout)
+	defer cancel()
+
+	start := time.Now()
+
+	status, err := ic.WithContext(ctx).AnalyzeProject(projectID, teamID, opts.Branch, token)
+	if err != nil {
+		return nil, fmt.Errorf("gate: %v", err.Error())
+	}
+
+	result, err := ic.WaitForAnalysis(ctx, status.ID, teamID, projectID, WaitOptions{
+		Token:       token,
+		MaxInterval: opts.PollInterval,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("gate: %v", err.Error())
+	}
+
+	return &gate.Result{
+		Status:         result.Status,
+		AppliedRuleset: result.AppliedRuleset,
+		CommitHash:     opts.CommitHash,
+		Author:         opts.Author,
+		Duration:       time.Since(start),
+	}, nil
+}
@@ -0,0 +1,144 @@
+// Package gate contains the pieces used to gate a CI pipeline on the result of
+// an Ion Channel analysis: the options for running the gate, the result it
+// produces, and the renderers used to report that result back to CI systems.
+package gate
+
+import (
+	"os"
+	"strings"
+	"time"
+
+	"github.com/ion-channel/ionic/rulesets"
+	"github.com/ion-channel/ionic/scanner"
+)
+
+const (
+	// DefaultTimeout is how long the gate waits for an analysis to finish when
+	// no timeout is given
+	DefaultTimeout = 30 * time.Minute
+	// DefaultPollInterval is the longest delay between checks of the analysis
+	// status when no interval is given
+	DefaultPollInterval = 10 * time.Second
+)
+
+var (
+	commitEnvVars = []string{
+		"IONCHANNEL_COMMIT_HASH",
+		"GIT_COMMIT",
+		"GITHUB_SHA",
+		"CI_COMMIT_SHA",
+		"CIRCLE_SHA1",
+		"TRAVIS_COMMIT",
+		"BITBUCKET_COMMIT",
+		"BUILD_SOURCEVERSION",
+	}
+	authorEnvVars = []string{
+		"IONCHANNEL_COMMIT_AUTHOR",
+		"GIT_AUTHOR_NAME",
+		"CI_COMMIT_AUTHOR",
+		"GITHUB_ACTOR",
+		"GITLAB_USER_NAME",
+		"CIRCLE_USERNAME",
+		"BUILD_REQUESTEDFOR",
+	}
+	branchEnvVars = []string{
+		"IONCHANNEL_BRANCH",
+		"GIT_BRANCH",
+		"GITHUB_HEAD_REF",
+		"GITHUB_REF_NAME",
+		"CI_COMMIT_REF_NAME",
+		"CIRCLE_BRANCH",
+		"TRAVIS_BRANCH",
+		"BITBUCKET_BRANCH",
+		"BUILD_SOURCEBRANCHNAME",
+	}
+)
+
+// Options represents the settings available when running a gate. All of the
+// options are optional; empty values are replaced with working defaults.
+type Options struct {
+	// Branch is the branch to analyze. The project's default branch is used if
+	// it is empty.
+	Branch string
+	// Timeout is the maximum amount of time to wait for the analysis to finish.
+	Timeout time.Duration
+	// PollInterval is the longest delay between checks of the analysis status.
+	PollInterval time.Duration
+	// CommitHash is the commit being gated, used for reporting.
+	CommitHash string
+	// Author is the author of the commit being gated, used for reporting.
+	Author string
+}
+
+// OptionsFromEnv returns Options with the branch, commit hash, and author
+// populated from the environment variables set by common CI systems.
+func OptionsFromEnv() Options {
+	return Options{
+		Branch:     BranchFromEnv(),
+		CommitHash: CommitFromEnv(),
+		Author:     AuthorFromEnv(),
+	}
+}
+
+// WithDefaults returns a copy of the Options with any empty values replaced
+// by their defaults.
+func (o Options) WithDefaults() Options {
+	if o.Timeout <= 0 {
+		o.Timeout = DefaultTimeout
+	}
+
+	if o.PollInterval <= 0 {
+		o.PollInterval = DefaultPollInterval
+	}
+
+	return o
+}
+
+// Result represents the outcome of running a gate against a project
+type Result struct {
+	Status         *scanner.AnalysisStatus         `json:"status"`
+	AppliedRuleset *rulesets.AppliedRulesetSummary `json:"applied_ruleset"`
+	CommitHash     string                          `json:"commit_hash,omitempty"`
+	Author         string                          `json:"author,omitempty"`
+	Duration       time.Duration                   `json:"duration"`
+}
+
+// Passed returns whether the analysis passed the project's ruleset. A result
+// without a rule evaluation is never considered passing.
+func (r *Result) Passed() bool {
+	return r.appliedRuleset().Passed()
+}
+
+// RulesetName returns the name of the ruleset the analysis was evaluated with
+func (r *Result) RulesetName() string {
+	return r.appliedRuleset().Name()
+}
+
+// CommitFromEnv returns the commit hash being built, as reported by the CI
+// system, or an empty string if it cannot be determined.
+func CommitFromEnv() string {
+	return firstEnv(commitEnvVars)
+}
+
+// AuthorFromEnv returns the author of the commit being built, as reported by
+// the CI system, or an empty string if it cannot be determined.
+func AuthorFromEnv() string {
+	return firstEnv(authorEnvVars)
+}
+
+// BranchFromEnv returns the branch being built, as reported by the CI system,
+// or an empty string if it cannot be determined.
+func BranchFromEnv() string {
+	branch := firstEnv(branchEnvVars)
+	return strings.TrimPrefix(branch, "origin/")
+}
+
+func firstEnv(names []string) string {
+	for _, name := range names {
+		if v := strings.TrimSpace(os.Getenv(name)); v != "" {
+			return v
+		}
+	}
+
+	return ""
+}
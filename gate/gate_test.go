@@ -0,0 +1,149 @@
+package gate
+
+import (
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"io"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/franela/goblin"
+	. "github.com/onsi/gomega"
+
+	"github.com/ion-channel/ionic/reports"
+	"github.com/ion-channel/ionic/rulesets"
+	"github.com/ion-channel/ionic/scanner"
+	"github.com/ion-channel/ionic/scans"
+)
+
+func TestGate(t *testing.T) {
+	g := goblin.Goblin(t)
+	RegisterFailHandler(func(m string, _ ...int) { g.Fail(m) })
+
+	g.Describe("Options", func() {
+		g.It("should fill in defaults", func() {
+			o := Options{}.WithDefaults()
+			Expect(o.Timeout).To(Equal(DefaultTimeout))
+			Expect(o.PollInterval).To(Equal(DefaultPollInterval))
+
+			o = Options{Timeout: time.Minute}.WithDefaults()
+			Expect(o.Timeout).To(Equal(time.Minute))
+		})
+
+		g.It("should pick up the commit, author, and branch from the environment", func() {
+			os.Setenv("IONCHANNEL_COMMIT_HASH", "abc123")
+			os.Setenv("IONCHANNEL_COMMIT_AUTHOR", "Jane Doe")
+			os.Setenv("IONCHANNEL_BRANCH", "origin/feature")
+			defer os.Unsetenv("IONCHANNEL_COMMIT_HASH")
+			defer os.Unsetenv("IONCHANNEL_COMMIT_AUTHOR")
+			defer os.Unsetenv("IONCHANNEL_BRANCH")
+
+			o := OptionsFromEnv()
+			Expect(o.CommitHash).To(Equal("abc123"))
+			Expect(o.Author).To(Equal("Jane Doe"))
+			Expect(o.Branch).To(Equal("feature"))
+		})
+	})
+
+	g.Describe("Result", func() {
+		g.It("should not pass without an evaluation", func() {
+			var r *Result
+			Expect(r.Passed()).To(BeFalse())
+			Expect((&Result{}).Passed()).To(BeFalse())
+		})
+
+		g.It("should pass when the evaluation passed", func() {
+			r := sampleResult(true)
+			Expect(r.Passed()).To(BeTrue())
+			Expect(r.RulesetName()).To(Equal("super cool ruleset"))
+		})
+	})
+
+	g.Describe("Reports", func() {
+		g.It("should write a table of the rule results", func() {
+			var buf bytes.Buffer
+			err := WriteTable(&buf, sampleResult(false))
+			Expect(err).To(BeNil())
+			Expect(buf.String()).To(ContainSubstring("has a readme"))
+			Expect(buf.String()).To(ContainSubstring("Ruleset \"super cool ruleset\": FAIL"))
+		})
+
+		g.It("should write junit xml with a test case per rule", func() {
+			var buf bytes.Buffer
+			err := WriteJUnit(&buf, sampleResult(false))
+			Expect(err).To(BeNil())
+
+			var suites reports.JUnitTestSuites
+			err = xml.Unmarshal(buf.Bytes(), &suites)
+			Expect(err).To(BeNil())
+			Expect(suites.Suites).To(HaveLen(1))
+			Expect(suites.Suites[0].Tests).To(Equal(2))
+			Expect(suites.Suites[0].Failures).To(Equal(1))
+			Expect(suites.Suites[0].Cases[1].Failure).NotTo(BeNil())
+			Expect(suites.Suites[0].Cases[1].Failure.Message).To(Equal("no critical vulnerabilities found: 2"))
+			Expect(suites.Suites[0].Properties).To(ContainElement(reports.JUnitProperty{Name: "commit", Value: "abc123"}))
+		})
+
+		g.It("should write a markdown summary", func() {
+			var buf bytes.Buffer
+			err := WriteMarkdown(&buf, sampleResult(true))
+			Expect(err).To(BeNil())
+			Expect(buf.String()).To(ContainSubstring("## Ion Channel: PASS"))
+			Expect(buf.String()).To(ContainSubstring("| has a readme | PASS | low |"))
+			Expect(buf.String()).To(ContainSubstring("- commit: `abc123`"))
+		})
+
+		g.It("should write reports without a result", func() {
+			for _, write := range []func(io.Writer, *Result) error{WriteTable, WriteJUnit, WriteMarkdown} {
+				var buf bytes.Buffer
+				Expect(write(&buf, nil)).To(BeNil())
+				Expect(buf.String()).NotTo(BeEmpty())
+			}
+		})
+	})
+}
+
+func sampleResult(passed bool) *Result {
+	var evals []scans.Evaluation
+	json.Unmarshal([]byte(sampleEvaluations), &evals)
+
+	return &Result{
+		Status: &scanner.AnalysisStatus{
+			ID:     "analysis-id",
+			Status: scanner.AnalysisStatusFinished,
+		},
+		AppliedRuleset: &rulesets.AppliedRulesetSummary{
+			RuleEvaluationSummary: &rulesets.RuleEvaluationSummary{
+				RulesetName: "super cool ruleset",
+				Passed:      passed,
+				Ruleresults: evals,
+			},
+		},
+		CommitHash: "abc123",
+		Author:     "Jane Doe",
+	}
+}
+
+const sampleEvaluations = `[
+  {
+    "rule_id": "rule-1",
+    "name": "has a readme",
+    "summary": "readme found",
+    "risk": "low",
+    "passed": true,
+    "duration": 1200,
+    "results": {"type": "about_yml", "data": {"message": "", "valid": true, "content": ""}}
+  },
+  {
+    "rule_id": "rule-2",
+    "name": "no critical vulnerabilities",
+    "summary": "no critical vulnerabilities\nfound: 2",
+    "description": "the project must not have critical vulnerabilities",
+    "risk": "high",
+    "passed": false,
+    "duration": 300,
+    "results": {"type": "external_vulnerability", "data": {"critical": 2, "high": 0, "medium": 0, "low": 0}}
+  }
+]`
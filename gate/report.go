@@ -0,0 +1,98 @@
+package gate
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"text/tabwriter"
+
+	"github.com/ion-channel/ionic/reports"
+	"github.com/ion-channel/ionic/rulesets"
+)
+
+// WriteTable writes a human readable, rule by rule table of the result to the
+// given writer.
+func WriteTable(w io.Writer, r *Result) error {
+	tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(tw, "RULE\tRESULT\tRISK\tSUMMARY")
+
+	for _, e := range r.appliedRuleset().RuleResults() {
+		fmt.Fprintf(tw, "%v\t%v\t%v\t%v\n", e.Name, reports.RuleResult(e), e.Risk, reports.OneLine(e.Summary))
+	}
+
+	err := tw.Flush()
+	if err != nil {
+		return fmt.Errorf("failed to write table: %v", err.Error())
+	}
+
+	_, err = fmt.Fprintf(w, "\nRuleset %q: %v\n", r.RulesetName(), reports.PassFail(r.Passed()))
+	return err
+}
+
+// WriteJUnit writes the result to the given writer as JUnit XML, with each
+// rule of the applied ruleset represented as a test case.
+func WriteJUnit(w io.Writer, r *Result) error {
+	return reports.WriteRulesetJUnit(w, r.appliedRuleset(), r.reportOptions())
+}
+
+// WriteMarkdown writes a Markdown summary of the result to the given writer,
+// suitable for CI annotations and pull request comments.
+func WriteMarkdown(w io.Writer, r *Result) error {
+	return reports.WriteRulesetMarkdown(w, r.appliedRuleset(), r.reportOptions())
+}
+
+// WriteJUnitFile writes the result as JUnit XML to the file at the given path
+func WriteJUnitFile(path string, r *Result) error {
+	return writeFile(path, r, WriteJUnit)
+}
+
+// WriteMarkdownFile writes the Markdown summary of the result to the file at
+// the given path
+func WriteMarkdownFile(path string, r *Result) error {
+	return writeFile(path, r, WriteMarkdown)
+}
+
+func writeFile(path string, r *Result, write func(io.Writer, *Result) error) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("failed to create %v: %v", path, err.Error())
+	}
+	defer f.Close()
+
+	err = write(f, r)
+	if err != nil {
+		return err
+	}
+
+	return f.Close()
+}
+
+// reportOptions returns the options used to render the applied ruleset of
+// the result, with the commit and author as properties
+func (r *Result) reportOptions() reports.RulesetReportOptions {
+	if r == nil {
+		return reports.RulesetReportOptions{}
+	}
+
+	opts := reports.RulesetReportOptions{Duration: r.Duration}
+
+	if r.CommitHash != "" {
+		opts.Properties = append(opts.Properties, reports.ReportProperty{Name: "commit", Value: r.CommitHash})
+	}
+
+	if r.Author != "" {
+		opts.Properties = append(opts.Properties, reports.ReportProperty{Name: "author", Value: r.Author})
+	}
+
+	return opts
+}
+
+// appliedRuleset returns the applied ruleset of the result, or nil if there
+// is no result
+func (r *Result) appliedRuleset() *rulesets.AppliedRulesetSummary {
+	if r == nil {
+		return nil
+	}
+
+	return r.AppliedRuleset
+}
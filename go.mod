@@ -8,10 +8,10 @@ require (
 	github.com/gomicro/bogus v0.1.2-0.20180508160002-615633fee854
 	github.com/gomicro/penname v0.1.0
 	github.com/google/uuid v1.2.0
-	github.com/hashicorp/go-version v1.3.0
 	github.com/kelseyhightower/envconfig v1.4.0
 	github.com/onsi/gomega v1.10.1
 	github.com/spdx/tools-golang v0.2.0
+	gopkg.in/yaml.v2 v2.4.0
 )
 
 require (
@@ -32,7 +32,6 @@ require (
 	golang.org/x/text v0.7.0 // indirect
 	golang.org/x/tools v0.1.12 // indirect
 	golang.org/x/xerrors v0.0.0-20200804184101-5ec99f83aff1 // indirect
-	gopkg.in/yaml.v2 v2.4.0 // indirect
 )
 
 replace github.com/spdx/tools-golang => github.com/ion-channel/tools-golang v0.0.0-20220425222917-af3d04c69209
@@ -0,0 +1,293 @@
+package licenses
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// Operator is a string enum of the operators combining license expressions
+type Operator string
+
+const (
+	// OperatorAnd requires both licenses to be complied with
+	OperatorAnd Operator = "AND"
+	// OperatorOr allows either license to be chosen
+	OperatorOr Operator = "OR"
+
+	operatorWith = "WITH"
+
+	// NoAssertion is the SPDX value for a license that was not determined
+	NoAssertion = "NOASSERTION"
+	// None is the SPDX value for a package without a license
+	None = "NONE"
+)
+
+var identifierPattern = regexp.MustCompile(`^(DocumentRef-[A-Za-z0-9.\-]+:)?[A-Za-z0-9.\-]+$`)
+
+// Expression represents a parsed SPDX license expression. An expression is
+// either a single license, with an optional exception, or two expressions
+// combined by an operator.
+type Expression struct {
+	// Operator combines the Left and Right expressions. It is empty when the
+	// expression is a single license.
+	Operator Operator
+	Left     *Expression
+	Right    *Expression
+
+	// License is the SPDX identifier of a single license.
+	License string
+	// OrLater is whether the license was followed by a +, allowing any later
+	// version of the license.
+	OrLater bool
+	// Exception is the SPDX identifier of the exception given with WITH.
+	Exception string
+}
+
+// ParseExpression parses an SPDX license expression, such as
+// "(MIT OR Apache-2.0) AND GPL-2.0+ WITH Classpath-exception-2.0". WITH binds
+// tighter than AND, which binds tighter than OR, and parentheses can be used
+// to group expressions. Operators are accepted in any case, and identifiers in
+// the license list are normalized to their SPDX case. Identifiers are not
+// required to be in the license list; use Unknown to find the ones that are
+// not.
+func ParseExpression(expression string) (*Expression, error) {
+	p := &expressionParser{tokens: tokenize(expression)}
+	if len(p.tokens) == 0 {
+		return nil, fmt.Errorf("license expression is empty")
+	}
+
+	e, err := p.parseOr()
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse license expression %q: %v", expression, err.Error())
+	}
+
+	if p.pos < len(p.tokens) {
+		return nil, fmt.Errorf("failed to parse license expression %q: unexpected %q", expression, p.tokens[p.pos])
+	}
+
+	return e, nil
+}
+
+// String returns the expression in its normalized SPDX form, with operators in
+// upper case and parentheses only where they are needed
+func (e *Expression) String() string {
+	if e == nil {
+		return ""
+	}
+
+	if e.Operator == "" {
+		s := e.License
+		if e.OrLater {
+			s += "+"
+		}
+
+		if e.Exception != "" {
+			s += " " + operatorWith + " " + e.Exception
+		}
+
+		return s
+	}
+
+	return e.operand(e.Left) + " " + string(e.Operator) + " " + e.operand(e.Right)
+}
+
+// operand returns the child expression, in parentheses if it binds more
+// loosely than the expression's operator
+func (e *Expression) operand(child *Expression) string {
+	if e.Operator == OperatorAnd && child.Operator == OperatorOr {
+		return "(" + child.String() + ")"
+	}
+
+	return child.String()
+}
+
+// Leaves returns the single licenses of the expression, from left to right
+func (e *Expression) Leaves() []*Expression {
+	if e == nil {
+		return nil
+	}
+
+	if e.Operator == "" {
+		return []*Expression{e}
+	}
+
+	return append(e.Left.Leaves(), e.Right.Leaves()...)
+}
+
+// Licenses returns the unique license identifiers of the expression, from
+// left to right
+func (e *Expression) Licenses() []string {
+	seen := make(map[string]bool)
+	var ids []string
+	for _, l := range e.Leaves() {
+		if !seen[l.License] {
+			seen[l.License] = true
+			ids = append(ids, l.License)
+		}
+	}
+
+	return ids
+}
+
+// Unknown returns the license and exception identifiers of the expression that
+// are not in the license list. References to custom licenses, NONE, and
+// NOASSERTION are not reported.
+func (e *Expression) Unknown() []string {
+	var unknown []string
+	for _, l := range e.Leaves() {
+		if _, ok := Lookup(l.License); !ok && !isSpecialIdentifier(l.License) {
+			unknown = append(unknown, l.License)
+		}
+
+		if l.Exception != "" {
+			if _, ok := LookupException(l.Exception); !ok {
+				unknown = append(unknown, l.Exception)
+			}
+		}
+	}
+
+	return unknown
+}
+
+func isSpecialIdentifier(id string) bool {
+	upper := strings.ToUpper(id)
+	return upper == NoAssertion || upper == None ||
+		strings.HasPrefix(upper, "LICENSEREF-") || strings.HasPrefix(upper, "DOCUMENTREF-")
+}
+
+func tokenize(expression string) []string {
+	var tokens []string
+	var current strings.Builder
+
+	flush := func() {
+		if current.Len() > 0 {
+			tokens = append(tokens, current.String())
+			current.Reset()
+		}
+	}
+
+	for _, r := range expression {
+		switch {
+		case r == '(' || r == ')':
+			flush()
+			tokens = append(tokens, string(r))
+		case r == ' ' || r == '\t' || r == '\n' || r == '\r':
+			flush()
+		default:
+			current.WriteRune(r)
+		}
+	}
+	flush()
+
+	return tokens
+}
+
+type expressionParser struct {
+	tokens []string
+	pos    int
+}
+
+func (p *expressionParser) peek() string {
+	if p.pos < len(p.tokens) {
+		return p.tokens[p.pos]
+	}
+
+	return ""
+}
+
+func (p *expressionParser) isOperator(op string) bool {
+	return strings.EqualFold(p.peek(), op)
+}
+
+func (p *expressionParser) parseOr() (*Expression, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+
+	for p.isOperator(string(OperatorOr)) {
+		p.pos++
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+
+		left = &Expression{Operator: OperatorOr, Left: left, Right: right}
+	}
+
+	return left, nil
+}
+
+func (p *expressionParser) parseAnd() (*Expression, error) {
+	left, err := p.parsePrimary()
+	if err != nil {
+		return nil, err
+	}
+
+	for p.isOperator(string(OperatorAnd)) {
+		p.pos++
+		right, err := p.parsePrimary()
+		if err != nil {
+			return nil, err
+		}
+
+		left = &Expression{Operator: OperatorAnd, Left: left, Right: right}
+	}
+
+	return left, nil
+}
+
+func (p *expressionParser) parsePrimary() (*Expression, error) {
+	token := p.peek()
+	switch {
+	case token == "":
+		return nil, fmt.Errorf("unexpected end of expression")
+	case token == "(":
+		p.pos++
+		e, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+
+		if p.peek() != ")" {
+			return nil, fmt.Errorf("missing closing parenthesis")
+		}
+
+		p.pos++
+		return e, nil
+	case token == ")" || p.isOperator(string(OperatorAnd)) || p.isOperator(string(OperatorOr)) || p.isOperator(operatorWith):
+		return nil, fmt.Errorf("unexpected %q", token)
+	}
+
+	p.pos++
+	e := &Expression{License: token}
+	if strings.HasSuffix(token, "+") {
+		e.License = strings.TrimSuffix(token, "+")
+		e.OrLater = true
+	}
+
+	if !identifierPattern.MatchString(e.License) {
+		return nil, fmt.Errorf("invalid license identifier %q", token)
+	}
+
+	if l, ok := Lookup(e.License); ok {
+		e.License = l.ID
+	}
+
+	if p.isOperator(operatorWith) {
+		p.pos++
+		exception := p.peek()
+		if exception == "" || exception == "(" || exception == ")" || !identifierPattern.MatchString(exception) {
+			return nil, fmt.Errorf("invalid license exception %q", exception)
+		}
+
+		p.pos++
+		e.Exception = exception
+		if ex, ok := LookupException(exception); ok {
+			e.Exception = ex.ID
+		}
+	}
+
+	return e, nil
+}
@@ -0,0 +1,209 @@
+package licenses
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/ion-channel/ionic/analyses"
+	"github.com/ion-channel/ionic/scans"
+
+	"github.com/franela/goblin"
+	. "github.com/onsi/gomega"
+)
+
+func TestLicenses(t *testing.T) {
+	g := goblin.Goblin(t)
+	RegisterFailHandler(func(m string, _ ...int) { g.Fail(m) })
+
+	g.Describe("License List", func() {
+		g.It("should look up licenses without regard to case", func() {
+			l, ok := Lookup("apache-2.0")
+			Expect(ok).To(BeTrue())
+			Expect(l.ID).To(Equal("Apache-2.0"))
+			Expect(*l.OSIApproved).To(BeTrue())
+			Expect(*l.FSFLibre).To(BeTrue())
+			Expect(l.Category).To(Equal(CategoryPermissive))
+
+			_, ok = Lookup("not-a-license")
+			Expect(ok).To(BeFalse())
+			Expect(CategoryOf("not-a-license")).To(Equal(CategoryUnknown))
+			Expect(ListVersion()).NotTo(BeEmpty())
+		})
+
+		g.It("should include every license of the SPDX list", func() {
+			for _, id := range []string{"curl", "MIT-CMU", "BSD-3-Clause-LBNL", "GPL-2.0+"} {
+				_, ok := Lookup(id)
+				Expect(ok).To(BeTrue(), id)
+			}
+
+			l, _ := Lookup("GPL-2.0+")
+			Expect(l.Deprecated).To(BeTrue())
+			Expect(CategoryOf("curl")).To(Equal(CategoryUnknown))
+
+			e, err := ParseExpression("curl AND MIT-CMU WITH LLVM-exception")
+			Expect(err).To(BeNil())
+			Expect(e.Unknown()).To(BeEmpty())
+		})
+
+		g.It("should know the OSI approval of licenses that are not categorized", func() {
+			for id, approved := range map[string]bool{"AFL-1.1": true, "AFL-2.1": true, "AGPL-1.0-only": false, "curl": false} {
+				l, _ := Lookup(id)
+				osi, known := l.IsOSIApproved()
+				Expect(known).To(BeTrue(), id)
+				Expect(osi).To(Equal(approved), id)
+			}
+
+			l, _ := Lookup("AFL-1.1")
+			Expect(l.Name).To(Equal("Academic Free License v1.1"))
+			_, known := l.IsFSFLibre()
+			Expect(known).To(BeFalse())
+
+			l, _ = Lookup("3D-Slicer-1.0")
+			_, known = l.IsOSIApproved()
+			Expect(known).To(BeFalse())
+			Expect(l.OSIApproved).To(BeNil())
+		})
+
+		g.It("should categorize copyleft licenses", func() {
+			Expect(CategoryOf("GPL-3.0-only").IsCopyleft()).To(BeTrue())
+			Expect(CategoryOf("AGPL-3.0-or-later")).To(Equal(CategoryNetworkCopyleft))
+			Expect(CategoryOf("MPL-2.0")).To(Equal(CategoryWeakCopyleft))
+			Expect(CategoryOf("MIT").IsCopyleft()).To(BeFalse())
+
+			_, ok := LookupException("classpath-exception-2.0")
+			Expect(ok).To(BeTrue())
+		})
+	})
+
+	g.Describe("Expressions", func() {
+		g.It("should parse a single license", func() {
+			e, err := ParseExpression("MIT")
+			Expect(err).To(BeNil())
+			Expect(e.Operator).To(BeEmpty())
+			Expect(e.License).To(Equal("MIT"))
+		})
+
+		g.It("should parse operators by precedence", func() {
+			e, err := ParseExpression("MIT OR Apache-2.0 AND GPL-2.0+ WITH Classpath-exception-2.0")
+			Expect(err).To(BeNil())
+			Expect(e.Operator).To(Equal(OperatorOr))
+			Expect(e.Left.License).To(Equal("MIT"))
+			Expect(e.Right.Operator).To(Equal(OperatorAnd))
+			Expect(e.Right.Right.License).To(Equal("GPL-2.0"))
+			Expect(e.Right.Right.OrLater).To(BeTrue())
+			Expect(e.Right.Right.Exception).To(Equal("Classpath-exception-2.0"))
+			Expect(e.Licenses()).To(Equal([]string{"MIT", "Apache-2.0", "GPL-2.0"}))
+		})
+
+		g.It("should normalize expressions", func() {
+			e, err := ParseExpression("((mit or  Apache-2.0)) and BSD-3-Clause")
+			Expect(err).To(BeNil())
+			Expect(e.String()).To(Equal("(MIT OR Apache-2.0) AND BSD-3-Clause"))
+
+			e, err = ParseExpression("MIT AND (Apache-2.0 AND ISC)")
+			Expect(err).To(BeNil())
+			Expect(e.String()).To(Equal("MIT AND Apache-2.0 AND ISC"))
+		})
+
+		g.It("should report unknown identifiers", func() {
+			e, err := ParseExpression("MIT OR made-up WITH fake-exception OR LicenseRef-custom OR NOASSERTION")
+			Expect(err).To(BeNil())
+			Expect(e.Unknown()).To(Equal([]string{"made-up", "fake-exception"}))
+		})
+
+		g.It("should return errors for invalid expressions", func() {
+			for _, expression := range []string{"", "MIT AND", "(MIT", "MIT)", "AND MIT", "MIT WITH", "(MIT) WITH LLVM-exception", "MIT Apache-2.0", "M!T"} {
+				_, err := ParseExpression(expression)
+				Expect(err).NotTo(BeNil(), expression)
+			}
+		})
+	})
+
+	g.Describe("Policy", func() {
+		policy := &Policy{
+			Allow:  []string{"MIT", "Apache-2.0", "GPL-2.0-only WITH Classpath-exception-2.0"},
+			Deny:   []string{"SSPL-1.0"},
+			Review: []string{"LGPL-2.1-only"},
+			Categories: map[Category]Decision{
+				CategoryPermissive:      DecisionAllow,
+				CategoryStrongCopyleft:  DecisionDeny,
+				CategoryNetworkCopyleft: DecisionDeny,
+			},
+		}
+
+		g.It("should decide single licenses", func() {
+			Expect(policy.Evaluate("mit").Decision).To(Equal(DecisionAllow))
+			Expect(policy.Evaluate("ISC").Decision).To(Equal(DecisionAllow))
+			Expect(policy.Evaluate("SSPL-1.0").Decision).To(Equal(DecisionDeny))
+			Expect(policy.Evaluate("LGPL-2.1-only").Decision).To(Equal(DecisionReview))
+			Expect(policy.Evaluate("GPL-3.0-or-later").Decision).To(Equal(DecisionDeny))
+			Expect(policy.Evaluate("MPL-2.0").Decision).To(Equal(DecisionReview))
+			Expect(policy.Evaluate("LicenseRef-custom").Decision).To(Equal(DecisionReview))
+		})
+
+		g.It("should decide licenses with exceptions", func() {
+			Expect(policy.Evaluate("GPL-2.0-only WITH Classpath-exception-2.0").Decision).To(Equal(DecisionAllow))
+			Expect(policy.Evaluate("GPL-3.0-only WITH GCC-exception-3.1").Decision).To(Equal(DecisionDeny))
+
+			linking := &Policy{LinkingExceptions: true, Categories: policy.Categories, Default: DecisionAllow}
+			Expect(linking.Evaluate("GPL-3.0-only WITH GCC-exception-3.1").Decision).To(Equal(DecisionAllow))
+			Expect(linking.Evaluate("GPL-3.0-only").Decision).To(Equal(DecisionDeny))
+		})
+
+		g.It("should decide choices and combinations of licenses", func() {
+			r := policy.Evaluate("GPL-3.0-only OR MIT")
+			Expect(r.Decision).To(Equal(DecisionAllow))
+			Expect(r.Licenses).To(Equal([]string{"MIT"}))
+
+			r = policy.Evaluate("Apache-2.0 AND (GPL-3.0-only OR SSPL-1.0)")
+			Expect(r.Decision).To(Equal(DecisionDeny))
+			Expect(r.Licenses).To(Equal([]string{"GPL-3.0-only", "SSPL-1.0"}))
+			Expect(r.Expression).To(Equal("Apache-2.0 AND (GPL-3.0-only OR SSPL-1.0)"))
+		})
+
+		g.It("should review expressions that cannot be parsed", func() {
+			r := policy.Evaluate("Apache License, Version 2.0")
+			Expect(r.Decision).To(Equal(DecisionReview))
+			Expect(r.Error).NotTo(BeEmpty())
+			Expect(r.Licenses).To(Equal([]string{"Apache License, Version 2.0"}))
+		})
+
+		g.It("should report violations with their files and packages", func() {
+			var a analyses.Analysis
+			Expect(json.Unmarshal([]byte(sampleLicenseAnalysis), &a)).To(Succeed())
+
+			violations, err := policy.CheckAnalysis(&a,
+				Component{Org: "mongodb", Name: "mongo", Version: "5.0", License: "SSPL-1.0"},
+				Component{Name: "lodash", Version: "4.17.21", License: "MIT"},
+				Component{Name: "mystery"},
+				Component{Name: "other-gpl", License: "GPL-3.0-only"},
+			)
+			Expect(err).To(BeNil())
+			Expect(violations).To(HaveLen(4))
+
+			Expect(violations[0].License).To(Equal("GPL-3.0-only"))
+			Expect(violations[0].Decision).To(Equal(DecisionDeny))
+			Expect(violations[0].Files).To(Equal([]string{"LICENSE.md"}))
+			Expect(violations[0].Packages).To(Equal([]string{"other-gpl"}))
+
+			Expect(violations[1].License).To(Equal("SSPL-1.0"))
+			Expect(violations[1].Packages).To(Equal([]string{"mongodb/mongo@5.0"}))
+
+			Expect(violations[2].License).To(Equal("LGPL-2.1-only"))
+			Expect(violations[2].Decision).To(Equal(DecisionReview))
+			Expect(violations[3].License).To(Equal(NoAssertion))
+			Expect(violations[3].Packages).To(Equal([]string{"mystery"}))
+		})
+
+		g.It("should return an error without license results", func() {
+			_, err := policy.CheckAnalysis(&analyses.Analysis{})
+			Expect(err).NotTo(BeNil())
+
+			Expect(policy.CheckLicenses(&scans.LicenseResults{})).To(BeEmpty())
+		})
+	})
+}
+
+const sampleLicenseAnalysis = `{"id":"a1","scan_summaries":[
+{"id":"s1","results":{"type":"license","data":{"license":{"name":"LICENSE.md","type":[{"name":"mit"},{"name":"gpl-3.0-only"},{"name":"lgpl-2.1-only"}]}}}}
+]}`
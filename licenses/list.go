@@ -0,0 +1,161 @@
+package licenses
+
+import (
+	"embed"
+	"encoding/json"
+	"strings"
+)
+
+//go:embed data/spdx.json
+var listData embed.FS
+
+// Category is a string enum of the broad kinds of license, by the obligations
+// they place on software distributed with them
+type Category string
+
+const (
+	// CategoryUnknown denotes that the license is not in the list
+	CategoryUnknown Category = "unknown"
+	// CategoryPublicDomain denotes a license dedicating the work to the public
+	// domain, or equivalent
+	CategoryPublicDomain Category = "public-domain"
+	// CategoryPermissive denotes a license only requiring attribution
+	CategoryPermissive Category = "permissive"
+	// CategoryWeakCopyleft denotes a license requiring changes to the licensed
+	// files, but not the larger work, to be shared under the same license
+	CategoryWeakCopyleft Category = "weak-copyleft"
+	// CategoryStrongCopyleft denotes a license requiring the larger work to be
+	// shared under the same license when distributed
+	CategoryStrongCopyleft Category = "strong-copyleft"
+	// CategoryNetworkCopyleft denotes a strong copyleft license that also
+	// applies to software used over a network
+	CategoryNetworkCopyleft Category = "network-copyleft"
+	// CategoryProprietary denotes a license with commercial or field of use
+	// restrictions
+	CategoryProprietary Category = "proprietary"
+)
+
+// Info represents a license from the SPDX license list. Every license and
+// exception of the list is included. OSI approval is known for nearly every
+// license, but FSF approval and categories are only recorded for the licenses
+// commonly found in open source packages. OSIApproved and FSFLibre are nil when
+// the approval is not known.
+type Info struct {
+	ID          string   `json:"licenseId"`
+	Name        string   `json:"name"`
+	OSIApproved *bool    `json:"isOsiApproved"`
+	FSFLibre    *bool    `json:"isFsfLibre"`
+	Deprecated  bool     `json:"isDeprecatedLicenseId"`
+	Category    Category `json:"category"`
+}
+
+// IsOSIApproved returns whether the license is approved by the Open Source
+// Initiative, and whether its approval is known
+func (i Info) IsOSIApproved() (approved, known bool) {
+	if i.OSIApproved == nil {
+		return false, false
+	}
+
+	return *i.OSIApproved, true
+}
+
+// IsFSFLibre returns whether the license is considered free by the Free
+// Software Foundation, and whether that is known
+func (i Info) IsFSFLibre() (libre, known bool) {
+	if i.FSFLibre == nil {
+		return false, false
+	}
+
+	return *i.FSFLibre, true
+}
+
+// Exception represents a license exception from the SPDX license list, used
+// with the WITH operator of a license expression
+type Exception struct {
+	ID   string `json:"licenseExceptionId"`
+	Name string `json:"name"`
+}
+
+type licenseList struct {
+	Version    string      `json:"licenseListVersion"`
+	Licenses   []Info      `json:"licenses"`
+	Exceptions []Exception `json:"exceptions"`
+
+	// licenses and exceptions are indexed by their lower cased identifiers
+	licenses   map[string]int
+	exceptions map[string]int
+}
+
+var spdxList = loadList()
+
+func loadList() *licenseList {
+	b, err := listData.ReadFile("data/spdx.json")
+	if err != nil {
+		panic("failed to read SPDX license list: " + err.Error())
+	}
+
+	var list licenseList
+	err = json.Unmarshal(b, &list)
+	if err != nil {
+		panic("failed to parse SPDX license list: " + err.Error())
+	}
+
+	list.licenses = make(map[string]int, len(list.Licenses))
+	for i, l := range list.Licenses {
+		list.licenses[strings.ToLower(l.ID)] = i
+	}
+
+	list.exceptions = make(map[string]int, len(list.Exceptions))
+	for i, e := range list.Exceptions {
+		list.exceptions[strings.ToLower(e.ID)] = i
+	}
+
+	return &list
+}
+
+// ListVersion returns the version of the embedded SPDX license list
+func ListVersion() string {
+	return spdxList.Version
+}
+
+// Licenses returns every license in the embedded SPDX license list
+func Licenses() []Info {
+	return append([]Info{}, spdxList.Licenses...)
+}
+
+// Lookup returns the license with the given SPDX identifier, compared without
+// regard to case, and whether it was found
+func Lookup(id string) (Info, bool) {
+	if i, ok := spdxList.licenses[strings.ToLower(id)]; ok {
+		return spdxList.Licenses[i], true
+	}
+
+	return Info{}, false
+}
+
+// LookupException returns the license exception with the given SPDX
+// identifier, compared without regard to case, and whether it was found
+func LookupException(id string) (Exception, bool) {
+	if i, ok := spdxList.exceptions[strings.ToLower(id)]; ok {
+		return spdxList.Exceptions[i], true
+	}
+
+	return Exception{}, false
+}
+
+// CategoryOf returns the category of the license with the given SPDX
+// identifier, or CategoryUnknown if it is not in the list or has not been
+// categorized
+func CategoryOf(id string) Category {
+	if l, ok := Lookup(id); ok && l.Category != "" {
+		return l.Category
+	}
+
+	return CategoryUnknown
+}
+
+// IsCopyleft returns whether the category requires derived works to be
+// shared under the same license
+func (c Category) IsCopyleft() bool {
+	return c == CategoryWeakCopyleft || c == CategoryStrongCopyleft || c == CategoryNetworkCopyleft
+}
@@ -0,0 +1,319 @@
+package licenses
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/ion-channel/ionic/analyses"
+	"github.com/ion-channel/ionic/scans"
+)
+
+// Decision is a string enum of the outcomes of evaluating a license against a
+// policy
+type Decision string
+
+const (
+	// DecisionAllow denotes a license that can be used
+	DecisionAllow Decision = "allow"
+	// DecisionReview denotes a license that must be reviewed before use
+	DecisionReview Decision = "review"
+	// DecisionDeny denotes a license that cannot be used
+	DecisionDeny Decision = "deny"
+)
+
+// Policy represents the licenses an organization allows, denies, or requires
+// to be reviewed. A license is decided by the first of these that matches it:
+// the deny, review, and allow lists, in that order; the decision for its
+// category; and the default decision.
+type Policy struct {
+	// Allow, Deny, and Review list SPDX license identifiers, compared without
+	// regard to case. An entry can also be a license with an exception, such
+	// as "GPL-2.0-only WITH Classpath-exception-2.0", to match only that
+	// combination.
+	Allow  []string `json:"allow,omitempty"`
+	Deny   []string `json:"deny,omitempty"`
+	Review []string `json:"review,omitempty"`
+	// Categories decides the licenses not in any list by their category, such
+	// as denying strong and network copyleft licenses.
+	Categories map[Category]Decision `json:"categories,omitempty"`
+	// LinkingExceptions treats a strong copyleft license given with an
+	// exception, such as Classpath-exception-2.0, as weak copyleft.
+	LinkingExceptions bool `json:"linking_exceptions,omitempty"`
+	// Default is the decision for licenses not matched by anything else. It
+	// is DecisionReview when empty.
+	Default Decision `json:"default,omitempty"`
+}
+
+// Result is the decision of a policy for a license expression
+type Result struct {
+	Expression string   `json:"expression"`
+	Decision   Decision `json:"decision"`
+	// Licenses are the licenses of the expression responsible for the
+	// decision.
+	Licenses []string `json:"licenses"`
+	// Error is the reason the expression could not be parsed, in which case
+	// it is decided as a single, unknown license.
+	Error string `json:"error,omitempty"`
+}
+
+// Component is a package and its license, such as a component of an SBOM
+type Component struct {
+	Org     string `json:"org,omitempty"`
+	Name    string `json:"name"`
+	Version string `json:"version,omitempty"`
+	// License is the SPDX license expression of the package. An empty license
+	// is treated as NOASSERTION.
+	License string `json:"license"`
+}
+
+// Violation is a license, found in files or packages, that the policy denies
+// or requires to be reviewed
+type Violation struct {
+	// License is the normalized license expression found.
+	License  string   `json:"license"`
+	Decision Decision `json:"decision"`
+	// Licenses are the licenses of the expression responsible for the
+	// decision.
+	Licenses []string `json:"licenses"`
+	Files    []string `json:"files,omitempty"`
+	Packages []string `json:"packages,omitempty"`
+}
+
+// String returns the component as org/name@version
+func (c Component) String() string {
+	s := c.Name
+	if c.Org != "" {
+		s = c.Org + "/" + s
+	}
+
+	if c.Version != "" {
+		s += "@" + c.Version
+	}
+
+	return s
+}
+
+// Evaluate returns the decision of the policy for the license expression. A
+// choice between licenses (OR) is decided by the most permissive choice, and
+// a combination of licenses (AND) by the most restrictive.
+func (p *Policy) Evaluate(expression string) Result {
+	e, err := ParseExpression(expression)
+	if err != nil {
+		leaf := &Expression{License: strings.TrimSpace(expression)}
+		if leaf.License == "" {
+			leaf.License = NoAssertion
+		}
+
+		return Result{
+			Expression: leaf.License,
+			Decision:   p.decide(leaf),
+			Licenses:   []string{leaf.License},
+			Error:      err.Error(),
+		}
+	}
+
+	decision, licenses := p.evaluate(e)
+	return Result{
+		Expression: e.String(),
+		Decision:   decision,
+		Licenses:   licenses,
+	}
+}
+
+func (p *Policy) evaluate(e *Expression) (Decision, []string) {
+	if e.Operator == "" {
+		return p.decide(e), []string{e.String()}
+	}
+
+	left, leftLicenses := p.evaluate(e.Left)
+	right, rightLicenses := p.evaluate(e.Right)
+
+	switch {
+	case left == right:
+		return left, appendUnique(leftLicenses, rightLicenses...)
+	case (e.Operator == OperatorOr) == (decisionRank(left) < decisionRank(right)):
+		return left, leftLicenses
+	default:
+		return right, rightLicenses
+	}
+}
+
+// decide returns the decision for a single license
+func (p *Policy) decide(license *Expression) Decision {
+	names := []string{license.String(), license.License}
+	for _, list := range []struct {
+		licenses []string
+		decision Decision
+	}{
+		{p.Deny, DecisionDeny},
+		{p.Review, DecisionReview},
+		{p.Allow, DecisionAllow},
+	} {
+		if listContains(list.licenses, names...) {
+			return list.decision
+		}
+	}
+
+	category := CategoryOf(license.License)
+	if p.LinkingExceptions && license.Exception != "" && category == CategoryStrongCopyleft {
+		category = CategoryWeakCopyleft
+	}
+
+	if d, ok := p.Categories[category]; ok && d != "" {
+		return d
+	}
+
+	if p.Default != "" {
+		return p.Default
+	}
+
+	return DecisionReview
+}
+
+// CheckLicenses returns the violations of the policy by the licenses found by
+// a license scan
+func (p *Policy) CheckLicenses(results *scans.LicenseResults) []Violation {
+	v := newViolations(p)
+	v.addResults(results)
+	return v.list()
+}
+
+// CheckComponents returns the violations of the policy by the licenses of the
+// components
+func (p *Policy) CheckComponents(components []Component) []Violation {
+	v := newViolations(p)
+	for _, c := range components {
+		v.add(c.License, "", c.String())
+	}
+
+	return v.list()
+}
+
+// CheckAnalysis returns the violations of the policy by the licenses found by
+// the license scan of an analysis, and by the given components, such as those
+// of the project's SBOM. It returns an error if the analysis has no license
+// scan results.
+func (p *Policy) CheckAnalysis(a *analyses.Analysis, components ...Component) ([]Violation, error) {
+	if a == nil {
+		return nil, fmt.Errorf("analysis is required to check licenses")
+	}
+
+	results, err := a.Licenses()
+	if err != nil {
+		return nil, err
+	}
+
+	v := newViolations(p)
+	v.addResults(results)
+
+	for _, c := range components {
+		v.add(c.License, "", c.String())
+	}
+
+	return v.list(), nil
+}
+
+// violations collects the violations of a policy by license expression
+type violations struct {
+	policy *Policy
+	byKey  map[string]*Violation
+}
+
+func newViolations(p *Policy) *violations {
+	return &violations{policy: p, byKey: make(map[string]*Violation)}
+}
+
+func (v *violations) addResults(results *scans.LicenseResults) {
+	if results == nil || results.License == nil {
+		return
+	}
+
+	for _, t := range results.Type {
+		v.add(t.Name, results.Name, "")
+	}
+}
+
+func (v *violations) add(expression, file, pkg string) {
+	if strings.TrimSpace(expression) == "" {
+		expression = NoAssertion
+	}
+
+	r := v.policy.Evaluate(expression)
+	if r.Decision == DecisionAllow {
+		return
+	}
+
+	violation, ok := v.byKey[r.Expression]
+	if !ok {
+		violation = &Violation{License: r.Expression, Decision: r.Decision, Licenses: r.Licenses}
+		v.byKey[r.Expression] = violation
+	}
+
+	if file != "" {
+		violation.Files = appendUnique(violation.Files, file)
+	}
+
+	if pkg != "" {
+		violation.Packages = appendUnique(violation.Packages, pkg)
+	}
+}
+
+// list returns the violations, denied licenses first
+func (v *violations) list() []Violation {
+	list := make([]Violation, 0, len(v.byKey))
+	for _, violation := range v.byKey {
+		list = append(list, *violation)
+	}
+
+	sort.Slice(list, func(i, j int) bool {
+		if list[i].Decision != list[j].Decision {
+			return decisionRank(list[i].Decision) > decisionRank(list[j].Decision)
+		}
+
+		return list[i].License < list[j].License
+	})
+
+	return list
+}
+
+func decisionRank(d Decision) int {
+	switch d {
+	case DecisionAllow:
+		return 0
+	case DecisionDeny:
+		return 2
+	default:
+		return 1
+	}
+}
+
+func listContains(list []string, names ...string) bool {
+	for _, l := range list {
+		for _, n := range names {
+			if strings.EqualFold(strings.Join(strings.Fields(l), " "), n) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+func appendUnique(list []string, items ...string) []string {
+	for _, item := range items {
+		found := false
+		for _, l := range list {
+			if l == item {
+				found = true
+				break
+			}
+		}
+
+		if !found {
+			list = append(list, item)
+		}
+	}
+
+	return list
+}
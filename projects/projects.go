@@ -12,6 +12,8 @@ import (
 	"time"
 
 	"github.com/ion-channel/ionic/aliases"
+	"github.com/ion-channel/ionic/cpe"
+	"github.com/ion-channel/ionic/purl"
 	"github.com/ion-channel/ionic/rulesets"
 	"github.com/ion-channel/ionic/tags"
 )
@@ -202,6 +204,14 @@ func (p *Project) ValidateRequiredFields() (map[string]string, error) {
 		projErr = ErrInvalidProject
 	}
 
+	if p.CPE != "" {
+		_, err := cpe.Parse(p.CPE)
+		if err != nil {
+			invalidFields["cpe"] = "cpe must be a valid cpe uri or formatted string"
+			projErr = ErrInvalidProject
+		}
+	}
+
 	if p.Type != nil {
 		switch strings.ToLower(*p.Type) {
 		case "artifact":
@@ -289,6 +299,14 @@ func (p *Project) Validate(client http.Client) (map[string]string, error) {
 		projErr = ErrInvalidProject
 	}
 
+	if p.CPE != "" {
+		_, err := cpe.Parse(p.CPE)
+		if err != nil {
+			invalidFields["cpe"] = "cpe must be a valid cpe uri or formatted string"
+			projErr = ErrInvalidProject
+		}
+	}
+
 	if p.Type != nil {
 		switch strings.ToLower(*p.Type) {
 		case "artifact":
@@ -448,9 +466,28 @@ func (pf *Filter) Param() string {
 
 // ProjectSliceContains checks if a given []Project contains a Project matching the given Project.
 // This is used to prevent duplicate Projects from appearing in a slice of Projects.
-// Returns true if the given Project has an alias that matches an alias of any other project in the slice.
+// Projects that both have a valid package URL match if they name the same version of the same package,
+// ignoring qualifiers and subpaths, and do not match if they name different versions of it.
+// Otherwise, returns true if the given Project has an alias that matches an alias of any other project in the slice.
 func ProjectSliceContains(projects []Project, projectToFind Project) bool {
+	purlToFind, _ := purl.Parse(projectToFind.PURL)
+
+	packageToFind := ""
+	if purlToFind != nil {
+		packageToFind = purlToFind.WithoutVersion().String()
+	}
+
 	for _, project := range projects {
+		if purlToFind != nil {
+			if p, err := purl.Parse(project.PURL); err == nil && p.WithoutVersion().String() == packageToFind {
+				if p.Version == purlToFind.Version {
+					return true
+				}
+
+				continue
+			}
+		}
+
 		for _, alias := range project.Aliases {
 			for _, aliasToFind := range projectToFind.Aliases {
 				if alias.Equal(aliasToFind) {
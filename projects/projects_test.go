@@ -205,6 +205,41 @@ func TestProject(t *testing.T) {
 			})
 		})
 
+		g.Describe("CPE", func() {
+			g.BeforeEach(func() {
+				server.AddPath("/v1/ruleset/getRuleset").
+					SetMethods("HEAD").
+					SetStatus(http.StatusOK)
+			})
+
+			g.It("should permit valid cpes", func() {
+				var p Project
+				err := json.Unmarshal([]byte(fmt.Sprintf(sampleValidBlankProject, host, port)), &p)
+				Expect(err).To(BeNil())
+
+				p.CPE = "cpe:/a:apache:struts:2.5.10"
+				fs, err := p.Validate(*client)
+				Expect(err).To(BeNil())
+				Expect(len(fs)).To(Equal(0))
+
+				p.CPE = "cpe:2.3:a:apache:struts:2.5.10:*:*:*:*:*:*:*"
+				fs, err = p.Validate(*client)
+				Expect(err).To(BeNil())
+				Expect(len(fs)).To(Equal(0))
+			})
+
+			g.It("should say a project is invalid if its cpe is invalid", func() {
+				var p Project
+				err := json.Unmarshal([]byte(fmt.Sprintf(sampleValidBlankProject, host, port)), &p)
+				Expect(err).To(BeNil())
+
+				p.CPE = "cpe:2.3:a:apache:struts"
+				fs, err := p.Validate(*client)
+				Expect(err).To(Equal(ErrInvalidProject))
+				Expect(fs["cpe"]).To(Equal("cpe must be a valid cpe uri or formatted string"))
+			})
+		})
+
 		g.Describe("Source", func() {
 			g.BeforeEach(func() {
 				server.AddPath("/v1/ruleset/getRuleset").
@@ -434,6 +469,61 @@ func TestProject(t *testing.T) {
 
 				Expect(ProjectSliceContains(projectList, project2)).To(BeFalse())
 			})
+
+			g.It("should match projects by normalized package url", func() {
+				project := Project{
+					PURL:    "pkg:pypi/Django_Rest@3.12",
+					Aliases: []aliases.Alias{{Name: "Django_Rest", Version: "3.12"}},
+				}
+
+				project2 := Project{
+					PURL:    "pkg:pypi/django-rest@3.12",
+					Aliases: []aliases.Alias{{Name: "django-rest", Version: "3.12"}},
+				}
+
+				project3 := Project{
+					PURL:    "pkg:pypi/django-rest@3.13",
+					Aliases: []aliases.Alias{{Name: "Django_Rest", Version: "3.12"}},
+				}
+
+				projectList := []Project{project}
+
+				Expect(ProjectSliceContains(projectList, project2)).To(BeTrue())
+				Expect(ProjectSliceContains(projectList, project3)).To(BeFalse())
+			})
+
+			g.It("should ignore the qualifiers and subpaths of package urls", func() {
+				project := Project{PURL: "pkg:maven/org.apache.logging.log4j/log4j-core@2.14.1?type=jar"}
+				project2 := Project{PURL: "pkg:maven/org.apache.logging.log4j/log4j-core@2.14.1?classifier=sources#META-INF"}
+				project3 := Project{PURL: "pkg:maven/org.apache.logging.log4j/log4j-core@2.15.0?type=jar"}
+
+				projectList := []Project{project}
+
+				Expect(ProjectSliceContains(projectList, project2)).To(BeTrue())
+				Expect(ProjectSliceContains(projectList, project3)).To(BeFalse())
+			})
+
+			g.It("should match projects with different package urls by alias", func() {
+				project := Project{
+					PURL:    "pkg:github/ion-channel/ionic@1.0.0",
+					Aliases: []aliases.Alias{{Org: "ion-channel", Name: "ionic", Version: "1.0.0"}},
+				}
+
+				project2 := Project{
+					PURL:    "pkg:golang/github.com/ion-channel/ionic@1.0.0",
+					Aliases: []aliases.Alias{{Org: "ion-channel", Name: "ionic", Version: "1.0.0"}},
+				}
+
+				project3 := Project{
+					PURL:    "pkg:golang/github.com/ion-channel/ionic@1.0.0",
+					Aliases: []aliases.Alias{{Org: "ion-channel", Name: "ionic", Version: "2.0.0"}},
+				}
+
+				projectList := []Project{project}
+
+				Expect(ProjectSliceContains(projectList, project2)).To(BeTrue())
+				Expect(ProjectSliceContains(projectList, project3)).To(BeFalse())
+			})
 		})
 	})
 }
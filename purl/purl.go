@@ -0,0 +1,399 @@
+// Package purl parses, validates, and builds package URLs, as defined by the
+// package URL specification at https://github.com/package-url/purl-spec, such
+// as "pkg:maven/org.apache.commons/commons-text@1.9?type=jar". Names are
+// normalized the way the specification requires for each package type.
+package purl
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Scheme is the scheme of every package URL
+const Scheme = "pkg"
+
+// PackageURL is a parsed package URL
+type PackageURL struct {
+	// Type is the package type, or protocol, such as "maven" or "npm"
+	Type string
+	// Namespace is the name prefix of the package, such as a Maven group or
+	// an npm scope, with its segments separated by "/"
+	Namespace string
+	Name      string
+	Version   string
+	// Qualifiers are extra data about the package, such as the architecture
+	// of an operating system package
+	Qualifiers map[string]string
+	// Subpath is a path within the package, with its segments separated by
+	// "/"
+	Subpath string
+}
+
+// New returns the normalized package URL of the given parts, or an error if
+// it is not valid
+func New(typ, namespace, name, version string, qualifiers map[string]string, subpath string) (*PackageURL, error) {
+	p := &PackageURL{
+		Type:       typ,
+		Namespace:  namespace,
+		Name:       name,
+		Version:    version,
+		Qualifiers: qualifiers,
+		Subpath:    subpath,
+	}
+
+	p.normalize()
+	err := p.Validate()
+	if err != nil {
+		return nil, err
+	}
+
+	return p, nil
+}
+
+// Parse parses and normalizes a package URL
+func Parse(s string) (*PackageURL, error) {
+	p := &PackageURL{}
+	rest := strings.TrimSpace(s)
+
+	if i := strings.LastIndex(rest, "#"); i >= 0 {
+		subpath, err := decodeSegments(rest[i+1:])
+		if err != nil {
+			return nil, fmt.Errorf("invalid purl %q: %v", s, err.Error())
+		}
+
+		p.Subpath, rest = subpath, rest[:i]
+	}
+
+	if i := strings.LastIndex(rest, "?"); i >= 0 {
+		qualifiers, err := decodeQualifiers(rest[i+1:])
+		if err != nil {
+			return nil, fmt.Errorf("invalid purl %q: %v", s, err.Error())
+		}
+
+		p.Qualifiers, rest = qualifiers, rest[:i]
+	}
+
+	i := strings.Index(rest, ":")
+	if i < 0 || !strings.EqualFold(rest[:i], Scheme) {
+		return nil, fmt.Errorf("invalid purl %q: missing %v scheme", s, Scheme)
+	}
+
+	rest = strings.Trim(rest[i+1:], "/")
+	i = strings.Index(rest, "/")
+	if i < 0 {
+		return nil, fmt.Errorf("invalid purl %q: missing name", s)
+	}
+
+	p.Type, rest = rest[:i], rest[i+1:]
+
+	if i := strings.LastIndex(rest, "@"); i >= 0 && i > strings.LastIndex(rest, "/") {
+		version, err := decode(rest[i+1:])
+		if err != nil {
+			return nil, fmt.Errorf("invalid purl %q: %v", s, err.Error())
+		}
+
+		p.Version, rest = version, rest[:i]
+	}
+
+	rest = strings.TrimRight(rest, "/")
+	if i := strings.LastIndex(rest, "/"); i >= 0 {
+		namespace, err := decodeSegments(rest[:i])
+		if err != nil {
+			return nil, fmt.Errorf("invalid purl %q: %v", s, err.Error())
+		}
+
+		p.Namespace, rest = namespace, rest[i+1:]
+	}
+
+	name, err := decode(rest)
+	if err != nil {
+		return nil, fmt.Errorf("invalid purl %q: %v", s, err.Error())
+	}
+
+	p.Name = name
+	p.normalize()
+
+	err = p.Validate()
+	if err != nil {
+		return nil, fmt.Errorf("invalid purl %q: %v", s, err.Error())
+	}
+
+	return p, nil
+}
+
+// Normalize returns the canonical form of a package URL, or the string
+// unchanged if it is not a valid package URL
+func Normalize(s string) string {
+	p, err := Parse(s)
+	if err != nil {
+		return s
+	}
+
+	return p.String()
+}
+
+// Same returns true if two strings are valid package URLs with the same
+// canonical form, such as "pkg:PyPI/Django_Rest@1.0" and
+// "pkg:pypi/django-rest@1.0"
+func Same(a, b string) bool {
+	pa, err := Parse(a)
+	if err != nil {
+		return false
+	}
+
+	pb, err := Parse(b)
+	if err != nil {
+		return false
+	}
+
+	return pa.String() == pb.String()
+}
+
+// Validate returns an error if the package URL is missing a part that it, or
+// its type, requires, or has a type or qualifier key with invalid characters
+func (p *PackageURL) Validate() error {
+	if p.Type == "" {
+		return fmt.Errorf("missing type")
+	}
+
+	if !validKey(p.Type, "+") {
+		return fmt.Errorf("invalid type: %q", p.Type)
+	}
+
+	if p.Name == "" {
+		return fmt.Errorf("missing name")
+	}
+
+	for key := range p.Qualifiers {
+		if !validKey(key, "_") {
+			return fmt.Errorf("invalid qualifier key: %q", key)
+		}
+	}
+
+	switch p.Type {
+	case TypeMaven:
+		if p.Namespace == "" {
+			return fmt.Errorf("missing namespace of %v package", p.Type)
+		}
+	case TypeSwift:
+		if p.Namespace == "" || p.Version == "" {
+			return fmt.Errorf("missing namespace or version of %v package", p.Type)
+		}
+	case TypeCRAN:
+		if p.Version == "" {
+			return fmt.Errorf("missing version of %v package", p.Type)
+		}
+	}
+
+	return nil
+}
+
+// String returns the canonical form of the package URL, with its parts
+// percent-encoded as the specification does for each of them and its
+// qualifiers sorted by key
+func (p PackageURL) String() string {
+	var b strings.Builder
+	b.WriteString(Scheme + ":" + p.Type + "/")
+
+	if p.Namespace != "" {
+		b.WriteString(encodeSegments(p.Namespace) + "/")
+	}
+
+	b.WriteString(encode(p.Name, ":"))
+
+	if p.Version != "" {
+		b.WriteString("@" + encode(p.Version, ":"))
+	}
+
+	keys := make([]string, 0, len(p.Qualifiers))
+	for key, value := range p.Qualifiers {
+		if value != "" {
+			keys = append(keys, key)
+		}
+	}
+
+	sort.Strings(keys)
+	for i, key := range keys {
+		sep := "&"
+		if i == 0 {
+			sep = "?"
+		}
+
+		b.WriteString(sep + key + "=" + encode(p.Qualifiers[key], ":/"))
+	}
+
+	if p.Subpath != "" {
+		b.WriteString("#" + encodeSegments(p.Subpath))
+	}
+
+	return b.String()
+}
+
+// WithoutVersion returns a copy of the package URL without its version,
+// qualifiers, or subpath, identifying the package rather than a release of it
+func (p PackageURL) WithoutVersion() PackageURL {
+	return PackageURL{Type: p.Type, Namespace: p.Namespace, Name: p.Name}
+}
+
+// normalize lower cases the type and qualifier keys, cleans up the namespace
+// and subpath, and normalizes the namespace and name as the type requires
+func (p *PackageURL) normalize() {
+	p.Type = strings.ToLower(strings.TrimSpace(p.Type))
+	p.Namespace = cleanSegments(p.Namespace, false)
+	p.Subpath = cleanSegments(p.Subpath, true)
+
+	if len(p.Qualifiers) > 0 {
+		qualifiers := make(map[string]string, len(p.Qualifiers))
+		for key, value := range p.Qualifiers {
+			if value != "" {
+				qualifiers[strings.ToLower(key)] = value
+			}
+		}
+
+		p.Qualifiers = qualifiers
+	}
+
+	if t, ok := types[p.Type]; ok && t.normalize != nil {
+		t.normalize(p)
+	}
+}
+
+// validKey returns true if the type or qualifier key is made of letters,
+// digits, ".", "-", and the given extra characters, and does not start with
+// a digit
+func validKey(key, extra string) bool {
+	if key == "" || (key[0] >= '0' && key[0] <= '9') {
+		return false
+	}
+
+	for _, c := range key {
+		switch {
+		case c >= 'a' && c <= 'z', c >= 'A' && c <= 'Z', c >= '0' && c <= '9', c == '.', c == '-':
+		case strings.ContainsRune(extra, c):
+		default:
+			return false
+		}
+	}
+
+	return true
+}
+
+// cleanSegments removes empty segments from a "/" separated path, and "."
+// and ".." segments from a subpath
+func cleanSegments(path string, subpath bool) string {
+	segments := []string{}
+	for _, segment := range strings.Split(path, "/") {
+		if segment == "" || (subpath && (segment == "." || segment == "..")) {
+			continue
+		}
+
+		segments = append(segments, segment)
+	}
+
+	return strings.Join(segments, "/")
+}
+
+func decodeQualifiers(s string) (map[string]string, error) {
+	qualifiers := map[string]string{}
+	for _, pair := range strings.Split(s, "&") {
+		if pair == "" {
+			continue
+		}
+
+		parts := strings.SplitN(pair, "=", 2)
+		if len(parts) != 2 {
+			return nil, fmt.Errorf("qualifier %q is missing a value", pair)
+		}
+
+		value, err := decode(parts[1])
+		if err != nil {
+			return nil, err
+		}
+
+		qualifiers[strings.ToLower(parts[0])] = value
+	}
+
+	return qualifiers, nil
+}
+
+func decodeSegments(path string) (string, error) {
+	segments := strings.Split(path, "/")
+	for i, segment := range segments {
+		decoded, err := decode(segment)
+		if err != nil {
+			return "", err
+		}
+
+		segments[i] = decoded
+	}
+
+	return strings.Join(segments, "/"), nil
+}
+
+func encodeSegments(path string) string {
+	segments := strings.Split(path, "/")
+	for i, segment := range segments {
+		segments[i] = encode(segment, ":")
+	}
+
+	return strings.Join(segments, "/")
+}
+
+// decode decodes the percent-encoded characters of a part of a package URL
+func decode(s string) (string, error) {
+	if !strings.Contains(s, "%") {
+		return s, nil
+	}
+
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		if s[i] != '%' {
+			b.WriteByte(s[i])
+			continue
+		}
+
+		if i+2 >= len(s) || !isHex(s[i+1]) || !isHex(s[i+2]) {
+			return "", fmt.Errorf("invalid percent encoding in %q", s)
+		}
+
+		b.WriteByte(unhex(s[i+1])<<4 | unhex(s[i+2]))
+		i += 2
+	}
+
+	return b.String(), nil
+}
+
+// encode percent-encodes every character of a part of a package URL other
+// than letters, digits, ".", "-", "_", "~", and the given characters that are
+// safe in the part, such as the ":" of a version or the "/" of a qualifier
+// value
+func encode(s, safe string) string {
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z') || (c >= '0' && c <= '9') || strings.IndexByte(".-_~", c) >= 0 || strings.IndexByte(safe, c) >= 0 {
+			b.WriteByte(c)
+			continue
+		}
+
+		fmt.Fprintf(&b, "%%%02X", c)
+	}
+
+	return b.String()
+}
+
+func isHex(c byte) bool {
+	return (c >= '0' && c <= '9') || (c >= 'a' && c <= 'f') || (c >= 'A' && c <= 'F')
+}
+
+func unhex(c byte) byte {
+	switch {
+	case c >= 'a':
+		return c - 'a' + 10
+	case c >= 'A':
+		return c - 'A' + 10
+	}
+
+	return c - '0'
+}
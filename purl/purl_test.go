@@ -0,0 +1,151 @@
+package purl
+
+import (
+	"testing"
+
+	"github.com/franela/goblin"
+	. "github.com/onsi/gomega"
+
+	"github.com/ion-channel/ionic/dependencies"
+)
+
+func TestPURL(t *testing.T) {
+	g := goblin.Goblin(t)
+	RegisterFailHandler(func(m string, _ ...int) { g.Fail(m) })
+
+	g.Describe("Parse", func() {
+		g.It("should parse every part of a package url", func() {
+			p, err := Parse("pkg:maven/org.apache.commons/commons-text@1.9?type=jar&Classifier=sources#src/main/../java/./")
+			Expect(err).To(BeNil())
+			Expect(p.Type).To(Equal(TypeMaven))
+			Expect(p.Namespace).To(Equal("org.apache.commons"))
+			Expect(p.Name).To(Equal("commons-text"))
+			Expect(p.Version).To(Equal("1.9"))
+			Expect(p.Qualifiers).To(Equal(map[string]string{"type": "jar", "classifier": "sources"}))
+			Expect(p.Subpath).To(Equal("src/main/java"))
+			Expect(p.String()).To(Equal("pkg:maven/org.apache.commons/commons-text@1.9?classifier=sources&type=jar#src/main/java"))
+		})
+
+		g.It("should decode and encode special characters", func() {
+			p, err := Parse("pkg:npm/%40angular/core@12.0.0")
+			Expect(err).To(BeNil())
+			Expect(p.Namespace).To(Equal("@angular"))
+			Expect(p.Name).To(Equal("core"))
+			Expect(p.String()).To(Equal("pkg:npm/%40angular/core@12.0.0"))
+
+			p, err = Parse("pkg:golang/github.com/Sirupsen/logrus@v1.0.0+incompatible")
+			Expect(err).To(BeNil())
+			Expect(p.Namespace).To(Equal("github.com/Sirupsen"))
+			Expect(p.Version).To(Equal("v1.0.0+incompatible"))
+			Expect(p.String()).To(Equal("pkg:golang/github.com/Sirupsen/logrus@v1.0.0%2Bincompatible"))
+		})
+
+		g.It("should encode each part as the specification test suite does", func() {
+			for _, s := range []string{
+				"pkg:docker/cassandra@sha256:244fd47e07d1004f0aed9c",
+				"pkg:deb/debian/curl@7.50.3-1?arch=i386&distro=jessie",
+				"pkg:rpm/fedora/curl@7.50.3-1.fc25?arch=i386&distro=fedora-25",
+				"pkg:maven/org.apache.xmlgraphics/batik-anim@1.9.1?repository_url=repo.spring.io/release",
+				"pkg:generic/openssl@1.1.10g?checksum=sha256:de4d501267da&download_url=https://openssl.org/source/openssl-1.1.0g.tar.gz",
+				"pkg:deb/debian/dpkg@1:2.3?arch=amd64",
+				"pkg:npm/%40angular/animation@12.3.1",
+			} {
+				p, err := Parse(s)
+				Expect(err).To(BeNil(), s)
+				Expect(p.String()).To(Equal(s))
+			}
+
+			p, err := Parse("pkg:docker/cassandra@sha256%3A244fd47e07d1004f0aed9c")
+			Expect(err).To(BeNil())
+			Expect(p.Version).To(Equal("sha256:244fd47e07d1004f0aed9c"))
+			Expect(p.String()).To(Equal("pkg:docker/cassandra@sha256:244fd47e07d1004f0aed9c"))
+
+			p, err = New(TypeGeneric, "", "file name", "1.0", map[string]string{"download_url": "https://example.com/a b?c"}, "")
+			Expect(err).To(BeNil())
+			Expect(p.String()).To(Equal("pkg:generic/file%20name@1.0?download_url=https://example.com/a%20b%3Fc"))
+		})
+
+		g.It("should normalize names by type", func() {
+			Expect(Normalize("PKG:PyPI/Django_Rest.Framework@3.12")).To(Equal("pkg:pypi/django-rest-framework@3.12"))
+			Expect(Normalize("pkg:GitHub/Ion-Channel/Ionic")).To(Equal("pkg:github/ion-channel/ionic"))
+			Expect(Normalize("pkg:npm/@Angular/Core")).To(Equal("pkg:npm/%40angular/core"))
+			Expect(Normalize("pkg:maven/Org.Example/Widget@1.0")).To(Equal("pkg:maven/Org.Example/Widget@1.0"))
+			Expect(Normalize("not a purl")).To(Equal("not a purl"))
+
+			Expect(Same("pkg:pypi/Flask_Login@0.5", "pkg:pypi/flask-login@0.5")).To(BeTrue())
+			Expect(Same("pkg:pypi/flask@0.5", "pkg:pypi/flask@0.6")).To(BeFalse())
+			Expect(Same("pkg:pypi/flask", "flask")).To(BeFalse())
+		})
+
+		g.It("should reject invalid package urls", func() {
+			for _, s := range []string{
+				"",
+				"lodash",
+				"pkg:lodash",
+				"npm/lodash@4.17.21",
+				"pkg:n&pm/lodash",
+				"pkg:1npm/lodash",
+				"pkg:npm/lodash@%zz",
+				"pkg:npm/lodash?arch",
+				"pkg:maven/commons-text@1.9",
+				"pkg:cran/A3",
+				"pkg:swift/Alamofire",
+			} {
+				_, err := Parse(s)
+				Expect(err).NotTo(BeNil(), s)
+			}
+		})
+	})
+
+	g.Describe("Dependencies", func() {
+		g.It("should build package urls from orgs and names", func() {
+			for _, c := range []struct {
+				depType, org, name, version string
+				expected                    string
+			}{
+				{"maven", "org.apache.logging.log4j", "log4j-core", "2.14.0", "pkg:maven/org.apache.logging.log4j/log4j-core@2.14.0"},
+				{"gradle", "", "com.google.guava:guava", "30.0-jre", "pkg:maven/com.google.guava/guava@30.0-jre"},
+				{"npm", "@scope", "log4js", "", "pkg:npm/%40scope/log4js"},
+				{"npm", "", "@types/node", "16.0.0", "pkg:npm/%40types/node@16.0.0"},
+				{"npm", "lodash", "lodash", "4.17.21", "pkg:npm/lodash@4.17.21"},
+				{"pip", "psf", "Requests", "2.25.1", "pkg:pypi/requests@2.25.1"},
+				{"go", "", "github.com/stretchr/testify", "v1.7.0", "pkg:golang/github.com/stretchr/testify@v1.7.0"},
+				{"composer", "", "Laravel/Framework", "8.0.0", "pkg:composer/laravel/framework@8.0.0"},
+				{"Debian:11", "debian", "openssl", "1.1.1k-1", "pkg:deb/debian/openssl@1.1.1k-1"},
+			} {
+				p, err := FromOrgName(c.depType, c.org, c.name, c.version)
+				Expect(err).To(BeNil(), c.expected)
+				Expect(p.String()).To(Equal(c.expected))
+			}
+
+			_, err := FromOrgName("maven", "", "commons-text", "1.9")
+			Expect(err).NotTo(BeNil())
+		})
+
+		g.It("should convert between dependencies and package urls", func() {
+			dep := dependencies.Dependency{Type: "npm", Org: "babel", Name: "core", Version: "7.15.0"}
+			p, err := FromDependency(dep)
+			Expect(err).To(BeNil())
+			Expect(p.String()).To(Equal("pkg:npm/%40babel/core@7.15.0"))
+			Expect(p.Dependency()).To(Equal(dep))
+
+			p, err = Parse("pkg:golang/golang.org/x/text@v0.3.6")
+			Expect(err).To(BeNil())
+			Expect(p.Dependency()).To(Equal(dependencies.Dependency{Type: "golang", Org: "golang.org/x", Name: "golang.org/x/text", Version: "v0.3.6"}))
+			Expect(p.WithoutVersion().String()).To(Equal("pkg:golang/golang.org/x/text"))
+		})
+
+		g.It("should map ecosystems to types", func() {
+			Expect(TypeFor("Gradle")).To(Equal(TypeMaven))
+			Expect(TypeFor("crates.io")).To(Equal(TypeCargo))
+			Expect(TypeFor("Alpine:v3.14")).To(Equal(TypeAlpine))
+			Expect(TypeFor("conan")).To(Equal("conan"))
+
+			typ, ok := KnownType("gomod")
+			Expect(ok).To(BeTrue())
+			Expect(typ).To(Equal(TypeGolang))
+			_, ok = KnownType("conan")
+			Expect(ok).To(BeFalse())
+		})
+	})
+}
@@ -0,0 +1,224 @@
+package purl
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/ion-channel/ionic/dependencies"
+)
+
+const (
+	// TypeAlpine is the type of Alpine Linux packages
+	TypeAlpine = "apk"
+	// TypeBitbucket is the type of Bitbucket repositories
+	TypeBitbucket = "bitbucket"
+	// TypeCargo is the type of Rust crates
+	TypeCargo = "cargo"
+	// TypeComposer is the type of PHP Composer packages
+	TypeComposer = "composer"
+	// TypeCRAN is the type of R packages
+	TypeCRAN = "cran"
+	// TypeDebian is the type of Debian and Ubuntu packages
+	TypeDebian = "deb"
+	// TypeDocker is the type of Docker images
+	TypeDocker = "docker"
+	// TypeGeneric is the type of packages without a more specific type
+	TypeGeneric = "generic"
+	// TypeGem is the type of Ruby gems
+	TypeGem = "gem"
+	// TypeGitHub is the type of GitHub repositories
+	TypeGitHub = "github"
+	// TypeGitLab is the type of GitLab repositories
+	TypeGitLab = "gitlab"
+	// TypeGolang is the type of Go modules and packages
+	TypeGolang = "golang"
+	// TypeHex is the type of Erlang and Elixir Hex packages
+	TypeHex = "hex"
+	// TypeMaven is the type of Maven artifacts
+	TypeMaven = "maven"
+	// TypeNPM is the type of npm packages
+	TypeNPM = "npm"
+	// TypeNuGet is the type of .NET NuGet packages
+	TypeNuGet = "nuget"
+	// TypePub is the type of Dart and Flutter packages
+	TypePub = "pub"
+	// TypePyPI is the type of Python packages
+	TypePyPI = "pypi"
+	// TypeRPM is the type of RPM packages
+	TypeRPM = "rpm"
+	// TypeSwift is the type of Swift packages
+	TypeSwift = "swift"
+)
+
+// packageType is how the names of a package type are normalized, and whether
+// its packages are named with a namespace
+type packageType struct {
+	namespaced bool
+	normalize  func(p *PackageURL)
+}
+
+var types = map[string]packageType{
+	TypeAlpine:    {namespaced: true, normalize: lowerAll},
+	TypeBitbucket: {namespaced: true, normalize: lowerAll},
+	TypeCargo:     {},
+	TypeComposer:  {namespaced: true, normalize: lowerAll},
+	TypeCRAN:      {},
+	TypeDebian:    {namespaced: true, normalize: lowerAll},
+	TypeDocker:    {namespaced: true},
+	TypeGeneric:   {namespaced: true},
+	TypeGem:       {},
+	TypeGitHub:    {namespaced: true, normalize: lowerAll},
+	TypeGitLab:    {namespaced: true, normalize: lowerAll},
+	TypeGolang:    {namespaced: true},
+	TypeHex:       {namespaced: true, normalize: lowerAll},
+	TypeMaven:     {namespaced: true},
+	TypeNPM:       {namespaced: true, normalize: lowerAll},
+	TypeNuGet:     {},
+	TypePub:       {normalize: lowerAll},
+	TypePyPI:      {normalize: normalizePyPI},
+	TypeRPM:       {namespaced: true},
+	TypeSwift:     {namespaced: true},
+}
+
+// ecosystems maps the names of ecosystems, as used by dependency types, OSV,
+// and product languages, to their package URL type
+var ecosystems = map[string]string{
+	"gradle":     TypeMaven,
+	"sbt":        TypeMaven,
+	"java":       TypeMaven,
+	"jar":        TypeMaven,
+	"yarn":       TypeNPM,
+	"pnpm":       TypeNPM,
+	"node":       TypeNPM,
+	"javascript": TypeNPM,
+	"pip":        TypePyPI,
+	"pipenv":     TypePyPI,
+	"poetry":     TypePyPI,
+	"python":     TypePyPI,
+	"gems":       TypeGem,
+	"rubygems":   TypeGem,
+	"ruby":       TypeGem,
+	"bundler":    TypeGem,
+	"go":         TypeGolang,
+	"gomod":      TypeGolang,
+	"dotnet":     TypeNuGet,
+	".net":       TypeNuGet,
+	"crates.io":  TypeCargo,
+	"rust":       TypeCargo,
+	"packagist":  TypeComposer,
+	"php":        TypeComposer,
+	"debian":     TypeDebian,
+	"ubuntu":     TypeDebian,
+	"redhat":     TypeRPM,
+	"centos":     TypeRPM,
+	"fedora":     TypeRPM,
+	"alpine":     TypeAlpine,
+	"r":          TypeCRAN,
+}
+
+var pypiSeparators = regexp.MustCompile(`[-_.]+`)
+
+// TypeFor returns the package URL type of an ecosystem, such as "maven" for
+// "gradle" or "golang" for "go", ignoring release suffixes such as the "11" of
+// "Debian:11". Unknown ecosystems are returned lower cased.
+func TypeFor(ecosystem string) string {
+	ecosystem = strings.ToLower(strings.TrimSpace(strings.SplitN(ecosystem, ":", 2)[0]))
+	if t, ok := ecosystems[ecosystem]; ok {
+		return t
+	}
+
+	return ecosystem
+}
+
+// KnownType returns the package URL type of an ecosystem, as TypeFor does, and
+// whether it is one of the types this package knows how to name packages of
+func KnownType(ecosystem string) (string, bool) {
+	typ := TypeFor(ecosystem)
+	_, ok := types[typ]
+	return typ, ok
+}
+
+// FromOrgName returns the package URL of a package named by the org, name,
+// and type of a dependency, placing the org and name in the namespace and name
+// the way the package type expects. Maven names may be given as
+// "group:artifact", npm names as "@scope/name", and Go and Composer names as
+// full paths such as "github.com/stretchr/testify".
+func FromOrgName(depType, org, name, version string) (*PackageURL, error) {
+	typ := TypeFor(depType)
+	org, name = strings.TrimSpace(org), strings.TrimSpace(name)
+
+	switch typ {
+	case TypeMaven:
+		if parts := strings.SplitN(name, ":", 2); len(parts) == 2 {
+			org, name = parts[0], parts[1]
+		}
+	case TypeNPM:
+		if strings.HasPrefix(name, "@") && strings.Contains(name, "/") {
+			parts := strings.SplitN(name, "/", 2)
+			org, name = parts[0], parts[1]
+		}
+
+		org = strings.TrimPrefix(org, "@")
+		if org != "" && org != name {
+			org = "@" + org
+		} else {
+			org = ""
+		}
+	case TypeGolang, TypeComposer:
+		if i := strings.LastIndex(name, "/"); i >= 0 {
+			org, name = name[:i], name[i+1:]
+		}
+	}
+
+	if org == name && typ != TypeMaven {
+		org = ""
+	}
+
+	if t, ok := types[typ]; ok && !t.namespaced {
+		org = ""
+	}
+
+	return New(typ, org, name, version, nil, "")
+}
+
+// FromDependency returns the package URL of a dependency
+func FromDependency(dep dependencies.Dependency) (*PackageURL, error) {
+	return FromOrgName(dep.Type, dep.Org, dep.Name, dep.Version)
+}
+
+// Dependency returns the name, org, type, and version of the package as a
+// dependency, without the "@" of npm scopes, and with the full path as the
+// name of Go packages
+func (p PackageURL) Dependency() dependencies.Dependency {
+	dep := dependencies.Dependency{
+		Type:    p.Type,
+		Org:     p.Namespace,
+		Name:    p.Name,
+		Version: p.Version,
+	}
+
+	switch p.Type {
+	case TypeNPM:
+		dep.Org = strings.TrimPrefix(p.Namespace, "@")
+	case TypeGolang:
+		if p.Namespace != "" {
+			dep.Name = p.Namespace + "/" + p.Name
+		}
+	}
+
+	return dep
+}
+
+// lowerAll lower cases the namespace and name of types that are not case
+// sensitive
+func lowerAll(p *PackageURL) {
+	p.Namespace = strings.ToLower(p.Namespace)
+	p.Name = strings.ToLower(p.Name)
+}
+
+// normalizePyPI lower cases the name of a Python package and replaces its
+// separators with "-", as PEP 503 does
+func normalizePyPI(p *PackageURL) {
+	p.Namespace = strings.ToLower(p.Namespace)
+	p.Name = pypiSeparators.ReplaceAllString(strings.ToLower(p.Name), "-")
+}
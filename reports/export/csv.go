@@ -0,0 +1,46 @@
+package export
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+)
+
+// WriteCSV writes the sheet to the given writer as CSV, with a header row of
+// the column headers followed by a record for each row. Records are written
+// as the rows are read.
+func WriteCSV(w io.Writer, sheet Sheet) error {
+	cw := csv.NewWriter(w)
+
+	record := make([]string, len(sheet.Columns))
+	for i, c := range sheet.Columns {
+		record[i] = c.Header
+	}
+
+	err := cw.Write(record)
+	if err != nil {
+		return fmt.Errorf("failed to write csv header: %v", err.Error())
+	}
+
+	if sheet.Rows != nil {
+		for row, ok := sheet.Rows(); ok; row, ok = sheet.Rows() {
+			for i, v := range values(sheet.Columns, row) {
+				record[i] = formatValue(v, sheet.Columns[i].Decimals)
+			}
+
+			err := cw.Write(record)
+			if err != nil {
+				return fmt.Errorf("failed to write csv record: %v", err.Error())
+			}
+		}
+	}
+
+	cw.Flush()
+
+	err = cw.Error()
+	if err != nil {
+		return fmt.Errorf("failed to write csv: %v", err.Error())
+	}
+
+	return nil
+}
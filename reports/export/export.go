@@ -0,0 +1,304 @@
+// Package export writes the project, analysis, and vulnerability export data
+// returned by the Ion Channel API to CSV and XLSX spreadsheets. Columns can be
+// selected and reordered, and rows are written as they are read so that large
+// portfolios can be exported without holding every row in memory.
+package export
+
+import (
+	"fmt"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ion-channel/ionic/analyses"
+	"github.com/ion-channel/ionic/reports"
+)
+
+const maxSheetNameLength = 31
+
+var invalidSheetNameChars = regexp.MustCompile(`[\[\]:*?/\\]`)
+
+// Column is a column of exported data
+type Column struct {
+	// Key identifies the column when selecting columns.
+	Key string
+	// Header is the human friendly name of the column written in the first
+	// row.
+	Header string
+	// Decimals is the number of decimal places floating point values are
+	// rounded to.
+	Decimals int
+	// Value returns the value of the column for a row. A nil value is written
+	// as an empty cell.
+	Value func(row interface{}) interface{}
+}
+
+// Rows returns the next row of a sheet each time it is called, and false once
+// there are no more rows. Rows can be read from a slice, a channel, or a
+// paginated API without holding all of them in memory.
+type Rows func() (interface{}, bool)
+
+// Sheet is a named table of exported data
+type Sheet struct {
+	// Name is the name of the sheet in an XLSX workbook. It is made valid and
+	// unique when the workbook is written.
+	Name    string
+	Columns []Column
+	Rows    Rows
+}
+
+// ProjectColumns are the columns of reports.ExportFields, in their default
+// order
+var ProjectColumns = []Column{
+	{Key: "project_name", Header: "Project", Value: projectValue(func(p reports.ExportFields) interface{} { return p.ProjectName })},
+	{Key: "project_id", Header: "Project ID", Value: projectValue(func(p reports.ExportFields) interface{} { return p.ProjectID })},
+	{Key: "product_name", Header: "Product", Value: projectValue(func(p reports.ExportFields) interface{} { return p.ProductName })},
+	{Key: "org", Header: "Organization", Value: projectValue(func(p reports.ExportFields) interface{} { return p.Org })},
+	{Key: "version", Header: "Version", Value: projectValue(func(p reports.ExportFields) interface{} { return p.Version })},
+	{Key: "cpe", Header: "CPE", Value: projectValue(func(p reports.ExportFields) interface{} { return p.CPE })},
+	{Key: "source", Header: "Source", Value: projectValue(func(p reports.ExportFields) interface{} { return p.Source })},
+	{Key: "current_status", Header: "Status", Value: projectValue(func(p reports.ExportFields) interface{} { return p.CurrentStatus })},
+	{Key: "committers_total_count", Header: "Committers", Value: projectValue(func(p reports.ExportFields) interface{} { return intValue(p.CommittersTotalCount) })},
+	{Key: "days_since_last_commit", Header: "Days Since Last Commit", Value: projectValue(func(p reports.ExportFields) interface{} { return intValue(p.DaysSinceLastCommit) })},
+	{Key: "vuln_count", Header: "Vulnerabilities", Value: projectValue(func(p reports.ExportFields) interface{} { return intValue(p.VulnCount) })},
+	{Key: "critical_vuln_count", Header: "Critical Vulnerabilities", Value: projectValue(func(p reports.ExportFields) interface{} { return intValue(p.CritVulnCount) })},
+	{Key: "high_vuln_count", Header: "High Vulnerabilities", Value: projectValue(func(p reports.ExportFields) interface{} { return intValue(p.HighVulnCount) })},
+	{Key: "virus_count", Header: "Viruses", Value: projectValue(func(p reports.ExportFields) interface{} { return intValue(p.VirusCount) })},
+}
+
+// AnalysisColumns are the columns of analyses.ExportData, in their default
+// order
+var AnalysisColumns = []Column{
+	{Key: "analysis_id", Header: "Analysis ID", Value: analysisValue(func(a analyses.ExportData) interface{} { return a.AnalysisID })},
+	{Key: "project_id", Header: "Project ID", Value: analysisValue(func(a analyses.ExportData) interface{} { return a.ProjectID })},
+	{Key: "cpe", Header: "CPE", Value: analysisValue(func(a analyses.ExportData) interface{} { return a.CPE })},
+	{Key: "source", Header: "Source", Value: analysisValue(func(a analyses.ExportData) interface{} { return a.Source })},
+	{Key: "status", Header: "Status", Value: analysisValue(func(a analyses.ExportData) interface{} { return a.Status })},
+	{Key: "committers_total_count", Header: "Committers", Value: analysisValue(func(a analyses.ExportData) interface{} { return a.CommittersTotalCount })},
+	{Key: "days_since_last_commit", Header: "Days Since Last Commit", Value: analysisValue(func(a analyses.ExportData) interface{} { return a.DaysSinceLastCommit })},
+	{Key: "vulnerability_count", Header: "Vulnerabilities", Value: analysisValue(func(a analyses.ExportData) interface{} { return a.VulnerabilityCount })},
+	{Key: "critical_vulnerability_count", Header: "Critical Vulnerabilities", Value: analysisValue(func(a analyses.ExportData) interface{} { return a.CritVulnCount })},
+	{Key: "high_vulnerability_count", Header: "High Vulnerabilities", Value: analysisValue(func(a analyses.ExportData) interface{} { return a.HighVulnCount })},
+	{Key: "virus_count", Header: "Viruses", Value: analysisValue(func(a analyses.ExportData) interface{} { return a.VirusCount })},
+}
+
+// VulnerabilityColumns are the columns of analyses.VulnerabilityExportData,
+// in their default order. The score is rounded to one decimal place.
+var VulnerabilityColumns = []Column{
+	{Key: "project_name", Header: "Project", Value: vulnerabilityValue(func(v analyses.VulnerabilityExportData) interface{} { return v.ProjectName })},
+	{Key: "project_id", Header: "Project ID", Value: vulnerabilityValue(func(v analyses.VulnerabilityExportData) interface{} { return v.ProjectID })},
+	{Key: "analysis_id", Header: "Analysis ID", Value: vulnerabilityValue(func(v analyses.VulnerabilityExportData) interface{} { return v.AnalysisID })},
+	{Key: "external_id", Header: "Vulnerability", Value: vulnerabilityValue(func(v analyses.VulnerabilityExportData) interface{} { return v.ExternalID })},
+	{Key: "title", Header: "Title", Value: vulnerabilityValue(func(v analyses.VulnerabilityExportData) interface{} { return v.Title })},
+	{Key: "severity", Header: "Severity", Value: vulnerabilityValue(func(v analyses.VulnerabilityExportData) interface{} { return v.Severity })},
+	{Key: "score", Header: "Score", Decimals: 1, Value: vulnerabilityValue(func(v analyses.VulnerabilityExportData) interface{} { return float64(v.Score) })},
+	{Key: "dependency", Header: "Dependency", Value: vulnerabilityValue(func(v analyses.VulnerabilityExportData) interface{} { return v.Dependency })},
+	{Key: "dependency_version", Header: "Dependency Version", Value: vulnerabilityValue(func(v analyses.VulnerabilityExportData) interface{} { return v.DependencyVersion })},
+}
+
+// Select returns the columns with the given keys, in the order the keys are
+// given. All of the columns are returned if no keys are given. It returns an
+// error if a key does not match any column.
+func Select(columns []Column, keys ...string) ([]Column, error) {
+	if len(keys) == 0 {
+		return columns, nil
+	}
+
+	byKey := make(map[string]Column, len(columns))
+	for _, c := range columns {
+		byKey[c.Key] = c
+	}
+
+	selected := make([]Column, 0, len(keys))
+	for _, key := range keys {
+		c, ok := byKey[strings.TrimSpace(key)]
+		if !ok {
+			return nil, fmt.Errorf("unknown column: %v", key)
+		}
+
+		selected = append(selected, c)
+	}
+
+	return selected, nil
+}
+
+// ProjectSheet returns a sheet of the exported project data with the default
+// columns
+func ProjectSheet(name string, data *reports.ExportedData) Sheet {
+	var projects []reports.ExportFields
+	if data != nil {
+		projects = data.Projects
+	}
+
+	i := 0
+	return Sheet{
+		Name:    name,
+		Columns: ProjectColumns,
+		Rows: func() (interface{}, bool) {
+			if i >= len(projects) {
+				return nil, false
+			}
+
+			i++
+			return projects[i-1], true
+		},
+	}
+}
+
+// AnalysisSheet returns a sheet of the exported analysis data with the
+// default columns
+func AnalysisSheet(name string, data []analyses.ExportData) Sheet {
+	i := 0
+	return Sheet{
+		Name:    name,
+		Columns: AnalysisColumns,
+		Rows: func() (interface{}, bool) {
+			if i >= len(data) {
+				return nil, false
+			}
+
+			i++
+			return data[i-1], true
+		},
+	}
+}
+
+// VulnerabilitySheet returns a sheet of the exported vulnerability data with
+// the default columns
+func VulnerabilitySheet(name string, data []analyses.VulnerabilityExportData) Sheet {
+	i := 0
+	return Sheet{
+		Name:    name,
+		Columns: VulnerabilityColumns,
+		Rows: func() (interface{}, bool) {
+			if i >= len(data) {
+				return nil, false
+			}
+
+			i++
+			return data[i-1], true
+		},
+	}
+}
+
+// values returns the values of the columns for the row, with floating point
+// values rounded
+func values(columns []Column, row interface{}) []interface{} {
+	vals := make([]interface{}, len(columns))
+	for i, c := range columns {
+		if c.Value == nil {
+			continue
+		}
+
+		v := c.Value(row)
+		if f, ok := v.(float64); ok {
+			v = round(f, c.Decimals)
+		}
+
+		vals[i] = v
+	}
+
+	return vals
+}
+
+// formatValue returns the text of a value as written to a CSV file or a
+// string cell
+func formatValue(v interface{}, decimals int) string {
+	switch t := v.(type) {
+	case nil:
+		return ""
+	case string:
+		return t
+	case float64:
+		return strconv.FormatFloat(t, 'f', decimals, 64)
+	case time.Time:
+		if t.IsZero() {
+			return ""
+		}
+
+		return t.UTC().Format(time.RFC3339)
+	default:
+		return fmt.Sprintf("%v", t)
+	}
+}
+
+func round(f float64, decimals int) float64 {
+	p := math.Pow(10, float64(decimals))
+	return math.Round(f*p) / p
+}
+
+// sheetNames returns a valid and unique name for each sheet
+func sheetNames(sheets []Sheet) []string {
+	names := make([]string, len(sheets))
+	used := make(map[string]bool)
+
+	for i, s := range sheets {
+		base := strings.TrimSpace(invalidSheetNameChars.ReplaceAllString(s.Name, "_"))
+		if base == "" {
+			base = fmt.Sprintf("Sheet%v", i+1)
+		}
+
+		base = truncate(base, maxSheetNameLength)
+
+		name := base
+		for n := 2; used[strings.ToLower(name)]; n++ {
+			suffix := fmt.Sprintf(" (%v)", n)
+			name = truncate(base, maxSheetNameLength-len(suffix)) + suffix
+		}
+
+		used[strings.ToLower(name)] = true
+		names[i] = name
+	}
+
+	return names
+}
+
+// truncate shortens the string to at most n characters
+func truncate(s string, n int) string {
+	r := []rune(s)
+	if len(r) <= n {
+		return s
+	}
+
+	return string(r[:n])
+}
+
+func projectValue(get func(reports.ExportFields) interface{}) func(interface{}) interface{} {
+	return func(row interface{}) interface{} {
+		if p, ok := row.(reports.ExportFields); ok {
+			return get(p)
+		}
+
+		return nil
+	}
+}
+
+func analysisValue(get func(analyses.ExportData) interface{}) func(interface{}) interface{} {
+	return func(row interface{}) interface{} {
+		if a, ok := row.(analyses.ExportData); ok {
+			return get(a)
+		}
+
+		return nil
+	}
+}
+
+func vulnerabilityValue(get func(analyses.VulnerabilityExportData) interface{}) func(interface{}) interface{} {
+	return func(row interface{}) interface{} {
+		if v, ok := row.(analyses.VulnerabilityExportData); ok {
+			return get(v)
+		}
+
+		return nil
+	}
+}
+
+func intValue(i *int) interface{} {
+	if i == nil {
+		return nil
+	}
+
+	return *i
+}
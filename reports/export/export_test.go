@@ -0,0 +1,167 @@
+package export
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/csv"
+	"encoding/xml"
+	"io/ioutil"
+	"strings"
+	"testing"
+
+	"github.com/ion-channel/ionic/analyses"
+	"github.com/ion-channel/ionic/reports"
+
+	"github.com/franela/goblin"
+	. "github.com/onsi/gomega"
+)
+
+func TestExport(t *testing.T) {
+	g := goblin.Goblin(t)
+	RegisterFailHandler(func(m string, _ ...int) { g.Fail(m) })
+
+	committers := 7
+	projects := &reports.ExportedData{
+		Projects: []reports.ExportFields{
+			{ProjectName: "ionic", ProjectID: "p1", CommittersTotalCount: &committers},
+			{ProjectName: "statler", ProjectID: "p2"},
+		},
+	}
+
+	vulns := []analyses.VulnerabilityExportData{
+		{ProjectName: "ionic", ExternalID: "CVE-2020-0001", Severity: "high", Score: 7.80000002, Dependency: "lib"},
+		{ProjectName: "ionic", ExternalID: "CVE-2020-0002", Severity: "low", Score: 2.25, Dependency: "other, lib"},
+	}
+
+	g.Describe("Columns", func() {
+		g.It("should select and order columns by key", func() {
+			cols, err := Select(VulnerabilityColumns, "score", "external_id")
+			Expect(err).To(BeNil())
+			Expect(cols).To(HaveLen(2))
+			Expect(cols[0].Header).To(Equal("Score"))
+			Expect(cols[1].Header).To(Equal("Vulnerability"))
+
+			cols, err = Select(VulnerabilityColumns)
+			Expect(err).To(BeNil())
+			Expect(cols).To(HaveLen(len(VulnerabilityColumns)))
+		})
+
+		g.It("should return an error for an unknown column", func() {
+			_, err := Select(ProjectColumns, "project_name", "nope")
+			Expect(err).NotTo(BeNil())
+			Expect(err.Error()).To(Equal("unknown column: nope"))
+		})
+
+		g.It("should make sheet names valid and unique", func() {
+			names := sheetNames([]Sheet{
+				{Name: "team/one"},
+				{Name: "team/one"},
+				{Name: ""},
+				{Name: strings.Repeat("x", 40)},
+				{Name: strings.Repeat("x", 40)},
+			})
+			Expect(names).To(Equal([]string{"team_one", "team_one (2)", "Sheet3", strings.Repeat("x", 31), strings.Repeat("x", 27) + " (2)"}))
+		})
+
+		g.It("should build cell references", func() {
+			Expect(cellRef(0, 1)).To(Equal("A1"))
+			Expect(cellRef(25, 2)).To(Equal("Z2"))
+			Expect(cellRef(26, 3)).To(Equal("AA3"))
+			Expect(cellRef(701, 4)).To(Equal("ZZ4"))
+			Expect(cellRef(702, 5)).To(Equal("AAA5"))
+		})
+	})
+
+	g.Describe("CSV", func() {
+		g.It("should write a header and a record per row", func() {
+			var buf bytes.Buffer
+			Expect(WriteCSV(&buf, ProjectSheet("projects", projects))).To(Succeed())
+
+			records, err := csv.NewReader(&buf).ReadAll()
+			Expect(err).To(BeNil())
+			Expect(records).To(HaveLen(3))
+			Expect(records[0][0]).To(Equal("Project"))
+			Expect(records[0][8]).To(Equal("Committers"))
+			Expect(records[1][8]).To(Equal("7"))
+			Expect(records[2][8]).To(Equal(""))
+		})
+
+		g.It("should write selected columns with rounded scores", func() {
+			sheet := VulnerabilitySheet("vulnerabilities", vulns)
+
+			var err error
+			sheet.Columns, err = Select(sheet.Columns, "external_id", "score", "dependency")
+			Expect(err).To(BeNil())
+
+			var buf bytes.Buffer
+			Expect(WriteCSV(&buf, sheet)).To(Succeed())
+			Expect(buf.String()).To(Equal("Vulnerability,Score,Dependency\nCVE-2020-0001,7.8,lib\nCVE-2020-0002,2.3,\"other, lib\"\n"))
+		})
+
+		g.It("should write rows as they are read", func() {
+			ch := make(chan interface{}, 2)
+			ch <- analyses.ExportData{AnalysisID: "a1", VulnerabilityCount: 3}
+			ch <- analyses.ExportData{AnalysisID: "a2"}
+			close(ch)
+
+			cols, _ := Select(AnalysisColumns, "analysis_id", "vulnerability_count")
+			sheet := Sheet{
+				Columns: cols,
+				Rows: func() (interface{}, bool) {
+					row, ok := <-ch
+					return row, ok
+				},
+			}
+
+			var buf bytes.Buffer
+			Expect(WriteCSV(&buf, sheet)).To(Succeed())
+			Expect(buf.String()).To(Equal("Analysis ID,Vulnerabilities\na1,3\na2,0\n"))
+		})
+	})
+
+	g.Describe("XLSX", func() {
+		g.It("should write a workbook with a worksheet per sheet", func() {
+			var buf bytes.Buffer
+			err := WriteXLSX(&buf,
+				ProjectSheet("Team <One>", projects),
+				VulnerabilitySheet("Team <One>", vulns),
+			)
+			Expect(err).To(BeNil())
+
+			zr, err := zip.NewReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+			Expect(err).To(BeNil())
+
+			parts := make(map[string]string)
+			for _, f := range zr.File {
+				rc, err := f.Open()
+				Expect(err).To(BeNil())
+				b, err := ioutil.ReadAll(rc)
+				Expect(err).To(BeNil())
+				rc.Close()
+
+				var v interface{}
+				Expect(xml.Unmarshal(b, &v)).To(Succeed(), f.Name)
+				parts[f.Name] = string(b)
+			}
+
+			Expect(parts).To(HaveKey("[Content_Types].xml"))
+			Expect(parts).To(HaveKey("xl/styles.xml"))
+			Expect(parts["xl/workbook.xml"]).To(ContainSubstring(`<sheet name="Team &lt;One&gt;" sheetId="1" r:id="rId1"/>`))
+			Expect(parts["xl/workbook.xml"]).To(ContainSubstring(`<sheet name="Team &lt;One&gt; (2)" sheetId="2" r:id="rId2"/>`))
+			Expect(parts["xl/styles.xml"]).To(ContainSubstring(`formatCode="0.0"`))
+
+			projectSheet := parts["xl/worksheets/sheet1.xml"]
+			Expect(projectSheet).To(ContainSubstring(`<c r="A1" s="1" t="inlineStr"><is><t xml:space="preserve">Project</t></is></c>`))
+			Expect(projectSheet).To(ContainSubstring(`<c r="I2"><v>7</v></c>`))
+			Expect(projectSheet).NotTo(ContainSubstring(`r="I3"`))
+
+			vulnSheet := parts["xl/worksheets/sheet2.xml"]
+			Expect(vulnSheet).To(ContainSubstring(`<c r="G2" s="3"><v>7.8</v></c>`))
+		})
+
+		g.It("should require a sheet", func() {
+			var buf bytes.Buffer
+			Expect(WriteXLSX(&buf)).NotTo(Succeed())
+		})
+	})
+}
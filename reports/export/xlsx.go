@@ -0,0 +1,287 @@
+package export
+
+import (
+	"archive/zip"
+	"bufio"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	xlsxContentTypes = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Types xmlns="http://schemas.openxmlformats.org/package/2006/content-types">
+<Default Extension="rels" ContentType="application/vnd.openxmlformats-package.relationships+xml"/>
+<Default Extension="xml" ContentType="application/xml"/>
+<Override PartName="/xl/workbook.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.sheet.main+xml"/>
+<Override PartName="/xl/styles.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.styles+xml"/>
+%v</Types>`
+	xlsxSheetContentType = `<Override PartName="/xl/worksheets/sheet%v.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.worksheet+xml"/>
+`
+
+	xlsxRootRels = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">
+<Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/officeDocument" Target="xl/workbook.xml"/>
+</Relationships>`
+
+	xlsxWorkbook = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<workbook xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main" xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships">
+<sheets>
+%v</sheets>
+</workbook>`
+	xlsxWorkbookSheet = `<sheet name="%v" sheetId="%v" r:id="rId%v"/>
+`
+
+	xlsxWorkbookRels = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">
+%v<Relationship Id="rId%v" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/styles" Target="styles.xml"/>
+</Relationships>`
+	xlsxWorkbookSheetRel = `<Relationship Id="rId%v" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/worksheet" Target="worksheets/sheet%v.xml"/>
+`
+
+	xlsxStylesXML = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<styleSheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main">
+<numFmts count="%v">%v</numFmts>
+<fonts count="2"><font><sz val="11"/><name val="Calibri"/></font><font><b/><sz val="11"/><name val="Calibri"/></font></fonts>
+<fills count="2"><fill><patternFill patternType="none"/></fill><fill><patternFill patternType="gray125"/></fill></fills>
+<borders count="1"><border><left/><right/><top/><bottom/><diagonal/></border></borders>
+<cellStyleXfs count="1"><xf numFmtId="0" fontId="0" fillId="0" borderId="0"/></cellStyleXfs>
+<cellXfs count="%v"><xf numFmtId="0" fontId="0" fillId="0" borderId="0" xfId="0"/><xf numFmtId="0" fontId="1" fillId="0" borderId="0" xfId="0" applyFont="1"/>%v</cellXfs>
+</styleSheet>`
+
+	xlsxSheetStart = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<worksheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main">
+<sheetViews><sheetView workbookViewId="0"><pane ySplit="1" topLeftCell="A2" activePane="bottomLeft" state="frozen"/></sheetView></sheetViews>
+<sheetData>
+`
+	xlsxSheetEnd = `</sheetData>
+</worksheet>`
+
+	// xlsxHeaderStyle is the index of the bold style used for header cells
+	xlsxHeaderStyle = 1
+	// xlsxFirstNumFmtID is the first ID available to custom number formats
+	xlsxFirstNumFmtID = 164
+)
+
+// WriteXLSX writes the sheets to the given writer as an XLSX workbook, with a
+// worksheet for each sheet. Each worksheet has a bold, frozen header row of
+// the column headers, and floating point values use a number format with the
+// column's decimal places. Rows are written as they are read.
+func WriteXLSX(w io.Writer, sheets ...Sheet) error {
+	if len(sheets) == 0 {
+		return fmt.Errorf("at least one sheet is required to write a workbook")
+	}
+
+	zw := zip.NewWriter(w)
+	styles := newXLSXStyles(sheets)
+	names := sheetNames(sheets)
+
+	var contentTypes, workbookSheets, workbookRels strings.Builder
+	for i, name := range names {
+		fmt.Fprintf(&contentTypes, xlsxSheetContentType, i+1)
+		fmt.Fprintf(&workbookSheets, xlsxWorkbookSheet, xmlEscape(name), i+1, i+1)
+		fmt.Fprintf(&workbookRels, xlsxWorkbookSheetRel, i+1, i+1)
+	}
+
+	parts := []struct {
+		name    string
+		content string
+	}{
+		{"[Content_Types].xml", fmt.Sprintf(xlsxContentTypes, contentTypes.String())},
+		{"_rels/.rels", xlsxRootRels},
+		{"xl/workbook.xml", fmt.Sprintf(xlsxWorkbook, workbookSheets.String())},
+		{"xl/_rels/workbook.xml.rels", fmt.Sprintf(xlsxWorkbookRels, workbookRels.String(), len(names)+1)},
+		{"xl/styles.xml", styles.xml()},
+	}
+
+	for _, p := range parts {
+		err := writeZipPart(zw, p.name, func(w io.Writer) error {
+			_, err := io.WriteString(w, p.content)
+			return err
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	for i, sheet := range sheets {
+		sheet := sheet
+		err := writeZipPart(zw, fmt.Sprintf("xl/worksheets/sheet%v.xml", i+1), func(w io.Writer) error {
+			return writeXLSXSheet(w, sheet, styles)
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	err := zw.Close()
+	if err != nil {
+		return fmt.Errorf("failed to write workbook: %v", err.Error())
+	}
+
+	return nil
+}
+
+func writeZipPart(zw *zip.Writer, name string, write func(io.Writer) error) error {
+	f, err := zw.CreateHeader(&zip.FileHeader{
+		Name:     name,
+		Method:   zip.Deflate,
+		Modified: time.Now(),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to create %v: %v", name, err.Error())
+	}
+
+	bw := bufio.NewWriter(f)
+	err = write(bw)
+	if err != nil {
+		return fmt.Errorf("failed to write %v: %v", name, err.Error())
+	}
+
+	err = bw.Flush()
+	if err != nil {
+		return fmt.Errorf("failed to write %v: %v", name, err.Error())
+	}
+
+	return nil
+}
+
+func writeXLSXSheet(w io.Writer, sheet Sheet, styles *xlsxStyles) error {
+	_, err := io.WriteString(w, xlsxSheetStart)
+	if err != nil {
+		return err
+	}
+
+	header := make([]interface{}, len(sheet.Columns))
+	for i, c := range sheet.Columns {
+		header[i] = c.Header
+	}
+
+	err = writeXLSXRow(w, 1, sheet.Columns, header, styles)
+	if err != nil {
+		return err
+	}
+
+	if sheet.Rows != nil {
+		rowNum := 2
+		for row, ok := sheet.Rows(); ok; row, ok = sheet.Rows() {
+			err := writeXLSXRow(w, rowNum, sheet.Columns, values(sheet.Columns, row), styles)
+			if err != nil {
+				return err
+			}
+
+			rowNum++
+		}
+	}
+
+	_, err = io.WriteString(w, xlsxSheetEnd)
+	return err
+}
+
+// writeXLSXRow writes a row of cells. The cells of the first row are styled
+// as headers.
+func writeXLSXRow(w io.Writer, rowNum int, columns []Column, vals []interface{}, styles *xlsxStyles) error {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, `<row r="%v">`, rowNum)
+
+	for i, v := range vals {
+		ref := cellRef(i, rowNum)
+
+		switch t := v.(type) {
+		case nil:
+			continue
+		case int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64:
+			fmt.Fprintf(&sb, `<c r="%v"><v>%v</v></c>`, ref, t)
+		case float32:
+			fmt.Fprintf(&sb, `<c r="%v" s="%v"><v>%v</v></c>`, ref, styles.numberStyle(columns[i].Decimals), strconv.FormatFloat(float64(t), 'f', -1, 32))
+		case float64:
+			fmt.Fprintf(&sb, `<c r="%v" s="%v"><v>%v</v></c>`, ref, styles.numberStyle(columns[i].Decimals), strconv.FormatFloat(t, 'f', -1, 64))
+		case bool:
+			b := 0
+			if t {
+				b = 1
+			}
+
+			fmt.Fprintf(&sb, `<c r="%v" t="b"><v>%v</v></c>`, ref, b)
+		default:
+			style := ""
+			if rowNum == 1 {
+				style = fmt.Sprintf(` s="%v"`, xlsxHeaderStyle)
+			}
+
+			fmt.Fprintf(&sb, `<c r="%v"%v t="inlineStr"><is><t xml:space="preserve">%v</t></is></c>`, ref, style, xmlEscape(formatValue(v, columns[i].Decimals)))
+		}
+	}
+
+	sb.WriteString("</row>\n")
+
+	_, err := io.WriteString(w, sb.String())
+	return err
+}
+
+// xlsxStyles tracks the cell styles of the number formats used by the columns
+type xlsxStyles struct {
+	decimals []int
+	styles   map[int]int
+}
+
+func newXLSXStyles(sheets []Sheet) *xlsxStyles {
+	s := &xlsxStyles{styles: make(map[int]int)}
+
+	for _, sheet := range sheets {
+		for _, c := range sheet.Columns {
+			if _, ok := s.styles[c.Decimals]; !ok {
+				s.styles[c.Decimals] = 0
+				s.decimals = append(s.decimals, c.Decimals)
+			}
+		}
+	}
+
+	sort.Ints(s.decimals)
+	for i, d := range s.decimals {
+		// the first two styles are the default and header styles
+		s.styles[d] = i + 2
+	}
+
+	return s
+}
+
+func (s *xlsxStyles) numberStyle(decimals int) int {
+	return s.styles[decimals]
+}
+
+func (s *xlsxStyles) xml() string {
+	var numFmts, xfs strings.Builder
+	for i, d := range s.decimals {
+		code := "0"
+		if d > 0 {
+			code += "." + strings.Repeat("0", d)
+		}
+
+		fmt.Fprintf(&numFmts, `<numFmt numFmtId="%v" formatCode="%v"/>`, xlsxFirstNumFmtID+i, code)
+		fmt.Fprintf(&xfs, `<xf numFmtId="%v" fontId="0" fillId="0" borderId="0" xfId="0" applyNumberFormat="1"/>`, xlsxFirstNumFmtID+i)
+	}
+
+	return fmt.Sprintf(xlsxStylesXML, len(s.decimals), numFmts.String(), len(s.decimals)+2, xfs.String())
+}
+
+// cellRef returns the A1 style reference of the cell in the zero based column
+// and one based row
+func cellRef(column, row int) string {
+	name := ""
+	for column++; column > 0; column = (column - 1) / 26 {
+		name = string(rune('A'+(column-1)%26)) + name
+	}
+
+	return fmt.Sprintf("%v%v", name, row)
+}
+
+func xmlEscape(s string) string {
+	var sb strings.Builder
+	xml.EscapeText(&sb, []byte(s))
+	return sb.String()
+}
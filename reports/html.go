@@ -0,0 +1,375 @@
+package reports
+
+import (
+	"embed"
+	"fmt"
+	"html/template"
+	"io"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/ion-channel/ionic/analyses"
+	"github.com/ion-channel/ionic/projects"
+	"github.com/ion-channel/ionic/rulesets"
+	"github.com/ion-channel/ionic/scans"
+)
+
+//go:embed templates/report.html templates/report.css
+var htmlAssets embed.FS
+
+var htmlTemplate = template.Must(template.New("report.html").Funcs(template.FuncMap{
+	"date":  formatDate,
+	"score": formatScore,
+	"lower": strings.ToLower,
+}).ParseFS(htmlAssets, "templates/report.html"))
+
+// HTMLOptions represents the additional content available to an HTML report.
+// All of the options are optional; sections without data are left out.
+type HTMLOptions struct {
+	// Title is the title of the report. The project or analysis name is used
+	// when it is empty.
+	Title string
+	// Project is the project the report is about, used for the project
+	// summary of an analysis report.
+	Project *projects.Project
+	// AppliedRuleset is the result of evaluating the analysis against its
+	// ruleset.
+	AppliedRuleset *rulesets.AppliedRulesetSummary
+	// History is the daily pass/fail history of the project, as returned by
+	// GetProjectPassFailHistory.
+	History []rulesets.ProjectPassFailHistory
+	// GeneratedAt is the time the report was generated. The current time is
+	// used when it is zero.
+	GeneratedAt time.Time
+}
+
+type htmlReport struct {
+	Title           string
+	GeneratedAt     time.Time
+	CSS             template.CSS
+	Project         *htmlProject
+	Analysis        *analyses.Analysis
+	Ruleset         *htmlRuleset
+	Vulnerabilities []htmlVulnerability
+	DependencyMeta  *scans.DependencyMeta
+	Outdated        []scans.Dependency
+	Licenses        []htmlLicense
+	Community       *scans.CommunityResults
+	Analyses        []analyses.Summary
+	Trend           *htmlTrend
+}
+
+type htmlProject struct {
+	ID          string
+	Name        string
+	Type        string
+	Source      string
+	Branch      string
+	Description string
+	RulesetName string
+}
+
+type htmlRuleset struct {
+	Name   string
+	Passed bool
+	Rules  []htmlRule
+}
+
+type htmlRule struct {
+	Name    string
+	Result  string
+	Risk    string
+	Summary string
+}
+
+type htmlVulnerability struct {
+	ExternalID string
+	Title      string
+	Package    string
+	Version    string
+	Score      float64
+	Scored     bool
+	Severity   string
+}
+
+type htmlLicense struct {
+	Name       string
+	File       string
+	Confidence float32
+}
+
+type htmlTrend struct {
+	Days       []rulesets.ProjectPassFailHistory
+	PassedDays int
+	Flips      int
+}
+
+// WriteHTML writes the analysis report to the given writer as a single,
+// self-contained HTML page suitable for archiving. It covers the project
+// summary, the ruleset result, vulnerabilities by score, outdated
+// dependencies, licenses, community health, and the pass/fail trend.
+func (ar *AnalysisReport) WriteHTML(w io.Writer, opts HTMLOptions) error {
+	if ar == nil || ar.Analysis == nil {
+		return fmt.Errorf("analysis is required to write an HTML report")
+	}
+
+	a := ar.Analysis
+	r := newHTMLReport(opts)
+	r.Analysis = a
+	r.Project = htmlProjectOf(opts.Project, a.RulesetName)
+	if r.Title == "" {
+		r.Title = htmlTitle(r.Project, a.Name)
+	}
+
+	r.Vulnerabilities = htmlVulnerabilities(a)
+
+	if deps, err := a.Dependencies(); err == nil {
+		r.DependencyMeta = &deps.Meta
+		r.Outdated = outdatedDependencies(deps.Dependencies)
+	}
+
+	if licenses, err := a.Licenses(); err == nil && licenses.License != nil {
+		for _, t := range licenses.Type {
+			r.Licenses = append(r.Licenses, htmlLicense{Name: t.Name, File: licenses.Name, Confidence: t.Confidence})
+		}
+	}
+
+	if community, err := a.Community(); err == nil {
+		r.Community = community
+	}
+
+	return r.write(w)
+}
+
+// WriteHTML writes the project report to the given writer as a single,
+// self-contained HTML page suitable for archiving. It covers the project
+// summary, the ruleset result, the project's analyses, and the pass/fail
+// trend.
+func (pr *ProjectReport) WriteHTML(w io.Writer, opts HTMLOptions) error {
+	if pr == nil || pr.Project == nil {
+		return fmt.Errorf("project is required to write an HTML report")
+	}
+
+	r := newHTMLReport(opts)
+	r.Project = htmlProjectOf(pr.Project, pr.RulesetName)
+	if r.Title == "" {
+		r.Title = htmlTitle(r.Project, "")
+	}
+
+	r.Analyses = append([]analyses.Summary{}, pr.AnalysisSummaries...)
+	sort.SliceStable(r.Analyses, func(i, j int) bool {
+		return r.Analyses[i].CreatedAt.After(r.Analyses[j].CreatedAt)
+	})
+
+	return r.write(w)
+}
+
+func newHTMLReport(opts HTMLOptions) *htmlReport {
+	r := &htmlReport{
+		Title:       opts.Title,
+		GeneratedAt: opts.GeneratedAt,
+	}
+
+	if r.GeneratedAt.IsZero() {
+		r.GeneratedAt = time.Now()
+	}
+
+	if opts.AppliedRuleset != nil {
+		r.Ruleset = &htmlRuleset{
+			Name:   opts.AppliedRuleset.Name(),
+			Passed: opts.AppliedRuleset.Passed(),
+		}
+
+		for _, e := range opts.AppliedRuleset.RuleResults() {
+			r.Ruleset.Rules = append(r.Ruleset.Rules, htmlRule{
+				Name:    e.Name,
+				Result:  RuleResult(e),
+				Risk:    e.Risk,
+				Summary: e.Summary,
+			})
+		}
+	}
+
+	if len(opts.History) > 0 {
+		r.Trend = &htmlTrend{Days: append([]rulesets.ProjectPassFailHistory{}, opts.History...)}
+		sort.SliceStable(r.Trend.Days, func(i, j int) bool {
+			return r.Trend.Days[i].CreatedAt.Before(r.Trend.Days[j].CreatedAt)
+		})
+
+		for _, day := range r.Trend.Days {
+			if day.Status {
+				r.Trend.PassedDays++
+			}
+
+			if day.StatusFlipped {
+				r.Trend.Flips++
+			}
+		}
+	}
+
+	return r
+}
+
+func (r *htmlReport) write(w io.Writer) error {
+	css, err := htmlAssets.ReadFile("templates/report.css")
+	if err != nil {
+		return fmt.Errorf("failed to read report stylesheet: %v", err.Error())
+	}
+
+	r.CSS = template.CSS(css)
+
+	err = htmlTemplate.Execute(w, r)
+	if err != nil {
+		return fmt.Errorf("failed to write HTML report: %v", err.Error())
+	}
+
+	return nil
+}
+
+func htmlProjectOf(p *projects.Project, rulesetName string) *htmlProject {
+	if p == nil {
+		return nil
+	}
+
+	return &htmlProject{
+		ID:          stringValue(p.ID),
+		Name:        stringValue(p.Name),
+		Type:        stringValue(p.Type),
+		Source:      stringValue(p.Source),
+		Branch:      stringValue(p.Branch),
+		Description: stringValue(p.Description),
+		RulesetName: rulesetName,
+	}
+}
+
+func htmlTitle(p *htmlProject, fallback string) string {
+	name := fallback
+	if p != nil && p.Name != "" {
+		name = p.Name
+	}
+
+	if name == "" {
+		return "Ion Channel Report"
+	}
+
+	return fmt.Sprintf("Ion Channel Report: %v", name)
+}
+
+// htmlVulnerabilities returns the vulnerabilities of the analysis, highest
+// score first
+func htmlVulnerabilities(a *analyses.Analysis) []htmlVulnerability {
+	results, err := a.Vulnerabilities()
+	if err != nil {
+		return nil
+	}
+
+	var vulns []htmlVulnerability
+	for _, product := range results.Vulnerabilities {
+		for _, v := range product.Vulnerabilities {
+			score, scored := vulnerabilityScore(v.Vulnerability)
+
+			severity := ""
+			if v.ScoreDetails.CVSSv3 != nil && v.ScoreDetails.CVSSv3.BaseSeverity != "" {
+				severity = strings.ToLower(v.ScoreDetails.CVSSv3.BaseSeverity)
+			} else if scored {
+				severity = scoreSeverity(score)
+			}
+
+			vulns = append(vulns, htmlVulnerability{
+				ExternalID: v.ExternalID,
+				Title:      v.Title,
+				Package:    product.Name,
+				Version:    product.Version,
+				Score:      score,
+				Scored:     scored,
+				Severity:   severity,
+			})
+		}
+	}
+
+	sort.SliceStable(vulns, func(i, j int) bool {
+		if vulns[i].Scored != vulns[j].Scored {
+			return vulns[i].Scored
+		}
+
+		return vulns[i].Score > vulns[j].Score
+	})
+
+	return vulns
+}
+
+// outdatedDependencies returns the unique dependencies in the trees that are
+// behind their latest version, most outdated first
+func outdatedDependencies(deps []scans.Dependency) []scans.Dependency {
+	seen := make(map[string]bool)
+	var outdated []scans.Dependency
+
+	var walk func([]scans.Dependency)
+	walk = func(deps []scans.Dependency) {
+		for _, d := range deps {
+			key := fmt.Sprintf("%v|%v|%v", d.Org, d.Name, d.Version)
+			if !seen[key] {
+				seen[key] = true
+				if o := d.OutdatedMeta; o != nil && (o.MajorBehind > 0 || o.MinorBehind > 0 || o.PatchBehind > 0) {
+					d.Dependencies = nil
+					outdated = append(outdated, d)
+				}
+			}
+
+			walk(d.Dependencies)
+		}
+	}
+	walk(deps)
+
+	sort.SliceStable(outdated, func(i, j int) bool {
+		a, b := outdated[i].OutdatedMeta, outdated[j].OutdatedMeta
+		if a.MajorBehind != b.MajorBehind {
+			return a.MajorBehind > b.MajorBehind
+		}
+
+		if a.MinorBehind != b.MinorBehind {
+			return a.MinorBehind > b.MinorBehind
+		}
+
+		return a.PatchBehind > b.PatchBehind
+	})
+
+	return outdated
+}
+
+// scoreSeverity returns the CVSS v3 severity of a score
+func scoreSeverity(score float64) string {
+	switch {
+	case score >= 9.0:
+		return "critical"
+	case score >= 7.0:
+		return "high"
+	case score >= 4.0:
+		return "medium"
+	case score > 0:
+		return "low"
+	default:
+		return "none"
+	}
+}
+
+func formatDate(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+
+	return t.UTC().Format("2006-01-02 15:04 MST")
+}
+
+func formatScore(score float64) string {
+	return fmt.Sprintf("%.1f", score)
+}
+
+func stringValue(s *string) string {
+	if s == nil {
+		return ""
+	}
+
+	return *s
+}
@@ -0,0 +1,106 @@
+package reports
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ion-channel/ionic/analyses"
+	"github.com/ion-channel/ionic/projects"
+	"github.com/ion-channel/ionic/rulesets"
+	"github.com/ion-channel/ionic/scans"
+
+	"github.com/franela/goblin"
+	. "github.com/onsi/gomega"
+)
+
+func TestHTMLReports(t *testing.T) {
+	g := goblin.Goblin(t)
+	RegisterFailHandler(func(m string, _ ...int) { g.Fail(m) })
+
+	g.Describe("HTML Reports", func() {
+		name := "ionic <sdk>"
+		id := "project-1"
+		project := &projects.Project{ID: &id, Name: &name}
+		generated := time.Date(2020, 1, 2, 3, 4, 0, 0, time.UTC)
+
+		history := []rulesets.ProjectPassFailHistory{
+			{Status: false, CreatedAt: generated.Add(-24 * time.Hour), StatusFlipped: true},
+			{Status: true, CreatedAt: generated.Add(-48 * time.Hour)},
+			{Status: true, CreatedAt: generated, StatusFlipped: true},
+		}
+
+		g.It("should write a self contained analysis report", func() {
+			var a analyses.Analysis
+			Expect(json.Unmarshal([]byte(sampleHTMLAnalysis), &a)).To(Succeed())
+
+			var evals []scans.Evaluation
+			Expect(json.Unmarshal([]byte(sampleRuleEvaluations), &evals)).To(Succeed())
+
+			var buf bytes.Buffer
+			err := (&AnalysisReport{Analysis: &a}).WriteHTML(&buf, HTMLOptions{
+				Project: project,
+				AppliedRuleset: &rulesets.AppliedRulesetSummary{
+					RuleEvaluationSummary: &rulesets.RuleEvaluationSummary{
+						RulesetName: "super cool ruleset",
+						Ruleresults: evals,
+					},
+				},
+				History:     history,
+				GeneratedAt: generated,
+			})
+			Expect(err).To(BeNil())
+
+			html := buf.String()
+			Expect(html).To(HavePrefix("<!DOCTYPE html>"))
+			Expect(html).To(ContainSubstring("<style>"))
+			Expect(html).NotTo(ContainSubstring("<link"))
+			Expect(html).NotTo(ContainSubstring("<script"))
+			Expect(html).To(ContainSubstring("<title>Ion Channel Report: ionic &lt;sdk&gt;</title>"))
+			Expect(html).To(ContainSubstring("Generated 2020-01-02 03:04 UTC"))
+			Expect(html).To(ContainSubstring("Ruleset: super cool ruleset"))
+			Expect(html).To(ContainSubstring(`<td class="error">ERROR</td>`))
+			Expect(html).To(ContainSubstring("Passing on 2 of 3 days, with 2 status changes."))
+			Expect(html).To(ContainSubstring("apache-2.0"))
+			Expect(html).To(ContainSubstring("ion-channel/ionic"))
+			Expect(strings.Index(html, "CVE-HIGH")).To(BeNumerically("<", strings.Index(html, "CVE-LOW")))
+			Expect(html).To(ContainSubstring("<td>google/guava</td><td>18.0</td><td>31.0</td><td>13</td>"))
+			Expect(html).NotTo(ContainSubstring("up-to-date"))
+		})
+
+		g.It("should write a project report with its analyses", func() {
+			pr := &ProjectReport{
+				Project:     project,
+				RulesetName: "super cool ruleset",
+				AnalysisSummaries: []analyses.Summary{
+					{ID: "old", Status: "finished", TriggerHash: "aaa111", CreatedAt: generated.Add(-time.Hour)},
+					{ID: "new", Status: "finished", TriggerHash: "bbb222", Passed: true, CreatedAt: generated},
+				},
+			}
+
+			var buf bytes.Buffer
+			Expect(pr.WriteHTML(&buf, HTMLOptions{Title: "Audit", History: history})).To(Succeed())
+
+			html := buf.String()
+			Expect(html).To(ContainSubstring("<title>Audit</title>"))
+			Expect(html).To(ContainSubstring("<dt>Ruleset</dt><dd>super cool ruleset</dd>"))
+			Expect(strings.Index(html, "bbb222")).To(BeNumerically("<", strings.Index(html, "aaa111")))
+			Expect(html).NotTo(ContainSubstring("<h2>Vulnerabilities</h2>"))
+		})
+
+		g.It("should return an error without data to report on", func() {
+			var buf bytes.Buffer
+			Expect((&AnalysisReport{}).WriteHTML(&buf, HTMLOptions{})).NotTo(Succeed())
+			Expect((&ProjectReport{}).WriteHTML(&buf, HTMLOptions{})).NotTo(Succeed())
+		})
+	})
+}
+
+const sampleHTMLAnalysis = `{"id":"a1","team_id":"t1","project_id":"project-1","name":"analysis","status":"finished","scan_summaries":[
+{"id":"s1","results":{"type":"vulnerability","data":{"vulnerabilities":[{"name":"lib","version":"1.0","vulnerabilities":[{"external_id":"CVE-LOW","title":"low","score":"2.0"},{"external_id":"CVE-HIGH","title":"high","score":"5.0","score_details":{"cvssv3":{"baseScore":8.1,"baseSeverity":"HIGH"}}}]}],"meta":{"vulnerability_count":2}}}},
+{"id":"s2","results":{"type":"dependency","data":{"dependencies":[{"org":"google","name":"guava","version":"18.0","latest_version":"31.0","outdated_version":{"major_behind":13},"dependencies":[{"name":"up-to-date","version":"1.0","latest_version":"1.0","outdated_version":{}}]}],"meta":{"first_degree_count":1,"total_unique_count":2}}}},
+{"id":"s3","results":{"type":"license","data":{"license":{"name":"LICENSE.md","type":[{"name":"apache-2.0"}]}}}},
+{"id":"s4","results":{"type":"community","data":{"name":"ion-channel/ionic","url":"https://github.com/ion-channel/ionic","committers_total_count":7}}}
+]}`
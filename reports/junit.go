@@ -0,0 +1,127 @@
+package reports
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+
+	"github.com/ion-channel/ionic/rulesets"
+)
+
+// JUnitTestSuites is the root element of a JUnit XML report
+type JUnitTestSuites struct {
+	XMLName xml.Name         `xml:"testsuites"`
+	Suites  []JUnitTestSuite `xml:"testsuite"`
+}
+
+// JUnitTestSuite represents an applied ruleset in a JUnit XML report
+type JUnitTestSuite struct {
+	Name       string          `xml:"name,attr"`
+	Tests      int             `xml:"tests,attr"`
+	Failures   int             `xml:"failures,attr"`
+	Errors     int             `xml:"errors,attr"`
+	Time       string          `xml:"time,attr"`
+	Properties []JUnitProperty `xml:"properties>property,omitempty"`
+	Cases      []JUnitTestCase `xml:"testcase"`
+}
+
+// JUnitProperty is a named detail of a JUnit test suite
+type JUnitProperty struct {
+	Name  string `xml:"name,attr"`
+	Value string `xml:"value,attr"`
+}
+
+// JUnitTestCase represents the evaluation of a rule in a JUnit XML report
+type JUnitTestCase struct {
+	Name      string        `xml:"name,attr"`
+	ClassName string        `xml:"classname,attr"`
+	Time      string        `xml:"time,attr"`
+	Failure   *JUnitFailure `xml:"failure,omitempty"`
+	Error     *JUnitFailure `xml:"error,omitempty"`
+	SystemOut string        `xml:"system-out,omitempty"`
+}
+
+// JUnitFailure describes why a JUnit test case failed or errored
+type JUnitFailure struct {
+	Message string `xml:"message,attr"`
+	Type    string `xml:"type,attr"`
+	Text    string `xml:",chardata"`
+}
+
+// RulesetJUnit returns a JUnit test suite of the applied ruleset, with a test
+// case for each rule evaluation. Rules that failed are reported as failures,
+// and rules whose scan produced no results as errors. The summary, risk, and
+// duration of each evaluation are carried on its test case.
+func RulesetJUnit(ar *rulesets.AppliedRulesetSummary, opts RulesetReportOptions) *JUnitTestSuites {
+	name := ar.Name()
+	suite := JUnitTestSuite{
+		Name: name,
+		Time: fmt.Sprintf("%.3f", opts.Duration.Seconds()),
+	}
+
+	if ar != nil {
+		for _, p := range []ReportProperty{
+			{Name: "ruleset_id", Value: ar.RulesetID},
+			{Name: "team_id", Value: ar.TeamID},
+			{Name: "project_id", Value: ar.ProjectID},
+			{Name: "analysis_id", Value: ar.AnalysisID},
+		} {
+			if p.Value != "" {
+				suite.Properties = append(suite.Properties, JUnitProperty(p))
+			}
+		}
+	}
+
+	for _, p := range opts.Properties {
+		suite.Properties = append(suite.Properties, JUnitProperty(p))
+	}
+
+	for _, e := range ar.RuleResults() {
+		tc := JUnitTestCase{
+			Name:      e.Name,
+			ClassName: "ionchannel." + name,
+			Time:      fmt.Sprintf("%.3f", e.Duration/1000),
+			SystemOut: fmt.Sprintf("risk: %v\nsummary: %v", e.Risk, e.Summary),
+		}
+
+		failure := &JUnitFailure{
+			Message: OneLine(e.Summary),
+			Type:    e.Risk,
+			Text:    e.Description,
+		}
+
+		switch RuleResult(e) {
+		case RuleResultFail:
+			suite.Failures++
+			tc.Failure = failure
+		case RuleResultError:
+			suite.Errors++
+			tc.Error = failure
+		}
+
+		suite.Cases = append(suite.Cases, tc)
+	}
+
+	suite.Tests = len(suite.Cases)
+
+	return &JUnitTestSuites{Suites: []JUnitTestSuite{suite}}
+}
+
+// WriteRulesetJUnit writes the applied ruleset to the given writer as JUnit
+// XML, with each rule evaluation represented as a test case
+func WriteRulesetJUnit(w io.Writer, ar *rulesets.AppliedRulesetSummary, opts RulesetReportOptions) error {
+	_, err := io.WriteString(w, xml.Header)
+	if err != nil {
+		return fmt.Errorf("failed to write junit: %v", err.Error())
+	}
+
+	enc := xml.NewEncoder(w)
+	enc.Indent("", "  ")
+	err = enc.Encode(RulesetJUnit(ar, opts))
+	if err != nil {
+		return fmt.Errorf("failed to write junit: %v", err.Error())
+	}
+
+	_, err = io.WriteString(w, "\n")
+	return err
+}
@@ -0,0 +1,113 @@
+package reports
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/ion-channel/ionic/rulesets"
+	"github.com/ion-channel/ionic/scans"
+)
+
+// riskOrder ranks the risk of rule evaluations from most to least severe
+var riskOrder = map[string]int{
+	"critical": 0,
+	"high":     1,
+	"medium":   2,
+	"low":      3,
+}
+
+// WriteRulesetMarkdown writes a Markdown summary of the applied ruleset to the
+// given writer, suitable for pull request comments. It includes the overall
+// result, links to the analysis and project when a console URL is given, the
+// riskiest failing rules, and a table of every rule evaluation.
+func WriteRulesetMarkdown(w io.Writer, ar *rulesets.AppliedRulesetSummary, opts RulesetReportOptions) error {
+	var sb strings.Builder
+
+	evals := ar.RuleResults()
+	passed := 0
+	for _, e := range evals {
+		if e.Passed {
+			passed++
+		}
+	}
+
+	overall := PassFail(ar.Passed())
+
+	fmt.Fprintf(&sb, "## Ion Channel: %v\n\n", overall)
+	fmt.Fprintf(&sb, "Ruleset: **%v** (%v of %v rules passed)\n\n", ar.Name(), passed, len(evals))
+
+	if ar != nil && ar.AnalysisID != "" {
+		fmt.Fprintf(&sb, "- Analysis: %v\n", markdownLink("`"+ar.AnalysisID+"`", opts.AnalysisURL(ar)))
+	}
+
+	if ar != nil && ar.ProjectID != "" {
+		fmt.Fprintf(&sb, "- Project: %v\n", markdownLink("`"+ar.ProjectID+"`", opts.ProjectURL(ar)))
+	}
+
+	for _, p := range opts.Properties {
+		fmt.Fprintf(&sb, "- %v: `%v`\n", p.Name, p.Value)
+	}
+
+	failing := topFailing(evals, opts.topFailures())
+	if len(failing) > 0 {
+		sb.WriteString("\n### Top failing rules\n\n")
+		for i, e := range failing {
+			fmt.Fprintf(&sb, "%v. **%v** (%v risk): %v\n", i+1, OneLine(e.Name), e.Risk, OneLine(e.Summary))
+		}
+	}
+
+	sb.WriteString("\n| Rule | Result | Risk | Duration | Summary |\n|---|---|---|---|---|\n")
+	for _, e := range evals {
+		fmt.Fprintf(&sb, "| %v | %v | %v | %.0fms | %v |\n", escapeCell(e.Name), RuleResult(e), e.Risk, e.Duration, escapeCell(e.Summary))
+	}
+
+	_, err := io.WriteString(w, sb.String())
+	if err != nil {
+		return fmt.Errorf("failed to write markdown: %v", err.Error())
+	}
+
+	return nil
+}
+
+// topFailing returns up to n of the evaluations that did not pass, riskiest
+// first, keeping the ruleset's order for rules of the same risk
+func topFailing(evals []scans.Evaluation, n int) []scans.Evaluation {
+	var failing []scans.Evaluation
+	for _, e := range evals {
+		if !e.Passed {
+			failing = append(failing, e)
+		}
+	}
+
+	sort.SliceStable(failing, func(i, j int) bool {
+		return riskRank(failing[i].Risk) < riskRank(failing[j].Risk)
+	})
+
+	if len(failing) > n {
+		failing = failing[:n]
+	}
+
+	return failing
+}
+
+func riskRank(risk string) int {
+	if rank, ok := riskOrder[strings.ToLower(risk)]; ok {
+		return rank
+	}
+
+	return len(riskOrder)
+}
+
+func markdownLink(text, url string) string {
+	if url == "" {
+		return text
+	}
+
+	return fmt.Sprintf("[%v](%v)", text, url)
+}
+
+func escapeCell(s string) string {
+	return strings.ReplaceAll(OneLine(s), "|", "\\|")
+}
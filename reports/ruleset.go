@@ -0,0 +1,110 @@
+package reports
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/ion-channel/ionic/rulesets"
+	"github.com/ion-channel/ionic/scans"
+)
+
+const (
+	// DefaultTopFailures is the number of failing rules highlighted in a
+	// ruleset summary when no number is given
+	DefaultTopFailures = 5
+
+	// RuleResultPass is the result of a rule that passed
+	RuleResultPass = "PASS"
+	// RuleResultFail is the result of a rule that failed
+	RuleResultFail = "FAIL"
+	// RuleResultError is the result of a rule that could not be evaluated
+	RuleResultError = "ERROR"
+)
+
+// ReportProperty is a named detail of the run being reported on, such as the
+// commit being built
+type ReportProperty struct {
+	Name  string
+	Value string
+}
+
+// RulesetReportOptions represents the settings available when rendering an
+// applied ruleset. All of the options are optional.
+type RulesetReportOptions struct {
+	// Properties are additional details of the run, included in the order
+	// given.
+	Properties []ReportProperty
+	// Duration is the time taken to produce the applied ruleset.
+	Duration time.Duration
+	// ConsoleURL is the base URL of the Ion Channel console, used to link to
+	// the analysis and project. Links are omitted when it is empty.
+	ConsoleURL string
+	// TopFailures is the number of failing rules highlighted in a summary.
+	// DefaultTopFailures is used when it is zero.
+	TopFailures int
+}
+
+// RuleResult returns whether the evaluation passed, failed, or errored. An
+// evaluation errored if it did not pass and has an error explaining why it
+// could not be evaluated. Evaluations without results, such as those of brief
+// applied rulesets, are reported by whether they passed.
+func RuleResult(e scans.Evaluation) string {
+	switch {
+	case e.Passed:
+		return RuleResultPass
+	case e.Error != "":
+		return RuleResultError
+	default:
+		return RuleResultFail
+	}
+}
+
+// AnalysisURL returns the console link to the analysis of the applied ruleset,
+// or an empty string if the console URL or an ID is missing
+func (o RulesetReportOptions) AnalysisURL(ar *rulesets.AppliedRulesetSummary) string {
+	if ar == nil || ar.AnalysisID == "" {
+		return ""
+	}
+
+	project := o.ProjectURL(ar)
+	if project == "" {
+		return ""
+	}
+
+	return fmt.Sprintf("%v/analyses/%v", project, ar.AnalysisID)
+}
+
+// ProjectURL returns the console link to the project of the applied ruleset,
+// or an empty string if the console URL or an ID is missing
+func (o RulesetReportOptions) ProjectURL(ar *rulesets.AppliedRulesetSummary) string {
+	if o.ConsoleURL == "" || ar == nil || ar.TeamID == "" || ar.ProjectID == "" {
+		return ""
+	}
+
+	return fmt.Sprintf("%v/teams/%v/projects/%v", strings.TrimSuffix(o.ConsoleURL, "/"), ar.TeamID, ar.ProjectID)
+}
+
+func (o RulesetReportOptions) topFailures() int {
+	if o.TopFailures <= 0 {
+		return DefaultTopFailures
+	}
+
+	return o.TopFailures
+}
+
+// PassFail returns RuleResultPass or RuleResultFail for whether something
+// passed
+func PassFail(passed bool) string {
+	if passed {
+		return RuleResultPass
+	}
+
+	return RuleResultFail
+}
+
+// OneLine collapses the whitespace of a string, including line breaks, to
+// single spaces so it can be shown on one line
+func OneLine(s string) string {
+	return strings.Join(strings.Fields(s), " ")
+}
@@ -0,0 +1,176 @@
+package reports
+
+import (
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ion-channel/ionic/rulesets"
+	"github.com/ion-channel/ionic/scans"
+
+	"github.com/franela/goblin"
+	. "github.com/onsi/gomega"
+)
+
+func TestRulesetReports(t *testing.T) {
+	g := goblin.Goblin(t)
+	RegisterFailHandler(func(m string, _ ...int) { g.Fail(m) })
+
+	g.Describe("Ruleset Reports", func() {
+		var ar *rulesets.AppliedRulesetSummary
+		opts := RulesetReportOptions{
+			Properties: []ReportProperty{{Name: "commit", Value: "abc123"}},
+			Duration:   1500 * time.Millisecond,
+			ConsoleURL: "https://console.example.com/",
+		}
+
+		g.BeforeEach(func() {
+			var evals []scans.Evaluation
+			Expect(json.Unmarshal([]byte(sampleRuleEvaluations), &evals)).To(Succeed())
+
+			ar = &rulesets.AppliedRulesetSummary{
+				TeamID:     "team-1",
+				ProjectID:  "project-1",
+				AnalysisID: "analysis-1",
+				RulesetID:  "ruleset-1",
+				RuleEvaluationSummary: &rulesets.RuleEvaluationSummary{
+					RulesetName: "super cool ruleset",
+					Passed:      false,
+					Ruleresults: evals,
+				},
+			}
+		})
+
+		g.It("should determine the result of each rule", func() {
+			evals := ar.RuleEvaluationSummary.Ruleresults
+			Expect(RuleResult(evals[0])).To(Equal(RuleResultPass))
+			Expect(RuleResult(evals[1])).To(Equal(RuleResultFail))
+			Expect(RuleResult(evals[2])).To(Equal(RuleResultFail))
+			Expect(RuleResult(evals[3])).To(Equal(RuleResultError))
+		})
+
+		g.It("should report failing rules without results as failed", func() {
+			var evals []scans.Evaluation
+			Expect(json.Unmarshal([]byte(`[{"rule_id":"rule-2","passed":false,"results":null},{"rule_id":"rule-1","passed":true,"results":null}]`), &evals)).To(Succeed())
+
+			Expect(RuleResult(evals[0])).To(Equal(RuleResultFail))
+			Expect(RuleResult(evals[1])).To(Equal(RuleResultPass))
+		})
+
+		g.It("should build links from the analysis IDs", func() {
+			Expect(opts.ProjectURL(ar)).To(Equal("https://console.example.com/teams/team-1/projects/project-1"))
+			Expect(opts.AnalysisURL(ar)).To(Equal("https://console.example.com/teams/team-1/projects/project-1/analyses/analysis-1"))
+			Expect(RulesetReportOptions{}.AnalysisURL(ar)).To(BeEmpty())
+		})
+
+		g.Describe("JUnit", func() {
+			g.It("should create a test case per rule evaluation", func() {
+				suites := RulesetJUnit(ar, opts)
+				Expect(suites.Suites).To(HaveLen(1))
+
+				suite := suites.Suites[0]
+				Expect(suite.Name).To(Equal("super cool ruleset"))
+				Expect(suite.Tests).To(Equal(4))
+				Expect(suite.Failures).To(Equal(2))
+				Expect(suite.Errors).To(Equal(1))
+				Expect(suite.Time).To(Equal("1.500"))
+				Expect(suite.Properties).To(ContainElement(JUnitProperty{Name: "analysis_id", Value: "analysis-1"}))
+				Expect(suite.Properties).To(ContainElement(JUnitProperty{Name: "commit", Value: "abc123"}))
+
+				Expect(suite.Cases[0].Failure).To(BeNil())
+				Expect(suite.Cases[0].Time).To(Equal("1.200"))
+				Expect(suite.Cases[1].Failure.Message).To(Equal("no critical vulnerabilities found: 2"))
+				Expect(suite.Cases[1].Failure.Type).To(Equal("high"))
+				Expect(suite.Cases[1].SystemOut).To(ContainSubstring("risk: high"))
+				Expect(suite.Cases[3].Error).NotTo(BeNil())
+			})
+
+			g.It("should write valid junit xml", func() {
+				var buf bytes.Buffer
+				Expect(WriteRulesetJUnit(&buf, ar, opts)).To(Succeed())
+				Expect(buf.String()).To(HavePrefix(xml.Header))
+
+				var suites JUnitTestSuites
+				Expect(xml.Unmarshal(buf.Bytes(), &suites)).To(Succeed())
+				Expect(suites.Suites[0].Cases).To(HaveLen(4))
+				Expect(suites.Suites[0].Cases[3].Error.Message).To(Equal("scan did not complete"))
+			})
+
+			g.It("should write an empty suite without an applied ruleset", func() {
+				var buf bytes.Buffer
+				Expect(WriteRulesetJUnit(&buf, nil, RulesetReportOptions{})).To(Succeed())
+				Expect(buf.String()).To(ContainSubstring(`tests="0"`))
+			})
+		})
+
+		g.Describe("Markdown", func() {
+			g.It("should write a summary with a table of the rules", func() {
+				var buf bytes.Buffer
+				Expect(WriteRulesetMarkdown(&buf, ar, opts)).To(Succeed())
+
+				md := buf.String()
+				Expect(md).To(ContainSubstring("## Ion Channel: FAIL"))
+				Expect(md).To(ContainSubstring("Ruleset: **super cool ruleset** (1 of 4 rules passed)"))
+				Expect(md).To(ContainSubstring("- Analysis: [`analysis-1`](https://console.example.com/teams/team-1/projects/project-1/analyses/analysis-1)"))
+				Expect(md).To(ContainSubstring("- commit: `abc123`"))
+				Expect(md).To(ContainSubstring("| has a readme | PASS | low | 1200ms | readme found |"))
+				Expect(md).To(ContainSubstring("| coverage | ERROR | medium | 0ms | scan did not complete |"))
+			})
+
+			g.It("should list the riskiest failing rules first", func() {
+				var buf bytes.Buffer
+				Expect(WriteRulesetMarkdown(&buf, ar, RulesetReportOptions{TopFailures: 2})).To(Succeed())
+
+				md := buf.String()
+				top := md[strings.Index(md, "### Top failing rules"):strings.Index(md, "| Rule |")]
+				Expect(top).To(ContainSubstring("1. **no viruses** (critical risk)"))
+				Expect(top).To(ContainSubstring("2. **no critical vulnerabilities** (high risk)"))
+				Expect(top).NotTo(ContainSubstring("coverage"))
+				Expect(md).NotTo(ContainSubstring("](")) // no console URL, no links
+			})
+		})
+	})
+}
+
+const sampleRuleEvaluations = `[
+  {
+    "rule_id": "rule-1",
+    "name": "has a readme",
+    "summary": "readme found",
+    "risk": "low",
+    "passed": true,
+    "duration": 1200,
+    "results": {"type": "about_yml", "data": {"message": "", "valid": true, "content": ""}}
+  },
+  {
+    "rule_id": "rule-2",
+    "name": "no critical vulnerabilities",
+    "summary": "no critical vulnerabilities\nfound: 2",
+    "description": "the project must not have critical vulnerabilities",
+    "risk": "high",
+    "passed": false,
+    "duration": 300,
+    "results": {"type": "external_vulnerability", "data": {"critical": 2, "high": 0, "medium": 0, "low": 0}}
+  },
+  {
+    "rule_id": "rule-3",
+    "name": "no viruses",
+    "summary": "1 infected file",
+    "risk": "critical",
+    "passed": false,
+    "duration": 10,
+    "results": {"type": "virus", "data": {"infected_files": 1}}
+  },
+  {
+    "rule_id": "rule-4",
+    "name": "coverage",
+    "summary": "scan did not complete",
+    "risk": "medium",
+    "passed": false,
+    "error": "no coverage results were found in the analysis",
+    "results": null
+  }
+]`
@@ -0,0 +1,274 @@
+package reports
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+
+	"github.com/ion-channel/ionic/analyses"
+	"github.com/ion-channel/ionic/rulesets"
+	"github.com/ion-channel/ionic/sarif"
+	"github.com/ion-channel/ionic/secrets"
+	"github.com/ion-channel/ionic/vulnerabilities"
+)
+
+const (
+	sarifToolName = "Ion Channel"
+	sarifToolURI  = "https://ionchannel.io"
+
+	sarifLevelError   = "error"
+	sarifLevelWarning = "warning"
+	sarifLevelNote    = "note"
+
+	// sarifRootArtifact is the location given to findings that do not come
+	// from a known file, since code scanning tools reject results without one
+	sarifRootArtifact = "."
+)
+
+// ToSARIF returns a SARIF 2.1.0 log of the findings of an analysis, so they
+// can be displayed by tools that consume SARIF. Vulnerabilities, secrets,
+// infected files and the rules that failed in the applied ruleset each become
+// a result, located in the file it came from or in the repository root.
+// Secret matches are masked. The applied ruleset may be nil.
+func ToSARIF(analysis *analyses.Analysis, appliedRuleset *rulesets.AppliedRulesetSummary) (*sarif.Log, error) {
+	if analysis == nil {
+		return nil, fmt.Errorf("analysis is required to create a SARIF log")
+	}
+
+	b := sarifBuilder{ruleIndexes: make(map[string]int)}
+	b.addVulnerabilities(analysis)
+	b.addSecrets(analysis)
+	b.addViruses(analysis)
+	b.addFailedRules(appliedRuleset)
+
+	return &sarif.Log{
+		Version: sarif.Version,
+		Schema:  sarif.Schema,
+		Runs: []sarif.Run{
+			{
+				Tool: sarif.Tool{
+					Driver: sarif.ToolComponent{
+						Name:           sarifToolName,
+						InformationURI: sarifToolURI,
+						Rules:          b.rules,
+					},
+				},
+				Results: b.results,
+			},
+		},
+	}, nil
+}
+
+type sarifBuilder struct {
+	rules       []sarif.ReportingDescriptor
+	ruleIndexes map[string]int
+	results     []sarif.Result
+}
+
+// add appends the result, adding its rule to the run the first time the rule
+// is seen
+func (b *sarifBuilder) add(rule sarif.ReportingDescriptor, result sarif.Result) {
+	index, ok := b.ruleIndexes[rule.ID]
+	if !ok {
+		index = len(b.rules)
+		b.ruleIndexes[rule.ID] = index
+		b.rules = append(b.rules, rule)
+	}
+
+	result.RuleID = rule.ID
+	result.RuleIndex = &index
+	b.results = append(b.results, result)
+}
+
+func (b *sarifBuilder) addVulnerabilities(a *analyses.Analysis) {
+	results, err := a.Vulnerabilities()
+	if err != nil {
+		return
+	}
+
+	for _, product := range results.Vulnerabilities {
+		for _, v := range product.Vulnerabilities {
+			score, scored := vulnerabilityScore(v.Vulnerability)
+
+			rule := sarif.ReportingDescriptor{
+				ID:               v.ExternalID,
+				Name:             v.Title,
+				ShortDescription: &sarif.Message{Text: v.Title},
+				DefaultConfiguration: &sarif.ReportingConfiguration{
+					Level: scoreLevel(score, scored),
+				},
+				Properties: sarif.PropertyBag{"tags": []string{"security", "vulnerability"}},
+			}
+
+			if v.Summary != "" {
+				rule.FullDescription = &sarif.Message{Text: v.Summary}
+			}
+
+			if len(v.References) > 0 {
+				rule.HelpURI = v.References[0].URL
+			}
+
+			if scored {
+				rule.Properties["security-severity"] = strconv.FormatFloat(score, 'f', 1, 64)
+			}
+
+			b.add(rule, sarif.Result{
+				Level:     scoreLevel(score, scored),
+				Locations: sarifLocations(product.Query.File),
+				Message:   sarif.Message{Text: fmt.Sprintf("%v found in %v %v: %v", v.ExternalID, product.Name, product.Version, v.Title)},
+				Properties: sarif.PropertyBag{
+					"package": product.Name,
+					"version": product.Version,
+				},
+			})
+		}
+	}
+}
+
+func (b *sarifBuilder) addSecrets(a *analyses.Analysis) {
+	results, err := a.Secrets()
+	if err != nil {
+		return
+	}
+
+	for _, s := range results.Secrets {
+		masked := secrets.Mask(s.Match)
+
+		result := sarif.Result{
+			Level:      sarifLevelError,
+			Message:    sarif.Message{Text: fmt.Sprintf("possible %v secret found: %v", s.Rule, masked)},
+			Properties: sarif.PropertyBag{"confidence": s.Confidence},
+			Locations:  sarifLocations(s.File),
+		}
+
+		if s.File != "" {
+			result.Locations[0].PhysicalLocation.Region = &sarif.Region{StartLine: s.Line, Snippet: &sarif.ArtifactContent{Text: masked}}
+		}
+
+		b.add(sarif.ReportingDescriptor{
+			ID:                   "secret/" + s.Rule,
+			Name:                 s.Rule,
+			ShortDescription:     &sarif.Message{Text: fmt.Sprintf("%v secret", s.Rule)},
+			DefaultConfiguration: &sarif.ReportingConfiguration{Level: sarifLevelError},
+			Properties:           sarif.PropertyBag{"tags": []string{"security", "secret"}},
+		}, result)
+	}
+}
+
+func (b *sarifBuilder) addViruses(a *analyses.Analysis) {
+	results, err := a.Viruses()
+	if err != nil {
+		return
+	}
+
+	files := make([]string, 0, len(results.FileNotes))
+	for file := range results.FileNotes {
+		files = append(files, file)
+	}
+
+	sort.Strings(files)
+
+	for _, file := range files {
+		text := fmt.Sprintf("virus scan flagged %v", file)
+		if notes := results.FileNotes[file]; len(notes) > 0 {
+			text = fmt.Sprintf("%v: %v", text, notes)
+		}
+
+		b.add(sarif.ReportingDescriptor{
+			ID:                   "virus",
+			Name:                 "Virus",
+			ShortDescription:     &sarif.Message{Text: "File flagged by the virus scan"},
+			DefaultConfiguration: &sarif.ReportingConfiguration{Level: sarifLevelError},
+			Properties:           sarif.PropertyBag{"tags": []string{"security", "virus"}},
+		}, sarif.Result{
+			Level:     sarifLevelError,
+			Message:   sarif.Message{Text: text},
+			Locations: sarifLocations(file),
+		})
+	}
+}
+
+func (b *sarifBuilder) addFailedRules(appliedRuleset *rulesets.AppliedRulesetSummary) {
+	if appliedRuleset == nil || appliedRuleset.RuleEvaluationSummary == nil {
+		return
+	}
+
+	for _, e := range appliedRuleset.RuleEvaluationSummary.Ruleresults {
+		if e.Passed {
+			continue
+		}
+
+		rule := sarif.ReportingDescriptor{
+			ID:                   "rule/" + e.RuleID,
+			Name:                 e.Name,
+			ShortDescription:     &sarif.Message{Text: e.Name},
+			DefaultConfiguration: &sarif.ReportingConfiguration{Level: sarifLevelError},
+			Properties: sarif.PropertyBag{
+				"tags":      []string{"ruleset"},
+				"scan_type": e.Type,
+			},
+		}
+
+		if e.Description != "" {
+			rule.FullDescription = &sarif.Message{Text: e.Description}
+		}
+
+		b.add(rule, sarif.Result{
+			Level:     sarifLevelError,
+			Locations: sarifLocations(""),
+			Message:   sarif.Message{Text: fmt.Sprintf("rule %v failed: %v", e.Name, e.Summary)},
+			Properties: sarif.PropertyBag{
+				"ruleset_id": e.RulesetID,
+				"risk":       e.Risk,
+			},
+		})
+	}
+}
+
+// sarifLocations returns the location of a finding in the file, or in the root
+// of the repository when the file is not known
+func sarifLocations(file string) []sarif.Location {
+	if file == "" {
+		file = sarifRootArtifact
+	}
+
+	return []sarif.Location{
+		{
+			PhysicalLocation: &sarif.PhysicalLocation{
+				ArtifactLocation: &sarif.ArtifactLocation{URI: file},
+			},
+		},
+	}
+}
+
+// vulnerabilityScore returns the most specific score known for the
+// vulnerability: its CVSS v3 score, then its CVSS v2 score, then its NPM
+// score, then its own score
+func vulnerabilityScore(v vulnerabilities.Vulnerability) (float64, bool) {
+	switch {
+	case v.ScoreDetails.CVSSv3 != nil:
+		return v.ScoreDetails.CVSSv3.BaseScore, true
+	case v.ScoreDetails.CVSSv2 != nil:
+		return v.ScoreDetails.CVSSv2.BaseScore, true
+	case v.ScoreDetails.NPM != nil:
+		return v.ScoreDetails.NPM.BaseScore, true
+	}
+
+	score, err := strconv.ParseFloat(v.Score, 64)
+	return score, err == nil
+}
+
+// scoreLevel maps a CVSS score onto a SARIF level: high and critical scores
+// are errors, medium and unknown scores are warnings, and low scores are notes
+func scoreLevel(score float64, scored bool) string {
+	switch {
+	case !scored:
+		return sarifLevelWarning
+	case score >= 7.0:
+		return sarifLevelError
+	case score >= 4.0:
+		return sarifLevelWarning
+	default:
+		return sarifLevelNote
+	}
+}
@@ -36,6 +36,36 @@ func (ar *AppliedRulesetSummary) SummarizeEvaluation() (string, bool) {
 	return "high", false
 }
 
+// Name returns the name of the ruleset the analysis was evaluated with, as
+// recorded by its evaluation when it has one
+func (ar *AppliedRulesetSummary) Name() string {
+	if ar == nil {
+		return ""
+	}
+
+	if ar.RuleEvaluationSummary != nil && ar.RuleEvaluationSummary.RulesetName != "" {
+		return ar.RuleEvaluationSummary.RulesetName
+	}
+
+	return ar.RulesetName
+}
+
+// RuleResults returns the evaluations of the rules of the ruleset, or nil if
+// it has not been evaluated
+func (ar *AppliedRulesetSummary) RuleResults() []scans.Evaluation {
+	if ar == nil || ar.RuleEvaluationSummary == nil {
+		return nil
+	}
+
+	return ar.RuleEvaluationSummary.Ruleresults
+}
+
+// Passed returns whether the evaluation of the ruleset passed. A ruleset that
+// has not been evaluated has not passed.
+func (ar *AppliedRulesetSummary) Passed() bool {
+	return ar != nil && ar.RuleEvaluationSummary != nil && ar.RuleEvaluationSummary.Passed
+}
+
 // RuleEvaluationSummary represents the ruleset and the scans that were
 // evaluated with the ruleset
 type RuleEvaluationSummary struct {
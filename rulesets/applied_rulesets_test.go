@@ -5,6 +5,8 @@ import (
 
 	"github.com/franela/goblin"
 	. "github.com/onsi/gomega"
+
+	"github.com/ion-channel/ionic/scans"
 )
 
 func TestAppliedRulesets(t *testing.T) {
@@ -41,5 +43,29 @@ func TestAppliedRulesets(t *testing.T) {
 			Expect(r).To(Equal("high"))
 			Expect(p).To(Equal(false))
 		})
+
+		g.It("should return the name, results, and outcome of the evaluation", func() {
+			ar := &AppliedRulesetSummary{
+				RulesetName: "ruleset",
+				RuleEvaluationSummary: &RuleEvaluationSummary{
+					RulesetName: "evaluated ruleset",
+					Passed:      true,
+					Ruleresults: make([]scans.Evaluation, 1),
+				},
+			}
+
+			Expect(ar.Name()).To(Equal("evaluated ruleset"))
+			Expect(ar.RuleResults()).To(HaveLen(1))
+			Expect(ar.Passed()).To(BeTrue())
+
+			ar.RuleEvaluationSummary = nil
+			Expect(ar.Name()).To(Equal("ruleset"))
+			Expect(ar.RuleResults()).To(BeNil())
+			Expect(ar.Passed()).To(BeFalse())
+
+			ar = nil
+			Expect(ar.Name()).To(Equal(""))
+			Expect(ar.Passed()).To(BeFalse())
+		})
 	})
 }
@@ -0,0 +1,453 @@
+// Package evaluator evaluates the rules of a ruleset against the scan results
+// of an analysis locally, without asking the Ion Channel platform. It can be
+// used to preview the effect of a ruleset change or to run checks offline.
+package evaluator
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ion-channel/ionic/analyses"
+	"github.com/ion-channel/ionic/rules"
+	"github.com/ion-channel/ionic/rulesets"
+	"github.com/ion-channel/ionic/scans"
+)
+
+const (
+	riskLow  = "low"
+	riskHigh = "high"
+)
+
+// severities are the vulnerability severities, from most to least severe
+var severities = []string{"critical", "high", "medium", "low"}
+
+// severityWords matches the severities named by a rule
+var severityWords = regexp.MustCompile(`\b(critical|high|medium|low)\b`)
+
+// coverageThreshold matches the coverage percent stated by a rule, such as
+// the "> 70%" of "Code Coverage > 70%"
+var coverageThreshold = regexp.MustCompile(`(>=|>|≥)?\s*(\d+(?:\.\d+)?)\s*%`)
+
+// scanTypeAliases maps the alternate names used for some scan types onto the
+// type of their translated results
+var scanTypeAliases = map[string]string{
+	"clamav":            "virus",
+	"external_coverage": "coverage",
+	"vulnerabilities":   "vulnerability",
+}
+
+// Check is the local implementation of a rule. It is given the rule and the
+// analysis results of the rule's scan type, and returns whether they pass the
+// rule along with a summary explaining the outcome, or an error if the rule
+// cannot be evaluated.
+type Check func(rule rules.Rule, results []scans.TranslatedResults) (bool, string, error)
+
+// Options represents the settings of the built in checks
+type Options struct {
+	// MinimumCoverage is the coverage percent a project must reach to pass
+	// coverage rules that do not state their own, such as the 70% of "Code
+	// Coverage > 70%". Those rules cannot be evaluated when it is zero.
+	MinimumCoverage float64
+	// VulnerabilitySeverity is the lowest severity of vulnerability that fails
+	// vulnerability rules that do not name their own, such as the "high" of
+	// "No high vulnerabilities". Those rules cannot be evaluated when it is
+	// empty.
+	VulnerabilitySeverity string
+	// AllowedLicenses is the list of license names allowed by license rules.
+	// License rules are not evaluated when it is empty.
+	AllowedLicenses []string
+}
+
+// Evaluator evaluates rules against analyses using the checks registered for
+// each rule's scan type
+type Evaluator struct {
+	checks map[string]Check
+}
+
+// New returns an Evaluator with the built in checks registered: no
+// vulnerabilities of the rule's severity or worse for vulnerability and
+// external vulnerability rules, coverage of the rule's percent for coverage
+// rules, no infected files for virus rules, and the license allowlist for
+// license rules if one is given. Rules that state no severity or percent use
+// the options instead.
+func New(opts Options) *Evaluator {
+	e := &Evaluator{checks: make(map[string]Check)}
+
+	e.Register("vulnerability", VulnerabilitySeverityBelow(opts.VulnerabilitySeverity))
+	e.Register("external_vulnerability", VulnerabilitySeverityBelow(opts.VulnerabilitySeverity))
+	e.Register("coverage", CoverageThreshold(opts.MinimumCoverage))
+	e.Register("virus", NoViruses())
+
+	if len(opts.AllowedLicenses) > 0 {
+		e.Register("license", LicenseAllowlist(opts.AllowedLicenses...))
+	}
+
+	return e
+}
+
+// Register sets the check used for rules of the given scan type, replacing
+// any check previously registered for it
+func (e *Evaluator) Register(scanType string, check Check) {
+	e.checks[normalizeScanType(scanType)] = check
+}
+
+// Unsupported returns the rules of the ruleset without a check registered for
+// their scan type. These rules always fail when evaluated.
+func (e *Evaluator) Unsupported(rs rulesets.RuleSet) []rules.Rule {
+	var unsupported []rules.Rule
+	for _, rule := range rs.Rules {
+		if _, ok := e.checks[normalizeScanType(rule.ScanType)]; !ok {
+			unsupported = append(unsupported, rule)
+		}
+	}
+
+	return unsupported
+}
+
+// Evaluate applies every rule of the ruleset to the scan results of the
+// analysis, and returns a summary with an evaluation for each rule. The
+// ruleset passes only if every rule passes. Rules without a registered check,
+// or whose scan results are missing from the analysis, fail with an error.
+func (e *Evaluator) Evaluate(a *analyses.Analysis, rs rulesets.RuleSet) *rulesets.RuleEvaluationSummary {
+	if a == nil {
+		a = &analyses.Analysis{}
+	}
+
+	summary := &rulesets.RuleEvaluationSummary{
+		RulesetName: rs.Name,
+		Passed:      true,
+		Ruleresults: []scans.Evaluation{},
+	}
+
+	for _, rule := range rs.Rules {
+		eval := e.evaluateRule(a, rs.ID, rule)
+		summary.Ruleresults = append(summary.Ruleresults, *eval)
+		summary.Passed = summary.Passed && eval.Passed
+	}
+
+	summary.Summary, summary.Risk = "fail", riskHigh
+	if summary.Passed {
+		summary.Summary, summary.Risk = "pass", riskLow
+	}
+
+	return summary
+}
+
+func (e *Evaluator) evaluateRule(a *analyses.Analysis, rulesetID string, rule rules.Rule) *scans.Evaluation {
+	start := time.Now()
+	scanType := normalizeScanType(rule.ScanType)
+
+	eval := scans.NewEval()
+	eval.TeamID = a.TeamID
+	eval.ProjectID = a.ProjectID
+	eval.AnalysisID = a.ID
+	eval.RuleID = rule.ID
+	eval.RulesetID = rulesetID
+	eval.Name = rule.Name
+	eval.Description = rule.Description
+	eval.Type = rule.ScanType
+	eval.CreatedAt = start
+
+	results := resultsOfType(a, scanType)
+	if len(results) > 0 {
+		eval.TranslatedResults = &results[0]
+	}
+
+	check, ok := e.checks[scanType]
+	switch {
+	case !ok:
+		eval.Error = fmt.Sprintf("no local check is available for %v rules", rule.ScanType)
+		eval.Summary = eval.Error
+	case len(results) == 0:
+		eval.Error = fmt.Sprintf("no %v results were found in the analysis", rule.ScanType)
+		eval.Summary = eval.Error
+	default:
+		passed, summary, err := check(rule, results)
+		if err != nil {
+			eval.Error = err.Error()
+			summary = eval.Error
+		}
+
+		eval.Passed, eval.Summary = passed && err == nil, summary
+	}
+
+	eval.Risk = riskHigh
+	if eval.Passed {
+		eval.Risk = riskLow
+	}
+
+	eval.UpdatedAt = time.Now()
+	eval.Duration = float64(eval.UpdatedAt.Sub(start)) / float64(time.Millisecond)
+
+	return eval
+}
+
+// NoCriticalVulnerabilities returns a check that passes when none of the
+// vulnerabilities found are critical, whatever the severity the rule names
+func NoCriticalVulnerabilities() Check {
+	return func(rule rules.Rule, results []scans.TranslatedResults) (bool, string, error) {
+		return checkSeverity("critical", results)
+	}
+}
+
+// VulnerabilitySeverityBelow returns a check that passes when none of the
+// vulnerabilities found are of the severity the rule names or worse. The
+// least severe of "critical", "high", "medium", and "low" in the rule's name
+// or description is used, so "No high or critical vulnerabilities" fails on
+// high vulnerabilities. Rules that name none use the given severity, and
+// cannot be evaluated if it is empty.
+func VulnerabilitySeverityBelow(fallback string) Check {
+	return func(rule rules.Rule, results []scans.TranslatedResults) (bool, string, error) {
+		severity := ruleSeverity(rule)
+		if severity == "" {
+			severity = strings.ToLower(strings.TrimSpace(fallback))
+		}
+
+		if severityRank(severity) < 0 {
+			return false, "", fmt.Errorf("no vulnerability severity is configured for rule %q", rule.Name)
+		}
+
+		return checkSeverity(severity, results)
+	}
+}
+
+// CoverageAtLeast returns a check that passes when the code coverage is at or
+// above the given percent, whatever the percent the rule states
+func CoverageAtLeast(minimum float64) Check {
+	return func(rule rules.Rule, results []scans.TranslatedResults) (bool, string, error) {
+		return checkCoverage(minimum, false, results)
+	}
+}
+
+// CoverageThreshold returns a check that passes when the code coverage meets
+// the percent the rule states in its name or description, such as "Code
+// Coverage > 70%", where ">" requires coverage above the percent and any
+// other wording coverage at or above it. Rules that state none use the given
+// minimum, and cannot be evaluated if it is zero.
+func CoverageThreshold(fallback float64) Check {
+	return func(rule rules.Rule, results []scans.TranslatedResults) (bool, string, error) {
+		minimum, exclusive, ok := ruleCoverage(rule)
+		if !ok {
+			if fallback <= 0 {
+				return false, "", fmt.Errorf("no coverage threshold is configured for rule %q", rule.Name)
+			}
+
+			minimum, exclusive = fallback, false
+		}
+
+		return checkCoverage(minimum, exclusive, results)
+	}
+}
+
+func checkSeverity(severity string, results []scans.TranslatedResults) (bool, string, error) {
+	limit := severityRank(severity)
+
+	found := 0
+	for _, r := range results {
+		switch data := r.Data.(type) {
+		case scans.VulnerabilityResults:
+			for _, product := range data.Vulnerabilities {
+				for _, v := range product.Vulnerabilities {
+					if rank := severityRank(vulnerabilitySeverity(v)); rank >= 0 && rank <= limit {
+						found++
+					}
+				}
+			}
+		case scans.ExternalVulnerabilitiesResults:
+			for i, count := range []int{data.Critical, data.High, data.Medium, data.Low} {
+				if i <= limit {
+					found += count
+				}
+			}
+		}
+	}
+
+	label := severity
+	if limit > 0 {
+		label += " or worse"
+	}
+
+	if found > 0 {
+		return false, fmt.Sprintf("%v %v vulnerabilities found", found, label), nil
+	}
+
+	return true, fmt.Sprintf("no %v vulnerabilities found", label), nil
+}
+
+func checkCoverage(minimum float64, exclusive bool, results []scans.TranslatedResults) (bool, string, error) {
+	for _, r := range results {
+		data, ok := r.Data.(scans.CoverageResults)
+		if !ok {
+			continue
+		}
+
+		switch {
+		case data.Value < minimum:
+			return false, fmt.Sprintf("coverage of %.2f%% is below the minimum of %.2f%%", data.Value, minimum), nil
+		case exclusive && data.Value == minimum:
+			return false, fmt.Sprintf("coverage of %.2f%% is not above %.2f%%", data.Value, minimum), nil
+		}
+
+		return true, fmt.Sprintf("coverage of %.2f%% meets the minimum of %.2f%%", data.Value, minimum), nil
+	}
+
+	return false, "no coverage value was found", nil
+}
+
+// ruleCoverage returns the coverage percent stated by the rule, and whether
+// coverage must be above it rather than at or above it
+func ruleCoverage(rule rules.Rule) (float64, bool, bool) {
+	for _, text := range []string{rule.Name, rule.Description} {
+		m := coverageThreshold.FindStringSubmatch(text)
+		if m == nil {
+			continue
+		}
+
+		percent, err := strconv.ParseFloat(m[2], 64)
+		if err != nil {
+			continue
+		}
+
+		return percent, m[1] == ">", true
+	}
+
+	return 0, false, false
+}
+
+// ruleSeverity returns the least severe of the severities named by the rule,
+// or an empty string if it names none
+func ruleSeverity(rule rules.Rule) string {
+	for _, text := range []string{rule.Name, rule.Description} {
+		least := ""
+		for _, severity := range severityWords.FindAllString(strings.ToLower(text), -1) {
+			if severityRank(severity) > severityRank(least) {
+				least = severity
+			}
+		}
+
+		if least != "" {
+			return least
+		}
+	}
+
+	return ""
+}
+
+// severityRank returns the position of the severity in severities, or -1 if
+// it is not one
+func severityRank(severity string) int {
+	for i, s := range severities {
+		if s == severity {
+			return i
+		}
+	}
+
+	return -1
+}
+
+// NoViruses returns a check that passes when the virus scan found no infected
+// files
+func NoViruses() Check {
+	return func(rule rules.Rule, results []scans.TranslatedResults) (bool, string, error) {
+		infected := 0
+		for _, r := range results {
+			if data, ok := r.Data.(scans.VirusResults); ok {
+				infected += data.InfectedFiles
+			}
+		}
+
+		if infected > 0 {
+			return false, fmt.Sprintf("%v infected files found", infected), nil
+		}
+
+		return true, "no infected files found", nil
+	}
+}
+
+// LicenseAllowlist returns a check that passes when every license found is
+// one of the allowed licenses, compared without regard to case
+func LicenseAllowlist(allowed ...string) Check {
+	allowedSet := make(map[string]bool)
+	for _, l := range allowed {
+		allowedSet[strings.ToLower(l)] = true
+	}
+
+	return func(rule rules.Rule, results []scans.TranslatedResults) (bool, string, error) {
+		disallowed := make(map[string]bool)
+		for _, r := range results {
+			data, ok := r.Data.(scans.LicenseResults)
+			if !ok || data.License == nil {
+				continue
+			}
+
+			for _, t := range data.Type {
+				if !allowedSet[strings.ToLower(t.Name)] {
+					disallowed[t.Name] = true
+				}
+			}
+		}
+
+		if len(disallowed) > 0 {
+			names := make([]string, 0, len(disallowed))
+			for name := range disallowed {
+				names = append(names, name)
+			}
+
+			sort.Strings(names)
+
+			return false, fmt.Sprintf("licenses not allowed: %v", strings.Join(names, ", ")), nil
+		}
+
+		return true, "all licenses are allowed", nil
+	}
+}
+
+// vulnerabilitySeverity returns the CVSS v3 severity of the vulnerability or,
+// without one, the severity of its score, or an empty string if it has none
+func vulnerabilitySeverity(v scans.VulnerabilityResultsVulnerability) string {
+	if v.ScoreDetails.CVSSv3 != nil && v.ScoreDetails.CVSSv3.BaseSeverity != "" {
+		return strings.ToLower(v.ScoreDetails.CVSSv3.BaseSeverity)
+	}
+
+	var score float64
+	_, err := fmt.Sscanf(v.Score, "%g", &score)
+	switch {
+	case err != nil || score <= 0:
+		return ""
+	case score >= 9.0:
+		return "critical"
+	case score >= 7.0:
+		return "high"
+	case score >= 4.0:
+		return "medium"
+	default:
+		return "low"
+	}
+}
+
+// resultsOfType returns the translated scan results of the analysis with the
+// given type
+func resultsOfType(a *analyses.Analysis, resultType string) []scans.TranslatedResults {
+	var results []scans.TranslatedResults
+	for i := range a.ScanSummaries {
+		tr := a.ScanSummaries[i].Translated()
+		if tr != nil && normalizeScanType(tr.Type) == resultType {
+			results = append(results, *tr)
+		}
+	}
+
+	return results
+}
+
+func normalizeScanType(scanType string) string {
+	scanType = strings.ToLower(strings.TrimSpace(scanType))
+	if alias, ok := scanTypeAliases[scanType]; ok {
+		return alias
+	}
+
+	return scanType
+}
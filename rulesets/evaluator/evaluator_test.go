@@ -0,0 +1,173 @@
+package evaluator
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/franela/goblin"
+	. "github.com/onsi/gomega"
+
+	"github.com/ion-channel/ionic/analyses"
+	"github.com/ion-channel/ionic/rules"
+	"github.com/ion-channel/ionic/rulesets"
+	"github.com/ion-channel/ionic/scans"
+)
+
+func TestEvaluator(t *testing.T) {
+	g := goblin.Goblin(t)
+	RegisterFailHandler(func(m string, _ ...int) { g.Fail(m) })
+
+	g.Describe("Evaluating a Ruleset", func() {
+		var a analyses.Analysis
+
+		g.BeforeEach(func() {
+			Expect(json.Unmarshal([]byte(sampleAnalysis), &a)).To(Succeed())
+		})
+
+		rs := rulesets.RuleSet{
+			ID:   "ruleset",
+			Name: "Offline",
+			Rules: []rules.Rule{
+				{ID: "r1", ScanType: "vulnerability", Name: "No critical vulnerabilities"},
+				{ID: "r2", ScanType: "coverage", Name: "Coverage above 70%"},
+				{ID: "r3", ScanType: "clamav", Name: "No viruses"},
+				{ID: "r4", ScanType: "license", Name: "Allowed licenses"},
+			},
+		}
+
+		g.It("should evaluate each rule with the built in checks", func() {
+			e := New(Options{MinimumCoverage: 70, AllowedLicenses: []string{"mit", "Apache-2.0"}})
+
+			s := e.Evaluate(&a, rs)
+			Expect(s.RulesetName).To(Equal("Offline"))
+			Expect(s.Passed).To(BeFalse())
+			Expect(s.Summary).To(Equal("fail"))
+			Expect(s.Risk).To(Equal("high"))
+			Expect(s.Ruleresults).To(HaveLen(4))
+
+			Expect(s.Ruleresults[0].RuleID).To(Equal("r1"))
+			Expect(s.Ruleresults[0].RulesetID).To(Equal("ruleset"))
+			Expect(s.Ruleresults[0].AnalysisID).To(Equal("analysis"))
+			Expect(s.Ruleresults[0].Passed).To(BeFalse())
+			Expect(s.Ruleresults[0].Summary).To(Equal("2 critical vulnerabilities found"))
+			Expect(s.Ruleresults[0].TranslatedResults.Type).To(Equal("vulnerability"))
+
+			Expect(s.Ruleresults[1].Passed).To(BeTrue())
+			Expect(s.Ruleresults[1].Risk).To(Equal("low"))
+			Expect(s.Ruleresults[2].Passed).To(BeTrue())
+			Expect(s.Ruleresults[3].Passed).To(BeFalse())
+			Expect(s.Ruleresults[3].Summary).To(Equal("licenses not allowed: GPL-3.0"))
+		})
+
+		g.It("should pass when every rule passes", func() {
+			e := New(Options{MinimumCoverage: 70})
+			e.Register("vulnerability", func(rules.Rule, []scans.TranslatedResults) (bool, string, error) {
+				return true, "accepted", nil
+			})
+
+			s := e.Evaluate(&a, rulesets.RuleSet{Rules: rs.Rules[:3]})
+			Expect(s.Passed).To(BeTrue())
+			Expect(s.Summary).To(Equal("pass"))
+			Expect(s.Ruleresults[0].Summary).To(Equal("accepted"))
+		})
+
+		g.It("should fail rules without a check or results", func() {
+			e := New(Options{})
+			Expect(e.Unsupported(rs)).To(Equal([]rules.Rule{rs.Rules[3]}))
+
+			s := e.Evaluate(&analyses.Analysis{}, rs)
+			Expect(s.Passed).To(BeFalse())
+			Expect(s.Ruleresults[0].Summary).To(Equal("no vulnerability results were found in the analysis"))
+			Expect(s.Ruleresults[3].Summary).To(Equal("no local check is available for license rules"))
+			Expect(s.Ruleresults[3].Error).To(Equal("no local check is available for license rules"))
+		})
+
+		g.It("should fail coverage below the minimum", func() {
+			e := New(Options{MinimumCoverage: 90})
+
+			s := e.Evaluate(&a, rulesets.RuleSet{Rules: []rules.Rule{{ID: "r2", ScanType: "coverage", Name: "Code Coverage"}}})
+			Expect(s.Passed).To(BeFalse())
+			Expect(s.Ruleresults[0].Summary).To(Equal("coverage of 75.50% is below the minimum of 90.00%"))
+		})
+
+		g.It("should take coverage thresholds from each rule", func() {
+			e := New(Options{})
+
+			s := e.Evaluate(&a, rulesets.RuleSet{Rules: []rules.Rule{
+				{ID: "c1", ScanType: "coverage", Name: "Code Coverage > 70%"},
+				{ID: "c2", ScanType: "coverage", Name: "Code Coverage > 80%"},
+				{ID: "c3", ScanType: "external_coverage", Name: "Coverage", Description: "at least 75.5% of lines are covered"},
+			}})
+			Expect(s.Ruleresults[0].Passed).To(BeTrue())
+			Expect(s.Ruleresults[1].Passed).To(BeFalse())
+			Expect(s.Ruleresults[1].Summary).To(Equal("coverage of 75.50% is below the minimum of 80.00%"))
+			Expect(s.Ruleresults[2].Passed).To(BeTrue())
+		})
+
+		g.It("should take vulnerability severities from each rule", func() {
+			var b analyses.Analysis
+			Expect(json.Unmarshal([]byte(sampleExternalAnalysis), &b)).To(Succeed())
+
+			e := New(Options{})
+
+			s := e.Evaluate(&b, rulesets.RuleSet{Rules: []rules.Rule{
+				{ID: "v1", ScanType: "external_vulnerability", Name: "Has No Critical Vulnerabilities"},
+				{ID: "v2", ScanType: "external_vulnerability", Name: "No high or critical vulnerabilities"},
+			}})
+			Expect(s.Ruleresults[0].Passed).To(BeTrue())
+			Expect(s.Ruleresults[0].Summary).To(Equal("no critical vulnerabilities found"))
+			Expect(s.Ruleresults[1].Passed).To(BeFalse())
+			Expect(s.Ruleresults[1].Summary).To(Equal("2 high or worse vulnerabilities found"))
+
+			s = e.Evaluate(&a, rulesets.RuleSet{Rules: []rules.Rule{
+				{ID: "v3", ScanType: "vulnerability", Name: "No medium vulnerabilities"},
+			}})
+			Expect(s.Ruleresults[0].Summary).To(Equal("3 medium or worse vulnerabilities found"))
+		})
+
+		g.It("should not evaluate rules without a threshold", func() {
+			e := New(Options{})
+
+			s := e.Evaluate(&a, rulesets.RuleSet{Rules: []rules.Rule{
+				{ID: "c1", ScanType: "coverage", Name: "Code Coverage"},
+				{ID: "v1", ScanType: "vulnerability", Name: "Vulnerabilities"},
+			}})
+			Expect(s.Passed).To(BeFalse())
+			Expect(s.Ruleresults[0].Error).To(Equal(`no coverage threshold is configured for rule "Code Coverage"`))
+			Expect(s.Ruleresults[1].Error).To(Equal(`no vulnerability severity is configured for rule "Vulnerabilities"`))
+
+			e = New(Options{MinimumCoverage: 70, VulnerabilitySeverity: "critical"})
+			s = e.Evaluate(&a, rulesets.RuleSet{Rules: []rules.Rule{
+				{ID: "c1", ScanType: "coverage", Name: "Code Coverage"},
+				{ID: "v1", ScanType: "vulnerability", Name: "Vulnerabilities"},
+			}})
+			Expect(s.Ruleresults[0].Passed).To(BeTrue())
+			Expect(s.Ruleresults[1].Summary).To(Equal("2 critical vulnerabilities found"))
+		})
+	})
+}
+
+const sampleExternalAnalysis = `{
+  "id": "analysis",
+  "scan_summaries": [
+    {"id": "s1", "results": {"type": "external_vulnerability", "data": {"critical": 0, "high": 2, "medium": 5, "low": 1}}}
+  ]
+}`
+
+const sampleAnalysis = `{
+  "id": "analysis",
+  "team_id": "team",
+  "project_id": "project",
+  "scan_summaries": [
+    {"id": "s1", "results": {"type": "vulnerability", "data": {"vulnerabilities": [
+      {"name": "log4j-core", "version": "2.14.1", "vulnerabilities": [
+        {"external_id": "CVE-2021-44228", "score": "10.0", "score_details": {"cvssv3": {"baseSeverity": "CRITICAL"}}},
+        {"external_id": "CVE-2021-45046", "score": "9.0"},
+        {"external_id": "CVE-2021-45105", "score": "5.9", "score_details": {"cvssv3": {"baseSeverity": "MEDIUM"}}}
+      ]}
+    ]}}},
+    {"id": "s2", "results": {"type": "coverage", "data": {"value": 75.5}}},
+    {"id": "s3", "results": {"type": "virus", "data": {"infected_files": 0, "file_notes": {}}}},
+    {"id": "s4", "results": {"type": "license", "data": {"license": {"name": "LICENSE", "type": [{"name": "MIT"}, {"name": "GPL-3.0"}]}}}}
+  ]
+}`
@@ -0,0 +1,238 @@
+package sarif
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/ion-channel/ionic/scanner"
+)
+
+const (
+	// SeverityCritical is the severity of findings with a CVSS score of 9.0 or higher
+	SeverityCritical = "critical"
+	// SeverityHigh is the severity of findings with a CVSS score of 7.0 or
+	// higher, or a level of error
+	SeverityHigh = "high"
+	// SeverityMedium is the severity of findings with a CVSS score of 4.0 or
+	// higher, or a level of warning
+	SeverityMedium = "medium"
+	// SeverityLow is the severity of findings with a CVSS score below 4.0, or a
+	// level of note
+	SeverityLow = "low"
+)
+
+// scoreProperties are the property names used by common tools to carry a CVSS
+// score, in order of preference
+var scoreProperties = []string{"security-severity", "cvss", "cvssScore", "cvss_score", "cvssV3_baseScore"}
+
+// Finding is the normalized representation of a SARIF result kept in the raw
+// data of an external scan
+type Finding struct {
+	Tool      string            `json:"tool"`
+	RuleID    string            `json:"rule_id,omitempty"`
+	RuleName  string            `json:"rule_name,omitempty"`
+	Level     string            `json:"level"`
+	Severity  string            `json:"severity"`
+	Score     *float64          `json:"score,omitempty"`
+	Message   string            `json:"message"`
+	HelpURI   string            `json:"help_uri,omitempty"`
+	Locations []FindingLocation `json:"locations,omitempty"`
+}
+
+// FindingLocation is the file and line where a finding was found
+type FindingLocation struct {
+	URI       string `json:"uri"`
+	StartLine int    `json:"start_line,omitempty"`
+	EndLine   int    `json:"end_line,omitempty"`
+}
+
+// Findings returns the normalized findings of every run in the log. Results
+// whose kind marks them as passing or not applicable are left out.
+func (l *Log) Findings() []Finding {
+	findings := []Finding{}
+	for i := range l.Runs {
+		run := &l.Runs[i]
+		for _, result := range run.Results {
+			if !isFailure(result) {
+				continue
+			}
+
+			findings = append(findings, newFinding(run, result))
+		}
+	}
+
+	return findings
+}
+
+// ToExternalScan converts the log into an external scan. The findings are
+// counted by severity into the scan's vulnerability counts and kept in its raw
+// data, and the tool driver of the first run is recorded as the scan's source.
+func (l *Log) ToExternalScan() (*scanner.ExternalScan, error) {
+	findings := l.Findings()
+
+	counts := &scanner.ExternalVulnerability{}
+	for _, f := range findings {
+		switch f.Severity {
+		case SeverityCritical:
+			counts.Critcal++
+		case SeverityHigh:
+			counts.High++
+		case SeverityMedium:
+			counts.Medium++
+		case SeverityLow:
+			counts.Low++
+		}
+	}
+
+	b, err := json.Marshal(findings)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal SARIF findings: %v", err.Error())
+	}
+
+	raw := json.RawMessage(b)
+
+	scan := &scanner.ExternalScan{
+		Vulnerability: counts,
+		Notes:         fmt.Sprintf("%v findings imported from SARIF %v", len(findings), l.Version),
+		Raw:           &raw,
+	}
+
+	if len(l.Runs) > 0 {
+		driver := l.Runs[0].Tool.Driver
+		scan.Source = scanner.Source{
+			Name: toolName(driver),
+			URL:  driver.InformationURI,
+		}
+	}
+
+	return scan, nil
+}
+
+func newFinding(run *Run, result Result) Finding {
+	f := Finding{
+		Tool:    toolName(run.Tool.Driver),
+		RuleID:  result.RuleID,
+		Level:   result.Level,
+		Message: result.Message.Text,
+	}
+
+	rule := run.rule(result)
+	if rule != nil {
+		f.RuleID = rule.ID
+		f.RuleName = rule.Name
+		f.HelpURI = rule.HelpURI
+
+		if f.Level == "" && rule.DefaultConfiguration != nil {
+			f.Level = rule.DefaultConfiguration.Level
+		}
+	}
+
+	// warning is the level SARIF assigns to results that do not specify one
+	if f.Level == "" {
+		f.Level = "warning"
+	}
+
+	f.Score = score(result.Properties)
+	if f.Score == nil && rule != nil {
+		f.Score = score(rule.Properties)
+	}
+
+	if f.Score != nil {
+		f.Severity = severityFromScore(*f.Score)
+	} else {
+		f.Severity = severityFromLevel(f.Level)
+	}
+
+	for _, loc := range result.Locations {
+		if loc.PhysicalLocation == nil || loc.PhysicalLocation.ArtifactLocation == nil {
+			continue
+		}
+
+		fl := FindingLocation{URI: loc.PhysicalLocation.ArtifactLocation.URI}
+		if loc.PhysicalLocation.Region != nil {
+			fl.StartLine = loc.PhysicalLocation.Region.StartLine
+			fl.EndLine = loc.PhysicalLocation.Region.EndLine
+		}
+
+		f.Locations = append(f.Locations, fl)
+	}
+
+	return f
+}
+
+// isFailure returns whether the result represents a problem, as opposed to a
+// check that passed or did not apply
+func isFailure(result Result) bool {
+	switch result.Kind {
+	case "pass", "notApplicable", "informational":
+		return false
+	}
+
+	return result.Level != "none"
+}
+
+func score(props PropertyBag) *float64 {
+	for _, name := range scoreProperties {
+		v, ok := props[name]
+		if !ok {
+			continue
+		}
+
+		var s float64
+		switch t := v.(type) {
+		case float64:
+			s = t
+		case string:
+			parsed, err := strconv.ParseFloat(strings.TrimSpace(t), 64)
+			if err != nil {
+				continue
+			}
+			s = parsed
+		default:
+			continue
+		}
+
+		return &s
+	}
+
+	return nil
+}
+
+func severityFromScore(score float64) string {
+	switch {
+	case score >= 9.0:
+		return SeverityCritical
+	case score >= 7.0:
+		return SeverityHigh
+	case score >= 4.0:
+		return SeverityMedium
+	default:
+		return SeverityLow
+	}
+}
+
+func severityFromLevel(level string) string {
+	switch level {
+	case "error":
+		return SeverityHigh
+	case "note":
+		return SeverityLow
+	default:
+		return SeverityMedium
+	}
+}
+
+func toolName(driver ToolComponent) string {
+	version := driver.SemanticVersion
+	if version == "" {
+		version = driver.Version
+	}
+
+	if version == "" {
+		return driver.Name
+	}
+
+	return fmt.Sprintf("%v %v", driver.Name, version)
+}
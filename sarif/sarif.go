@@ -0,0 +1,164 @@
+// Package sarif contains a representation of the Static Analysis Results
+// Interchange Format (SARIF) 2.1.0, and the conversion of SARIF logs into
+// external scans that can be submitted to Ion Channel.
+package sarif
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+)
+
+const (
+	// Version is the version of SARIF supported by this package
+	Version = "2.1.0"
+	// Schema is the location of the JSON schema for SARIF 2.1.0
+	Schema = "https://json.schemastore.org/sarif-2.1.0.json"
+)
+
+// Log is the top level object of a SARIF file, containing the runs of one or
+// more analysis tools
+type Log struct {
+	Version string `json:"version"`
+	Schema  string `json:"$schema,omitempty"`
+	Runs    []Run  `json:"runs"`
+}
+
+// Run represents a single invocation of an analysis tool
+type Run struct {
+	Tool    Tool     `json:"tool"`
+	Results []Result `json:"results"`
+}
+
+// Tool describes the analysis tool that was run
+type Tool struct {
+	Driver     ToolComponent   `json:"driver"`
+	Extensions []ToolComponent `json:"extensions,omitempty"`
+}
+
+// ToolComponent represents the driver or an extension of an analysis tool,
+// along with the rules it evaluates
+type ToolComponent struct {
+	Name            string                `json:"name"`
+	Version         string                `json:"version,omitempty"`
+	SemanticVersion string                `json:"semanticVersion,omitempty"`
+	InformationURI  string                `json:"informationUri,omitempty"`
+	Rules           []ReportingDescriptor `json:"rules,omitempty"`
+}
+
+// ReportingDescriptor describes a rule evaluated by an analysis tool
+type ReportingDescriptor struct {
+	ID                   string                  `json:"id"`
+	Name                 string                  `json:"name,omitempty"`
+	ShortDescription     *Message                `json:"shortDescription,omitempty"`
+	FullDescription      *Message                `json:"fullDescription,omitempty"`
+	Help                 *Message                `json:"help,omitempty"`
+	HelpURI              string                  `json:"helpUri,omitempty"`
+	DefaultConfiguration *ReportingConfiguration `json:"defaultConfiguration,omitempty"`
+	Properties           PropertyBag             `json:"properties,omitempty"`
+}
+
+// ReportingConfiguration contains the default settings of a rule
+type ReportingConfiguration struct {
+	Level string `json:"level,omitempty"`
+}
+
+// Result represents a single finding produced by an analysis tool
+type Result struct {
+	RuleID              string            `json:"ruleId,omitempty"`
+	RuleIndex           *int              `json:"ruleIndex,omitempty"`
+	Kind                string            `json:"kind,omitempty"`
+	Level               string            `json:"level,omitempty"`
+	Message             Message           `json:"message"`
+	Locations           []Location        `json:"locations,omitempty"`
+	PartialFingerprints map[string]string `json:"partialFingerprints,omitempty"`
+	Properties          PropertyBag       `json:"properties,omitempty"`
+}
+
+// Message is a message and its optional markdown variant
+type Message struct {
+	Text     string `json:"text,omitempty"`
+	Markdown string `json:"markdown,omitempty"`
+}
+
+// Location identifies the place a result was found
+type Location struct {
+	PhysicalLocation *PhysicalLocation `json:"physicalLocation,omitempty"`
+	Message          *Message          `json:"message,omitempty"`
+}
+
+// PhysicalLocation identifies a region within an artifact
+type PhysicalLocation struct {
+	ArtifactLocation *ArtifactLocation `json:"artifactLocation,omitempty"`
+	Region           *Region           `json:"region,omitempty"`
+}
+
+// ArtifactLocation identifies an artifact, such as a file, by its URI
+type ArtifactLocation struct {
+	URI       string `json:"uri,omitempty"`
+	URIBaseID string `json:"uriBaseId,omitempty"`
+}
+
+// Region is a contiguous portion of an artifact
+type Region struct {
+	StartLine   int              `json:"startLine,omitempty"`
+	StartColumn int              `json:"startColumn,omitempty"`
+	EndLine     int              `json:"endLine,omitempty"`
+	EndColumn   int              `json:"endColumn,omitempty"`
+	Snippet     *ArtifactContent `json:"snippet,omitempty"`
+}
+
+// ArtifactContent is the content of a portion of an artifact
+type ArtifactContent struct {
+	Text string `json:"text,omitempty"`
+}
+
+// PropertyBag contains the tool specific properties of a SARIF object
+type PropertyBag map[string]interface{}
+
+// Parse reads a SARIF log from the given reader. It returns an error if the
+// log cannot be decoded or is not SARIF 2.1.0.
+func Parse(r io.Reader) (*Log, error) {
+	var l Log
+	err := json.NewDecoder(r).Decode(&l)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse SARIF log: %v", err.Error())
+	}
+
+	if l.Version != Version {
+		return nil, fmt.Errorf("unsupported SARIF version: %q", l.Version)
+	}
+
+	return &l, nil
+}
+
+// ParseFile reads a SARIF log from the file at the given path
+func ParseFile(path string) (*Log, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open SARIF log: %v", err.Error())
+	}
+	defer f.Close()
+
+	return Parse(f)
+}
+
+// rule returns the rule of the tool driver or extensions referenced by the
+// result, or nil if the run does not describe it
+func (r *Run) rule(result Result) *ReportingDescriptor {
+	if result.RuleIndex != nil && *result.RuleIndex >= 0 && *result.RuleIndex < len(r.Tool.Driver.Rules) {
+		return &r.Tool.Driver.Rules[*result.RuleIndex]
+	}
+
+	components := append([]ToolComponent{r.Tool.Driver}, r.Tool.Extensions...)
+	for i := range components {
+		for j := range components[i].Rules {
+			if components[i].Rules[j].ID == result.RuleID {
+				return &components[i].Rules[j]
+			}
+		}
+	}
+
+	return nil
+}
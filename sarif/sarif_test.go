@@ -0,0 +1,98 @@
+package sarif
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/franela/goblin"
+	. "github.com/onsi/gomega"
+)
+
+func TestSARIF(t *testing.T) {
+	g := goblin.Goblin(t)
+	RegisterFailHandler(func(m string, _ ...int) { g.Fail(m) })
+
+	g.Describe("Parsing", func() {
+		g.It("should parse a SARIF 2.1.0 log", func() {
+			l, err := Parse(strings.NewReader(sampleLog))
+			Expect(err).To(BeNil())
+			Expect(l.Runs).To(HaveLen(1))
+			Expect(l.Runs[0].Tool.Driver.Name).To(Equal("gosec"))
+			Expect(l.Runs[0].Results).To(HaveLen(5))
+		})
+
+		g.It("should reject other versions", func() {
+			_, err := Parse(strings.NewReader(`{"version":"1.0.0","runs":[]}`))
+			Expect(err).NotTo(BeNil())
+			Expect(err.Error()).To(ContainSubstring("unsupported SARIF version"))
+		})
+
+		g.It("should return an error for invalid JSON", func() {
+			_, err := Parse(strings.NewReader(`{"version":`))
+			Expect(err).NotTo(BeNil())
+		})
+	})
+
+	g.Describe("Converting to an External Scan", func() {
+		g.It("should count findings by score and level", func() {
+			l, _ := Parse(strings.NewReader(sampleLog))
+
+			scan, err := l.ToExternalScan()
+			Expect(err).To(BeNil())
+			Expect(scan.Source.Name).To(Equal("gosec 2.15.0"))
+			Expect(scan.Source.URL).To(Equal("https://github.com/securego/gosec"))
+			Expect(scan.Vulnerability.Critcal).To(Equal(1))
+			Expect(scan.Vulnerability.High).To(Equal(1))
+			Expect(scan.Vulnerability.Medium).To(Equal(1))
+			Expect(scan.Vulnerability.Low).To(Equal(1))
+		})
+
+		g.It("should keep the details of each finding in the raw data", func() {
+			l, _ := Parse(strings.NewReader(sampleLog))
+
+			scan, _ := l.ToExternalScan()
+
+			var findings []Finding
+			Expect(json.Unmarshal(*scan.Raw, &findings)).To(Succeed())
+			Expect(findings).To(HaveLen(4))
+			Expect(findings[0].RuleID).To(Equal("G101"))
+			Expect(findings[0].Severity).To(Equal(SeverityCritical))
+			Expect(*findings[0].Score).To(Equal(9.8))
+			Expect(findings[0].Locations).To(Equal([]FindingLocation{{URI: "main.go", StartLine: 12, EndLine: 12}}))
+			Expect(findings[1].Level).To(Equal("error"))
+			Expect(findings[1].Severity).To(Equal(SeverityHigh))
+			Expect(findings[2].Level).To(Equal("warning"))
+			Expect(findings[2].Severity).To(Equal(SeverityMedium))
+			Expect(findings[3].Severity).To(Equal(SeverityLow))
+		})
+	})
+}
+
+const sampleLog = `{
+  "version": "2.1.0",
+  "$schema": "https://json.schemastore.org/sarif-2.1.0.json",
+  "runs": [{
+    "tool": {
+      "driver": {
+        "name": "gosec",
+        "semanticVersion": "2.15.0",
+        "informationUri": "https://github.com/securego/gosec",
+        "rules": [
+          {"id": "G101", "name": "HardcodedCredentials", "properties": {"security-severity": "9.8"}},
+          {"id": "G104", "defaultConfiguration": {"level": "error"}},
+          {"id": "G304"},
+          {"id": "G307", "defaultConfiguration": {"level": "note"}}
+        ]
+      }
+    },
+    "results": [
+      {"ruleId": "G101", "ruleIndex": 0, "message": {"text": "Potential hardcoded credentials"},
+       "locations": [{"physicalLocation": {"artifactLocation": {"uri": "main.go"}, "region": {"startLine": 12, "endLine": 12}}}]},
+      {"ruleId": "G104", "message": {"text": "Errors unhandled"}},
+      {"ruleId": "G304", "message": {"text": "Potential file inclusion"}},
+      {"ruleId": "G307", "message": {"text": "Deferring unsafe method"}},
+      {"ruleId": "G304", "kind": "pass", "message": {"text": "Checked"}}
+    ]
+  }]
+}`
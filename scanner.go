@@ -4,11 +4,14 @@ import (
 	"bytes"
 	"encoding/json"
 	"fmt"
+	"github.com/ion-channel/ionic/coverage"
 	"github.com/ion-channel/ionic/pagination"
+	"github.com/ion-channel/ionic/sarif"
 	"github.com/ion-channel/ionic/scans"
 	"net/url"
 
 	"github.com/ion-channel/ionic/scanner"
+	"github.com/ion-channel/ionic/scanner/importers"
 )
 
 type addScanRequest struct {
@@ -176,6 +179,44 @@ func (ic *IonClient) AddScanResult(scanResultID, teamID, projectID, status, scan
 	return &a, nil
 }
 
+// AddSARIFScanResult takes a scanResultID, teamID, projectID, status, and a
+// SARIF log, converts the log into an external vulnerability scan, and adds it
+// to the returned project analysis or an error encountered by the API
+func (ic *IonClient) AddSARIFScanResult(scanResultID, teamID, projectID, status, token string, log *sarif.Log) (*scanner.AnalysisStatus, error) {
+	scan, err := log.ToExternalScan()
+	if err != nil {
+		return nil, fmt.Errorf("failed to convert SARIF log: %v", err.Error())
+	}
+
+	return ic.AddScanResult(scanResultID, teamID, projectID, status, "external_vulnerability", token, *scan)
+}
+
+// AddCoverageFromReport takes a scanResultID, teamID, projectID, status, and a
+// parsed coverage report, converts the report into an external coverage scan,
+// and adds it to the returned project analysis or an error encountered by the
+// API
+func (ic *IonClient) AddCoverageFromReport(scanResultID, teamID, projectID, status, token string, report *coverage.Report) (*scanner.AnalysisStatus, error) {
+	scan, err := report.ToExternalScan()
+	if err != nil {
+		return nil, fmt.Errorf("failed to convert coverage report: %v", err.Error())
+	}
+
+	return ic.AddScanResult(scanResultID, teamID, projectID, status, "external_coverage", token, *scan)
+}
+
+// AddImportedScanResult takes a scanResultID, teamID, projectID, status, and
+// the findings imported from third party scanner reports, converts them into an
+// external vulnerability scan, and adds it to the returned project analysis or
+// an error encountered by the API
+func (ic *IonClient) AddImportedScanResult(scanResultID, teamID, projectID, status, token string, report *importers.Report) (*scanner.AnalysisStatus, error) {
+	scan, err := report.ToExternalScan()
+	if err != nil {
+		return nil, fmt.Errorf("failed to convert imported findings: %v", err.Error())
+	}
+
+	return ic.AddScanResult(scanResultID, teamID, projectID, status, "external_vulnerability", token, *scan)
+}
+
 type projectStates struct {
 	Filter string   `json:"filter"`
 	IDs    []string `json:"ids"`
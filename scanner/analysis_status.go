@@ -57,9 +57,23 @@ type AnalysisStatus struct {
 func (a *AnalysisStatus) Done() bool {
 	return a.Status == AnalysisStatusErrored ||
 		a.Status == AnalysisStatusFailed ||
+		a.Status == AnalysisStatusPassed ||
 		a.Status == AnalysisStatusFinished
 }
 
+// ErroredScans returns the statuses of the scans within the analysis that
+// have errored
+func (a *AnalysisStatus) ErroredScans() []ScanStatus {
+	var errored []ScanStatus
+	for i := range a.ScanStatus {
+		if a.ScanStatus[i].Errored() {
+			errored = append(errored, a.ScanStatus[i])
+		}
+	}
+
+	return errored
+}
+
 // Navigation represents a navigational meta data reference to given analysis
 type Navigation struct {
 	Analysis       *AnalysisStatus `json:"analysis"`
@@ -21,6 +21,28 @@ func TestEvaluation(t *testing.T) {
 			Expect(a.Done()).To(BeTrue())
 		})
 
+		g.It("should consider a passed analysis done", func() {
+			a := &AnalysisStatus{
+				Status: AnalysisStatusPassed,
+			}
+
+			Expect(a.Done()).To(BeTrue())
+		})
+
+		g.It("should return the errored scans", func() {
+			a := &AnalysisStatus{
+				ScanStatus: []ScanStatus{
+					{Name: "license", Status: ScanStatusFinished},
+					{Name: "vulnerability", Status: ScanStatusErrored, Message: "timed out"},
+				},
+			}
+
+			errored := a.ErroredScans()
+			Expect(errored).To(HaveLen(1))
+			Expect(errored[0].Name).To(Equal("vulnerability"))
+			Expect(errored[0].Message).To(Equal("timed out"))
+		})
+
 		g.It("should provide a simple function for determining not done status", func() {
 			a := &AnalysisStatus{
 				Status: AnalysisStatusAnalyzing,
@@ -0,0 +1,90 @@
+package importers
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/ion-channel/ionic/purl"
+	"github.com/ion-channel/ionic/scanner"
+)
+
+type dependencyCheckReport struct {
+	ScanInfo struct {
+		EngineVersion string `json:"engineVersion"`
+	} `json:"scanInfo"`
+	Dependencies []struct {
+		FileName string `json:"fileName"`
+		Packages []struct {
+			ID string `json:"id"`
+		} `json:"packages"`
+		Vulnerabilities []struct {
+			Name        string `json:"name"`
+			Severity    string `json:"severity"`
+			Description string `json:"description"`
+			CVSSv2      *struct {
+				Score float64 `json:"score"`
+			} `json:"cvssv2"`
+			CVSSv3 *struct {
+				BaseScore float64 `json:"baseScore"`
+			} `json:"cvssv3"`
+		} `json:"vulnerabilities"`
+	} `json:"dependencies"`
+}
+
+// ParseDependencyCheck reads an OWASP Dependency-Check JSON report. Packages
+// are identified by the package URL Dependency-Check assigned to them, or by
+// their file name when it could not identify them. Dependency-Check reports do
+// not include fixed versions.
+func ParseDependencyCheck(r io.Reader) (*Report, error) {
+	var dr dependencyCheckReport
+	err := json.NewDecoder(r).Decode(&dr)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse Dependency-Check report: %v", err.Error())
+	}
+
+	findings := []Finding{}
+	for _, d := range dr.Dependencies {
+		name, version, id := d.FileName, "", ""
+		if len(d.Packages) > 0 {
+			if p, err := purl.Parse(d.Packages[0].ID); err == nil {
+				name, version, id = p.Name, p.Version, p.String()
+			}
+		}
+
+		for _, v := range d.Vulnerabilities {
+			var scores []float64
+			if v.CVSSv3 != nil {
+				scores = append(scores, v.CVSSv3.BaseScore)
+			}
+
+			if v.CVSSv2 != nil {
+				scores = append(scores, v.CVSSv2.Score)
+			}
+
+			score := maxScore(scores...)
+
+			findings = append(findings, Finding{
+				ID:       v.Name,
+				Package:  name,
+				PURL:     id,
+				Version:  version,
+				Severity: normalizeSeverity(v.Severity, score),
+				Score:    score,
+				Title:    firstLine(v.Description),
+			})
+		}
+	}
+
+	name := "OWASP Dependency-Check"
+	if dr.ScanInfo.EngineVersion != "" {
+		name = fmt.Sprintf("%v %v", name, dr.ScanInfo.EngineVersion)
+	}
+
+	return newReport(scanner.Source{Name: name, URL: "https://owasp.org/www-project-dependency-check/"}, findings), nil
+}
+
+func firstLine(s string) string {
+	return strings.TrimSpace(strings.SplitN(s, "\n", 2)[0])
+}
@@ -0,0 +1,89 @@
+package importers
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"github.com/ion-channel/ionic/scanner"
+)
+
+type grypeCVSS struct {
+	Metrics struct {
+		BaseScore float64 `json:"baseScore"`
+	} `json:"metrics"`
+}
+
+type grypeReport struct {
+	Matches []struct {
+		Vulnerability struct {
+			ID         string      `json:"id"`
+			DataSource string      `json:"dataSource"`
+			Severity   string      `json:"severity"`
+			CVSS       []grypeCVSS `json:"cvss"`
+			Fix        struct {
+				Versions []string `json:"versions"`
+			} `json:"fix"`
+		} `json:"vulnerability"`
+		RelatedVulnerabilities []struct {
+			ID   string      `json:"id"`
+			CVSS []grypeCVSS `json:"cvss"`
+		} `json:"relatedVulnerabilities"`
+		Artifact struct {
+			Name    string `json:"name"`
+			Version string `json:"version"`
+			PURL    string `json:"purl"`
+		} `json:"artifact"`
+	} `json:"matches"`
+	Descriptor struct {
+		Name    string `json:"name"`
+		Version string `json:"version"`
+	} `json:"descriptor"`
+}
+
+// ParseGrype reads a Grype JSON report. The IDs of related vulnerabilities,
+// such as the CVE of a GitHub advisory, are kept as aliases.
+func ParseGrype(r io.Reader) (*Report, error) {
+	var gr grypeReport
+	err := json.NewDecoder(r).Decode(&gr)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse Grype report: %v", err.Error())
+	}
+
+	findings := []Finding{}
+	for _, m := range gr.Matches {
+		var scores []float64
+		for _, cvss := range m.Vulnerability.CVSS {
+			scores = append(scores, cvss.Metrics.BaseScore)
+		}
+
+		var aliases []string
+		for _, related := range m.RelatedVulnerabilities {
+			aliases = append(aliases, related.ID)
+			for _, cvss := range related.CVSS {
+				scores = append(scores, cvss.Metrics.BaseScore)
+			}
+		}
+
+		score := maxScore(scores...)
+
+		findings = append(findings, Finding{
+			ID:            m.Vulnerability.ID,
+			Aliases:       union(nil, aliases, m.Vulnerability.ID),
+			Package:       m.Artifact.Name,
+			PURL:          m.Artifact.PURL,
+			Version:       m.Artifact.Version,
+			FixedVersions: m.Vulnerability.Fix.Versions,
+			Severity:      normalizeSeverity(m.Vulnerability.Severity, score),
+			Score:         score,
+			URL:           m.Vulnerability.DataSource,
+		})
+	}
+
+	name := "Grype"
+	if gr.Descriptor.Version != "" {
+		name = fmt.Sprintf("%v %v", name, gr.Descriptor.Version)
+	}
+
+	return newReport(scanner.Source{Name: name, URL: "https://github.com/anchore/grype"}, findings), nil
+}
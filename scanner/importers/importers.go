@@ -0,0 +1,432 @@
+// Package importers parses the JSON reports of third party vulnerability
+// scanners into a normalized set of findings that can be submitted to Ion
+// Channel as external scans.
+package importers
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/ion-channel/ionic/purl"
+	"github.com/ion-channel/ionic/scanner"
+)
+
+const (
+	// FormatTrivy is the JSON report format of Trivy
+	FormatTrivy = "trivy"
+	// FormatGrype is the JSON report format of Grype
+	FormatGrype = "grype"
+	// FormatDependencyCheck is the JSON report format of OWASP Dependency-Check
+	FormatDependencyCheck = "dependency-check"
+	// FormatNPMAudit is the JSON report format of npm audit
+	FormatNPMAudit = "npm-audit"
+)
+
+const (
+	// SeverityCritical is the normalized critical severity
+	SeverityCritical = "critical"
+	// SeverityHigh is the normalized high severity
+	SeverityHigh = "high"
+	// SeverityMedium is the normalized medium severity
+	SeverityMedium = "medium"
+	// SeverityLow is the normalized low severity
+	SeverityLow = "low"
+	// SeverityUnknown is the severity of findings that could not be rated.
+	// They are kept in the raw data but are not counted.
+	SeverityUnknown = "unknown"
+)
+
+var severityRanks = map[string]int{
+	SeverityUnknown:  0,
+	SeverityLow:      1,
+	SeverityMedium:   2,
+	SeverityHigh:     3,
+	SeverityCritical: 4,
+}
+
+// Finding is a single vulnerability affecting a package, normalized from the
+// report of any supported scanner
+type Finding struct {
+	ID              string   `json:"id"`
+	Aliases         []string `json:"aliases,omitempty"`
+	Package         string   `json:"package"`
+	PURL            string   `json:"purl,omitempty"`
+	Version         string   `json:"version,omitempty"`
+	VulnerableRange string   `json:"vulnerable_range,omitempty"`
+	FixedVersions   []string `json:"fixed_versions,omitempty"`
+	Severity        string   `json:"severity"`
+	Score           *float64 `json:"score,omitempty"`
+	Title           string   `json:"title,omitempty"`
+	URL             string   `json:"url,omitempty"`
+	Tools           []string `json:"tools"`
+}
+
+// CVE returns the first CVE identifier among the finding's ID and aliases, or
+// an empty string if it has none
+func (f *Finding) CVE() string {
+	for _, id := range append([]string{f.ID}, f.Aliases...) {
+		if strings.HasPrefix(strings.ToUpper(id), "CVE-") {
+			return id
+		}
+	}
+
+	return ""
+}
+
+// Report is the normalized content of one or more scanner reports
+type Report struct {
+	Tools    []scanner.Source `json:"tools"`
+	Findings []Finding        `json:"findings"`
+}
+
+// Parse reads a scanner report from the given reader, detecting which
+// scanner produced it from its contents
+func Parse(r io.Reader) (*Report, error) {
+	b, err := ioutil.ReadAll(r)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read scanner report: %v", err.Error())
+	}
+
+	format, err := detectFormat(b)
+	if err != nil {
+		return nil, err
+	}
+
+	return ParseFormat(format, bytes.NewReader(b))
+}
+
+// ParseFile reads a scanner report from the file at the given path,
+// detecting which scanner produced it from its contents
+func ParseFile(path string) (*Report, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open scanner report: %v", err.Error())
+	}
+	defer f.Close()
+
+	return Parse(f)
+}
+
+// ParseFormat reads a scanner report of the given format from the reader
+func ParseFormat(format string, r io.Reader) (*Report, error) {
+	switch format {
+	case FormatTrivy:
+		return ParseTrivy(r)
+	case FormatGrype:
+		return ParseGrype(r)
+	case FormatDependencyCheck:
+		return ParseDependencyCheck(r)
+	case FormatNPMAudit:
+		return ParseNPMAudit(r)
+	default:
+		return nil, fmt.Errorf("unsupported scanner report format: %q", format)
+	}
+}
+
+func detectFormat(b []byte) (string, error) {
+	// Trivy reports before schema version 2 are a bare list of results
+	if bytes.HasPrefix(bytes.TrimSpace(b), []byte("[")) {
+		return FormatTrivy, nil
+	}
+
+	var keys map[string]json.RawMessage
+	err := json.Unmarshal(b, &keys)
+	if err != nil {
+		return "", fmt.Errorf("failed to parse scanner report: %v", err.Error())
+	}
+
+	has := func(key string) bool {
+		_, ok := keys[key]
+		return ok
+	}
+
+	switch {
+	case has("SchemaVersion"), has("Results"):
+		return FormatTrivy, nil
+	case has("matches"):
+		return FormatGrype, nil
+	case has("scanInfo"), has("dependencies"):
+		return FormatDependencyCheck, nil
+	case has("auditReportVersion"), has("advisories"):
+		return FormatNPMAudit, nil
+	default:
+		return "", fmt.Errorf("unrecognized scanner report format")
+	}
+}
+
+// Merge combines the reports of several scanners into one. Findings reported
+// by more than one scanner for the same vulnerability and package version are
+// combined, keeping the highest severity and score and every fixed version.
+// Packages are compared by their package URLs when the scanners report them,
+// and findings without a version are combined with a finding for the same
+// vulnerability and package in any version.
+func Merge(reports ...*Report) *Report {
+	merged := &Report{
+		Tools:    []scanner.Source{},
+		Findings: []Finding{},
+	}
+
+	// findings are indexed by their vulnerability and package, and by their
+	// vulnerability, package, and version
+	index := make(map[string]int)
+	for _, r := range reports {
+		if r == nil {
+			continue
+		}
+
+		merged.Tools = append(merged.Tools, r.Tools...)
+
+		for _, f := range r.Findings {
+			key, version := dedupeKey(f)
+			i, ok := index[key+"|"+version]
+			if !ok && version == "" {
+				i, ok = index[key]
+			}
+
+			if !ok && version != "" {
+				i, ok = index[key+"|"]
+				delete(index, key+"|")
+			}
+
+			if !ok {
+				i = len(merged.Findings)
+				merged.Findings = append(merged.Findings, f)
+			} else {
+				merged.Findings[i] = mergeFindings(merged.Findings[i], f)
+			}
+
+			index[key+"|"+version] = i
+			if _, ok := index[key]; !ok {
+				index[key] = i
+			}
+		}
+	}
+
+	return merged
+}
+
+// ToExternalScan converts the report into an external scan with the findings
+// counted by severity, the normalized findings kept in its raw data, and the
+// scanners recorded as its source
+func (r *Report) ToExternalScan() (*scanner.ExternalScan, error) {
+	counts := &scanner.ExternalVulnerability{}
+	for _, f := range r.Findings {
+		switch f.Severity {
+		case SeverityCritical:
+			counts.Critcal++
+		case SeverityHigh:
+			counts.High++
+		case SeverityMedium:
+			counts.Medium++
+		case SeverityLow:
+			counts.Low++
+		}
+	}
+
+	b, err := json.Marshal(r)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal findings: %v", err.Error())
+	}
+
+	raw := json.RawMessage(b)
+
+	return &scanner.ExternalScan{
+		Vulnerability: counts,
+		Source:        r.source(),
+		Notes:         fmt.Sprintf("%v vulnerabilities imported", len(r.Findings)),
+		Raw:           &raw,
+	}, nil
+}
+
+// source returns the scanner that produced the report, or a combined source
+// naming every scanner for merged reports
+func (r *Report) source() scanner.Source {
+	if len(r.Tools) == 1 {
+		return r.Tools[0]
+	}
+
+	names := make([]string, 0, len(r.Tools))
+	for _, t := range r.Tools {
+		names = append(names, t.Name)
+	}
+
+	return scanner.Source{Name: strings.Join(names, ", ")}
+}
+
+func newReport(tool scanner.Source, findings []Finding) *Report {
+	for i := range findings {
+		findings[i].Tools = []string{tool.Name}
+	}
+
+	// a scanner may report the same vulnerability once per target
+	return Merge(&Report{
+		Tools:    []scanner.Source{tool},
+		Findings: findings,
+	})
+}
+
+// dedupeKey returns the key of the vulnerability and package of a finding,
+// and the version of the package, which may be empty
+func dedupeKey(f Finding) (string, string) {
+	id := f.CVE()
+	if id == "" {
+		id = f.ID
+	}
+
+	pkg, version := strings.ToLower(f.Package), f.Version
+	if p, err := purl.Parse(f.PURL); err == nil {
+		pkg = p.WithoutVersion().String()
+		if version == "" {
+			version = p.Version
+		}
+	}
+
+	return strings.ToUpper(id) + "|" + pkg, version
+}
+
+// packageURL returns the package URL of a package of the given ecosystem, or
+// an empty string if it has none
+func packageURL(ecosystem, name, version string) string {
+	if ecosystem == "" || name == "" {
+		return ""
+	}
+
+	p, err := purl.FromOrgName(ecosystem, "", name, version)
+	if err != nil {
+		return ""
+	}
+
+	return p.String()
+}
+
+func mergeFindings(a, b Finding) Finding {
+	if a.Version == "" && b.Version != "" {
+		a.Version = b.Version
+		a.PURL = b.PURL
+	}
+
+	if a.PURL == "" {
+		a.PURL = b.PURL
+	}
+
+	if severityRanks[b.Severity] > severityRanks[a.Severity] {
+		a.Severity = b.Severity
+	}
+
+	if b.Score != nil && (a.Score == nil || *b.Score > *a.Score) {
+		a.Score = b.Score
+	}
+
+	if a.Title == "" {
+		a.Title = b.Title
+	}
+
+	if a.URL == "" {
+		a.URL = b.URL
+	}
+
+	if a.VulnerableRange == "" {
+		a.VulnerableRange = b.VulnerableRange
+	}
+
+	a.Aliases = union(a.Aliases, append([]string{b.ID}, b.Aliases...), a.ID)
+	a.FixedVersions = union(a.FixedVersions, b.FixedVersions, "")
+	a.Tools = union(a.Tools, b.Tools, "")
+
+	return a
+}
+
+// union returns the values of a followed by the values of b not already in a,
+// leaving out the excluded value
+func union(a, b []string, exclude string) []string {
+	seen := map[string]bool{exclude: true}
+	out := []string{}
+	for _, v := range append(append([]string{}, a...), b...) {
+		if v == "" || seen[v] {
+			continue
+		}
+
+		seen[v] = true
+		out = append(out, v)
+	}
+
+	if len(out) == 0 {
+		return nil
+	}
+
+	return out
+}
+
+// normalizeSeverity maps the severity names used by the supported scanners
+// onto the normalized severities, falling back to the score when the name is
+// not recognized
+func normalizeSeverity(severity string, score *float64) string {
+	switch strings.ToLower(strings.TrimSpace(severity)) {
+	case "critical":
+		return SeverityCritical
+	case "high":
+		return SeverityHigh
+	case "medium", "moderate":
+		return SeverityMedium
+	case "low", "negligible", "info":
+		return SeverityLow
+	}
+
+	if score == nil {
+		return SeverityUnknown
+	}
+
+	switch {
+	case *score >= 9.0:
+		return SeverityCritical
+	case *score >= 7.0:
+		return SeverityHigh
+	case *score >= 4.0:
+		return SeverityMedium
+	case *score > 0:
+		return SeverityLow
+	default:
+		return SeverityUnknown
+	}
+}
+
+// splitVersions splits a list of versions separated by commas
+func splitVersions(s string) []string {
+	var versions []string
+	for _, v := range strings.Split(s, ",") {
+		v = strings.TrimSpace(v)
+		if v != "" {
+			versions = append(versions, v)
+		}
+	}
+
+	return versions
+}
+
+func sortedKeys(m map[string]json.RawMessage) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+
+	sort.Strings(keys)
+
+	return keys
+}
+
+func maxScore(scores ...float64) *float64 {
+	var max *float64
+	for i := range scores {
+		if scores[i] > 0 && (max == nil || scores[i] > *max) {
+			max = &scores[i]
+		}
+	}
+
+	return max
+}
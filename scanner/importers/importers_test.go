@@ -0,0 +1,243 @@
+package importers
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/franela/goblin"
+	. "github.com/onsi/gomega"
+)
+
+func TestImporters(t *testing.T) {
+	g := goblin.Goblin(t)
+	RegisterFailHandler(func(m string, _ ...int) { g.Fail(m) })
+
+	g.Describe("Detecting Formats", func() {
+		g.It("should detect each supported scanner", func() {
+			for format, report := range map[string]string{
+				FormatTrivy:           sampleTrivy,
+				FormatGrype:           sampleGrype,
+				FormatDependencyCheck: sampleDependencyCheck,
+				FormatNPMAudit:        sampleNPMAudit,
+			} {
+				detected, err := detectFormat([]byte(report))
+				Expect(err).To(BeNil())
+				Expect(detected).To(Equal(format))
+			}
+
+			detected, err := detectFormat([]byte(`[{"Target":"alpine"}]`))
+			Expect(err).To(BeNil())
+			Expect(detected).To(Equal(FormatTrivy))
+		})
+
+		g.It("should reject unknown reports", func() {
+			_, err := Parse(strings.NewReader(`{"foo":"bar"}`))
+			Expect(err).NotTo(BeNil())
+		})
+	})
+
+	g.Describe("Trivy", func() {
+		g.It("should parse findings and dedupe them across targets", func() {
+			r, err := Parse(strings.NewReader(sampleTrivy))
+			Expect(err).To(BeNil())
+			Expect(r.Tools[0].Name).To(Equal("Trivy"))
+			Expect(r.Findings).To(HaveLen(2))
+			Expect(r.Findings[0].ID).To(Equal("CVE-2021-44228"))
+			Expect(r.Findings[0].Package).To(Equal("org.apache.logging.log4j:log4j-core"))
+			Expect(r.Findings[0].PURL).To(Equal("pkg:maven/org.apache.logging.log4j/log4j-core@2.14.1"))
+			Expect(r.Findings[0].FixedVersions).To(Equal([]string{"2.15.0", "2.12.2"}))
+			Expect(r.Findings[0].Severity).To(Equal(SeverityCritical))
+			Expect(*r.Findings[0].Score).To(Equal(10.0))
+			Expect(r.Findings[1].Severity).To(Equal(SeverityLow))
+		})
+	})
+
+	g.Describe("Grype", func() {
+		g.It("should parse findings with related vulnerabilities as aliases", func() {
+			r, err := Parse(strings.NewReader(sampleGrype))
+			Expect(err).To(BeNil())
+			Expect(r.Tools[0].Name).To(Equal("Grype 0.65.0"))
+			Expect(r.Findings).To(HaveLen(1))
+			Expect(r.Findings[0].ID).To(Equal("GHSA-jfh8-c2jp-5v3q"))
+			Expect(r.Findings[0].CVE()).To(Equal("CVE-2021-44228"))
+			Expect(r.Findings[0].Severity).To(Equal(SeverityCritical))
+			Expect(r.Findings[0].FixedVersions).To(Equal([]string{"2.15.0"}))
+		})
+	})
+
+	g.Describe("Dependency-Check", func() {
+		g.It("should parse findings and read packages from their package URLs", func() {
+			r, err := Parse(strings.NewReader(sampleDependencyCheck))
+			Expect(err).To(BeNil())
+			Expect(r.Tools[0].Name).To(Equal("OWASP Dependency-Check 8.4.0"))
+			Expect(r.Findings).To(HaveLen(2))
+			Expect(r.Findings[0].Package).To(Equal("log4j-core"))
+			Expect(r.Findings[0].Version).To(Equal("2.14.1"))
+			Expect(r.Findings[0].Title).To(Equal("Apache Log4j2 JNDI features do not protect against attacker controlled LDAP endpoints."))
+			Expect(r.Findings[1].Package).To(Equal("unknown.jar"))
+			Expect(r.Findings[1].Severity).To(Equal(SeverityMedium))
+		})
+	})
+
+	g.Describe("npm audit", func() {
+		g.It("should parse the npm 7 format", func() {
+			r, err := Parse(strings.NewReader(sampleNPMAudit))
+			Expect(err).To(BeNil())
+			Expect(r.Findings).To(HaveLen(1))
+			Expect(r.Findings[0].ID).To(Equal("GHSA-p6mc-m468-83gw"))
+			Expect(r.Findings[0].Package).To(Equal("lodash"))
+			Expect(r.Findings[0].VulnerableRange).To(Equal("<4.17.19"))
+			Expect(r.Findings[0].FixedVersions).To(Equal([]string{"4.17.21"}))
+			Expect(r.Findings[0].Severity).To(Equal(SeverityHigh))
+		})
+
+		g.It("should parse the npm 6 format", func() {
+			r, err := Parse(strings.NewReader(sampleNPMAuditV1))
+			Expect(err).To(BeNil())
+			Expect(r.Findings).To(HaveLen(1))
+			Expect(r.Findings[0].ID).To(Equal("GHSA-p6mc-m468-83gw"))
+			Expect(r.Findings[0].CVE()).To(Equal("CVE-2020-8203"))
+			Expect(r.Findings[0].Version).To(Equal("4.17.15"))
+			Expect(r.Findings[0].FixedVersions).To(Equal([]string{">=4.17.19"}))
+			Expect(r.Findings[0].Severity).To(Equal(SeverityMedium))
+		})
+	})
+
+	g.Describe("Merging", func() {
+		g.It("should dedupe findings reported by several scanners", func() {
+			trivy, _ := ParseTrivy(strings.NewReader(sampleTrivy))
+			grype, _ := ParseGrype(strings.NewReader(sampleGrype))
+
+			r := Merge(trivy, grype)
+			Expect(r.Tools).To(HaveLen(2))
+			Expect(r.Findings).To(HaveLen(2))
+			Expect(r.Findings[0].ID).To(Equal("CVE-2021-44228"))
+			Expect(r.Findings[0].Aliases).To(Equal([]string{"GHSA-jfh8-c2jp-5v3q"}))
+			Expect(r.Findings[0].Tools).To(Equal([]string{"Trivy", "Grype 0.65.0"}))
+			Expect(r.Findings[0].FixedVersions).To(Equal([]string{"2.15.0", "2.12.2"}))
+		})
+
+		g.It("should dedupe packages named differently by each scanner", func() {
+			trivy, _ := ParseTrivy(strings.NewReader(sampleTrivy))
+			check, _ := ParseDependencyCheck(strings.NewReader(sampleDependencyCheck))
+
+			r := Merge(trivy, check)
+			Expect(r.Findings).To(HaveLen(3))
+			Expect(r.Findings[0].ID).To(Equal("CVE-2021-44228"))
+			Expect(r.Findings[0].Tools).To(Equal([]string{"Trivy", "OWASP Dependency-Check 8.4.0"}))
+		})
+
+		g.It("should dedupe findings without a version with one that has it", func() {
+			npm, _ := ParseNPMAudit(strings.NewReader(sampleNPMAudit))
+			trivy, _ := ParseTrivy(strings.NewReader(sampleTrivyNPM))
+
+			r := Merge(npm, trivy)
+			Expect(r.Findings).To(HaveLen(1))
+			Expect(r.Findings[0].ID).To(Equal("GHSA-p6mc-m468-83gw"))
+			Expect(r.Findings[0].Version).To(Equal("4.17.15"))
+			Expect(r.Findings[0].PURL).To(Equal("pkg:npm/lodash@4.17.15"))
+			Expect(r.Findings[0].Tools).To(Equal([]string{"npm audit", "Trivy"}))
+
+			r = Merge(trivy, npm, trivy)
+			Expect(r.Findings).To(HaveLen(1))
+		})
+
+		g.It("should not dedupe different versions of a package", func() {
+			v1, _ := ParseNPMAudit(strings.NewReader(sampleNPMAuditV1))
+			trivy, _ := ParseTrivy(strings.NewReader(strings.Replace(sampleTrivyNPM, "4.17.15", "4.17.16", -1)))
+
+			Expect(Merge(v1, trivy).Findings).To(HaveLen(2))
+		})
+	})
+
+	g.Describe("Converting to an External Scan", func() {
+		g.It("should count findings and keep them in the raw data", func() {
+			trivy, _ := ParseTrivy(strings.NewReader(sampleTrivy))
+			npm, _ := ParseNPMAudit(strings.NewReader(sampleNPMAudit))
+
+			scan, err := Merge(trivy, npm).ToExternalScan()
+			Expect(err).To(BeNil())
+			Expect(scan.Source.Name).To(Equal("Trivy, npm audit"))
+			Expect(scan.Vulnerability.Critcal).To(Equal(1))
+			Expect(scan.Vulnerability.High).To(Equal(1))
+			Expect(scan.Vulnerability.Low).To(Equal(1))
+
+			var raw Report
+			Expect(json.Unmarshal(*scan.Raw, &raw)).To(Succeed())
+			Expect(raw.Findings).To(HaveLen(3))
+		})
+	})
+}
+
+const sampleTrivy = `{
+  "SchemaVersion": 2,
+  "ArtifactName": "app:latest",
+  "Results": [
+    {"Target": "app.jar", "Type": "jar", "Vulnerabilities": [
+      {"VulnerabilityID": "CVE-2021-44228", "PkgName": "org.apache.logging.log4j:log4j-core", "InstalledVersion": "2.14.1", "FixedVersion": "2.15.0, 2.12.2",
+       "Severity": "CRITICAL", "Title": "Log4Shell", "PrimaryURL": "https://avd.aquasec.com/nvd/cve-2021-44228",
+       "CVSS": {"nvd": {"V2Score": 9.3, "V3Score": 10}}}
+    ]},
+    {"Target": "app:latest (alpine 3.12.0)", "Type": "alpine", "Vulnerabilities": [
+      {"VulnerabilityID": "CVE-2020-0001", "PkgName": "busybox", "InstalledVersion": "1.31.0", "Severity": "LOW"}
+    ]},
+    {"Target": "lib/app.jar", "Type": "jar", "Vulnerabilities": [
+      {"VulnerabilityID": "CVE-2021-44228", "PkgName": "org.apache.logging.log4j:log4j-core", "InstalledVersion": "2.14.1", "FixedVersion": "2.15.0", "Severity": "CRITICAL"}
+    ]}
+  ]
+}`
+
+const sampleTrivyNPM = `{
+  "SchemaVersion": 2,
+  "Results": [
+    {"Target": "package-lock.json", "Type": "npm", "Vulnerabilities": [
+      {"VulnerabilityID": "GHSA-p6mc-m468-83gw", "PkgName": "lodash", "InstalledVersion": "4.17.15", "FixedVersion": "4.17.19",
+       "PkgIdentifier": {"PURL": "pkg:npm/lodash@4.17.15"}, "Severity": "HIGH"}
+    ]}
+  ]
+}`
+
+const sampleGrype = `{
+  "matches": [{
+    "vulnerability": {"id": "GHSA-jfh8-c2jp-5v3q", "dataSource": "https://github.com/advisories/GHSA-jfh8-c2jp-5v3q",
+      "severity": "Critical", "cvss": [{"metrics": {"baseScore": 10}}], "fix": {"versions": ["2.15.0"], "state": "fixed"}},
+    "relatedVulnerabilities": [{"id": "CVE-2021-44228", "cvss": [{"metrics": {"baseScore": 10}}]}],
+    "artifact": {"name": "log4j-core", "version": "2.14.1", "type": "java-archive",
+      "purl": "pkg:maven/org.apache.logging.log4j/log4j-core@2.14.1"}
+  }],
+  "descriptor": {"name": "grype", "version": "0.65.0"}
+}`
+
+const sampleDependencyCheck = `{
+  "reportSchema": "1.1",
+  "scanInfo": {"engineVersion": "8.4.0"},
+  "dependencies": [
+    {"fileName": "log4j-core-2.14.1.jar", "packages": [{"id": "pkg:maven/org.apache.logging.log4j/log4j-core@2.14.1"}],
+     "vulnerabilities": [{"name": "CVE-2021-44228", "severity": "CRITICAL", "cvssv3": {"baseScore": 10.0},
+       "description": "Apache Log4j2 JNDI features do not protect against attacker controlled LDAP endpoints.\nMore detail."}]},
+    {"fileName": "unknown.jar",
+     "vulnerabilities": [{"name": "CVE-2019-0001", "cvssv2": {"score": 5.0}}]},
+    {"fileName": "clean.jar"}
+  ]
+}`
+
+const sampleNPMAudit = `{
+  "auditReportVersion": 2,
+  "vulnerabilities": {
+    "lodash": {"name": "lodash", "severity": "high", "via": [
+      {"source": 1523, "name": "lodash", "title": "Prototype Pollution in lodash", "url": "https://github.com/advisories/GHSA-p6mc-m468-83gw",
+       "severity": "high", "cvss": {"score": 7.4}, "range": "<4.17.19"}
+    ], "range": "<4.17.19", "fixAvailable": {"name": "lodash", "version": "4.17.21", "isSemVerMajor": false}},
+    "some-lib": {"name": "some-lib", "severity": "high", "via": ["lodash"], "fixAvailable": true}
+  }
+}`
+
+const sampleNPMAuditV1 = `{
+  "advisories": {
+    "1523": {"id": 1523, "module_name": "lodash", "severity": "moderate", "title": "Prototype Pollution",
+      "url": "https://npmjs.com/advisories/1523", "github_advisory_id": "GHSA-p6mc-m468-83gw", "cves": ["CVE-2020-8203"],
+      "vulnerable_versions": "<4.17.19", "patched_versions": ">=4.17.19", "findings": [{"version": "4.17.15", "paths": ["lodash"]}]}
+  },
+  "metadata": {}
+}`
@@ -0,0 +1,190 @@
+package importers
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/ion-channel/ionic/purl"
+	"github.com/ion-channel/ionic/scanner"
+)
+
+type npmAuditReport struct {
+	AuditReportVersion int                        `json:"auditReportVersion"`
+	Vulnerabilities    map[string]json.RawMessage `json:"vulnerabilities"`
+	Advisories         map[string]json.RawMessage `json:"advisories"`
+}
+
+// npmAuditVulnerability is an entry of the vulnerabilities of npm 7 and later
+type npmAuditVulnerability struct {
+	Name         string            `json:"name"`
+	Severity     string            `json:"severity"`
+	Via          []json.RawMessage `json:"via"`
+	FixAvailable json.RawMessage   `json:"fixAvailable"`
+}
+
+// npmAuditVia is an advisory a package is vulnerable through. Packages that
+// are only vulnerable through their dependencies list the names of those
+// dependencies instead.
+type npmAuditVia struct {
+	Source   int    `json:"source"`
+	Name     string `json:"name"`
+	Title    string `json:"title"`
+	URL      string `json:"url"`
+	Severity string `json:"severity"`
+	Range    string `json:"range"`
+	CVSS     struct {
+		Score float64 `json:"score"`
+	} `json:"cvss"`
+}
+
+// npmAuditAdvisory is an entry of the advisories of npm 6 and earlier
+type npmAuditAdvisory struct {
+	ID                 int      `json:"id"`
+	ModuleName         string   `json:"module_name"`
+	Severity           string   `json:"severity"`
+	Title              string   `json:"title"`
+	URL                string   `json:"url"`
+	CVEs               []string `json:"cves"`
+	GithubAdvisoryID   string   `json:"github_advisory_id"`
+	VulnerableVersions string   `json:"vulnerable_versions"`
+	PatchedVersions    string   `json:"patched_versions"`
+	CVSS               struct {
+		Score float64 `json:"score"`
+	} `json:"cvss"`
+	Findings []struct {
+		Version string `json:"version"`
+	} `json:"findings"`
+}
+
+// ParseNPMAudit reads the JSON output of npm audit, written by either npm 7
+// and later or npm 6 and earlier. The newer format does not include installed
+// versions, so its findings carry the vulnerable range instead.
+func ParseNPMAudit(r io.Reader) (*Report, error) {
+	var nr npmAuditReport
+	err := json.NewDecoder(r).Decode(&nr)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse npm audit report: %v", err.Error())
+	}
+
+	var findings []Finding
+	if nr.Advisories != nil {
+		findings, err = npmAuditAdvisoryFindings(nr.Advisories)
+	} else {
+		findings, err = npmAuditVulnerabilityFindings(nr.Vulnerabilities)
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse npm audit report: %v", err.Error())
+	}
+
+	return newReport(scanner.Source{Name: "npm audit", URL: "https://docs.npmjs.com/cli/commands/npm-audit"}, findings), nil
+}
+
+func npmAuditVulnerabilityFindings(vulnerabilities map[string]json.RawMessage) ([]Finding, error) {
+	findings := []Finding{}
+	for _, key := range sortedKeys(vulnerabilities) {
+		var v npmAuditVulnerability
+		err := json.Unmarshal(vulnerabilities[key], &v)
+		if err != nil {
+			return nil, err
+		}
+
+		var fix struct {
+			Name    string `json:"name"`
+			Version string `json:"version"`
+		}
+		// fixAvailable is either a boolean or the package update that fixes it
+		_ = json.Unmarshal(v.FixAvailable, &fix)
+
+		for _, raw := range v.Via {
+			var via npmAuditVia
+			if json.Unmarshal(raw, &via) != nil {
+				continue
+			}
+
+			score := maxScore(via.CVSS.Score)
+
+			f := Finding{
+				ID:              npmAdvisoryID(via.URL, via.Source),
+				Package:         via.Name,
+				PURL:            packageURL(purl.TypeNPM, via.Name, ""),
+				VulnerableRange: via.Range,
+				Severity:        normalizeSeverity(via.Severity, score),
+				Score:           score,
+				Title:           via.Title,
+				URL:             via.URL,
+			}
+
+			if fix.Name == via.Name && fix.Version != "" {
+				f.FixedVersions = []string{fix.Version}
+			}
+
+			findings = append(findings, f)
+		}
+	}
+
+	return findings, nil
+}
+
+func npmAuditAdvisoryFindings(advisories map[string]json.RawMessage) ([]Finding, error) {
+	findings := []Finding{}
+	for _, key := range sortedKeys(advisories) {
+		var a npmAuditAdvisory
+		err := json.Unmarshal(advisories[key], &a)
+		if err != nil {
+			return nil, err
+		}
+
+		id := npmAdvisoryID(a.URL, a.ID)
+		if a.GithubAdvisoryID != "" {
+			id = a.GithubAdvisoryID
+		}
+
+		var fixed []string
+		// npm marks advisories without a fix as patched in <0.0.0
+		if a.PatchedVersions != "" && a.PatchedVersions != "<0.0.0" {
+			fixed = []string{a.PatchedVersions}
+		}
+
+		score := maxScore(a.CVSS.Score)
+
+		versions := []string{""}
+		if len(a.Findings) > 0 {
+			versions = versions[:0]
+			for _, f := range a.Findings {
+				versions = append(versions, f.Version)
+			}
+		}
+
+		for _, version := range versions {
+			findings = append(findings, Finding{
+				ID:              id,
+				Aliases:         union(nil, a.CVEs, id),
+				Package:         a.ModuleName,
+				PURL:            packageURL(purl.TypeNPM, a.ModuleName, version),
+				Version:         version,
+				VulnerableRange: a.VulnerableVersions,
+				FixedVersions:   fixed,
+				Severity:        normalizeSeverity(a.Severity, score),
+				Score:           score,
+				Title:           a.Title,
+				URL:             a.URL,
+			})
+		}
+	}
+
+	return findings, nil
+}
+
+// npmAdvisoryID returns the GitHub advisory ID at the end of an advisory URL,
+// falling back to the npm advisory number
+func npmAdvisoryID(url string, source int) string {
+	last := url[strings.LastIndex(url, "/")+1:]
+	if strings.HasPrefix(last, "GHSA-") {
+		return last
+	}
+
+	return fmt.Sprintf("NPM-%v", source)
+}
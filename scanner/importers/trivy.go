@@ -0,0 +1,90 @@
+package importers
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+
+	"github.com/ion-channel/ionic/scanner"
+)
+
+type trivyReport struct {
+	SchemaVersion int           `json:"SchemaVersion"`
+	Results       []trivyResult `json:"Results"`
+}
+
+type trivyResult struct {
+	Target          string `json:"Target"`
+	Type            string `json:"Type"`
+	Vulnerabilities []struct {
+		VulnerabilityID string `json:"VulnerabilityID"`
+		PkgName         string `json:"PkgName"`
+		PkgIdentifier   struct {
+			PURL string `json:"PURL"`
+		} `json:"PkgIdentifier"`
+		InstalledVersion string `json:"InstalledVersion"`
+		FixedVersion     string `json:"FixedVersion"`
+		Severity         string `json:"Severity"`
+		Title            string `json:"Title"`
+		PrimaryURL       string `json:"PrimaryURL"`
+		CVSS             map[string]struct {
+			V2Score float64 `json:"V2Score"`
+			V3Score float64 `json:"V3Score"`
+		} `json:"CVSS"`
+	} `json:"Vulnerabilities"`
+}
+
+// ParseTrivy reads a Trivy JSON report. Both the current report schema and
+// the list of results written by older versions are supported.
+func ParseTrivy(r io.Reader) (*Report, error) {
+	b, err := ioutil.ReadAll(r)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read Trivy report: %v", err.Error())
+	}
+
+	var tr trivyReport
+	if bytes.HasPrefix(bytes.TrimSpace(b), []byte("[")) {
+		err = json.Unmarshal(b, &tr.Results)
+	} else {
+		err = json.Unmarshal(b, &tr)
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse Trivy report: %v", err.Error())
+	}
+
+	findings := []Finding{}
+	for _, result := range tr.Results {
+		for _, v := range result.Vulnerabilities {
+			var scores []float64
+			for _, cvss := range v.CVSS {
+				scores = append(scores, cvss.V3Score, cvss.V2Score)
+			}
+
+			score := maxScore(scores...)
+
+			// Trivy reports package URLs since 0.47, and the ecosystem of
+			// each result before that
+			id := v.PkgIdentifier.PURL
+			if id == "" {
+				id = packageURL(result.Type, v.PkgName, v.InstalledVersion)
+			}
+
+			findings = append(findings, Finding{
+				ID:            v.VulnerabilityID,
+				Package:       v.PkgName,
+				PURL:          id,
+				Version:       v.InstalledVersion,
+				FixedVersions: splitVersions(v.FixedVersion),
+				Severity:      normalizeSeverity(v.Severity, score),
+				Score:         score,
+				Title:         v.Title,
+				URL:           v.PrimaryURL,
+			})
+		}
+	}
+
+	return newReport(scanner.Source{Name: "Trivy", URL: "https://github.com/aquasecurity/trivy"}, findings), nil
+}
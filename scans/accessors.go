@@ -0,0 +1,145 @@
+package scans
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrResultsNotPresent is returned by the typed result accessors when the
+// requested type of results is not present. It is wrapped with the name of
+// the missing results, so it should be checked for with errors.Is.
+var ErrResultsNotPresent = errors.New("scan results not present")
+
+// NotPresentError returns an error wrapping ErrResultsNotPresent for the named
+// type of results
+func NotPresentError(resultType string) error {
+	return fmt.Errorf("%w: %v", ErrResultsNotPresent, resultType)
+}
+
+// Translated returns the translated results of the scan. Untranslated results
+// are translated without modifying the scan. It returns nil if the scan has
+// no results.
+func (s *Scan) Translated() *TranslatedResults {
+	if s == nil {
+		return nil
+	}
+
+	if s.TranslatedResults != nil {
+		return s.TranslatedResults
+	}
+
+	if s.UntranslatedResults != nil {
+		return s.UntranslatedResults.Translate()
+	}
+
+	return nil
+}
+
+func (s *Scan) data() interface{} {
+	tr := s.Translated()
+	if tr == nil {
+		return nil
+	}
+
+	return tr.Data
+}
+
+// Vulnerabilities returns the results of the scan if it is a vulnerability
+// scan, or an error if it is not
+func (s *Scan) Vulnerabilities() (*VulnerabilityResults, error) {
+	if r, ok := s.data().(VulnerabilityResults); ok {
+		return &r, nil
+	}
+
+	return nil, NotPresentError("vulnerability")
+}
+
+// ExternalVulnerabilities returns the results of the scan if it is an
+// external vulnerability scan, or an error if it is not
+func (s *Scan) ExternalVulnerabilities() (*ExternalVulnerabilitiesResults, error) {
+	if r, ok := s.data().(ExternalVulnerabilitiesResults); ok {
+		return &r, nil
+	}
+
+	return nil, NotPresentError("external vulnerability")
+}
+
+// Dependencies returns the results of the scan if it is a dependency scan, or
+// an error if it is not
+func (s *Scan) Dependencies() (*DependencyResults, error) {
+	if r, ok := s.data().(DependencyResults); ok {
+		return &r, nil
+	}
+
+	return nil, NotPresentError("dependency")
+}
+
+// Licenses returns the results of the scan if it is a license scan, or an
+// error if it is not
+func (s *Scan) Licenses() (*LicenseResults, error) {
+	if r, ok := s.data().(LicenseResults); ok {
+		return &r, nil
+	}
+
+	return nil, NotPresentError("license")
+}
+
+// Secrets returns the results of the scan if it is a secrets scan, or an
+// error if it is not
+func (s *Scan) Secrets() (*SecretResults, error) {
+	if r, ok := s.data().(SecretResults); ok {
+		return &r, nil
+	}
+
+	return nil, NotPresentError("secrets")
+}
+
+// Viruses returns the results of the scan if it is a virus scan, or an error
+// if it is not
+func (s *Scan) Viruses() (*VirusResults, error) {
+	if r, ok := s.data().(VirusResults); ok {
+		return &r, nil
+	}
+
+	return nil, NotPresentError("virus")
+}
+
+// Coverage returns the results of the scan if it is a coverage scan, or an
+// error if it is not
+func (s *Scan) Coverage() (*CoverageResults, error) {
+	if r, ok := s.data().(CoverageResults); ok {
+		return &r, nil
+	}
+
+	return nil, NotPresentError("coverage")
+}
+
+// Community returns the results of the scan if it is a community scan, or an
+// error if it is not
+func (s *Scan) Community() (*CommunityResults, error) {
+	if r, ok := s.data().(CommunityResults); ok {
+		return &r, nil
+	}
+
+	return nil, NotPresentError("community")
+}
+
+// Ecosystems returns the results of the scan if it is an ecosystems scan, or
+// an error if it is not
+func (s *Scan) Ecosystems() (*EcosystemResults, error) {
+	if r, ok := s.data().(EcosystemResults); ok {
+		return &r, nil
+	}
+
+	return nil, NotPresentError("ecosystems")
+}
+
+// Risk returns the results of the scan if it is a risk scan, or an error if
+// it is not
+func (s *Scan) Risk() (*RiskResults, error) {
+	if r, ok := s.data().(RiskResults); ok {
+		return &r, nil
+	}
+
+	return nil, NotPresentError("risk")
+}
@@ -0,0 +1,74 @@
+package scans
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/franela/goblin"
+	. "github.com/onsi/gomega"
+)
+
+func TestAccessors(t *testing.T) {
+	g := goblin.Goblin(t)
+	RegisterFailHandler(func(m string, _ ...int) { g.Fail(m) })
+
+	g.Describe("Typed Accessors", func() {
+		g.It("should return translated results of the matching type", func() {
+			var s Scan
+			Expect(json.Unmarshal([]byte(`{"id":"s1","results":{"type":"coverage","data":{"value":42.5}}}`), &s)).To(Succeed())
+
+			c, err := s.Coverage()
+			Expect(err).To(BeNil())
+			Expect(c.Value).To(Equal(42.5))
+
+			_, err = s.Vulnerabilities()
+			Expect(errors.Is(err, ErrResultsNotPresent)).To(BeTrue())
+			Expect(err.Error()).To(Equal("scan results not present: vulnerability"))
+		})
+
+		g.It("should return untranslated results without translating the scan", func() {
+			s := Scan{
+				scan: &scan{ID: "s1"},
+				UntranslatedResults: &UntranslatedResults{
+					Virus: &VirusResults{InfectedFiles: 2},
+				},
+			}
+
+			v, err := s.Viruses()
+			Expect(err).To(BeNil())
+			Expect(v.InfectedFiles).To(Equal(2))
+			Expect(s.TranslatedResults).To(BeNil())
+			Expect(s.Translated().Type).To(Equal("virus"))
+		})
+
+		g.It("should return each type of results", func() {
+			for _, tc := range []struct {
+				results string
+				get     func(s *Scan) error
+			}{
+				{`{"type":"dependency","data":{"dependencies":[],"meta":{}}}`, func(s *Scan) error { _, err := s.Dependencies(); return err }},
+				{`{"type":"license","data":{"license":{"name":"MIT","type":[]}}}`, func(s *Scan) error { _, err := s.Licenses(); return err }},
+				{`{"type":"secrets","data":[]}`, func(s *Scan) error { _, err := s.Secrets(); return err }},
+				{`{"type":"community","data":{"name":"ionic"}}`, func(s *Scan) error { _, err := s.Community(); return err }},
+				{`{"type":"ecosystems","data":{"Go":100}}`, func(s *Scan) error { _, err := s.Ecosystems(); return err }},
+				{`{"type":"risk","data":{}}`, func(s *Scan) error { _, err := s.Risk(); return err }},
+				{`{"type":"external_vulnerability","data":{"critical":1}}`, func(s *Scan) error { _, err := s.ExternalVulnerabilities(); return err }},
+			} {
+				var s Scan
+				Expect(json.Unmarshal([]byte(`{"results":`+tc.results+`}`), &s)).To(Succeed())
+				Expect(tc.get(&s)).To(BeNil())
+			}
+		})
+
+		g.It("should return a not present error for scans without results", func() {
+			s := &Scan{}
+			_, err := s.Licenses()
+			Expect(errors.Is(err, ErrResultsNotPresent)).To(BeTrue())
+
+			var nilScan *Scan
+			_, err = nilScan.Coverage()
+			Expect(errors.Is(err, ErrResultsNotPresent)).To(BeTrue())
+		})
+	})
+}
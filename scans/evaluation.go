@@ -2,8 +2,8 @@ package scans
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
-	"strings"
 	"time"
 )
 
@@ -33,6 +33,9 @@ type evaluation struct {
 	Risk        string          `json:"risk"`
 	Type        string          `json:"type"`
 	Passed      bool            `json:"passed"`
+	// Error explains why the rule could not be evaluated, such as its scan
+	// producing no results. It is empty for rules that passed or failed.
+	Error string `json:"error,omitempty"`
 }
 
 // NewEval returns an Evaluation that wont throw nil pointer exceptions
@@ -104,7 +107,7 @@ func (e *Evaluation) UnmarshalJSON(b []byte) error {
 	var tr TranslatedResults
 	err = json.Unmarshal(e.Results, &tr)
 	if err != nil {
-		if strings.Contains(err.Error(), "unsupported results type found") {
+		if errors.Is(err, ErrUnsupportedResultsType) {
 			var un UntranslatedResults
 			err := json.Unmarshal(e.Results, &un)
 			if err != nil {
@@ -0,0 +1,152 @@
+package scans
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"sync"
+)
+
+// ErrUnsupportedResultsType is returned when unmarshalling results without a
+// type, which is how results in the untranslated format are recognized. It is
+// wrapped with the type found, so it should be checked for with errors.Is.
+var ErrUnsupportedResultsType = errors.New("unsupported results type found")
+
+// ResultFactory returns a pointer to a new, empty value of a type of results
+// for its JSON data to be unmarshalled into
+type ResultFactory func() interface{}
+
+type resultDecoder func(data json.RawMessage) (interface{}, error)
+
+type resultType struct {
+	name   string
+	decode resultDecoder
+}
+
+var (
+	registryMu  sync.RWMutex
+	resultTypes = map[string]resultType{}
+	resultNames = map[reflect.Type]string{}
+)
+
+func init() {
+	registerBuiltin("about_yml", func() interface{} { return &AboutYMLResults{} })
+	registerBuiltin("buildsystems", func() interface{} { return &BuildsystemResults{} })
+	registerBuiltin("coverage", func() interface{} { return &CoverageResults{} }, "external_coverage")
+	registerBuiltin("dependency", func() interface{} { return &DependencyResults{} })
+	registerBuiltin("difference", func() interface{} { return &DifferenceResults{} })
+	registerBuiltin("ecosystems", func() interface{} { return &EcosystemResults{} })
+	registerBuiltin("external_vulnerability", func() interface{} { return &ExternalVulnerabilitiesResults{} })
+	registerBuiltin("license", func() interface{} { return &LicenseResults{} })
+	registerBuiltin("metrics", func() interface{} { return &MetricsResults{} })
+	registerBuiltin("risk", func() interface{} { return &RiskResults{} })
+	registerBuiltin("secrets", func() interface{} { return &SecretResults{} })
+	registerBuiltin("virus", func() interface{} { return &VirusResults{} }, "clamav")
+	registerBuiltin("vulnerability", func() interface{} { return &VulnerabilityResults{} })
+
+	register("community", reflect.TypeOf(CommunityResults{}), decodeCommunity)
+}
+
+// RegisterResultType registers a type of results by the name found in the
+// type field of its JSON. Results of the type are unmarshalled into the value
+// returned by the factory, and stored in TranslatedResults.Data without the
+// pointer. Registering a name again replaces the previous registration.
+func RegisterResultType(name string, factory ResultFactory) {
+	register(name, reflect.TypeOf(factory()).Elem(), factoryDecoder(factory))
+}
+
+func registerBuiltin(name string, factory ResultFactory, aliases ...string) {
+	RegisterResultType(name, factory)
+
+	for _, alias := range aliases {
+		registryMu.Lock()
+		resultTypes[alias] = resultTypes[name]
+		registryMu.Unlock()
+	}
+}
+
+func register(name string, t reflect.Type, decode resultDecoder) {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+
+	name = strings.ToLower(name)
+	resultTypes[name] = resultType{name: name, decode: decode}
+
+	if _, ok := resultNames[t]; !ok {
+		resultNames[t] = name
+	}
+}
+
+func lookupResultType(name string) (resultType, bool) {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+
+	rt, ok := resultTypes[strings.ToLower(name)]
+	return rt, ok
+}
+
+// resultTypeName returns the name registered for the type of the given
+// results, or an empty string if it has not been registered
+func resultTypeName(results interface{}) string {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+
+	return resultNames[reflect.TypeOf(results)]
+}
+
+func factoryDecoder(factory ResultFactory) resultDecoder {
+	return func(data json.RawMessage) (interface{}, error) {
+		v := factory()
+		err := json.Unmarshal(data, v)
+		if err != nil {
+			return nil, err
+		}
+
+		return reflect.ValueOf(v).Elem().Interface(), nil
+	}
+}
+
+// decodeCommunity decodes community results, which are sometimes sent as a
+// slice containing the results instead of the results alone
+func decodeCommunity(data json.RawMessage) (interface{}, error) {
+	var c CommunityResults
+	err := json.Unmarshal(data, &c)
+	if err != nil {
+		if strings.Contains(err.Error(), "cannot unmarshal array") {
+			var sliceOfCommunityResults []CommunityResults
+			if json.Unmarshal(data, &sliceOfCommunityResults) == nil && len(sliceOfCommunityResults) > 0 {
+				return sliceOfCommunityResults[0], nil
+			}
+		}
+
+		return nil, err
+	}
+
+	return c, nil
+}
+
+// unmarshalResults returns the results of the given type from their JSON
+// data. Data of a type that has not been registered is returned as the raw
+// JSON so that it is not lost.
+func unmarshalResults(resultType string, data json.RawMessage) (interface{}, error) {
+	rt, ok := lookupResultType(resultType)
+	if !ok {
+		if len(data) == 0 {
+			return nil, nil
+		}
+
+		raw := make(json.RawMessage, len(data))
+		copy(raw, data)
+
+		return raw, nil
+	}
+
+	v, err := rt.decode(data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to unmarshall %v results: %v", strings.Replace(rt.name, "_", " ", -1), err)
+	}
+
+	return v, nil
+}
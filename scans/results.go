@@ -3,7 +3,7 @@ package scans
 import (
 	"encoding/json"
 	"fmt"
-	"strings"
+	"reflect"
 	"time"
 
 	"github.com/ion-channel/ionic/dependencies"
@@ -33,70 +33,33 @@ type UntranslatedResults struct {
 }
 
 // Translate moves information from the particular sub-struct, IE
-// AboutYMLResults or LicenseResults into a generic, Data struct
+// AboutYMLResults or LicenseResults into a generic, Data struct. The type of
+// the translated results is the name registered for the sub-struct's type.
 func (u *UntranslatedResults) Translate() *TranslatedResults {
 	var tr TranslatedResults
-	// There is an argument to be made that the following "if" clauses
-	// could be simplified with introspection since they all do
-	// basically the same thing. I've (dmiles) chosen to writ it all
-	// out in the name of explicit, easily-readable code.
-	if u.AboutYML != nil {
-		tr.Type = "about_yml"
-		tr.Data = *u.AboutYML
-	}
-	if u.Buildsystem != nil {
-		tr.Type = "buildsystems"
-		tr.Data = *u.Buildsystem
-	}
-	if u.Community != nil {
-		tr.Type = "community"
-		tr.Data = *u.Community
-	}
-	if u.Coverage != nil {
-		tr.Type = "coverage"
-		tr.Data = *u.Coverage
-	}
-	if u.Dependency != nil {
-		tr.Type = "dependency"
-		tr.Data = *u.Dependency
-	}
-	if u.Difference != nil {
-		tr.Type = "difference"
-		tr.Data = *u.Difference
-	}
-	if u.Ecosystem != nil {
-		tr.Type = "ecosystems"
-		tr.Data = *u.Ecosystem
-	}
-	if u.ExternalVulnerabilities != nil {
-		tr.Type = "external_vulnerability"
-		tr.Data = *u.ExternalVulnerabilities
-	}
-	if u.License != nil {
-		tr.Type = "license"
-		tr.Data = *u.License
-	}
-	if u.Metrics != nil {
-		tr.Type = "metrics"
-		tr.Data = *u.Metrics
-	}
-	if u.Risk != nil {
-		tr.Type = "risk"
-		tr.Data = *u.Risk
-	}
-	if u.Secret != nil {
-		tr.Type = "secrets"
-		tr.Data = *u.Secret
-	}
-	if u.Virus != nil {
-		tr.Type = "virus"
-		u.Virus.ClamavDetails = *u.VirusDetails
-		tr.Data = *u.Virus
-	}
-	if u.Vulnerability != nil {
-		tr.Type = "vulnerability"
-		tr.Data = *u.Vulnerability
+
+	v := reflect.ValueOf(u).Elem()
+	for i := 0; i < v.NumField(); i++ {
+		f := v.Field(i)
+		if f.Kind() != reflect.Ptr || f.IsNil() {
+			continue
+		}
+
+		data := f.Elem().Interface()
+		name := resultTypeName(data)
+		if name == "" {
+			continue
+		}
+
+		if virus, ok := data.(VirusResults); ok && u.VirusDetails != nil {
+			virus.ClamavDetails = *u.VirusDetails
+			data = virus
+		}
+
+		tr.Type = name
+		tr.Data = data
 	}
+
 	return &tr
 }
 
@@ -114,7 +77,8 @@ type translatedResults struct {
 
 // UnmarshalJSON is a custom JSON unmarshaller implementation for the standard
 // go json package to know how to properly interpret ScanSummaryResults from
-// JSON.
+// JSON. Data of a type that has not been registered with RegisterResultType
+// is kept as a json.RawMessage, and is marshalled back unchanged.
 func (r *TranslatedResults) UnmarshalJSON(b []byte) error {
 	var tr translatedResults
 	err := json.Unmarshal(b, &tr)
@@ -122,134 +86,18 @@ func (r *TranslatedResults) UnmarshalJSON(b []byte) error {
 		return err
 	}
 
-	r.Type = tr.Type
-
-	switch strings.ToLower(tr.Type) {
-	case "about_yml":
-		var a AboutYMLResults
-		err := json.Unmarshal(tr.RawData, &a)
-		if err != nil {
-			return fmt.Errorf("failed to unmarshall about yml results: %v", err)
-		}
-
-		r.Data = a
-	case "buildsystems":
-		var b BuildsystemResults
-		err := json.Unmarshal(tr.RawData, &b)
-		if err != nil {
-			return fmt.Errorf("failed to unmarshall buildsystems results: %v", err)
-		}
-
-		r.Data = b
-	case "community":
-		var c CommunityResults
-		err := json.Unmarshal(tr.RawData, &c)
-		if err != nil {
-			// Note: Could be a slice, needs to be fixed
-			if strings.Contains(err.Error(), "cannot unmarshal array") {
-				var sliceOfCommunityResults []CommunityResults
-				err := json.Unmarshal(tr.RawData, &sliceOfCommunityResults)
-				if err == nil {
-					c = sliceOfCommunityResults[0]
-					break
-				}
-			}
-			return fmt.Errorf("failed to unmarshall community results: %v", err)
-		}
-
-		r.Data = c
-	case "coverage", "external_coverage":
-		var c CoverageResults
-		err := json.Unmarshal(tr.RawData, &c)
-		if err != nil {
-			return fmt.Errorf("failed to unmarshall coverage results: %v", err)
-		}
-
-		r.Data = c
-	case "dependency":
-		var d DependencyResults
-		err := json.Unmarshal(tr.RawData, &d)
-		if err != nil {
-			return fmt.Errorf("failed to unmarshall dependency results: %v", err)
-		}
-
-		r.Data = d
-	case "ecosystems":
-		var e EcosystemResults
-		err := json.Unmarshal(tr.RawData, &e)
-		if err != nil {
-			return fmt.Errorf("failed to unmarshall ecosystems results: %v", err)
-		}
-
-		r.Data = e
-	case "license":
-		var l LicenseResults
-		err := json.Unmarshal(tr.RawData, &l)
-		if err != nil {
-			return fmt.Errorf("failed to unmarshall license results: %v", err)
-		}
-
-		r.Data = l
-	case "metrics":
-		var b MetricsResults
-		err := json.Unmarshal(tr.RawData, &b)
-		if err != nil {
-			return fmt.Errorf("failed to unmarshall metrics results: %v", err)
-		}
-
-		r.Data = b
-	case "risk":
-		var b RiskResults
-		err := json.Unmarshal(tr.RawData, &b)
-		if err != nil {
-			return fmt.Errorf("failed to unmarshall risk results: %v", err)
-		}
-
-		r.Data = b
-	case "secrets":
-		var b SecretResults
-		err := json.Unmarshal(tr.RawData, &b)
-		if err != nil {
-			return fmt.Errorf("failed to unmarshall secrets results: %v", err)
-		}
-
-		r.Data = b
-	case "virus", "clamav":
-		var v VirusResults
-		err := json.Unmarshal(tr.RawData, &v)
-		if err != nil {
-			return fmt.Errorf("failed to unmarshall virus results: %v", err)
-		}
-
-		r.Data = v
-	case "vulnerability":
-		var v VulnerabilityResults
-		err := json.Unmarshal(tr.RawData, &v)
-		if err != nil {
-			return fmt.Errorf("failed to unmarshall vulnerability results: %v", err)
-		}
-
-		r.Data = v
-	case "external_vulnerability":
-		var v ExternalVulnerabilitiesResults
-		err := json.Unmarshal(tr.RawData, &v)
-		if err != nil {
-			return fmt.Errorf("failed to unmarshall external vulnerabilities results: %v", err)
-		}
-
-		r.Data = v
-	case "difference":
-		var v DifferenceResults
-		err := json.Unmarshal(tr.RawData, &v)
-		if err != nil {
-			return fmt.Errorf("failed to unmarshall difference results: %v", err)
-		}
+	if tr.Type == "" {
+		return fmt.Errorf("%w: %v", ErrUnsupportedResultsType, tr.Type)
+	}
 
-		r.Data = v
-	default:
-		return fmt.Errorf("unsupported results type found: %v", tr.Type)
+	data, err := unmarshalResults(tr.Type, tr.RawData)
+	if err != nil {
+		return err
 	}
 
+	r.Type = tr.Type
+	r.Data = data
+
 	return nil
 }
 
@@ -505,6 +353,30 @@ func (e *RiskResults) UnmarshalJSON(b []byte) error {
 type Secret struct {
 	secrets.Secret
 	File string `json:"file" xml:"file"`
+	Line int    `json:"line,omitempty" xml:"line,omitempty"`
+}
+
+// UnmarshalJSON meets the unmarshaller interface to read the file and line of
+// the secret, which the embedded secret's unmarshaller would otherwise drop
+func (s *Secret) UnmarshalJSON(b []byte) error {
+	err := json.Unmarshal(b, &s.Secret)
+	if err != nil {
+		return err
+	}
+
+	var location struct {
+		File string `json:"file"`
+		Line int    `json:"line"`
+	}
+
+	err = json.Unmarshal(b, &location)
+	if err != nil {
+		return err
+	}
+
+	s.File = location.File
+	s.Line = location.Line
+	return nil
 }
 
 // SecretResults contains secrets finding data
@@ -546,7 +418,7 @@ type VirusResults struct {
 	ClamavDetails      ClamavDetails `json:"clam_av_details" xml:"clam_av_details"`
 }
 
-//VulnerabilityResults represents the data collected from a vulnerability scan.  It includes
+// VulnerabilityResults represents the data collected from a vulnerability scan.  It includes
 // information of the vulnerabilities seen.
 type VulnerabilityResults struct {
 	Vulnerabilities []VulnerabilityResultsProduct `json:"vulnerabilities" xml:"vulnerabilities"`
@@ -578,8 +450,62 @@ type VulnerabilityResultsProduct struct {
 	Query           Dependency                          `json:"query" xml:"query"`
 }
 
-// VulnerabilityResultsVulnerability wrapper
+// VulnerabilityResultsVulnerability wrapper. The VEX fields are set when a
+// VEX statement about the vulnerability has been applied to the results, and
+// the affected range and fixed version when it was matched offline.
 type VulnerabilityResultsVulnerability struct {
 	vulnerabilities.Vulnerability
-	Dependencies []VulnerabilityResultsProduct `json:"dependencies" xml:"dependencies"`
+	Dependencies     []VulnerabilityResultsProduct `json:"dependencies" xml:"dependencies"`
+	VEXStatus        string                        `json:"vex_status,omitempty" xml:"vex_status,omitempty"`
+	VEXJustification string                        `json:"vex_justification,omitempty" xml:"vex_justification,omitempty"`
+	VEXStatement     string                        `json:"vex_statement,omitempty" xml:"vex_statement,omitempty"`
+	AffectedRange    string                        `json:"affected_range,omitempty" xml:"affected_range,omitempty"`
+	FixedVersion     string                        `json:"fixed_version,omitempty" xml:"fixed_version,omitempty"`
+}
+
+// UnmarshalJSON meets the unmarshaller interface to read the dependencies and
+// VEX fields of the vulnerability, which the embedded vulnerability's
+// unmarshaller would otherwise drop
+func (v *VulnerabilityResultsVulnerability) UnmarshalJSON(b []byte) error {
+	var fields map[string]json.RawMessage
+	err := json.Unmarshal(b, &fields)
+	if err != nil {
+		return err
+	}
+
+	var wrapper struct {
+		Dependencies     []VulnerabilityResultsProduct `json:"dependencies"`
+		VEXStatus        string                        `json:"vex_status"`
+		VEXJustification string                        `json:"vex_justification"`
+		VEXStatement     string                        `json:"vex_statement"`
+		AffectedRange    string                        `json:"affected_range"`
+		FixedVersion     string                        `json:"fixed_version"`
+	}
+
+	err = json.Unmarshal(b, &wrapper)
+	if err != nil {
+		return err
+	}
+
+	for _, k := range []string{"dependencies", "vex_status", "vex_justification", "vex_statement", "affected_range", "fixed_version"} {
+		delete(fields, k)
+	}
+
+	rest, err := json.Marshal(fields)
+	if err != nil {
+		return err
+	}
+
+	err = json.Unmarshal(rest, &v.Vulnerability)
+	if err != nil {
+		return err
+	}
+
+	v.Dependencies = wrapper.Dependencies
+	v.VEXStatus = wrapper.VEXStatus
+	v.VEXJustification = wrapper.VEXJustification
+	v.VEXStatement = wrapper.VEXStatement
+	v.AffectedRange = wrapper.AffectedRange
+	v.FixedVersion = wrapper.FixedVersion
+	return nil
 }
@@ -2,6 +2,7 @@ package scans
 
 import (
 	"encoding/json"
+	"errors"
 	"testing"
 
 	"github.com/franela/goblin"
@@ -342,6 +343,9 @@ func TestScanResults(t *testing.T) {
 			e, ok := r.Data.(SecretResults)
 			Expect(ok).To(Equal(true))
 			Expect(len(e.Secrets)).To(Equal(1))
+			Expect(e.Secrets[0].Rule).To(Equal("Slack Webhook"))
+			Expect(e.Secrets[0].File).To(Equal("text.txt"))
+			Expect(e.Secrets[0].Line).To(Equal(0))
 		})
 
 		g.It("should marshal a scan result with secrets data", func() {
@@ -400,14 +404,66 @@ func TestScanResults(t *testing.T) {
 			Expect(ok).To(Equal(true))
 			Expect(v.Meta.VulnerabilityCount).To(Equal(1))
 			Expect(v.Vulnerabilities[0].Query.Name).To(Equal("broken"))
+
+			vuln := v.Vulnerabilities[0].Vulnerabilities[0]
+			Expect(vuln.ExternalID).To(Equal("CVE-2017-7669"))
+			Expect(vuln.ScoreDetails.CVSSv3.BaseScore).To(Equal(7.5))
+
+			vector, err := vuln.CVSS()
+			Expect(err).To(BeNil())
+			Expect(vector.BaseScore()).To(Equal(7.5))
+		})
+
+		g.It("should unmarshal the dependencies of a vulnerability", func() {
+			var v VulnerabilityResultsVulnerability
+			err := json.Unmarshal([]byte(`{"external_id":"CVE-1","vector":"CVSS:3.1/AV:N/AC:L/PR:N/UI:N/S:U/C:H/I:H/A:H","dependencies":[{"name":"lib","version":"1.0"}]}`), &v)
+
+			Expect(err).To(BeNil())
+			Expect(v.ExternalID).To(Equal("CVE-1"))
+			Expect(v.ScoreDetails.CVSSv3.BaseScore).To(Equal(9.8))
+			Expect(v.Dependencies).To(HaveLen(1))
+			Expect(v.Dependencies[0].Name).To(Equal("lib"))
 		})
 
-		g.It("should return an error for an invalid results type", func() {
+		g.It("should preserve the data of an unknown results type", func() {
 			var r TranslatedResults
 			err := json.Unmarshal([]byte(SampleInvalidResults), &r)
 
+			Expect(err).To(BeNil())
+			Expect(r.Type).To(Equal("fooresult"))
+			Expect(r.Data).To(Equal(json.RawMessage(`"I pitty the foo"`)))
+
+			b, err := json.Marshal(r)
+			Expect(err).To(BeNil())
+			Expect(string(b)).To(Equal(`{"type":"fooresult","data":"I pitty the foo"}`))
+		})
+
+		g.It("should return an unsupported type error for results without a type", func() {
+			var r TranslatedResults
+			err := json.Unmarshal([]byte(`{"community":{"name":"ionic"}}`), &r)
+
+			Expect(errors.Is(err, ErrUnsupportedResultsType)).To(BeTrue())
+		})
+
+		g.It("should unmarshal a registered results type", func() {
+			type fooResults struct {
+				Foo string `json:"foo"`
+			}
+			RegisterResultType("foo_registered", func() interface{} { return &fooResults{} })
+
+			var r TranslatedResults
+			err := json.Unmarshal([]byte(`{"type":"foo_registered","data":{"foo":"bar"}}`), &r)
+
+			Expect(err).To(BeNil())
+			Expect(r.Data).To(Equal(fooResults{Foo: "bar"}))
+		})
+
+		g.It("should return an error for results that do not match their type", func() {
+			var r TranslatedResults
+			err := json.Unmarshal([]byte(`{"type":"coverage","data":"not coverage"}`), &r)
+
 			Expect(err).NotTo(BeNil())
-			Expect(err.Error()).To(ContainSubstring("unsupported results type found:"))
+			Expect(err.Error()).To(ContainSubstring("failed to unmarshall coverage results"))
 		})
 	})
 }
@@ -113,6 +113,19 @@ func TestScan(t *testing.T) {
 				err := json.Unmarshal([]byte(badCommunityResults), &ss)
 				Expect(err).NotTo(HaveOccurred())
 			})
+
+			g.It("should keep the results of an unknown type through a round trip", func() {
+				var ss Scan
+				err := json.Unmarshal([]byte(`{"id":"s1","results":{"type":"new_scan","data":{"found":[1,2]}}}`), &ss)
+
+				Expect(err).To(BeNil())
+				Expect(ss.UntranslatedResults).To(BeNil())
+				Expect(ss.TranslatedResults.Type).To(Equal("new_scan"))
+
+				b, err := json.Marshal(&ss)
+				Expect(err).To(BeNil())
+				Expect(string(b)).To(ContainSubstring(`"results":{"type":"new_scan","data":{"found":[1,2]}}`))
+			})
 		})
 
 		g.Describe("Marshalling", func() {
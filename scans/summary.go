@@ -2,8 +2,8 @@ package scans
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
-	"strings"
 	"time"
 )
 
@@ -123,7 +123,7 @@ func (s *Summary) UnmarshalJSON(b []byte) error {
 	var tr TranslatedResults
 	err = json.Unmarshal(s.Results, &tr)
 	if err != nil {
-		if strings.Contains(err.Error(), "unsupported results type found") {
+		if errors.Is(err, ErrUnsupportedResultsType) {
 			var un UntranslatedResults
 			err := json.Unmarshal(s.Results, &un)
 			if err != nil {
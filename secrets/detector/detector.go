@@ -0,0 +1,446 @@
+// Package detector finds secrets, such as cloud keys, tokens, and private
+// keys, in local files without sending their contents to Ion Channel. Findings
+// are reported as the same secrets returned by GetSecrets and formatted the
+// same way as the results of a secrets scan.
+package detector
+
+import (
+	"bufio"
+	"bytes"
+	"embed"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"runtime"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/ion-channel/ionic/scans"
+	"github.com/ion-channel/ionic/secrets"
+)
+
+const (
+	// DefaultMaxFileSize is the size, in bytes, of the largest file scanned
+	// unless the detector is configured otherwise
+	DefaultMaxFileSize = 1 << 20
+
+	maxLineLength     = 1 << 20
+	binarySniffLength = 8000
+)
+
+//go:embed rules.json
+var rulesData embed.FS
+
+// Rule is a pattern identifying a kind of secret
+type Rule struct {
+	// Name is the name reported as the rule of a matching secret.
+	Name string `json:"name"`
+	// Pattern is the regular expression matching the secret. When it has a
+	// capture group, the first group is the secret checked for entropy.
+	Pattern string `json:"pattern"`
+	// Keywords are lower case strings, at least one of which must be in a
+	// line for the pattern to be checked against it. A rule without keywords
+	// is checked against every line.
+	Keywords []string `json:"keywords,omitempty"`
+	// MinEntropy is the minimum Shannon entropy, in bits per character, of
+	// the secret for it to be reported. It filters out placeholders such as
+	// "password = 'changeme-changeme'".
+	MinEntropy float64 `json:"min_entropy,omitempty"`
+	// Confidence is the confidence, from 0.0 to 1.0, reported with a
+	// matching secret.
+	Confidence float32 `json:"confidence"`
+
+	regex *regexp.Regexp
+}
+
+// Detector finds secrets in text and files using a set of rules
+type Detector struct {
+	// Ignore are glob patterns of the files and directories to skip when
+	// scanning paths. A pattern without a slash matches a name anywhere in
+	// the tree, such as "*.min.js" or "node_modules"; a pattern with a slash
+	// matches the path relative to the scanned directory, such as
+	// "testdata/*.pem". Files given to Scan directly are matched relative to
+	// the working directory.
+	Ignore []string
+	// MaxFileSize is the size, in bytes, of the largest file scanned. Larger
+	// files are skipped. DefaultMaxFileSize is used when it is zero.
+	MaxFileSize int64
+
+	rules []*Rule
+}
+
+// DefaultRules returns the built in rules of the detector
+func DefaultRules() []Rule {
+	f, err := rulesData.Open("rules.json")
+	if err != nil {
+		panic("failed to open default secret rules: " + err.Error())
+	}
+	defer f.Close()
+
+	rules, err := LoadRules(f)
+	if err != nil {
+		panic("failed to load default secret rules: " + err.Error())
+	}
+
+	return rules
+}
+
+// LoadRules reads a JSON array of rules, in the same format as the built in
+// rules, for use with a detector
+func LoadRules(r io.Reader) ([]Rule, error) {
+	var rules []Rule
+	err := json.NewDecoder(r).Decode(&rules)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read secret rules: %v", err.Error())
+	}
+
+	return rules, nil
+}
+
+// New returns a detector using the built in rules and any additional rules
+// given. It returns an error if a rule is invalid.
+func New(rules ...Rule) (*Detector, error) {
+	d := &Detector{}
+
+	err := d.AddRules(append(DefaultRules(), rules...)...)
+	if err != nil {
+		return nil, err
+	}
+
+	return d, nil
+}
+
+// AddRules adds rules to the detector. It returns an error if a rule has no
+// name or its pattern does not compile, in which case no rules are added.
+func (d *Detector) AddRules(rules ...Rule) error {
+	compiled := make([]*Rule, 0, len(rules))
+	for i, r := range rules {
+		if r.Name == "" {
+			return fmt.Errorf("secret rule name is required")
+		}
+
+		regex, err := regexp.Compile(r.Pattern)
+		if err != nil {
+			return fmt.Errorf("failed to compile secret rule %v: %v", r.Name, err.Error())
+		}
+
+		r := r
+		r.regex = regex
+		r.Keywords = make([]string, 0, len(rules[i].Keywords))
+		for _, k := range rules[i].Keywords {
+			r.Keywords = append(r.Keywords, strings.ToLower(k))
+		}
+
+		compiled = append(compiled, &r)
+	}
+
+	d.rules = append(d.rules, compiled...)
+	return nil
+}
+
+// Rules returns the rules used by the detector
+func (d *Detector) Rules() []Rule {
+	rules := make([]Rule, 0, len(d.rules))
+	for _, r := range d.rules {
+		rules = append(rules, *r)
+	}
+
+	return rules
+}
+
+// FindSecrets returns the secrets found in the text, the same way as
+// GetSecrets, with the matches masked. An error is returned when a line of the
+// text is too long to scan.
+func (d *Detector) FindSecrets(text string) ([]secrets.Secret, error) {
+	found, err := d.ScanReader("", strings.NewReader(text))
+	if err != nil {
+		return nil, fmt.Errorf("failed to scan text: %v", err.Error())
+	}
+
+	s := make([]secrets.Secret, 0, len(found))
+	for _, f := range found {
+		s = append(s, f.Secret)
+	}
+
+	return s, nil
+}
+
+// ScanReader returns the secrets found in the contents of the reader, with the
+// given file name and the line of each secret
+func (d *Detector) ScanReader(file string, r io.Reader) ([]scans.Secret, error) {
+	var found []scans.Secret
+
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), maxLineLength)
+
+	for lineNum := 1; scanner.Scan(); lineNum++ {
+		line := scanner.Text()
+		lower := strings.ToLower(line)
+
+		for _, rule := range d.rules {
+			if !rule.matchesKeywords(lower) {
+				continue
+			}
+
+			for _, m := range rule.regex.FindAllStringSubmatch(line, -1) {
+				secret := m[0]
+				if len(m) > 1 && m[1] != "" {
+					secret = m[1]
+				}
+
+				if rule.MinEntropy > 0 && Entropy(secret) < rule.MinEntropy {
+					continue
+				}
+
+				found = append(found, scans.Secret{
+					Secret: secrets.Secret{
+						Rule:       rule.Name,
+						Match:      secrets.Mask(m[0]),
+						Confidence: rule.Confidence,
+					},
+					File: file,
+					Line: lineNum,
+				})
+			}
+		}
+	}
+
+	err := scanner.Err()
+	if err != nil {
+		return found, fmt.Errorf("failed to read %v: %v", file, err.Error())
+	}
+
+	return found, nil
+}
+
+// ScanFile returns the secrets found in the file. Binary files and files
+// larger than the maximum file size are skipped.
+func (d *Detector) ScanFile(file string) ([]scans.Secret, error) {
+	f, err := os.Open(file)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open %v: %v", file, err.Error())
+	}
+	defer f.Close()
+
+	info, err := f.Stat()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %v: %v", file, err.Error())
+	}
+
+	if info.Size() > d.maxFileSize() {
+		return nil, nil
+	}
+
+	br := bufio.NewReader(f)
+	head, _ := br.Peek(binarySniffLength)
+	if bytes.IndexByte(head, 0) >= 0 {
+		return nil, nil
+	}
+
+	return d.ScanReader(filepath.ToSlash(file), br)
+}
+
+// Scan returns the secrets found in the given files and directories, ordered
+// by file and line. Directories are walked, skipping the ignored files and
+// directories, and ignored files given directly are skipped too. Files are scanned in parallel, which makes scanning the staged
+// files of a commit fast enough for a pre-commit hook.
+func (d *Detector) Scan(paths ...string) ([]scans.Secret, error) {
+	var files []string
+	for _, p := range paths {
+		info, err := os.Stat(p)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %v: %v", p, err.Error())
+		}
+
+		if !info.IsDir() {
+			if !d.ignoredFile(p) {
+				files = append(files, p)
+			}
+
+			continue
+		}
+
+		err = filepath.Walk(p, func(file string, info os.FileInfo, err error) error {
+			if err != nil {
+				return err
+			}
+
+			rel, err := filepath.Rel(p, file)
+			if err != nil || rel == "." {
+				return nil
+			}
+
+			if d.ignored(filepath.ToSlash(rel)) {
+				if info.IsDir() {
+					return filepath.SkipDir
+				}
+
+				return nil
+			}
+
+			if info.Mode().IsRegular() {
+				files = append(files, file)
+			}
+
+			return nil
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to walk %v: %v", p, err.Error())
+		}
+	}
+
+	return d.scanFiles(files)
+}
+
+func (d *Detector) scanFiles(files []string) ([]scans.Secret, error) {
+	results := make([][]scans.Secret, len(files))
+	errs := make([]error, len(files))
+
+	jobs := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < runtime.NumCPU(); w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				results[i], errs[i] = d.ScanFile(files[i])
+			}
+		}()
+	}
+
+	for i := range files {
+		jobs <- i
+	}
+	close(jobs)
+	wg.Wait()
+
+	var found []scans.Secret
+	for i := range files {
+		if errs[i] != nil {
+			return nil, errs[i]
+		}
+
+		found = append(found, results[i]...)
+	}
+
+	sort.SliceStable(found, func(i, j int) bool {
+		if found[i].File != found[j].File {
+			return found[i].File < found[j].File
+		}
+
+		return found[i].Line < found[j].Line
+	})
+
+	return found, nil
+}
+
+// ignoredFile returns whether the file, or any directory it is in, matches an
+// ignore pattern, relative to the working directory
+func (d *Detector) ignoredFile(file string) bool {
+	rel := filepath.Clean(file)
+	if abs, err := filepath.Abs(file); err == nil {
+		if wd, err := os.Getwd(); err == nil {
+			if r, err := filepath.Rel(wd, abs); err == nil {
+				rel = r
+			}
+		}
+	}
+
+	var dir string
+	for _, part := range strings.Split(filepath.ToSlash(rel), "/") {
+		dir = path.Join(dir, part)
+		if part == ".." || part == "." {
+			continue
+		}
+
+		if d.ignored(dir) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// ignored returns whether the slash separated path, relative to the scanned
+// directory, matches an ignore pattern
+func (d *Detector) ignored(rel string) bool {
+	for _, pattern := range d.Ignore {
+		pattern = strings.TrimSuffix(strings.TrimPrefix(filepath.ToSlash(pattern), "**/"), "/")
+		if pattern == "" {
+			continue
+		}
+
+		if strings.HasPrefix(pattern, "/") || strings.Contains(pattern, "/") {
+			if ok, _ := path.Match(strings.TrimPrefix(pattern, "/"), rel); ok {
+				return true
+			}
+
+			continue
+		}
+
+		if ok, _ := path.Match(pattern, path.Base(rel)); ok {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (d *Detector) maxFileSize() int64 {
+	if d.MaxFileSize > 0 {
+		return d.MaxFileSize
+	}
+
+	return DefaultMaxFileSize
+}
+
+func (r *Rule) matchesKeywords(lowerLine string) bool {
+	if len(r.Keywords) == 0 {
+		return true
+	}
+
+	for _, k := range r.Keywords {
+		if strings.Contains(lowerLine, k) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Entropy returns the Shannon entropy of the string, in bits per character
+func Entropy(s string) float64 {
+	if s == "" {
+		return 0
+	}
+
+	counts := make(map[rune]int)
+	total := 0
+	for _, r := range s {
+		counts[r]++
+		total++
+	}
+
+	entropy := 0.0
+	for _, c := range counts {
+		p := float64(c) / float64(total)
+		entropy -= p * math.Log2(p)
+	}
+
+	return entropy
+}
+
+// Results returns the secrets formatted as the results of a secrets scan
+func Results(found []scans.Secret) scans.SecretResults {
+	if found == nil {
+		found = []scans.Secret{}
+	}
+
+	return scans.SecretResults{Secrets: found}
+}
@@ -22,21 +22,27 @@ type Secret struct {
 
 // UnmarshalJSON custom unmarshal to mask secrets
 func (s *Secret) UnmarshalJSON(data []byte) error {
-	var v map[string]interface{}
+	var v struct {
+		Rule       string  `json:"rule"`
+		Match      string  `json:"match"`
+		Confidence float32 `json:"confidence"`
+	}
+
 	err := json.Unmarshal(data, &v)
 	if err != nil {
 		return err
 	}
 
-	// t, ok := v.(Secret)
-	// if !ok {
-	// 	return fmt.Errorf("Data was not in the form of a secret")
-	// }
-	s.Rule = v["rule"].(string)
-	s.Match = v["match"].(string)
-	s.Confidence = float32(v["confidence"].(float64))
+	s.Rule = v.Rule
+	s.Match = Mask(v.Match)
+	s.Confidence = v.Confidence
 
-	half := len(s.Match) / 2
-	s.Match = s.Match[:half] + strings.Repeat("*", half)
 	return nil
 }
+
+// Mask hides the second half of a matched secret behind asterisks. Masking an
+// already masked match leaves it unchanged.
+func Mask(match string) string {
+	half := len(match) / 2
+	return match[:half] + strings.Repeat("*", half)
+}
@@ -0,0 +1,50 @@
+package spdx
+
+import (
+	"fmt"
+
+	"github.com/ion-channel/ionic/licenses"
+	"github.com/spdx/tools-golang/spdx"
+)
+
+// LicenseComponents returns the packages of an SPDX Document (v2.1 or v2.2) with
+// their licenses, for use with a license policy. The concluded license of a
+// package is used, or its declared license when no license was concluded.
+// The given document must be of the type *spdx.Document2_1 or *spdx.Document2_2.
+func LicenseComponents(doc interface{}) ([]licenses.Component, error) {
+	var components []licenses.Component
+
+	switch doc.(type) {
+	case *spdx.Document2_1:
+		for _, p := range doc.(*spdx.Document2_1).Packages {
+			components = append(components, licenseComponent(packageInfoFromPackage(*p), p.PackageLicenseConcluded, p.PackageLicenseDeclared))
+		}
+	case *spdx.Document2_2:
+		for _, p := range doc.(*spdx.Document2_2).Packages {
+			components = append(components, licenseComponent(packageInfoFromPackage(*p), p.PackageLicenseConcluded, p.PackageLicenseDeclared))
+		}
+	default:
+		return nil, fmt.Errorf("wrong document type given, need *spdx.Document2_1 or *spdx.Document2_2")
+	}
+
+	return components, nil
+}
+
+func licenseComponent(pkg packageInfo, concluded, declared string) licenses.Component {
+	license := concluded
+	if license == "" || license == licenses.NoAssertion {
+		license = declared
+	}
+
+	version := pkg.Version
+	if version == licenses.NoAssertion {
+		version = ""
+	}
+
+	return licenses.Component{
+		Org:     pkg.Organization,
+		Name:    pkg.Name,
+		Version: version,
+		License: license,
+	}
+}
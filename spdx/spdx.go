@@ -10,6 +10,7 @@ import (
 	"github.com/google/uuid"
 	"github.com/ion-channel/ionic/aliases"
 	"github.com/ion-channel/ionic/projects"
+	"github.com/ion-channel/ionic/purl"
 	"github.com/spdx/tools-golang/spdx"
 	"github.com/spdx/tools-golang/spdxlib"
 )
@@ -190,7 +191,7 @@ func ProjectsFromSPDX(doc interface{}, includeDependencies bool) ([]projects.Pro
 			Active:      true,
 			Monitor:     true,
 			CPE:         pkg.CPE,
-			PURL:        pkg.PURL,
+			PURL:        purl.Normalize(pkg.PURL),
 		}
 
 		// check if version, org, or name are not empty strings
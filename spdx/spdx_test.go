@@ -379,4 +379,32 @@ func TestSPDX(t *testing.T) {
 			Expect(email).To(Equal(""))
 		})
 	})
+
+	g.Describe("licenses of SPDX packages", func() {
+		g.It("should return the concluded or declared license of each package", func() {
+			doc := &spdx.Document2_2{Packages: []*spdx.Package2_2{{
+				PackageName:             "concluded",
+				PackageVersion:          "NOASSERTION",
+				PackageLicenseConcluded: "MIT",
+				PackageLicenseDeclared:  "Apache-2.0",
+			}, {
+				PackageName:             "declared",
+				PackageVersion:          "1.0.0",
+				PackageSupplier:         &spdx.Supplier{Supplier: "The Org", SupplierType: "Organization"},
+				PackageLicenseConcluded: "NOASSERTION",
+				PackageLicenseDeclared:  "GPL-2.0-only",
+			}}}
+
+			components, err := LicenseComponents(doc)
+			Expect(err).To(BeNil())
+			Expect(len(components)).To(Equal(2))
+			Expect(components[0].License).To(Equal("MIT"))
+			Expect(components[0].Version).To(Equal(""))
+			Expect(components[1].License).To(Equal("GPL-2.0-only"))
+			Expect(components[1].String()).To(Equal("The Org/declared@1.0.0"))
+
+			_, err = LicenseComponents("not a document")
+			Expect(err).NotTo(BeNil())
+		})
+	})
 }
@@ -0,0 +1,258 @@
+package versioning
+
+import (
+	"strings"
+)
+
+var (
+	// Debian is the versioning scheme of Debian packages, with an optional
+	// epoch and revision, such as "1:2.30-1ubuntu1", and where a tilde sorts
+	// before anything, so "1.0~rc1" < "1.0"
+	Debian Scheme = packageScheme{name: "debian", compare: dpkgCompare}
+	// RPM is the versioning scheme of RPM packages, with an optional epoch and
+	// release, such as "1:2.30-1.el8"
+	RPM Scheme = packageScheme{name: "rpm", compare: rpmCompare}
+)
+
+type packageScheme struct {
+	name    string
+	compare func(a, b string) int
+}
+
+// packageVersion is a version of an operating system package
+type packageVersion struct {
+	text     string
+	epoch    int
+	upstream string
+	revision string
+	compare  func(a, b string) int
+}
+
+func (s packageScheme) Name() string {
+	return s.name
+}
+
+func (s packageScheme) Parse(version string) (Version, error) {
+	text := strings.TrimSpace(version)
+	v := &packageVersion{text: version, compare: s.compare}
+
+	if i := strings.Index(text, ":"); i >= 0 {
+		if !isDigits(text[:i]) {
+			return nil, invalid(s.name, version)
+		}
+
+		v.epoch = atoi(text[:i])
+		text = text[i+1:]
+	}
+
+	v.upstream = text
+	if i := strings.LastIndex(text, "-"); i >= 0 {
+		v.upstream, v.revision = text[:i], text[i+1:]
+	}
+
+	if v.upstream == "" || v.upstream[0] < '0' || v.upstream[0] > '9' {
+		return nil, invalid(s.name, version)
+	}
+
+	return v, nil
+}
+
+func (v *packageVersion) String() string {
+	return v.text
+}
+
+func (v *packageVersion) Release() []int {
+	release := []int{}
+	for _, part := range strings.Split(v.upstream, ".") {
+		digits := part
+		if i := strings.IndexFunc(part, func(r rune) bool { return r < '0' || r > '9' }); i >= 0 {
+			digits = part[:i]
+		}
+
+		if digits == "" {
+			break
+		}
+
+		release = append(release, atoi(digits))
+		if len(digits) != len(part) {
+			break
+		}
+	}
+
+	return release
+}
+
+func (v *packageVersion) Prerelease() bool {
+	return strings.Contains(v.upstream, "~")
+}
+
+func (v *packageVersion) Compare(other Version) int {
+	o, ok := other.(*packageVersion)
+	if !ok {
+		return compareForeign(v, other)
+	}
+
+	if v.epoch != o.epoch {
+		return sign(v.epoch - o.epoch)
+	}
+
+	if c := v.compare(v.upstream, o.upstream); c != 0 {
+		return c
+	}
+
+	return v.compare(v.revision, o.revision)
+}
+
+// dpkgCompare compares version parts as dpkg does, alternating between runs
+// of non-digits, compared with letters before other characters and a tilde
+// before anything, and runs of digits, compared numerically
+func dpkgCompare(a, b string) int {
+	for a != "" || b != "" {
+		var na, nb string
+		na, a = splitRun(a, false)
+		nb, b = splitRun(b, false)
+
+		for i := 0; i < len(na) || i < len(nb); i++ {
+			if c := sign(dpkgOrder(na, i) - dpkgOrder(nb, i)); c != 0 {
+				return c
+			}
+		}
+
+		var da, db string
+		da, a = splitRun(a, true)
+		db, b = splitRun(b, true)
+		if c := compareNumeric(zeroIfEmpty(da), zeroIfEmpty(db)); c != 0 {
+			return c
+		}
+	}
+
+	return 0
+}
+
+// dpkgOrder returns the sort weight of the character at the index, where the
+// end of the string sorts after a tilde and before everything else
+func dpkgOrder(s string, i int) int {
+	if i >= len(s) {
+		return 0
+	}
+
+	c := s[i]
+	switch {
+	case c == '~':
+		return -1
+	case c >= '0' && c <= '9':
+		return 0
+	case (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z'):
+		return int(c)
+	}
+
+	return int(c) + 256
+}
+
+// rpmCompare compares version parts as rpmvercmp does, comparing alphanumeric
+// segments and ignoring separators, where a tilde sorts before anything and a
+// caret sorts after the end of a version but before any other segment
+func rpmCompare(a, b string) int {
+	for {
+		a = strings.TrimLeftFunc(a, isRPMSeparator)
+		b = strings.TrimLeftFunc(b, isRPMSeparator)
+
+		ta, tb := hasPrefix(a, '~'), hasPrefix(b, '~')
+		switch {
+		case ta && tb:
+			a, b = a[1:], b[1:]
+			continue
+		case ta:
+			return -1
+		case tb:
+			return 1
+		}
+
+		ca, cb := hasPrefix(a, '^'), hasPrefix(b, '^')
+		switch {
+		case ca && cb:
+			a, b = a[1:], b[1:]
+			continue
+		case ca && b == "":
+			return 1
+		case ca:
+			return -1
+		case cb && a == "":
+			return -1
+		case cb:
+			return 1
+		}
+
+		if a == "" || b == "" {
+			return sign(len(a) - len(b))
+		}
+
+		numeric := a[0] >= '0' && a[0] <= '9'
+		var sa, sb string
+		sa, a = splitRPMSegment(a, numeric)
+		sb, b = splitRPMSegment(b, numeric)
+
+		if sb == "" {
+			// a numeric segment is newer than an alphabetic one
+			if numeric {
+				return 1
+			}
+
+			return -1
+		}
+
+		c := 0
+		if numeric {
+			c = compareNumeric(sa, sb)
+		} else {
+			c = strings.Compare(sa, sb)
+		}
+
+		if c != 0 {
+			return c
+		}
+	}
+}
+
+func hasPrefix(s string, c byte) bool {
+	return s != "" && s[0] == c
+}
+
+func isRPMSeparator(r rune) bool {
+	return !(r >= '0' && r <= '9') && !(r >= 'a' && r <= 'z') && !(r >= 'A' && r <= 'Z') && r != '~' && r != '^'
+}
+
+func splitRPMSegment(s string, numeric bool) (string, string) {
+	i := 0
+	for i < len(s) {
+		c := s[i]
+		isDigit := c >= '0' && c <= '9'
+		isAlpha := (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z')
+		if (numeric && !isDigit) || (!numeric && !isAlpha) {
+			break
+		}
+
+		i++
+	}
+
+	return s[:i], s[i:]
+}
+
+// splitRun splits the leading run of digits, or of non-digits, from the
+// string
+func splitRun(s string, digits bool) (string, string) {
+	i := 0
+	for i < len(s) && (s[i] >= '0' && s[i] <= '9') == digits {
+		i++
+	}
+
+	return s[:i], s[i:]
+}
+
+func zeroIfEmpty(s string) string {
+	if s == "" {
+		return "0"
+	}
+
+	return s
+}
@@ -0,0 +1,313 @@
+package versioning
+
+import (
+	"strings"
+)
+
+var (
+	// Maven is the versioning scheme of Maven, following the ordering of
+	// Maven's ComparableVersion, where "1.0-alpha" < "1.0-rc" < "1.0" <
+	// "1.0-sp"
+	Maven Scheme = mavenScheme{name: "maven"}
+	// Generic is the versioning scheme for ecosystems without one of their
+	// own. It orders any non-empty version using Maven's rules, which handle
+	// calendar versions and common qualifiers.
+	Generic Scheme = mavenScheme{name: "generic"}
+)
+
+// mavenQualifiers are the well known qualifiers in order, where the empty
+// qualifier is a release
+var mavenQualifiers = []string{"alpha", "beta", "milestone", "rc", "snapshot", "", "sp"}
+
+var mavenAliases = map[string]string{
+	"ga":      "",
+	"final":   "",
+	"release": "",
+	"cr":      "rc",
+}
+
+const (
+	mavenInt = iota
+	mavenString
+	mavenList
+)
+
+// mavenItem is a segment of a Maven version: a number, a qualifier, or a
+// list of items that followed a hyphen
+type mavenItem struct {
+	kind  int
+	value string
+	list  []*mavenItem
+}
+
+type mavenScheme struct {
+	name string
+}
+
+type mavenVersion struct {
+	text  string
+	items *mavenItem
+}
+
+func (s mavenScheme) Name() string {
+	return s.name
+}
+
+func (s mavenScheme) Parse(version string) (Version, error) {
+	text := strings.ToLower(strings.TrimSpace(version))
+	if text == "" {
+		return nil, invalid(s.name, version)
+	}
+
+	return &mavenVersion{text: version, items: parseMaven(text)}, nil
+}
+
+// parseMaven splits a version into items at dots, hyphens, and transitions
+// between digits and letters, as ComparableVersion does
+func parseMaven(text string) *mavenItem {
+	root := &mavenItem{kind: mavenList}
+	list := root
+	stack := []*mavenItem{root}
+
+	isDigit := false
+	start := 0
+
+	sublist := func() {
+		next := &mavenItem{kind: mavenList}
+		list.list = append(list.list, next)
+		list = next
+		stack = append(stack, next)
+	}
+
+	for i := 0; i < len(text); i++ {
+		c := text[i]
+		switch {
+		case c == '.':
+			if i == start {
+				list.list = append(list.list, &mavenItem{kind: mavenInt, value: "0"})
+			} else {
+				list.list = append(list.list, newMavenItem(isDigit, text[start:i], false))
+			}
+
+			start = i + 1
+		case c == '-':
+			if i == start {
+				list.list = append(list.list, &mavenItem{kind: mavenInt, value: "0"})
+			} else {
+				list.list = append(list.list, newMavenItem(isDigit, text[start:i], false))
+			}
+
+			start = i + 1
+			sublist()
+		case c >= '0' && c <= '9':
+			if !isDigit && i > start {
+				list.list = append(list.list, newMavenItem(false, text[start:i], true))
+				start = i
+				sublist()
+			}
+
+			isDigit = true
+		default:
+			if isDigit && i > start {
+				list.list = append(list.list, newMavenItem(true, text[start:i], false))
+				start = i
+				sublist()
+			}
+
+			isDigit = false
+		}
+	}
+
+	if len(text) > start {
+		list.list = append(list.list, newMavenItem(isDigit, text[start:], false))
+	}
+
+	for i := len(stack) - 1; i >= 0; i-- {
+		stack[i].normalize()
+	}
+
+	return root
+}
+
+func newMavenItem(isDigit bool, value string, followedByDigit bool) *mavenItem {
+	if isDigit {
+		value = strings.TrimLeft(value, "0")
+		if value == "" {
+			value = "0"
+		}
+
+		return &mavenItem{kind: mavenInt, value: value}
+	}
+
+	if followedByDigit && len(value) == 1 {
+		switch value {
+		case "a":
+			value = "alpha"
+		case "b":
+			value = "beta"
+		case "m":
+			value = "milestone"
+		}
+	}
+
+	if alias, ok := mavenAliases[value]; ok {
+		value = alias
+	}
+
+	return &mavenItem{kind: mavenString, value: value}
+}
+
+// normalize removes trailing null items, such as zeros and release
+// qualifiers, stopping at the last non-list item
+func (m *mavenItem) normalize() {
+	for i := len(m.list) - 1; i >= 0; i-- {
+		item := m.list[i]
+		if item.isNull() {
+			m.list = append(m.list[:i], m.list[i+1:]...)
+		} else if item.kind != mavenList {
+			break
+		}
+	}
+}
+
+func (m *mavenItem) isNull() bool {
+	switch m.kind {
+	case mavenInt:
+		return m.value == "0"
+	case mavenString:
+		return m.value == ""
+	}
+
+	return len(m.list) == 0
+}
+
+// compare compares the item with another, where a nil item is a missing one
+func (m *mavenItem) compare(o *mavenItem) int {
+	switch m.kind {
+	case mavenInt:
+		if o == nil {
+			if m.value == "0" {
+				return 0
+			}
+
+			return 1
+		}
+
+		if o.kind == mavenInt {
+			return compareNumeric(m.value, o.value)
+		}
+
+		return 1
+	case mavenString:
+		if o == nil {
+			return strings.Compare(mavenQualifier(m.value), mavenQualifier(""))
+		}
+
+		switch o.kind {
+		case mavenInt:
+			return -1
+		case mavenString:
+			return strings.Compare(mavenQualifier(m.value), mavenQualifier(o.value))
+		}
+
+		return -1
+	}
+
+	if o == nil {
+		if len(m.list) == 0 {
+			return 0
+		}
+
+		return m.list[0].compare(nil)
+	}
+
+	switch o.kind {
+	case mavenInt:
+		return -1
+	case mavenString:
+		return 1
+	}
+
+	for i := 0; i < len(m.list) || i < len(o.list); i++ {
+		var l, r *mavenItem
+		if i < len(m.list) {
+			l = m.list[i]
+		}
+
+		if i < len(o.list) {
+			r = o.list[i]
+		}
+
+		c := 0
+		if l == nil {
+			c = -r.compare(nil)
+		} else {
+			c = l.compare(r)
+		}
+
+		if c != 0 {
+			return c
+		}
+	}
+
+	return 0
+}
+
+// mavenQualifier returns a string that orders the qualifier among the well
+// known ones, with unknown qualifiers ordered after them alphabetically
+func mavenQualifier(q string) string {
+	for i, known := range mavenQualifiers {
+		if q == known {
+			return string(rune('0' + i))
+		}
+	}
+
+	return string(rune('0'+len(mavenQualifiers))) + "-" + q
+}
+
+func (v *mavenVersion) String() string {
+	return v.text
+}
+
+func (v *mavenVersion) Compare(other Version) int {
+	o, ok := other.(*mavenVersion)
+	if !ok {
+		return compareForeign(v, other)
+	}
+
+	return v.items.compare(o.items)
+}
+
+func (v *mavenVersion) Release() []int {
+	release := []int{}
+	for _, item := range v.items.list {
+		if item.kind != mavenInt {
+			break
+		}
+
+		release = append(release, atoi(item.value))
+	}
+
+	return release
+}
+
+func (v *mavenVersion) Prerelease() bool {
+	return v.items.hasPrerelease()
+}
+
+func (m *mavenItem) hasPrerelease() bool {
+	for _, item := range m.list {
+		switch item.kind {
+		case mavenString:
+			if mavenQualifier(item.value) < mavenQualifier("") {
+				return true
+			}
+		case mavenList:
+			if item.hasPrerelease() {
+				return true
+			}
+		}
+	}
+
+	return false
+}
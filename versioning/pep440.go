@@ -0,0 +1,175 @@
+package versioning
+
+import (
+	"regexp"
+	"strings"
+)
+
+// PEP440 is the versioning scheme of Python packages, defined by PEP 440,
+// where "1.0.dev1" < "1.0a1" < "1.0rc1" < "1.0" < "1.0.post1"
+var PEP440 Scheme = pep440Scheme{}
+
+var pep440Pattern = regexp.MustCompile(`^v?(?:(\d+)!)?(\d+(?:\.\d+)*)` +
+	`(?:[-_.]?(a|alpha|b|beta|c|rc|pre|preview)[-_.]?(\d*))?` +
+	`(?:-(\d+)|[-_.]?(post|rev|r)[-_.]?(\d*))?` +
+	`(?:[-_.]?(dev)[-_.]?(\d*))?` +
+	`(?:\+([a-z0-9]+(?:[-_.][a-z0-9]+)*))?$`)
+
+var pep440Phases = map[string]int{
+	"a": 0, "alpha": 0,
+	"b": 1, "beta": 1,
+	"c": 2, "rc": 2, "pre": 2, "preview": 2,
+}
+
+const (
+	pep440Min = -1 << 30
+	pep440Max = 1 << 30
+)
+
+type pep440Scheme struct{}
+
+type pep440Version struct {
+	text    string
+	epoch   int
+	release []int
+	pre     [2]int
+	post    int
+	dev     int
+	local   []string
+}
+
+func (pep440Scheme) Name() string {
+	return "pep440"
+}
+
+func (pep440Scheme) Parse(version string) (Version, error) {
+	m := pep440Pattern.FindStringSubmatch(strings.ToLower(strings.TrimSpace(version)))
+	if m == nil {
+		return nil, invalid("pep440", version)
+	}
+
+	v := &pep440Version{text: version, epoch: atoi(m[1])}
+	for _, n := range strings.Split(m[2], ".") {
+		v.release = append(v.release, atoi(n))
+	}
+
+	// the pre, post, and dev keys are chosen so that comparing them in turn
+	// gives the PEP 440 ordering
+	switch {
+	case m[3] != "":
+		v.pre = [2]int{pep440Phases[m[3]], atoi(m[4])}
+	case m[5] == "" && m[6] == "" && m[8] != "":
+		v.pre = [2]int{pep440Min, 0}
+	default:
+		v.pre = [2]int{pep440Max, 0}
+	}
+
+	switch {
+	case m[5] != "":
+		v.post = atoi(m[5])
+	case m[6] != "":
+		v.post = atoi(m[7])
+	default:
+		v.post = pep440Min
+	}
+
+	v.dev = pep440Max
+	if m[8] != "" {
+		v.dev = atoi(m[9])
+	}
+
+	if m[10] != "" {
+		v.local = strings.FieldsFunc(m[10], func(r rune) bool { return r == '-' || r == '_' || r == '.' })
+	}
+
+	return v, nil
+}
+
+func (v *pep440Version) String() string {
+	return v.text
+}
+
+func (v *pep440Version) Release() []int {
+	return v.release
+}
+
+func (v *pep440Version) Prerelease() bool {
+	return v.pre[0] != pep440Max || v.dev != pep440Max
+}
+
+// isPostOrLocal returns true if the version is a PEP 440 post-release or has
+// a local version label
+func isPostOrLocal(v Version) bool {
+	p, ok := v.(*pep440Version)
+	return ok && (p.post != pep440Min || len(p.local) > 0)
+}
+
+// hasLocal returns true if the version is a PEP 440 version with a local
+// version label
+func hasLocal(v Version) bool {
+	p, ok := v.(*pep440Version)
+	return ok && len(p.local) > 0
+}
+
+// withoutLocal returns the public part of a PEP 440 version, without its local
+// version label
+func withoutLocal(v Version) Version {
+	if !hasLocal(v) {
+		return v
+	}
+
+	public := *v.(*pep440Version)
+	public.local = nil
+	if i := strings.Index(public.text, "+"); i >= 0 {
+		public.text = public.text[:i]
+	}
+
+	return &public
+}
+
+func (v *pep440Version) Compare(other Version) int {
+	o, ok := other.(*pep440Version)
+	if !ok {
+		return compareForeign(v, other)
+	}
+
+	for _, c := range []int{
+		sign(v.epoch - o.epoch),
+		compareInts(v.release, o.release),
+		sign(v.pre[0] - o.pre[0]),
+		sign(v.pre[1] - o.pre[1]),
+		sign(v.post - o.post),
+		sign(v.dev - o.dev),
+	} {
+		if c != 0 {
+			return c
+		}
+	}
+
+	return compareLocal(v.local, o.local)
+}
+
+// compareLocal compares local version labels, where a version with a label
+// is newer than one without, and numeric segments are newer than
+// alphanumeric ones
+func compareLocal(a, b []string) int {
+	for i := 0; i < len(a) && i < len(b); i++ {
+		numA, numB := isDigits(a[i]), isDigits(b[i])
+		switch {
+		case numA && numB:
+			if c := compareNumeric(a[i], b[i]); c != 0 {
+				return c
+			}
+		case numA:
+			return 1
+		case numB:
+			return -1
+		default:
+			if c := strings.Compare(a[i], b[i]); c != 0 {
+				return c
+			}
+		}
+	}
+
+	return sign(len(a) - len(b))
+}
@@ -0,0 +1,539 @@
+package versioning
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// operators are the range operators, longest first so that they are matched
+// before their prefixes
+var operators = []string{"===", "~=", "~>", "==", "!=", ">=", "<=", ">>", "<<", "^", "~", ">", "<", "="}
+
+// Range is a set of versions of a scheme, parsed from the range syntax of its
+// ecosystem. It understands comparisons such as ">= 1.0, < 2.0", npm carets,
+// tildes, hyphens, and wildcards such as "^1.2" and "1.x", RubyGems and PEP
+// 440 pessimistic constraints such as "~> 1.2" and "~= 1.2", Maven and NuGet
+// intervals such as "[1.0,2.0)", and alternatives joined by "||".
+//
+// Pre-releases follow the rules of the scheme. An npm pre-release is only
+// within a set of comparisons if one of them is a pre-release of the same
+// major, minor, and patch, so "^1.2" does not contain "1.3.0-beta". A PEP 440
+// "<V" does not contain the pre-releases of V, and ">V" does not contain its
+// post-releases or local versions, unless V is one itself. An empty range
+// contains every version, pre-releases included.
+type Range struct {
+	scheme Scheme
+	text   string
+	// the range contains a version if all the constraints of any set match
+	sets [][]constraint
+}
+
+type constraint struct {
+	op      string
+	version Version
+	// upper is the exclusive upper bound of a "!*" constraint, which excludes
+	// the versions from version up to upper
+	upper Version
+	// excludePre excludes the pre-releases of the bound of a "<" constraint,
+	// so that "^1.2" does not contain "2.0.0-rc1"
+	excludePre bool
+	// excludePost excludes the post-releases and local versions of the bound
+	// of a ">" constraint, as PEP 440 does
+	excludePost bool
+	// ignoreLocal compares versions without their local version labels, as
+	// PEP 440 does when the version of the constraint has none, so that
+	// "==1.0" contains "1.0+abc"
+	ignoreLocal bool
+	// given is true when the version was written in the range, rather than
+	// implied by a caret, tilde, or wildcard
+	given bool
+}
+
+// ParseRange parses a range of versions of the scheme. An empty range or "*"
+// contains every version.
+func ParseRange(s Scheme, text string) (*Range, error) {
+	r := &Range{scheme: s, text: text}
+
+	for _, alt := range strings.Split(text, "||") {
+		alt = strings.TrimSpace(alt)
+
+		switch {
+		case strings.HasPrefix(alt, "[") || strings.HasPrefix(alt, "("):
+			sets, err := r.parseIntervals(alt)
+			if err != nil {
+				return nil, err
+			}
+
+			r.sets = append(r.sets, sets...)
+		case strings.Contains(alt, " - "):
+			set, err := r.parseHyphen(alt)
+			if err != nil {
+				return nil, err
+			}
+
+			r.sets = append(r.sets, set)
+		default:
+			set := []constraint{}
+			for _, token := range tokenize(alt) {
+				cs, err := r.parseConstraint(token)
+				if err != nil {
+					return nil, err
+				}
+
+				set = append(set, cs...)
+			}
+
+			r.sets = append(r.sets, set)
+		}
+	}
+
+	return r, nil
+}
+
+// Satisfies returns true if the version is within the range, both of the
+// given scheme
+func Satisfies(s Scheme, version, rangeText string) (bool, error) {
+	r, err := ParseRange(s, rangeText)
+	if err != nil {
+		return false, err
+	}
+
+	return r.Contains(version)
+}
+
+// Scheme returns the versioning scheme of the range
+func (r *Range) Scheme() Scheme {
+	return r.scheme
+}
+
+// String returns the range as it was given
+func (r *Range) String() string {
+	return r.text
+}
+
+// Contains parses the version and returns true if it is within the range
+func (r *Range) Contains(version string) (bool, error) {
+	v, err := r.scheme.Parse(version)
+	if err != nil {
+		return false, err
+	}
+
+	return r.Check(v), nil
+}
+
+// Check returns true if the parsed version is within the range
+func (r *Range) Check(v Version) bool {
+	for _, set := range r.sets {
+		if r.setContains(set, v) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// UpperBound returns the lowest exclusive upper bound written in the sets of
+// comparisons that contain the version, such as the "2.0" of "< 2.0" or
+// "[1.0,2.0)", or nil if there is none. Inclusive bounds, and the bounds
+// implied by carets, tildes, and wildcards, are not upper bounds.
+func (r *Range) UpperBound(v Version) Version {
+	var upper Version
+	for _, set := range r.sets {
+		if !r.setContains(set, v) {
+			continue
+		}
+
+		for _, c := range set {
+			if c.op != "<" || !c.given {
+				continue
+			}
+
+			if upper == nil || c.version.Compare(upper) < 0 {
+				upper = c.version
+			}
+		}
+	}
+
+	return upper
+}
+
+// setContains returns true if the version matches every constraint of the
+// set and its pre-releases are allowed by it
+func (r *Range) setContains(set []constraint, v Version) bool {
+	for _, c := range set {
+		if !c.check(v) {
+			return false
+		}
+	}
+
+	return r.allowsPrerelease(set, v)
+}
+
+// allowsPrerelease returns true unless the version is an npm pre-release and
+// none of the comparisons of the set is a pre-release of the same release
+func (r *Range) allowsPrerelease(set []constraint, v Version) bool {
+	if r.scheme != NPM || !v.Prerelease() || len(set) == 0 {
+		return true
+	}
+
+	for _, c := range set {
+		if c.version.Prerelease() && compareInts(c.version.Release(), v.Release()) == 0 {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (c constraint) check(v Version) bool {
+	if c.ignoreLocal {
+		v = withoutLocal(v)
+	}
+
+	cmp := v.Compare(c.version)
+	switch c.op {
+	case "=":
+		return cmp == 0
+	case "!=":
+		return cmp != 0
+	case ">":
+		return cmp > 0 && !(c.excludePost && isPostOrLocal(v) && compareInts(v.Release(), c.version.Release()) == 0)
+	case ">=":
+		return cmp >= 0
+	case "<=":
+		return cmp <= 0
+	case "<":
+		return cmp < 0 && !(c.excludePre && v.Prerelease() && compareInts(v.Release(), c.version.Release()) == 0)
+	case "!*":
+		below := constraint{op: "<", version: c.upper, excludePre: true}
+		return cmp < 0 || !below.check(v)
+	}
+
+	return false
+}
+
+// tokenize splits a list of comparisons on commas and spaces, keeping each
+// operator with its version
+func tokenize(text string) []string {
+	fields := strings.Fields(strings.Replace(text, ",", " ", -1))
+
+	tokens := []string{}
+	for i := 0; i < len(fields); i++ {
+		f := fields[i]
+		if strings.Trim(f, "<>=!~^") == "" && i+1 < len(fields) {
+			f += fields[i+1]
+			i++
+		}
+
+		tokens = append(tokens, f)
+	}
+
+	return tokens
+}
+
+// parseConstraint parses a single comparison, such as ">=1.0", "^1.2", or
+// "1.2.*", into the constraints it stands for
+func (r *Range) parseConstraint(token string) ([]constraint, error) {
+	op := ""
+	for _, o := range operators {
+		if strings.HasPrefix(token, o) {
+			op = o
+			break
+		}
+	}
+
+	text := strings.TrimSpace(strings.TrimPrefix(token, op))
+	arbitrary := op == "==="
+	switch op {
+	case "==", "===":
+		op = "="
+	case ">>":
+		op = ">"
+	case "<<":
+		op = "<"
+	}
+
+	prefix, wildcard := wildcardPrefix(text)
+	if wildcard {
+		return r.parseWildcard(op, prefix)
+	}
+
+	v, err := r.parse(text)
+	if err != nil {
+		return nil, err
+	}
+
+	release := givenRelease(text, v)
+	switch op {
+	case "", "=":
+		if r.partialIsWildcard(text, v) {
+			return r.parseWildcard(op, text)
+		}
+
+		if op == "" && r.scheme == NuGet {
+			return []constraint{{op: ">=", version: v}}, nil
+		}
+
+		c := constraint{op: "=", version: v}
+		c.ignoreLocal = r.scheme == PEP440 && !arbitrary && !hasLocal(v)
+		return []constraint{c}, nil
+	case "^":
+		i := 0
+		for i < len(release)-1 && release[i] == 0 {
+			i++
+		}
+
+		return r.bounded(v, release[:i+1])
+	case "~":
+		if len(release) > 2 {
+			release = release[:2]
+		}
+
+		return r.bounded(v, release)
+	case "~>", "~=":
+		if len(release) > 1 {
+			release = release[:len(release)-1]
+		}
+
+		return r.bounded(v, release)
+	}
+
+	c := constraint{op: op, version: v, given: true}
+	if r.scheme == PEP440 {
+		c.excludePre = op == "<" && !v.Prerelease()
+		c.excludePost = op == ">" && !isPostOrLocal(v)
+		c.ignoreLocal = (op == "!=" || op == "<=" || op == ">=") && !hasLocal(v)
+	}
+
+	return []constraint{c}, nil
+}
+
+// parseWildcard returns the constraints of a comparison with a wildcard
+// version, such as "1.2.x", given the release before the wildcard
+func (r *Range) parseWildcard(op, prefix string) ([]constraint, error) {
+	if prefix == "" {
+		if op == "!=" || op == "<" || op == ">" {
+			return nil, fmt.Errorf("invalid range: %v*", op)
+		}
+
+		return []constraint{}, nil
+	}
+
+	lower, err := r.parse(prefix)
+	if err != nil {
+		return nil, err
+	}
+
+	upper, err := r.bump(givenRelease(prefix, lower))
+	if err != nil {
+		return nil, err
+	}
+
+	switch op {
+	case ">=":
+		return []constraint{{op: ">=", version: lower}}, nil
+	case ">":
+		return []constraint{{op: ">=", version: upper}}, nil
+	case "<":
+		return []constraint{{op: "<", version: lower, excludePre: true}}, nil
+	case "<=":
+		return []constraint{{op: "<", version: upper, excludePre: true}}, nil
+	case "!=":
+		return []constraint{{op: "!*", version: lower, upper: upper}}, nil
+	}
+
+	return []constraint{{op: ">=", version: lower}, {op: "<", version: upper, excludePre: true}}, nil
+}
+
+// parseHyphen parses an npm hyphen range, such as "1.2.3 - 2.3", where a
+// partial upper version includes every version it is a prefix of
+func (r *Range) parseHyphen(text string) ([]constraint, error) {
+	parts := strings.SplitN(text, " - ", 2)
+
+	lower, err := r.parse(strings.TrimSpace(parts[0]))
+	if err != nil {
+		return nil, err
+	}
+
+	upperText := strings.TrimSpace(parts[1])
+	upper, err := r.parse(upperText)
+	if err != nil {
+		return nil, err
+	}
+
+	if r.partialIsWildcard(upperText, upper) {
+		bumped, err := r.bump(givenRelease(upperText, upper))
+		if err != nil {
+			return nil, err
+		}
+
+		return []constraint{{op: ">=", version: lower}, {op: "<", version: bumped, excludePre: true}}, nil
+	}
+
+	return []constraint{{op: ">=", version: lower}, {op: "<=", version: upper}}, nil
+}
+
+// parseIntervals parses Maven and NuGet interval notation, where each
+// interval, such as "[1.0,2.0)" or "[1.5]", is an alternative
+func (r *Range) parseIntervals(text string) ([][]constraint, error) {
+	sets := [][]constraint{}
+
+	rest := text
+	for rest = strings.TrimLeft(rest, ", "); rest != ""; rest = strings.TrimLeft(rest, ", ") {
+		if rest[0] != '[' && rest[0] != '(' {
+			return nil, fmt.Errorf("invalid range: %v", text)
+		}
+
+		end := strings.IndexAny(rest, "])")
+		if end < 0 {
+			return nil, fmt.Errorf("invalid range: %v", text)
+		}
+
+		opening, closing, body := rest[0], rest[end], rest[1:end]
+		rest = rest[end+1:]
+
+		bounds := strings.Split(body, ",")
+		if len(bounds) == 1 {
+			if opening != '[' || closing != ']' {
+				return nil, fmt.Errorf("invalid range: %v", text)
+			}
+
+			v, err := r.parse(strings.TrimSpace(body))
+			if err != nil {
+				return nil, err
+			}
+
+			sets = append(sets, []constraint{{op: "=", version: v}})
+			continue
+		}
+
+		if len(bounds) != 2 {
+			return nil, fmt.Errorf("invalid range: %v", text)
+		}
+
+		set := []constraint{}
+		if lower := strings.TrimSpace(bounds[0]); lower != "" {
+			v, err := r.parse(lower)
+			if err != nil {
+				return nil, err
+			}
+
+			op := ">"
+			if opening == '[' {
+				op = ">="
+			}
+
+			set = append(set, constraint{op: op, version: v})
+		}
+
+		if upper := strings.TrimSpace(bounds[1]); upper != "" {
+			v, err := r.parse(upper)
+			if err != nil {
+				return nil, err
+			}
+
+			op := "<"
+			if closing == ']' {
+				op = "<="
+			}
+
+			set = append(set, constraint{op: op, version: v, given: true})
+		}
+
+		sets = append(sets, set)
+	}
+
+	return sets, nil
+}
+
+// bounded returns the constraints from the version up to, but excluding, the
+// release that follows the given prefix of it
+func (r *Range) bounded(v Version, prefix []int) ([]constraint, error) {
+	upper, err := r.bump(prefix)
+	if err != nil {
+		return nil, err
+	}
+
+	return []constraint{{op: ">=", version: v}, {op: "<", version: upper, excludePre: true}}, nil
+}
+
+// bump parses the release that follows the given one, incrementing its last
+// segment
+func (r *Range) bump(release []int) (Version, error) {
+	if len(release) == 0 {
+		return nil, fmt.Errorf("invalid range: %v", r.text)
+	}
+
+	parts := make([]string, len(release))
+	for i, n := range release {
+		if i == len(release)-1 {
+			n++
+		}
+
+		parts[i] = strconv.Itoa(n)
+	}
+
+	return r.parse(strings.Join(parts, "."))
+}
+
+func (r *Range) parse(text string) (Version, error) {
+	v, err := r.scheme.Parse(text)
+	if err != nil {
+		return nil, fmt.Errorf("invalid range %q: %v", r.text, err.Error())
+	}
+
+	return v, nil
+}
+
+// partialIsWildcard returns true if the version is missing segments that the
+// scheme's ranges treat as wildcards, as npm does with "1.2"
+func (r *Range) partialIsWildcard(text string, v Version) bool {
+	if r.scheme != NPM && r.scheme != SemVer {
+		return false
+	}
+
+	return !v.Prerelease() && strings.Count(strings.SplitN(text, "+", 2)[0], ".") < 2
+}
+
+// wildcardPrefix returns the part of the version before a wildcard segment,
+// such as "1.2" of "1.2.x", and whether it had one
+func wildcardPrefix(text string) (string, bool) {
+	segments := strings.Split(text, ".")
+	for i, s := range segments {
+		if s == "x" || s == "X" || s == "*" {
+			return strings.Join(segments[:i], "."), true
+		}
+	}
+
+	return text, false
+}
+
+// givenRelease returns the release of the version with as many segments as
+// were written in its text, since schemes such as Maven drop trailing zeros
+// that a range like "~> 1.2.0" depends on
+func givenRelease(text string, v Version) []int {
+	n := 0
+	for _, s := range strings.Split(strings.TrimLeft(text, "vV"), ".") {
+		if !isDigits(s) {
+			if s != "" && s[0] >= '0' && s[0] <= '9' {
+				n++
+			}
+
+			break
+		}
+
+		n++
+	}
+
+	release := append([]int{}, v.Release()...)
+	for len(release) < n {
+		release = append(release, 0)
+	}
+
+	if n > 0 && len(release) > n {
+		release = release[:n]
+	}
+
+	return release
+}
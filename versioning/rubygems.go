@@ -0,0 +1,137 @@
+package versioning
+
+import (
+	"regexp"
+	"strings"
+)
+
+// RubyGems is the versioning scheme of Ruby gems, where any letter makes a
+// version a pre-release, so "1.0.a" < "1.0.b1" < "1.0"
+var RubyGems Scheme = rubyGemsScheme{}
+
+var (
+	gemPattern  = regexp.MustCompile(`^[0-9]+(?:\.[0-9a-zA-Z]+)*(?:-[0-9A-Za-z-]+(?:\.[0-9A-Za-z-]+)*)?$`)
+	gemSegments = regexp.MustCompile(`[0-9]+|[a-zA-Z]+`)
+)
+
+type rubyGemsScheme struct{}
+
+type gemVersion struct {
+	text     string
+	segments []string
+}
+
+func (rubyGemsScheme) Name() string {
+	return "rubygems"
+}
+
+func (rubyGemsScheme) Parse(version string) (Version, error) {
+	text := strings.TrimSpace(version)
+	if !gemPattern.MatchString(text) {
+		return nil, invalid("rubygems", version)
+	}
+
+	text = strings.Replace(text, "-", ".pre.", -1)
+	v := &gemVersion{text: version}
+	for _, s := range gemSegments.FindAllString(text, -1) {
+		if isDigits(s) {
+			s = strings.TrimLeft(s, "0")
+			if s == "" {
+				s = "0"
+			}
+		}
+
+		v.segments = append(v.segments, s)
+	}
+
+	v.segments = canonicalGemSegments(v.segments)
+	return v, nil
+}
+
+// canonicalGemSegments removes trailing zeros from the release and
+// pre-release parts of the segments, so "1.0" is the same as "1"
+func canonicalGemSegments(segments []string) []string {
+	split := len(segments)
+	for i, s := range segments {
+		if !isDigits(s) {
+			split = i
+			break
+		}
+	}
+
+	release := trimZeros(segments[:split])
+	pre := trimZeros(segments[split:])
+	return append(append([]string{}, release...), pre...)
+}
+
+func trimZeros(segments []string) []string {
+	for len(segments) > 0 && segments[len(segments)-1] == "0" {
+		segments = segments[:len(segments)-1]
+	}
+
+	return segments
+}
+
+func (v *gemVersion) String() string {
+	return v.text
+}
+
+func (v *gemVersion) Release() []int {
+	release := []int{}
+	for _, s := range v.segments {
+		if !isDigits(s) {
+			break
+		}
+
+		release = append(release, atoi(s))
+	}
+
+	return release
+}
+
+func (v *gemVersion) Prerelease() bool {
+	for _, s := range v.segments {
+		if !isDigits(s) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (v *gemVersion) Compare(other Version) int {
+	o, ok := other.(*gemVersion)
+	if !ok {
+		return compareForeign(v, other)
+	}
+
+	for i := 0; i < len(v.segments) || i < len(o.segments); i++ {
+		a, b := "0", "0"
+		if i < len(v.segments) {
+			a = v.segments[i]
+		}
+
+		if i < len(o.segments) {
+			b = o.segments[i]
+		}
+
+		numA, numB := isDigits(a), isDigits(b)
+		c := 0
+		switch {
+		case numA && numB:
+			c = compareNumeric(a, b)
+		case numA:
+			c = 1
+		case numB:
+			c = -1
+		default:
+			c = strings.Compare(a, b)
+		}
+
+		if c != 0 {
+			return c
+		}
+	}
+
+	return 0
+}
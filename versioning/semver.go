@@ -0,0 +1,164 @@
+package versioning
+
+import (
+	"regexp"
+	"strings"
+)
+
+var (
+	// SemVer is the semantic versioning scheme, used by Cargo, Composer, Hex,
+	// and Pub
+	SemVer Scheme = semverScheme{name: "semver"}
+	// NPM is the versioning scheme of npm, which is semantic versioning with
+	// npm's range syntax
+	NPM Scheme = semverScheme{name: "npm"}
+	// NuGet is the versioning scheme of NuGet, which is semantic versioning
+	// with up to four release segments and case insensitive pre-releases
+	NuGet Scheme = semverScheme{name: "nuget", ignoreCase: true}
+	// Go is the versioning scheme of Go modules, which is semantic versioning
+	// where pseudo-versions order by their commit time
+	Go Scheme = semverScheme{name: "go"}
+)
+
+var (
+	semverPattern = regexp.MustCompile(`^[vV]?(\d+(?:\.\d+)*)(?:-([0-9A-Za-z.-]+))?(?:\+([0-9A-Za-z.-]+))?$`)
+	pseudoPattern = regexp.MustCompile(`(^|[.-])(0\.)?\d{14}-[0-9a-f]{12}$`)
+)
+
+type semverScheme struct {
+	name       string
+	ignoreCase bool
+}
+
+// semver is a semantic version. Missing minor and patch segments are treated
+// as zero.
+type semver struct {
+	text       string
+	release    []int
+	numbers    []string
+	prerelease []string
+	ignoreCase bool
+}
+
+func (s semverScheme) Name() string {
+	return s.name
+}
+
+func (s semverScheme) Parse(version string) (Version, error) {
+	m := semverPattern.FindStringSubmatch(strings.TrimSpace(version))
+	if m == nil {
+		return nil, invalid(s.name, version)
+	}
+
+	v := &semver{text: version, ignoreCase: s.ignoreCase}
+	for _, n := range strings.Split(m[1], ".") {
+		v.numbers = append(v.numbers, n)
+		v.release = append(v.release, atoi(n))
+	}
+
+	if m[2] != "" {
+		v.prerelease = strings.Split(m[2], ".")
+	}
+
+	return v, nil
+}
+
+// IsPseudoVersion returns true if the Go module version is a pseudo-version,
+// such as "v0.0.0-20191109021931-daa7c04131f5", which refers to a commit
+// rather than a tagged release
+func IsPseudoVersion(version string) bool {
+	v, err := Go.Parse(version)
+	if err != nil {
+		return false
+	}
+
+	pre := strings.Join(v.(*semver).prerelease, ".")
+	return pseudoPattern.MatchString(pre)
+}
+
+func (v *semver) String() string {
+	return v.text
+}
+
+func (v *semver) Release() []int {
+	return v.release
+}
+
+func (v *semver) Prerelease() bool {
+	return len(v.prerelease) > 0
+}
+
+func (v *semver) Compare(other Version) int {
+	o, ok := other.(*semver)
+	if !ok {
+		return compareForeign(v, other)
+	}
+
+	n := len(v.numbers)
+	if len(o.numbers) > n {
+		n = len(o.numbers)
+	}
+
+	for i := 0; i < n; i++ {
+		a, b := "0", "0"
+		if i < len(v.numbers) {
+			a = v.numbers[i]
+		}
+
+		if i < len(o.numbers) {
+			b = o.numbers[i]
+		}
+
+		if c := compareNumeric(a, b); c != 0 {
+			return c
+		}
+	}
+
+	// a version without a pre-release is newer than one with
+	switch {
+	case len(v.prerelease) == 0 && len(o.prerelease) == 0:
+		return 0
+	case len(v.prerelease) == 0:
+		return 1
+	case len(o.prerelease) == 0:
+		return -1
+	}
+
+	for i := 0; i < len(v.prerelease) && i < len(o.prerelease); i++ {
+		if c := comparePrereleaseID(v.prerelease[i], o.prerelease[i], v.ignoreCase); c != 0 {
+			return c
+		}
+	}
+
+	return sign(len(v.prerelease) - len(o.prerelease))
+}
+
+// comparePrereleaseID compares pre-release identifiers, where numeric
+// identifiers are compared numerically and are older than alphanumeric ones
+func comparePrereleaseID(a, b string, ignoreCase bool) int {
+	numA, numB := isDigits(a), isDigits(b)
+	switch {
+	case numA && numB:
+		return compareNumeric(a, b)
+	case numA:
+		return -1
+	case numB:
+		return 1
+	}
+
+	if ignoreCase {
+		a, b = strings.ToLower(a), strings.ToLower(b)
+	}
+
+	return strings.Compare(a, b)
+}
+
+// compareForeign compares versions of different schemes by their releases,
+// and then by their text
+func compareForeign(a, b Version) int {
+	if c := compareInts(a.Release(), b.Release()); c != 0 {
+		return c
+	}
+
+	return strings.Compare(a.String(), b.String())
+}
@@ -0,0 +1,244 @@
+// Package versioning parses, compares, and matches versions using the
+// versioning scheme of each package ecosystem, such as semantic versions for
+// npm, ComparableVersion for Maven, and PEP 440 for PyPI.
+package versioning
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Version is a parsed version of a particular scheme
+type Version interface {
+	// String returns the version as it was given
+	String() string
+	// Compare returns -1, 0, or 1 if the version is older than, the same as,
+	// or newer than the other version of the same scheme
+	Compare(other Version) int
+	// Release returns the numeric release segments of the version, such as
+	// major, minor, and patch
+	Release() []int
+	// Prerelease returns true if the version is a pre-release, such as an
+	// alpha, beta, or release candidate
+	Prerelease() bool
+}
+
+// Scheme is the versioning scheme of a package ecosystem
+type Scheme interface {
+	// Name returns the name of the scheme
+	Name() string
+	// Parse parses a version of the scheme
+	Parse(version string) (Version, error)
+}
+
+// Distance is how far a version is behind a newer one, by release segment
+type Distance struct {
+	Major int
+	Minor int
+	Patch int
+}
+
+var schemes = map[string]Scheme{
+	"maven":      Maven,
+	"gradle":     Maven,
+	"sbt":        Maven,
+	"java":       Maven,
+	"npm":        NPM,
+	"yarn":       NPM,
+	"pnpm":       NPM,
+	"node":       NPM,
+	"javascript": NPM,
+	"pypi":       PEP440,
+	"pip":        PEP440,
+	"pipenv":     PEP440,
+	"poetry":     PEP440,
+	"python":     PEP440,
+	"gem":        RubyGems,
+	"gems":       RubyGems,
+	"rubygems":   RubyGems,
+	"ruby":       RubyGems,
+	"bundler":    RubyGems,
+	"nuget":      NuGet,
+	"dotnet":     NuGet,
+	".net":       NuGet,
+	"go":         Go,
+	"golang":     Go,
+	"gomod":      Go,
+	"deb":        Debian,
+	"debian":     Debian,
+	"dpkg":       Debian,
+	"ubuntu":     Debian,
+	"rpm":        RPM,
+	"centos":     RPM,
+	"redhat":     RPM,
+	"fedora":     RPM,
+	"cargo":      SemVer,
+	"crates.io":  SemVer,
+	"rust":       SemVer,
+	"composer":   SemVer,
+	"packagist":  SemVer,
+	"php":        SemVer,
+	"hex":        SemVer,
+	"pub":        SemVer,
+	"semver":     SemVer,
+}
+
+// For returns the versioning scheme of the given ecosystem, such as the type
+// of a dependency. Unknown ecosystems use the generic scheme.
+func For(ecosystem string) Scheme {
+	if s, ok := schemes[strings.ToLower(strings.TrimSpace(ecosystem))]; ok {
+		return s
+	}
+
+	return Generic
+}
+
+// Compare parses two versions of the scheme and returns -1, 0, or 1 if a is
+// older than, the same as, or newer than b
+func Compare(s Scheme, a, b string) (int, error) {
+	va, err := s.Parse(a)
+	if err != nil {
+		return 0, err
+	}
+
+	vb, err := s.Parse(b)
+	if err != nil {
+		return 0, err
+	}
+
+	return va.Compare(vb), nil
+}
+
+// Sort sorts versions of the scheme from oldest to newest. Versions that can
+// not be parsed are sorted after the others, in their original order.
+func Sort(s Scheme, versions []string) {
+	parsed := make(map[string]Version, len(versions))
+	for _, v := range versions {
+		if p, err := s.Parse(v); err == nil {
+			parsed[v] = p
+		}
+	}
+
+	sort.SliceStable(versions, func(i, j int) bool {
+		a, okA := parsed[versions[i]]
+		b, okB := parsed[versions[j]]
+		if !okA || !okB {
+			return okA && !okB
+		}
+
+		return a.Compare(b) < 0
+	})
+}
+
+// Difference returns how far the older version is behind the newer one by
+// major, minor, and patch release, or a zero distance if it is not older
+func Difference(s Scheme, newer, older string) (Distance, error) {
+	n, err := s.Parse(newer)
+	if err != nil {
+		return Distance{}, err
+	}
+
+	o, err := s.Parse(older)
+	if err != nil {
+		return Distance{}, err
+	}
+
+	if o.Compare(n) >= 0 {
+		return Distance{}, nil
+	}
+
+	var behind [3]int
+	nr, or := n.Release(), o.Release()
+	for i := range behind {
+		if d := segment(nr, i) - segment(or, i); d > 0 {
+			behind[i] = d
+		}
+	}
+
+	return Distance{Major: behind[0], Minor: behind[1], Patch: behind[2]}, nil
+}
+
+// segment returns the release segment at the index, or zero if the release
+// is shorter
+func segment(release []int, i int) int {
+	if i < len(release) {
+		return release[i]
+	}
+
+	return 0
+}
+
+// compareInts compares two release segment lists, treating missing segments
+// as zero
+func compareInts(a, b []int) int {
+	n := len(a)
+	if len(b) > n {
+		n = len(b)
+	}
+
+	for i := 0; i < n; i++ {
+		x, y := segment(a, i), segment(b, i)
+		if x != y {
+			return sign(x - y)
+		}
+	}
+
+	return 0
+}
+
+// compareNumeric compares two strings of digits numerically, without limit on
+// their size
+func compareNumeric(a, b string) int {
+	a = strings.TrimLeft(a, "0")
+	b = strings.TrimLeft(b, "0")
+	if len(a) != len(b) {
+		return sign(len(a) - len(b))
+	}
+
+	return strings.Compare(a, b)
+}
+
+// atoi converts a string of digits to an int, saturating on overflow
+func atoi(s string) int {
+	n, err := strconv.Atoi(s)
+	if err != nil {
+		if len(strings.TrimLeft(s, "0123456789")) == 0 && s != "" {
+			return int(^uint(0) >> 1)
+		}
+
+		return 0
+	}
+
+	return n
+}
+
+func isDigits(s string) bool {
+	if s == "" {
+		return false
+	}
+
+	for _, c := range s {
+		if c < '0' || c > '9' {
+			return false
+		}
+	}
+
+	return true
+}
+
+func sign(i int) int {
+	switch {
+	case i < 0:
+		return -1
+	case i > 0:
+		return 1
+	}
+
+	return 0
+}
+
+func invalid(scheme, version string) error {
+	return fmt.Errorf("invalid %v version: %q", scheme, version)
+}
@@ -0,0 +1,285 @@
+package versioning
+
+import (
+	"testing"
+
+	"github.com/franela/goblin"
+	. "github.com/onsi/gomega"
+)
+
+func TestVersioning(t *testing.T) {
+	g := goblin.Goblin(t)
+	RegisterFailHandler(func(m string, _ ...int) { g.Fail(m) })
+
+	// ordered lists each version of a scheme in ascending order
+	ordered := func(s Scheme, versions ...string) {
+		for i := 0; i < len(versions); i++ {
+			for j := 0; j < len(versions); j++ {
+				c, err := Compare(s, versions[i], versions[j])
+				Expect(err).To(BeNil(), versions[i])
+				Expect(c).To(Equal(sign(i-j)), "%v %v: %v vs %v", s.Name(), versions[i], versions[j], c)
+			}
+		}
+	}
+
+	g.Describe("Schemes", func() {
+		g.It("should order semantic versions", func() {
+			ordered(SemVer, "1.0.0-alpha", "1.0.0-alpha.1", "1.0.0-alpha.beta", "1.0.0-beta", "1.0.0-beta.2", "1.0.0-beta.11", "1.0.0-rc.1", "1.0.0", "1.2.0", "1.10.0", "2.0.0")
+
+			c, err := Compare(NPM, "v1.2", "1.2.0+build.5")
+			Expect(err).To(BeNil())
+			Expect(c).To(Equal(0))
+
+			_, err = SemVer.Parse("not-a-version")
+			Expect(err).NotTo(BeNil())
+		})
+
+		g.It("should order go module pseudo-versions", func() {
+			ordered(Go, "v0.0.0-20190101000000-abcdefabcdef", "v0.0.0-20200101000000-abcdefabcdef", "v0.1.0", "v1.2.3", "v1.2.4-0.20210101000000-abcdefabcdef", "v1.2.4", "v2.0.0+incompatible")
+			Expect(IsPseudoVersion("v0.0.0-20191109021931-daa7c04131f5")).To(BeTrue())
+			Expect(IsPseudoVersion("v1.2.4-pre.0.20210101000000-abcdefabcdef")).To(BeTrue())
+			Expect(IsPseudoVersion("v1.2.4-rc.1")).To(BeFalse())
+		})
+
+		g.It("should order nuget versions", func() {
+			ordered(NuGet, "1.0.0-Alpha", "1.0.0-beta", "1.0.0", "1.0.0.1", "1.0.1")
+
+			c, err := Compare(NuGet, "1.0", "1.0.0.0")
+			Expect(err).To(BeNil())
+			Expect(c).To(Equal(0))
+		})
+
+		g.It("should order maven versions", func() {
+			ordered(Maven, "1.0-alpha-1", "1.0-alpha2", "1.0-beta", "1.0-M1", "1.0-rc1", "1.0-SNAPSHOT", "1", "1.0-sp", "1.0-xyz", "1.0.1", "1.1", "1.10", "2.0")
+
+			for _, same := range [][2]string{{"1", "1.0.0"}, {"1.0", "1.0-ga"}, {"1.0-final", "1.0"}, {"1.0-cr1", "1.0-rc1"}, {"1.0a1", "1.0-alpha-1"}} {
+				c, err := Compare(Maven, same[0], same[1])
+				Expect(err).To(BeNil())
+				Expect(c).To(Equal(0), same[0]+" "+same[1])
+			}
+
+			v, err := Maven.Parse("4.5.2-RC1")
+			Expect(err).To(BeNil())
+			Expect(v.Release()).To(Equal([]int{4, 5, 2}))
+			Expect(v.Prerelease()).To(BeTrue())
+
+			_, err = Maven.Parse("")
+			Expect(err).NotTo(BeNil())
+		})
+
+		g.It("should order calendar versions with the generic scheme", func() {
+			ordered(Generic, "1.6", "2021.3.1", "2021.03.15", "2022.1", "20041127.091804")
+		})
+
+		g.It("should order pep 440 versions", func() {
+			ordered(PEP440, "1.0.dev1", "1.0a1.dev1", "1.0a1", "1.0b2", "1.0rc1", "1.0", "1.0+local.1", "1.0.post1.dev1", "1.0.post1", "1.1", "1!0.5")
+
+			c, err := Compare(PEP440, "1.0-ALPHA-1", "1.0a1")
+			Expect(err).To(BeNil())
+			Expect(c).To(Equal(0))
+
+			v, err := PEP440.Parse("2.0.0rc1")
+			Expect(err).To(BeNil())
+			Expect(v.Prerelease()).To(BeTrue())
+		})
+
+		g.It("should order rubygems versions", func() {
+			ordered(RubyGems, "1.0.a", "1.0.a1", "1.0.b1", "1.0.rc1", "1.0", "1.0.1", "1.1", "2")
+
+			c, err := Compare(RubyGems, "1.0.0", "1")
+			Expect(err).To(BeNil())
+			Expect(c).To(Equal(0))
+		})
+
+		g.It("should order debian versions", func() {
+			ordered(Debian, "1.0~rc1", "1.0", "1.0-1", "1.0-1ubuntu1", "1.0a", "1.0+dfsg-1", "1.1", "1:0.9")
+
+			v, err := Debian.Parse("1:2.30-1ubuntu1")
+			Expect(err).To(BeNil())
+			Expect(v.Release()).To(Equal([]int{2, 30}))
+
+			_, err = Debian.Parse("x:1.0")
+			Expect(err).NotTo(BeNil())
+		})
+
+		g.It("should order rpm versions", func() {
+			ordered(RPM, "1.0~rc1", "1.0", "1.0^20200101", "1.0a", "1.0.1", "1.1-1.el8", "1.1-2.el8", "1:0.1")
+		})
+
+		g.It("should find the scheme of an ecosystem", func() {
+			Expect(For("maven")).To(Equal(Maven))
+			Expect(For("NPM")).To(Equal(NPM))
+			Expect(For("pypi")).To(Equal(PEP440))
+			Expect(For("gem")).To(Equal(RubyGems))
+			Expect(For("golang")).To(Equal(Go))
+			Expect(For("unknown")).To(Equal(Generic))
+		})
+	})
+
+	g.Describe("Sorting", func() {
+		g.It("should sort versions and keep unparseable ones last", func() {
+			versions := []string{"1.10.0", "bogus", "1.2.0", "1.2.0-rc.1", "0.9.0"}
+			Sort(SemVer, versions)
+			Expect(versions).To(Equal([]string{"0.9.0", "1.2.0-rc.1", "1.2.0", "1.10.0", "bogus"}))
+		})
+	})
+
+	g.Describe("Ranges", func() {
+		check := func(s Scheme, rangeText string, in []string, out []string) {
+			r, err := ParseRange(s, rangeText)
+			Expect(err).To(BeNil(), rangeText)
+
+			for _, v := range in {
+				ok, err := r.Contains(v)
+				Expect(err).To(BeNil(), v)
+				Expect(ok).To(BeTrue(), "%v should contain %v", rangeText, v)
+			}
+
+			for _, v := range out {
+				ok, err := r.Contains(v)
+				Expect(err).To(BeNil(), v)
+				Expect(ok).To(BeFalse(), "%v should not contain %v", rangeText, v)
+			}
+		}
+
+		g.It("should match npm ranges", func() {
+			check(NPM, "^1.2", []string{"1.2.0", "1.9.9"}, []string{"1.1.9", "2.0.0", "2.0.0-rc.1"})
+			check(NPM, "^0.2.3", []string{"0.2.3", "0.2.9"}, []string{"0.3.0"})
+			check(NPM, "^0.0.3", []string{"0.0.3"}, []string{"0.0.4"})
+			check(NPM, "~1.2.3", []string{"1.2.3", "1.2.9"}, []string{"1.3.0"})
+			check(NPM, "1.x || >=3.0.0 <3.1.0", []string{"1.0.0", "1.9.0", "3.0.5"}, []string{"2.0.0", "3.1.0"})
+			check(NPM, "1.2", []string{"1.2.0", "1.2.7"}, []string{"1.3.0"})
+			check(NPM, "1.2.3 - 2.3", []string{"1.2.3", "2.3.9"}, []string{"1.2.2", "2.4.0"})
+			check(NPM, "*", []string{"0.0.1", "9.9.9"}, nil)
+			check(NPM, "", []string{"1.0.0"}, nil)
+		})
+
+		g.It("should only match npm pre-releases of a release a comparison names", func() {
+			check(NPM, "^1.2", []string{"1.2.0"}, []string{"1.3.0-beta", "1.2.0-rc.1"})
+			check(NPM, ">=1.2.3-beta.1 <2.0.0", []string{"1.2.3-beta.2", "1.2.3", "1.9.0"}, []string{"1.2.4-beta.1", "2.0.0-rc.1"})
+			check(NPM, "<2.0.0", []string{"1.9.9"}, []string{"2.0.0-rc.1", "1.0.0-beta"})
+			check(NPM, "1.x || 2.0.0-rc.1 - 2.0.0", []string{"1.5.0", "2.0.0-rc.2"}, []string{"1.5.0-beta"})
+			check(NPM, "", []string{"1.0.0-beta"}, nil)
+		})
+
+		g.It("should return the written exclusive upper bound containing a version", func() {
+			upper := func(s Scheme, rangeText, version string) string {
+				r, err := ParseRange(s, rangeText)
+				Expect(err).To(BeNil(), rangeText)
+
+				v, err := s.Parse(version)
+				Expect(err).To(BeNil(), version)
+
+				if u := r.UpperBound(v); u != nil {
+					return u.String()
+				}
+
+				return ""
+			}
+
+			Expect(upper(SemVer, "< 2.0.0", "1.5.0")).To(Equal("2.0.0"))
+			Expect(upper(Maven, "[1.0,2.0)", "1.5")).To(Equal("2.0"))
+			Expect(upper(Maven, "[1.0,2.0) || [3.0,3.5)", "3.1")).To(Equal("3.5"))
+			Expect(upper(PEP440, ">=1.0, <1.8, <2.0", "1.5")).To(Equal("1.8"))
+			Expect(upper(Maven, "[1.0,2.0]", "1.5")).To(Equal(""))
+			Expect(upper(SemVer, "<= 2.0.0", "1.5.0")).To(Equal(""))
+			Expect(upper(NPM, "^1.2.0", "1.5.0")).To(Equal(""))
+			Expect(upper(SemVer, "< 2.0.0", "2.1.0")).To(Equal(""))
+		})
+
+		g.It("should exclude pep 440 pre-releases and post-releases of exclusive bounds", func() {
+			check(PEP440, "<2.0", []string{"1.9", "1.9rc1"}, []string{"2.0rc1", "2.0.dev1", "2.0.0a1", "2.0"})
+			check(PEP440, "<2.0rc2", []string{"2.0rc1", "1.9"}, []string{"2.0rc2", "2.0"})
+			check(PEP440, ">1.7", []string{"1.7.1", "1.8"}, []string{"1.7", "1.7.post2", "1.7+local"})
+			check(PEP440, ">1.7.post2", []string{"1.7.post3", "1.8"}, []string{"1.7.post1"})
+			check(PEP440, "<=2.0", []string{"2.0rc1", "2.0"}, []string{"2.0.post1"})
+
+			ok, err := Satisfies(PEP440, "2.0rc1", "<2.0")
+			Expect(err).To(BeNil())
+			Expect(ok).To(BeFalse())
+
+			ok, err = Satisfies(NPM, "1.3.0-beta", "^1.2")
+			Expect(err).To(BeNil())
+			Expect(ok).To(BeFalse())
+		})
+
+		g.It("should ignore pep 440 local labels unless the specifier has one", func() {
+			check(PEP440, "==1.0", []string{"1.0", "1.0+abc", "1.0.0+abc.5"}, []string{"1.0.post1", "1.1+abc"})
+			check(PEP440, "==1.0+abc", []string{"1.0+abc"}, []string{"1.0", "1.0+abd"})
+			check(PEP440, "!=1.0", []string{"1.1", "1.0.post1"}, []string{"1.0", "1.0+abc"})
+			check(PEP440, "<=1.0", []string{"1.0", "1.0+abc"}, []string{"1.0.post1"})
+			check(PEP440, ">=1.0", []string{"1.0+abc", "1.1"}, []string{"1.0rc1"})
+			check(PEP440, "===1.0", []string{"1.0"}, []string{"1.0+abc"})
+		})
+
+		g.It("should match rubygems and pep 440 pessimistic ranges", func() {
+			check(RubyGems, "~> 1.2", []string{"1.2", "1.9.1"}, []string{"1.1", "2.0"})
+			check(RubyGems, "~> 1.2.0", []string{"1.2.0", "1.2.9"}, []string{"1.3.0"})
+			check(RubyGems, ">= 1.0, < 2.0, != 1.5", []string{"1.0", "1.4"}, []string{"1.5", "2.0"})
+			check(PEP440, "~=1.4.2", []string{"1.4.2", "1.4.9"}, []string{"1.5.0", "1.4.1"})
+			check(PEP440, ">=1.0,!=1.4.*", []string{"1.3", "1.5"}, []string{"0.9", "1.4.0", "1.4.7"})
+			check(PEP440, "==2.0.*", []string{"2.0", "2.0.5"}, []string{"2.1"})
+		})
+
+		g.It("should match maven and nuget intervals", func() {
+			check(Maven, "[1.0,2.0)", []string{"1.0", "1.5.3"}, []string{"0.9", "2.0"})
+			check(Maven, "(,1.0],[1.2,)", []string{"0.5", "1.0", "1.2", "3"}, []string{"1.1"})
+			check(Maven, "[1.5]", []string{"1.5", "1.5.0"}, []string{"1.5.1"})
+			check(NuGet, "1.0", []string{"1.0.0", "2.0.0"}, []string{"0.9.0"})
+			check(NuGet, "(1.0,)", []string{"1.0.1"}, []string{"1.0.0"})
+		})
+
+		g.It("should match debian ranges", func() {
+			check(Debian, ">= 1.0-1, << 2.0", []string{"1.0-1", "1.9~rc1"}, []string{"1.0~rc1", "2.0"})
+		})
+
+		g.It("should reject invalid ranges", func() {
+			for _, text := range []string{"[1.0,2.0", ">=bogus", "[1.0,2.0,3.0]", "(1.0)"} {
+				_, err := ParseRange(Maven, text)
+				if text == ">=bogus" {
+					_, err = ParseRange(SemVer, text)
+				}
+
+				Expect(err).NotTo(BeNil(), text)
+			}
+
+			ok, err := Satisfies(SemVer, "1.0.0", ">=1.0.0")
+			Expect(err).To(BeNil())
+			Expect(ok).To(BeTrue())
+
+			_, err = Satisfies(SemVer, "bogus", ">=1.0.0")
+			Expect(err).NotTo(BeNil())
+		})
+	})
+
+	g.Describe("Difference", func() {
+		g.It("should count releases behind", func() {
+			d, err := Difference(Maven, "4.5.2", "4.3.4")
+			Expect(err).To(BeNil())
+			Expect(d).To(Equal(Distance{Major: 0, Minor: 2, Patch: 0}))
+
+			d, err = Difference(SemVer, "2.1.3", "1.9.0")
+			Expect(err).To(BeNil())
+			Expect(d).To(Equal(Distance{Major: 1, Minor: 0, Patch: 3}))
+		})
+
+		g.It("should not count newer or pre-release versions as behind", func() {
+			d, err := Difference(Maven, "1.4-atlassian-1", "1.3")
+			Expect(err).To(BeNil())
+			Expect(d).To(Equal(Distance{Minor: 1}))
+
+			d, err = Difference(Maven, "2.0-rc1", "2.0")
+			Expect(err).To(BeNil())
+			Expect(d).To(Equal(Distance{}))
+
+			d, err = Difference(PEP440, "1.0.post1", "1.0")
+			Expect(err).To(BeNil())
+			Expect(d).To(Equal(Distance{}))
+		})
+
+		g.It("should fail on unparseable versions", func() {
+			_, err := Difference(SemVer, "2.0.0", "latest")
+			Expect(err).NotTo(BeNil())
+		})
+	})
+}
@@ -0,0 +1,192 @@
+package vex
+
+import (
+	"strings"
+
+	"github.com/ion-channel/ionic/analyses"
+	"github.com/ion-channel/ionic/purl"
+	"github.com/ion-channel/ionic/scans"
+)
+
+// Finding is a vulnerability found in a component, with the statement that
+// applies to it
+type Finding struct {
+	Vulnerability string    `json:"vulnerability"`
+	Component     string    `json:"component"`
+	Version       string    `json:"version"`
+	Statement     Statement `json:"statement"`
+}
+
+// AnnotateResults sets the VEX fields of each vulnerability in the results
+// that a statement applies to. The project, when given, is matched against
+// the products of statements that name subcomponents.
+func (d *Document) AnnotateResults(results *scans.VulnerabilityResults, project ...Component) {
+	if results == nil {
+		return
+	}
+
+	for ii := range results.Vulnerabilities {
+		p := &results.Vulnerabilities[ii]
+		for jj := range p.Vulnerabilities {
+			v := &p.Vulnerabilities[jj]
+			if s := d.Find(v.ExternalID, v.Aliases, resultComponents(*p, project)...); s != nil {
+				annotateVulnerability(v, s)
+			}
+		}
+	}
+}
+
+// FilterResults removes the vulnerabilities of the results that a statement
+// marks as not affected or fixed, along with products left without
+// vulnerabilities, and annotates the remaining ones. The vulnerability count
+// is updated, and the removed vulnerabilities are returned as findings so the
+// suppression can be reported.
+func (d *Document) FilterResults(results *scans.VulnerabilityResults, project ...Component) []Finding {
+	suppressed := []Finding{}
+	if results == nil {
+		return suppressed
+	}
+
+	kept := []scans.VulnerabilityResultsProduct{}
+	count := 0
+	for _, p := range results.Vulnerabilities {
+		vulns := []scans.VulnerabilityResultsVulnerability{}
+		for _, v := range p.Vulnerabilities {
+			s := d.Find(v.ExternalID, v.Aliases, resultComponents(p, project)...)
+			if s != nil && s.Status.Suppresses() {
+				suppressed = append(suppressed, Finding{
+					Vulnerability: v.ExternalID,
+					Component:     componentName(p.Org, p.Name),
+					Version:       p.Version,
+					Statement:     *s,
+				})
+				continue
+			}
+
+			if s != nil {
+				annotateVulnerability(&v, s)
+			}
+
+			vulns = append(vulns, v)
+		}
+
+		if len(vulns) == 0 && len(p.Vulnerabilities) > 0 {
+			continue
+		}
+
+		p.Vulnerabilities = vulns
+		count += len(vulns)
+		kept = append(kept, p)
+	}
+
+	results.Vulnerabilities = kept
+	results.Meta.VulnerabilityCount = count
+	return suppressed
+}
+
+// AnnotateExportData sets the VEX fields of each row of the export data that
+// a statement applies to. The project of each row is matched by its ID or
+// name.
+func (d *Document) AnnotateExportData(data []analyses.VulnerabilityExportData) {
+	for ii := range data {
+		if s := d.Find(data[ii].ExternalID, nil, exportComponents(data[ii])...); s != nil {
+			data[ii].VEXStatus = string(s.Status)
+			data[ii].VEXJustification = string(s.Justification)
+			data[ii].VEXStatement = statementText(s)
+		}
+	}
+}
+
+// FilterExportData returns the rows of the export data that no statement
+// marks as not affected or fixed, annotated with the statements that apply to
+// them, and the removed rows as findings
+func (d *Document) FilterExportData(data []analyses.VulnerabilityExportData) ([]analyses.VulnerabilityExportData, []Finding) {
+	kept := []analyses.VulnerabilityExportData{}
+	suppressed := []Finding{}
+
+	for _, row := range data {
+		s := d.Find(row.ExternalID, nil, exportComponents(row)...)
+		if s != nil && s.Status.Suppresses() {
+			suppressed = append(suppressed, Finding{
+				Vulnerability: row.ExternalID,
+				Component:     row.Dependency,
+				Version:       row.DependencyVersion,
+				Statement:     *s,
+			})
+			continue
+		}
+
+		if s != nil {
+			row.VEXStatus = string(s.Status)
+			row.VEXJustification = string(s.Justification)
+			row.VEXStatement = statementText(s)
+		}
+
+		kept = append(kept, row)
+	}
+
+	return kept, suppressed
+}
+
+// resultComponents returns the project, if any, followed by the product of
+// the vulnerability results, identified by its package URL when the type of
+// the dependency it was found in is known
+func resultComponents(p scans.VulnerabilityResultsProduct, project []Component) []Component {
+	c := Component{
+		ID:      p.ExternalID,
+		Org:     p.Org,
+		Name:    p.Name,
+		Version: p.Version,
+	}
+
+	if c.Name == "" {
+		c.Org, c.Name, c.Version = p.Query.Org, p.Query.Name, p.Query.Version
+	}
+
+	if strings.HasPrefix(p.ExternalID, "pkg:") {
+		c.PURL = p.ExternalID
+	} else if typ, ok := purl.KnownType(p.Query.Type); ok {
+		if u, err := purl.FromOrgName(typ, c.Org, c.Name, c.Version); err == nil {
+			c.PURL = u.String()
+		}
+	}
+
+	return append(append([]Component{}, project...), c)
+}
+
+// exportComponents returns the project and dependency of a row of export data
+func exportComponents(row analyses.VulnerabilityExportData) []Component {
+	org, name := "", row.Dependency
+	if i := strings.LastIndexAny(name, "/:"); i >= 0 {
+		org, name = name[:i], name[i+1:]
+	}
+
+	return []Component{
+		{ID: row.ProjectID, Name: row.ProjectName},
+		{Org: org, Name: name, Version: row.DependencyVersion},
+	}
+}
+
+func annotateVulnerability(v *scans.VulnerabilityResultsVulnerability, s *Statement) {
+	v.VEXStatus = string(s.Status)
+	v.VEXJustification = string(s.Justification)
+	v.VEXStatement = statementText(s)
+}
+
+// statementText returns the impact statement of the statement, or its action
+// statement when the product is affected
+func statementText(s *Statement) string {
+	if s.Status == StatusAffected && s.ActionStatement != "" {
+		return s.ActionStatement
+	}
+
+	return s.ImpactStatement
+}
+
+func componentName(org, name string) string {
+	if org == "" {
+		return name
+	}
+
+	return org + "/" + name
+}
@@ -0,0 +1,239 @@
+package vex
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+)
+
+// CycloneDXSpecVersion is the CycloneDX specification version of the VEX
+// documents written by this package
+const CycloneDXSpecVersion = "1.4"
+
+// states maps CycloneDX impact analysis states to statuses
+var states = map[string]Status{
+	"not_affected":           StatusNotAffected,
+	"false_positive":         StatusNotAffected,
+	"exploitable":            StatusAffected,
+	"resolved":               StatusFixed,
+	"resolved_with_pedigree": StatusFixed,
+	"in_triage":              StatusUnderInvestigation,
+}
+
+// cycloneDXStates maps statuses to CycloneDX impact analysis states
+var cycloneDXStates = map[Status]string{
+	StatusNotAffected:        "not_affected",
+	StatusAffected:           "exploitable",
+	StatusFixed:              "resolved",
+	StatusUnderInvestigation: "in_triage",
+}
+
+// cycloneDXJustifications maps CycloneDX impact analysis justifications to
+// justifications
+var cycloneDXJustifications = map[string]Justification{
+	"code_not_present":                JustificationVulnerableCodeNotPresent,
+	"code_not_reachable":              JustificationVulnerableCodeNotInExecutePath,
+	"requires_configuration":          JustificationVulnerableCodeCannotBeControlledByAdversary,
+	"requires_dependency":             JustificationComponentNotPresent,
+	"requires_environment":            JustificationVulnerableCodeCannotBeControlledByAdversary,
+	"protected_by_compiler":           JustificationInlineMitigationsAlreadyExist,
+	"protected_at_runtime":            JustificationInlineMitigationsAlreadyExist,
+	"protected_at_perimeter":          JustificationInlineMitigationsAlreadyExist,
+	"protected_by_mitigating_control": JustificationInlineMitigationsAlreadyExist,
+}
+
+// justificationsToCycloneDX maps justifications to CycloneDX impact analysis
+// justifications
+var justificationsToCycloneDX = map[Justification]string{
+	JustificationComponentNotPresent:                         "requires_dependency",
+	JustificationVulnerableCodeNotPresent:                    "code_not_present",
+	JustificationVulnerableCodeNotInExecutePath:              "code_not_reachable",
+	JustificationVulnerableCodeCannotBeControlledByAdversary: "requires_environment",
+	JustificationInlineMitigationsAlreadyExist:               "protected_by_mitigating_control",
+}
+
+type cycloneDXDocument struct {
+	BOMFormat       string                   `json:"bomFormat"`
+	SpecVersion     string                   `json:"specVersion"`
+	SerialNumber    string                   `json:"serialNumber,omitempty"`
+	Version         int                      `json:"version"`
+	Metadata        *cycloneDXMetadata       `json:"metadata,omitempty"`
+	Vulnerabilities []cycloneDXVulnerability `json:"vulnerabilities"`
+}
+
+type cycloneDXMetadata struct {
+	Timestamp *time.Time `json:"timestamp,omitempty"`
+	Authors   []struct {
+		Name string `json:"name"`
+	} `json:"authors,omitempty"`
+}
+
+type cycloneDXVulnerability struct {
+	ID         string              `json:"id"`
+	References []cycloneDXAlias    `json:"references,omitempty"`
+	Analysis   *cycloneDXAnalysis  `json:"analysis,omitempty"`
+	Affects    []cycloneDXAffected `json:"affects,omitempty"`
+	Updated    *time.Time          `json:"updated,omitempty"`
+}
+
+type cycloneDXAlias struct {
+	ID string `json:"id"`
+}
+
+type cycloneDXAnalysis struct {
+	State         string   `json:"state,omitempty"`
+	Justification string   `json:"justification,omitempty"`
+	Response      []string `json:"response,omitempty"`
+	Detail        string   `json:"detail,omitempty"`
+}
+
+type cycloneDXAffected struct {
+	Ref string `json:"ref"`
+}
+
+// ParseCycloneDX reads the vulnerabilities of a CycloneDX BOM as a VEX
+// document. The affected references become the products of each statement,
+// and the analysis detail its impact statement. Vulnerabilities without an
+// analysis state are under investigation.
+func ParseCycloneDX(r io.Reader) (*Document, error) {
+	var raw cycloneDXDocument
+	err := json.NewDecoder(r).Decode(&raw)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode cyclonedx document: %v", err.Error())
+	}
+
+	if !strings.EqualFold(raw.BOMFormat, "CycloneDX") {
+		return nil, fmt.Errorf("not a cyclonedx document: %v", raw.BOMFormat)
+	}
+
+	doc := &Document{
+		ID:         raw.SerialNumber,
+		Version:    raw.Version,
+		Statements: []Statement{},
+	}
+
+	if raw.Metadata != nil {
+		if raw.Metadata.Timestamp != nil {
+			doc.Timestamp = *raw.Metadata.Timestamp
+		}
+
+		if len(raw.Metadata.Authors) > 0 {
+			doc.Author = raw.Metadata.Authors[0].Name
+		}
+	}
+
+	for _, v := range raw.Vulnerabilities {
+		s := Statement{
+			Vulnerability: v.ID,
+			Status:        StatusUnderInvestigation,
+			Timestamp:     v.Updated,
+		}
+
+		for _, ref := range v.References {
+			s.Aliases = appendUnique(s.Aliases, ref.ID)
+		}
+
+		for _, a := range v.Affects {
+			s.Products = appendUnique(s.Products, a.Ref)
+		}
+
+		if v.Analysis != nil {
+			if status, ok := states[v.Analysis.State]; ok {
+				s.Status = status
+			}
+
+			s.Justification = cycloneDXJustifications[v.Analysis.Justification]
+			if s.Status == StatusNotAffected && v.Analysis.State == "false_positive" && s.Justification == "" {
+				s.Justification = JustificationVulnerableCodeNotPresent
+			}
+
+			if s.Status == StatusAffected {
+				s.ActionStatement = v.Analysis.Detail
+			} else {
+				s.ImpactStatement = v.Analysis.Detail
+			}
+		}
+
+		doc.Statements = append(doc.Statements, s)
+	}
+
+	return doc, nil
+}
+
+// WriteCycloneDX writes the document as a CycloneDX VEX BOM. The
+// subcomponents of each statement, or its products when it has none, become
+// its affected references.
+func WriteCycloneDX(w io.Writer, doc *Document) error {
+	out := cycloneDXDocument{
+		BOMFormat:       "CycloneDX",
+		SpecVersion:     CycloneDXSpecVersion,
+		SerialNumber:    doc.ID,
+		Version:         doc.Version,
+		Vulnerabilities: []cycloneDXVulnerability{},
+	}
+
+	if out.Version == 0 {
+		out.Version = 1
+	}
+
+	if !doc.Timestamp.IsZero() || doc.Author != "" {
+		out.Metadata = &cycloneDXMetadata{}
+		if !doc.Timestamp.IsZero() {
+			timestamp := doc.Timestamp
+			out.Metadata.Timestamp = &timestamp
+		}
+
+		if doc.Author != "" {
+			out.Metadata.Authors = append(out.Metadata.Authors, struct {
+				Name string `json:"name"`
+			}{doc.Author})
+		}
+	}
+
+	for _, s := range doc.Statements {
+		v := cycloneDXVulnerability{
+			ID:      s.Vulnerability,
+			Updated: s.Timestamp,
+			Analysis: &cycloneDXAnalysis{
+				State:         cycloneDXStates[s.Status],
+				Justification: justificationsToCycloneDX[s.Justification],
+				Detail:        s.ImpactStatement,
+			},
+		}
+
+		if s.Status == StatusAffected {
+			v.Analysis.Detail = s.ActionStatement
+		}
+
+		if s.Status == StatusFixed {
+			v.Analysis.Response = []string{"update"}
+		}
+
+		for _, alias := range s.Aliases {
+			v.References = append(v.References, cycloneDXAlias{ID: alias})
+		}
+
+		refs := s.Subcomponents
+		if len(refs) == 0 {
+			refs = s.Products
+		}
+
+		for _, ref := range refs {
+			v.Affects = append(v.Affects, cycloneDXAffected{Ref: ref})
+		}
+
+		out.Vulnerabilities = append(out.Vulnerabilities, v)
+	}
+
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+
+	err := enc.Encode(out)
+	if err != nil {
+		return fmt.Errorf("failed to encode cyclonedx document: %v", err.Error())
+	}
+
+	return nil
+}
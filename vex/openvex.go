@@ -0,0 +1,217 @@
+package vex
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"time"
+)
+
+// OpenVEXContext is the context of the OpenVEX documents written by this
+// package
+const OpenVEXContext = "https://openvex.dev/ns/v0.2.0"
+
+type openVEXDocument struct {
+	Context    string             `json:"@context"`
+	ID         string             `json:"@id"`
+	Author     string             `json:"author"`
+	Timestamp  time.Time          `json:"timestamp"`
+	Version    int                `json:"version"`
+	Statements []openVEXStatement `json:"statements"`
+}
+
+type openVEXStatement struct {
+	Vulnerability   openVEXVulnerability `json:"vulnerability"`
+	Products        []openVEXProduct     `json:"products,omitempty"`
+	Status          Status               `json:"status"`
+	Justification   Justification        `json:"justification,omitempty"`
+	ImpactStatement string               `json:"impact_statement,omitempty"`
+	ActionStatement string               `json:"action_statement,omitempty"`
+	Timestamp       *time.Time           `json:"timestamp,omitempty"`
+}
+
+type openVEXVulnerability struct {
+	Name    string   `json:"name"`
+	Aliases []string `json:"aliases,omitempty"`
+}
+
+// UnmarshalJSON reads a vulnerability given either as an object or, as in
+// OpenVEX versions before 0.2.0, as a plain name
+func (v *openVEXVulnerability) UnmarshalJSON(b []byte) error {
+	var name string
+	if json.Unmarshal(b, &name) == nil {
+		v.Name = name
+		return nil
+	}
+
+	var vuln struct {
+		ID      string   `json:"@id"`
+		Name    string   `json:"name"`
+		Aliases []string `json:"aliases"`
+	}
+
+	err := json.Unmarshal(b, &vuln)
+	if err != nil {
+		return err
+	}
+
+	v.Name = vuln.Name
+	if v.Name == "" {
+		v.Name = vuln.ID
+	}
+
+	v.Aliases = vuln.Aliases
+	return nil
+}
+
+type openVEXProduct struct {
+	ID            string           `json:"@id"`
+	Subcomponents []openVEXProduct `json:"subcomponents,omitempty"`
+}
+
+// UnmarshalJSON reads a product given either as an object or, as in OpenVEX
+// versions before 0.2.0, as a plain identifier
+func (p *openVEXProduct) UnmarshalJSON(b []byte) error {
+	var id string
+	if json.Unmarshal(b, &id) == nil {
+		p.ID = id
+		return nil
+	}
+
+	var product struct {
+		ID          string `json:"@id"`
+		Identifiers struct {
+			PURL string `json:"purl"`
+		} `json:"identifiers"`
+		Subcomponents []openVEXProduct `json:"subcomponents"`
+	}
+
+	err := json.Unmarshal(b, &product)
+	if err != nil {
+		return err
+	}
+
+	p.ID = product.ID
+	if p.ID == "" {
+		p.ID = product.Identifiers.PURL
+	}
+
+	p.Subcomponents = product.Subcomponents
+	return nil
+}
+
+// ParseOpenVEX reads an OpenVEX document
+func ParseOpenVEX(r io.Reader) (*Document, error) {
+	var raw struct {
+		openVEXDocument
+		// statements of OpenVEX documents before 0.2.0 list subcomponents
+		// beside the products rather than within them
+		Statements []struct {
+			openVEXStatement
+			Subcomponents []openVEXProduct `json:"subcomponents"`
+		} `json:"statements"`
+	}
+
+	err := json.NewDecoder(r).Decode(&raw)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode openvex document: %v", err.Error())
+	}
+
+	doc := &Document{
+		ID:         raw.ID,
+		Author:     raw.Author,
+		Timestamp:  raw.Timestamp,
+		Version:    raw.Version,
+		Statements: []Statement{},
+	}
+
+	for _, s := range raw.Statements {
+		statement := Statement{
+			Vulnerability:   s.Vulnerability.Name,
+			Aliases:         s.Vulnerability.Aliases,
+			Status:          s.Status,
+			Justification:   s.Justification,
+			ImpactStatement: s.ImpactStatement,
+			ActionStatement: s.ActionStatement,
+			Timestamp:       s.Timestamp,
+		}
+
+		for _, p := range s.Products {
+			statement.Products = appendUnique(statement.Products, p.ID)
+			for _, sub := range p.Subcomponents {
+				statement.Subcomponents = appendUnique(statement.Subcomponents, sub.ID)
+			}
+		}
+
+		for _, sub := range s.Subcomponents {
+			statement.Subcomponents = appendUnique(statement.Subcomponents, sub.ID)
+		}
+
+		doc.Statements = append(doc.Statements, statement)
+	}
+
+	return doc, nil
+}
+
+// WriteOpenVEX writes the document in the OpenVEX format. The subcomponents
+// of a statement are listed under each of its products.
+func WriteOpenVEX(w io.Writer, doc *Document) error {
+	out := openVEXDocument{
+		Context:    OpenVEXContext,
+		ID:         doc.ID,
+		Author:     doc.Author,
+		Timestamp:  doc.Timestamp,
+		Version:    doc.Version,
+		Statements: []openVEXStatement{},
+	}
+
+	if out.Version == 0 {
+		out.Version = 1
+	}
+
+	for _, s := range doc.Statements {
+		subs := []openVEXProduct{}
+		for _, sub := range s.Subcomponents {
+			subs = append(subs, openVEXProduct{ID: sub})
+		}
+
+		statement := openVEXStatement{
+			Vulnerability:   openVEXVulnerability{Name: s.Vulnerability, Aliases: s.Aliases},
+			Status:          s.Status,
+			Justification:   s.Justification,
+			ImpactStatement: s.ImpactStatement,
+			ActionStatement: s.ActionStatement,
+			Timestamp:       s.Timestamp,
+		}
+
+		for _, p := range s.Products {
+			statement.Products = append(statement.Products, openVEXProduct{ID: p, Subcomponents: subs})
+		}
+
+		out.Statements = append(out.Statements, statement)
+	}
+
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+
+	err := enc.Encode(out)
+	if err != nil {
+		return fmt.Errorf("failed to encode openvex document: %v", err.Error())
+	}
+
+	return nil
+}
+
+func appendUnique(list []string, s string) []string {
+	if s == "" {
+		return list
+	}
+
+	for _, l := range list {
+		if l == s {
+			return list
+		}
+	}
+
+	return append(list, s)
+}
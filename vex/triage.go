@@ -0,0 +1,250 @@
+package vex
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/ion-channel/ionic/analyses"
+	"github.com/ion-channel/ionic/purl"
+)
+
+// Triage represents the decisions a team has made about the vulnerabilities
+// found in a project, kept alongside the project in a triage file
+type Triage struct {
+	Author  string        `json:"author"`
+	Product string        `json:"product,omitempty"`
+	Entries []TriageEntry `json:"triage"`
+}
+
+// TriageEntry represents the decision about a vulnerability. Components are
+// identified the same way as statement products, and an entry without
+// components applies to the vulnerability wherever it was found.
+type TriageEntry struct {
+	Vulnerability   string        `json:"vulnerability"`
+	Components      []string      `json:"components,omitempty"`
+	Status          Status        `json:"status"`
+	Justification   Justification `json:"justification,omitempty"`
+	ImpactStatement string        `json:"impact,omitempty"`
+	ActionStatement string        `json:"action,omitempty"`
+}
+
+// ParseTriage reads a triage file, returning an error if an entry has no
+// vulnerability or an unknown status or justification
+func ParseTriage(r io.Reader) (*Triage, error) {
+	var t Triage
+	err := json.NewDecoder(r).Decode(&t)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode triage file: %v", err.Error())
+	}
+
+	for ii, e := range t.Entries {
+		if e.Vulnerability == "" {
+			return nil, fmt.Errorf("triage entry %v has no vulnerability", ii)
+		}
+
+		if !validStatus(e.Status) {
+			return nil, fmt.Errorf("triage entry %v has an invalid status: %v", ii, e.Status)
+		}
+
+		if e.Justification != "" && !validJustification(e.Justification) {
+			return nil, fmt.Errorf("triage entry %v has an invalid justification: %v", ii, e.Justification)
+		}
+	}
+
+	return &t, nil
+}
+
+// Generate builds a VEX document from the vulnerabilities found by an
+// analysis and the decisions in a triage file. Every vulnerability found is
+// stated for the product, with the component it was found in as a
+// subcomponent. The product defaults to the package URL of the repository the
+// analysis was run against, or its project when the repository is not hosted
+// on GitHub, GitLab, or Bitbucket. Components are identified by their package
+// URLs when the type of the dependency they were found in is known. Vulnerabilities without a
+// triage entry are under investigation. Triage entries that match no finding
+// are kept as statements of their own.
+func Generate(a *analyses.Analysis, t *Triage) (*Document, error) {
+	if a == nil {
+		return nil, fmt.Errorf("no analysis given")
+	}
+
+	if t == nil {
+		t = &Triage{}
+	}
+
+	results, err := a.Vulnerabilities()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get vulnerability results: %v", err.Error())
+	}
+
+	product := t.Product
+	if product == "" {
+		product = sourcePURL(a.Source, a.TriggerHash)
+	}
+
+	if product == "" {
+		product = a.ProjectID
+	}
+
+	doc := &Document{
+		ID:         "urn:uuid:" + uuid.New().String(),
+		Author:     t.Author,
+		Timestamp:  time.Now().UTC(),
+		Version:    1,
+		Statements: []Statement{},
+	}
+
+	// statements are merged by vulnerability and triage entry, with -1 for
+	// findings that have no entry
+	type key struct {
+		vulnerability string
+		entry         int
+	}
+
+	index := map[key]int{}
+	used := map[int]bool{}
+
+	for _, p := range results.Vulnerabilities {
+		component := resultComponents(p, nil)[0]
+		id := componentID(component)
+
+		for _, v := range p.Vulnerabilities {
+			entry := t.find(v.ExternalID, v.Aliases, component)
+			k := key{v.ExternalID, entry}
+
+			if i, ok := index[k]; ok {
+				doc.Statements[i].Subcomponents = appendUnique(doc.Statements[i].Subcomponents, id)
+				continue
+			}
+
+			s := Statement{
+				Vulnerability: v.ExternalID,
+				Aliases:       v.Aliases,
+				Products:      appendUnique(nil, product),
+				Subcomponents: appendUnique(nil, id),
+				Status:        StatusUnderInvestigation,
+			}
+
+			if entry >= 0 {
+				used[entry] = true
+				t.Entries[entry].apply(&s)
+			}
+
+			index[k] = len(doc.Statements)
+			doc.Statements = append(doc.Statements, s)
+		}
+	}
+
+	for ii, e := range t.Entries {
+		if used[ii] {
+			continue
+		}
+
+		s := Statement{
+			Vulnerability: e.Vulnerability,
+			Products:      appendUnique(nil, product),
+			Subcomponents: e.Components,
+		}
+
+		e.apply(&s)
+		doc.Statements = append(doc.Statements, s)
+	}
+
+	err = doc.Validate()
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate vex document: %v", err.Error())
+	}
+
+	return doc, nil
+}
+
+// find returns the index of the triage entry for a vulnerability found in the
+// component, or -1 if there is none
+func (t *Triage) find(vulnerability string, aliases []string, c Component) int {
+	for ii, e := range t.Entries {
+		s := Statement{Vulnerability: e.Vulnerability}
+		if !s.matchesVulnerability(vulnerability, aliases) {
+			continue
+		}
+
+		if len(e.Components) == 0 {
+			return ii
+		}
+
+		for _, id := range e.Components {
+			if matchComponent(id, c) {
+				return ii
+			}
+		}
+	}
+
+	return -1
+}
+
+func (e TriageEntry) apply(s *Statement) {
+	s.Status = e.Status
+	s.Justification = e.Justification
+	s.ImpactStatement = e.ImpactStatement
+	s.ActionStatement = e.ActionStatement
+}
+
+// componentID returns the package URL of a component, or its
+// "org/name@version" identifier when its package URL is not known
+func componentID(c Component) string {
+	if c.PURL != "" {
+		return c.PURL
+	}
+
+	id := componentName(c.Org, c.Name)
+	if c.Version != "" {
+		id += "@" + c.Version
+	}
+
+	return id
+}
+
+// repositoryHosts maps the hosts of source repositories to their package URL
+// types
+var repositoryHosts = map[string]string{
+	"github.com":    purl.TypeGitHub,
+	"gitlab.com":    purl.TypeGitLab,
+	"bitbucket.org": purl.TypeBitbucket,
+}
+
+// sourcePURL returns the package URL of a repository given by its URL, such as
+// https://github.com/ion-channel/ionic.git or git@github.com:ion-channel/ionic,
+// at the given commit, or an empty string if it is not hosted somewhere with a
+// package URL type
+func sourcePURL(source, commit string) string {
+	source = strings.TrimSpace(source)
+	if strings.HasPrefix(source, "git@") {
+		source = "ssh://" + strings.Replace(strings.TrimPrefix(source, "git@"), ":", "/", 1)
+	}
+
+	u, err := url.Parse(source)
+	if err != nil {
+		return ""
+	}
+
+	typ, ok := repositoryHosts[strings.TrimPrefix(strings.ToLower(u.Hostname()), "www.")]
+	if !ok {
+		return ""
+	}
+
+	parts := strings.Split(strings.Trim(strings.TrimSuffix(u.Path, ".git"), "/"), "/")
+	if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+		return ""
+	}
+
+	p, err := purl.New(typ, parts[0], parts[1], commit, nil, "")
+	if err != nil {
+		return ""
+	}
+
+	return p.String()
+}
@@ -0,0 +1,335 @@
+// Package vex reads and writes Vulnerability Exploitability eXchange (VEX)
+// documents in the OpenVEX and CycloneDX formats, and applies their
+// statements to vulnerability findings to suppress or justify them.
+package vex
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"strings"
+	"time"
+
+	"github.com/ion-channel/ionic/purl"
+)
+
+// Status is the status of a vulnerability for a product
+type Status string
+
+const (
+	// StatusNotAffected means the product is not affected by the vulnerability
+	StatusNotAffected Status = "not_affected"
+	// StatusAffected means the product is affected by the vulnerability and
+	// action is required
+	StatusAffected Status = "affected"
+	// StatusFixed means the product contains a fix for the vulnerability
+	StatusFixed Status = "fixed"
+	// StatusUnderInvestigation means it is not yet known whether the product
+	// is affected by the vulnerability
+	StatusUnderInvestigation Status = "under_investigation"
+)
+
+// Justification is the reason a product is not affected by a vulnerability
+type Justification string
+
+const (
+	// JustificationComponentNotPresent means the vulnerable component is not
+	// included in the product
+	JustificationComponentNotPresent Justification = "component_not_present"
+	// JustificationVulnerableCodeNotPresent means the vulnerable code was
+	// removed or never included in the component
+	JustificationVulnerableCodeNotPresent Justification = "vulnerable_code_not_present"
+	// JustificationVulnerableCodeNotInExecutePath means the vulnerable code
+	// can not be executed by the product
+	JustificationVulnerableCodeNotInExecutePath Justification = "vulnerable_code_not_in_execute_path"
+	// JustificationVulnerableCodeCannotBeControlledByAdversary means the
+	// vulnerable code can not be reached with attacker controlled input
+	JustificationVulnerableCodeCannotBeControlledByAdversary Justification = "vulnerable_code_cannot_be_controlled_by_adversary"
+	// JustificationInlineMitigationsAlreadyExist means the product has built
+	// in protections against the vulnerability
+	JustificationInlineMitigationsAlreadyExist Justification = "inline_mitigations_already_exist"
+)
+
+var statuses = []Status{StatusNotAffected, StatusAffected, StatusFixed, StatusUnderInvestigation}
+
+var justifications = []Justification{
+	JustificationComponentNotPresent,
+	JustificationVulnerableCodeNotPresent,
+	JustificationVulnerableCodeNotInExecutePath,
+	JustificationVulnerableCodeCannotBeControlledByAdversary,
+	JustificationInlineMitigationsAlreadyExist,
+}
+
+// Suppresses returns true if findings with the status should be removed from
+// the vulnerabilities reported for a product
+func (s Status) Suppresses() bool {
+	return s == StatusNotAffected || s == StatusFixed
+}
+
+// Document represents a VEX document, independent of the format it was read
+// from or will be written to
+type Document struct {
+	ID         string      `json:"id"`
+	Author     string      `json:"author"`
+	Timestamp  time.Time   `json:"timestamp"`
+	Version    int         `json:"version"`
+	Statements []Statement `json:"statements"`
+}
+
+// Statement represents the status of a vulnerability for a set of products.
+// Products and subcomponents are identified by package URLs, Ion Channel
+// project IDs, or "org/name@version" strings, where the org and version may
+// be left out to match any. A statement without products applies to every
+// product. When subcomponents are given, the statement only applies to
+// findings in those components of the products.
+type Statement struct {
+	Vulnerability   string        `json:"vulnerability"`
+	Aliases         []string      `json:"aliases,omitempty"`
+	Products        []string      `json:"products,omitempty"`
+	Subcomponents   []string      `json:"subcomponents,omitempty"`
+	Status          Status        `json:"status"`
+	Justification   Justification `json:"justification,omitempty"`
+	ImpactStatement string        `json:"impact_statement,omitempty"`
+	ActionStatement string        `json:"action_statement,omitempty"`
+	Timestamp       *time.Time    `json:"timestamp,omitempty"`
+}
+
+// Component identifies a product, or a component of a product, that a
+// finding was reported against
+type Component struct {
+	ID      string
+	PURL    string
+	Org     string
+	Name    string
+	Version string
+}
+
+// Validate returns an error if a statement of the document has no
+// vulnerability, an unknown status, or is not affected without a
+// justification or impact statement
+func (d *Document) Validate() error {
+	for ii, s := range d.Statements {
+		if s.Vulnerability == "" {
+			return fmt.Errorf("statement %v has no vulnerability", ii)
+		}
+
+		if !validStatus(s.Status) {
+			return fmt.Errorf("statement %v has an invalid status: %v", ii, s.Status)
+		}
+
+		if s.Justification != "" && !validJustification(s.Justification) {
+			return fmt.Errorf("statement %v has an invalid justification: %v", ii, s.Justification)
+		}
+
+		if s.Status == StatusNotAffected && s.Justification == "" && s.ImpactStatement == "" {
+			return fmt.Errorf("statement %v is not affected without a justification or impact statement", ii)
+		}
+	}
+
+	return nil
+}
+
+// Find returns the statement that applies to a vulnerability, identified by
+// its ID and aliases, found in the given components, or nil if none applies.
+// The components are the product followed by the component of it the
+// vulnerability was found in. When several statements apply, the most recent
+// one wins, and statements later in the document win ties.
+func (d *Document) Find(vulnerability string, aliases []string, components ...Component) *Statement {
+	var found *Statement
+	var foundAt time.Time
+
+	for ii := range d.Statements {
+		s := &d.Statements[ii]
+		if !s.matchesVulnerability(vulnerability, aliases) || !s.matchesComponents(components) {
+			continue
+		}
+
+		at := d.Timestamp
+		if s.Timestamp != nil {
+			at = *s.Timestamp
+		}
+
+		if found == nil || !at.Before(foundAt) {
+			found = s
+			foundAt = at
+		}
+	}
+
+	return found
+}
+
+// Parse reads a VEX document in either the OpenVEX or CycloneDX format
+func Parse(r io.Reader) (*Document, error) {
+	b, err := ioutil.ReadAll(r)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read vex document: %v", err.Error())
+	}
+
+	var probe struct {
+		Context    string            `json:"@context"`
+		BOMFormat  string            `json:"bomFormat"`
+		Statements []json.RawMessage `json:"statements"`
+	}
+
+	err = json.Unmarshal(b, &probe)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode vex document: %v", err.Error())
+	}
+
+	switch {
+	case probe.BOMFormat != "":
+		return ParseCycloneDX(bytes.NewReader(b))
+	case probe.Context != "" || probe.Statements != nil:
+		return ParseOpenVEX(bytes.NewReader(b))
+	}
+
+	return nil, fmt.Errorf("unrecognized vex document format")
+}
+
+// matchesVulnerability returns true if the statement is about the
+// vulnerability with the given ID or aliases
+func (s *Statement) matchesVulnerability(id string, aliases []string) bool {
+	ids := append([]string{s.Vulnerability}, s.Aliases...)
+	for _, a := range ids {
+		if strings.EqualFold(a, id) {
+			return true
+		}
+
+		for _, alias := range aliases {
+			if strings.EqualFold(a, alias) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// matchesComponents returns true if any of the statement's products match
+// the components, and, when it has subcomponents, one of them matches the
+// last component
+func (s *Statement) matchesComponents(components []Component) bool {
+	if len(s.Products) > 0 {
+		matched := false
+		for _, p := range s.Products {
+			for _, c := range components {
+				if matchComponent(p, c) {
+					matched = true
+				}
+			}
+		}
+
+		if !matched {
+			return false
+		}
+	}
+
+	if len(s.Subcomponents) == 0 {
+		return true
+	}
+
+	if len(components) == 0 {
+		return false
+	}
+
+	for _, sub := range s.Subcomponents {
+		if matchComponent(sub, components[len(components)-1]) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// matchComponent returns true if the product identifier refers to the
+// component
+func matchComponent(id string, c Component) bool {
+	id = strings.TrimSpace(id)
+	if id == "" {
+		return false
+	}
+
+	if id == "*" || id == c.ID || (c.PURL != "" && strings.EqualFold(id, c.PURL)) {
+		return true
+	}
+
+	org, name, version := splitIdentifier(id)
+	if name == "" || !sameName(org, name, c.Org, c.Name) {
+		return false
+	}
+
+	if org != "" && c.Org != "" && !strings.EqualFold(org, c.Org) {
+		return false
+	}
+
+	return version == "" || version == c.Version
+}
+
+// splitIdentifier splits a package URL or "org/name@version" identifier into
+// its org, name, and version. An invalid package URL has no name.
+func splitIdentifier(id string) (string, string, string) {
+	if strings.HasPrefix(id, "pkg:") {
+		p, err := purl.Parse(id)
+		if err != nil {
+			return "", "", ""
+		}
+
+		dep := p.Dependency()
+		return dep.Org, dep.Name, dep.Version
+	}
+
+	version := ""
+	if i := strings.LastIndex(id, "@"); i > 0 {
+		id, version = id[:i], id[i+1:]
+	}
+
+	org := ""
+	if i := strings.LastIndex(id, "/"); i >= 0 {
+		org, id = id[:i], id[i+1:]
+	}
+
+	return strings.TrimPrefix(org, "@"), id, version
+}
+
+// sameName returns true if the names are equal, either by themselves or
+// qualified by their orgs, so that a Go module named by its full path matches
+// the same module split into an org and name
+func sameName(org, name, otherOrg, otherName string) bool {
+	if strings.EqualFold(name, otherName) {
+		return true
+	}
+
+	return strings.EqualFold(qualifiedName(org, name), qualifiedName(otherOrg, otherName))
+}
+
+// qualifiedName returns the name prefixed with its org, unless it already is
+func qualifiedName(org, name string) string {
+	org, name = strings.TrimPrefix(org, "@"), strings.TrimPrefix(name, "@")
+	if org == "" || strings.HasPrefix(strings.ToLower(name), strings.ToLower(org)+"/") {
+		return name
+	}
+
+	return org + "/" + name
+}
+
+func validStatus(s Status) bool {
+	for _, v := range statuses {
+		if v == s {
+			return true
+		}
+	}
+
+	return false
+}
+
+func validJustification(j Justification) bool {
+	for _, v := range justifications {
+		if v == j {
+			return true
+		}
+	}
+
+	return false
+}
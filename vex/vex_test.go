@@ -0,0 +1,357 @@
+package vex
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/franela/goblin"
+	"github.com/ion-channel/ionic/analyses"
+	. "github.com/onsi/gomega"
+)
+
+func TestVEX(t *testing.T) {
+	g := goblin.Goblin(t)
+	RegisterFailHandler(func(m string, _ ...int) { g.Fail(m) })
+
+	g.Describe("Parsing", func() {
+		g.It("should parse an openvex document", func() {
+			doc, err := Parse(strings.NewReader(sampleOpenVEX))
+			Expect(err).To(BeNil())
+			Expect(doc.Author).To(Equal("Security Team"))
+			Expect(doc.Statements).To(HaveLen(2))
+
+			s := doc.Statements[0]
+			Expect(s.Vulnerability).To(Equal("CVE-2021-44228"))
+			Expect(s.Aliases).To(Equal([]string{"GHSA-jfh8-c2jp-5v3q"}))
+			Expect(s.Products).To(Equal([]string{"pkg:oci/app@sha256%3A1234"}))
+			Expect(s.Subcomponents).To(Equal([]string{"pkg:maven/org.apache.logging.log4j/log4j-core@2.14.1"}))
+			Expect(s.Status).To(Equal(StatusNotAffected))
+			Expect(s.Justification).To(Equal(JustificationVulnerableCodeNotInExecutePath))
+			Expect(doc.Validate()).To(BeNil())
+		})
+
+		g.It("should parse an openvex document before 0.2.0", func() {
+			doc, err := Parse(strings.NewReader(`{
+  "@context": "https://openvex.dev/ns",
+  "statements": [{
+    "vulnerability": "CVE-2023-0001",
+    "products": ["pkg:npm/app@1.0.0"],
+    "subcomponents": ["pkg:npm/%40scope/lib@2.0.0"],
+    "status": "fixed"
+  }]
+}`))
+			Expect(err).To(BeNil())
+			Expect(doc.Statements[0].Vulnerability).To(Equal("CVE-2023-0001"))
+			Expect(doc.Statements[0].Products).To(Equal([]string{"pkg:npm/app@1.0.0"}))
+			Expect(doc.Statements[0].Subcomponents).To(Equal([]string{"pkg:npm/%40scope/lib@2.0.0"}))
+		})
+
+		g.It("should parse a cyclonedx vex document", func() {
+			doc, err := Parse(strings.NewReader(sampleCycloneDX))
+			Expect(err).To(BeNil())
+			Expect(doc.Author).To(Equal("Security Team"))
+			Expect(doc.Statements).To(HaveLen(3))
+			Expect(doc.Statements[0].Status).To(Equal(StatusNotAffected))
+			Expect(doc.Statements[0].Justification).To(Equal(JustificationVulnerableCodeNotPresent))
+			Expect(doc.Statements[0].ImpactStatement).To(Equal("JndiLookup class removed"))
+			Expect(doc.Statements[0].Products).To(Equal([]string{"pkg:maven/org.apache.logging.log4j/log4j-core@2.14.1"}))
+			Expect(doc.Statements[1].Status).To(Equal(StatusAffected))
+			Expect(doc.Statements[1].ActionStatement).To(Equal("upgrade to 4.17.19"))
+			Expect(doc.Statements[2].Status).To(Equal(StatusUnderInvestigation))
+		})
+
+		g.It("should reject documents of other formats", func() {
+			_, err := Parse(strings.NewReader(`{"spdxVersion": "SPDX-2.2"}`))
+			Expect(err).NotTo(BeNil())
+		})
+
+		g.It("should validate statements", func() {
+			doc := &Document{Statements: []Statement{{Vulnerability: "CVE-1", Status: StatusNotAffected}}}
+			Expect(doc.Validate()).NotTo(BeNil())
+
+			doc.Statements[0].Justification = "because"
+			Expect(doc.Validate()).NotTo(BeNil())
+
+			doc.Statements[0].Justification = JustificationComponentNotPresent
+			Expect(doc.Validate()).To(BeNil())
+
+			doc.Statements[0].Status = "ignored"
+			Expect(doc.Validate()).NotTo(BeNil())
+		})
+	})
+
+	g.Describe("Writing", func() {
+		g.It("should round trip through openvex", func() {
+			doc, err := Parse(strings.NewReader(sampleOpenVEX))
+			Expect(err).To(BeNil())
+
+			buf := &bytes.Buffer{}
+			Expect(WriteOpenVEX(buf, doc)).To(BeNil())
+			Expect(buf.String()).To(ContainSubstring(OpenVEXContext))
+
+			again, err := Parse(buf)
+			Expect(err).To(BeNil())
+			Expect(again.Statements).To(Equal(doc.Statements))
+		})
+
+		g.It("should round trip through cyclonedx", func() {
+			doc, err := Parse(strings.NewReader(sampleOpenVEX))
+			Expect(err).To(BeNil())
+
+			buf := &bytes.Buffer{}
+			Expect(WriteCycloneDX(buf, doc)).To(BeNil())
+
+			var raw map[string]interface{}
+			Expect(json.Unmarshal(buf.Bytes(), &raw)).To(BeNil())
+			Expect(raw["bomFormat"]).To(Equal("CycloneDX"))
+
+			again, err := Parse(bytes.NewReader(buf.Bytes()))
+			Expect(err).To(BeNil())
+			Expect(again.Statements).To(HaveLen(2))
+			Expect(again.Statements[0].Status).To(Equal(StatusNotAffected))
+			Expect(again.Statements[0].Justification).To(Equal(JustificationVulnerableCodeNotInExecutePath))
+			Expect(again.Statements[0].Products).To(Equal(doc.Statements[0].Subcomponents))
+			Expect(again.Statements[1].Status).To(Equal(StatusFixed))
+		})
+	})
+
+	g.Describe("Applying", func() {
+		var a analyses.Analysis
+		var doc *Document
+
+		g.BeforeEach(func() {
+			a = analyses.Analysis{}
+			Expect(json.Unmarshal([]byte(sampleAnalysis), &a)).To(Succeed())
+
+			var err error
+			doc, err = Parse(strings.NewReader(sampleCycloneDX))
+			Expect(err).To(BeNil())
+		})
+
+		g.It("should annotate vulnerability results", func() {
+			r, err := a.Vulnerabilities()
+			Expect(err).To(BeNil())
+
+			doc.AnnotateResults(r)
+			Expect(r.Vulnerabilities[0].Vulnerabilities[0].VEXStatus).To(Equal("not_affected"))
+			Expect(r.Vulnerabilities[0].Vulnerabilities[0].VEXJustification).To(Equal("vulnerable_code_not_present"))
+			Expect(r.Vulnerabilities[0].Vulnerabilities[0].VEXStatement).To(Equal("JndiLookup class removed"))
+			Expect(r.Vulnerabilities[0].Vulnerabilities[1].VEXStatus).To(Equal(""))
+			Expect(r.Vulnerabilities[1].Vulnerabilities[0].VEXStatus).To(Equal("affected"))
+			Expect(r.Vulnerabilities[1].Vulnerabilities[0].VEXStatement).To(Equal("upgrade to 4.17.19"))
+		})
+
+		g.It("should filter suppressed vulnerability results", func() {
+			r, err := a.Vulnerabilities()
+			Expect(err).To(BeNil())
+
+			suppressed := doc.FilterResults(r)
+			Expect(suppressed).To(HaveLen(1))
+			Expect(suppressed[0].Vulnerability).To(Equal("CVE-2021-44228"))
+			Expect(suppressed[0].Component).To(Equal("org.apache.logging.log4j/log4j-core"))
+			Expect(suppressed[0].Statement.Justification).To(Equal(JustificationVulnerableCodeNotPresent))
+
+			Expect(r.Vulnerabilities).To(HaveLen(2))
+			Expect(r.Vulnerabilities[0].Vulnerabilities).To(HaveLen(1))
+			Expect(r.Vulnerabilities[0].Vulnerabilities[0].ExternalID).To(Equal("CVE-2021-45046"))
+			Expect(r.Meta.VulnerabilityCount).To(Equal(2))
+		})
+
+		g.It("should match statements by alias", func() {
+			doc := &Document{Statements: []Statement{
+				{Vulnerability: "GHSA-jfh8-c2jp-5v3q", Status: StatusFixed},
+			}}
+
+			r, err := a.Vulnerabilities()
+			Expect(err).To(BeNil())
+			r.Vulnerabilities[0].Vulnerabilities[0].Aliases = []string{"GHSA-jfh8-c2jp-5v3q"}
+
+			Expect(doc.FilterResults(r)).To(HaveLen(1))
+		})
+
+		g.It("should prefer the most recent statement", func() {
+			doc, err := Parse(strings.NewReader(`{
+  "@context": "https://openvex.dev/ns/v0.2.0",
+  "timestamp": "2023-01-01T00:00:00Z",
+  "statements": [
+    {"vulnerability": {"name": "CVE-1"}, "status": "fixed", "timestamp": "2023-03-01T00:00:00Z"},
+    {"vulnerability": {"name": "CVE-1"}, "status": "affected", "timestamp": "2023-02-01T00:00:00Z"},
+    {"vulnerability": {"name": "CVE-2"}, "status": "affected"},
+    {"vulnerability": {"name": "CVE-2"}, "status": "under_investigation"}
+  ]
+}`))
+			Expect(err).To(BeNil())
+			Expect(doc.Find("CVE-1", nil).Status).To(Equal(StatusFixed))
+			Expect(doc.Find("CVE-2", nil).Status).To(Equal(StatusUnderInvestigation))
+			Expect(doc.Find("CVE-3", nil)).To(BeNil())
+		})
+
+		g.It("should match package URLs whose namespaces contain slashes", func() {
+			doc := &Document{Statements: []Statement{
+				{Vulnerability: "GO-2022-1059", Products: []string{"pkg:golang/golang.org/x/text@v0.3.7"}, Status: StatusNotAffected},
+				{Vulnerability: "CVE-2022-0001", Products: []string{"pkg:swift/github.com/apple/swift-nio@2.40.0"}, Status: StatusFixed},
+			}}
+
+			Expect(doc.Find("GO-2022-1059", nil, Component{Name: "golang.org/x/text", Version: "v0.3.7"})).NotTo(BeNil())
+			Expect(doc.Find("GO-2022-1059", nil, Component{Org: "golang.org/x", Name: "text", Version: "v0.3.7"})).NotTo(BeNil())
+			Expect(doc.Find("GO-2022-1059", nil, Component{Name: "golang.org/x/text", Version: "v0.3.8"})).To(BeNil())
+			Expect(doc.Find("GO-2022-1059", nil, Component{Name: "text", Org: "example.com/x", Version: "v0.3.7"})).To(BeNil())
+			Expect(doc.Find("CVE-2022-0001", nil, Component{Org: "github.com/apple", Name: "swift-nio", Version: "2.40.0"})).NotTo(BeNil())
+			Expect(doc.Find("CVE-2022-0001", nil, Component{Name: "github.com/apple/swift-nio", Version: "2.40.0"})).NotTo(BeNil())
+		})
+
+		g.It("should filter and annotate export data", func() {
+			data := []analyses.VulnerabilityExportData{
+				{ProjectID: "p1", ExternalID: "CVE-2021-44228", Dependency: "log4j-core", DependencyVersion: "2.14.1"},
+				{ProjectID: "p1", ExternalID: "CVE-2021-44228", Dependency: "log4j-core", DependencyVersion: "2.15.0"},
+				{ProjectID: "p1", ExternalID: "CVE-2020-8203", Dependency: "lodash", DependencyVersion: "4.17.15"},
+			}
+
+			kept, suppressed := doc.FilterExportData(data)
+			Expect(suppressed).To(HaveLen(1))
+			Expect(suppressed[0].Version).To(Equal("2.14.1"))
+			Expect(kept).To(HaveLen(2))
+			Expect(kept[0].VEXStatus).To(Equal(""))
+			Expect(kept[1].VEXStatus).To(Equal("affected"))
+
+			doc.AnnotateExportData(data)
+			Expect(data[0].VEXStatus).To(Equal("not_affected"))
+		})
+	})
+
+	g.Describe("Generating", func() {
+		g.It("should generate a document from an analysis and triage file", func() {
+			var a analyses.Analysis
+			Expect(json.Unmarshal([]byte(sampleAnalysis), &a)).To(Succeed())
+
+			triage, err := ParseTriage(strings.NewReader(`{
+  "author": "Security Team",
+  "product": "pkg:oci/app",
+  "triage": [
+    {"vulnerability": "CVE-2021-44228", "components": ["log4j-core"], "status": "not_affected", "justification": "vulnerable_code_not_present", "impact": "JndiLookup class removed"},
+    {"vulnerability": "CVE-2022-0001", "status": "fixed"}
+  ]
+}`))
+			Expect(err).To(BeNil())
+
+			doc, err := Generate(&a, triage)
+			Expect(err).To(BeNil())
+			Expect(doc.Author).To(Equal("Security Team"))
+			Expect(doc.Statements).To(HaveLen(4))
+
+			Expect(doc.Statements[0].Vulnerability).To(Equal("CVE-2021-44228"))
+			Expect(doc.Statements[0].Status).To(Equal(StatusNotAffected))
+			Expect(doc.Statements[0].Products).To(Equal([]string{"pkg:oci/app"}))
+			Expect(doc.Statements[0].Subcomponents).To(Equal([]string{"pkg:maven/org.apache.logging.log4j/log4j-core@2.14.1"}))
+
+			Expect(doc.Statements[1].Vulnerability).To(Equal("CVE-2021-45046"))
+			Expect(doc.Statements[1].Status).To(Equal(StatusUnderInvestigation))
+
+			Expect(doc.Statements[2].Vulnerability).To(Equal("CVE-2020-8203"))
+			Expect(doc.Statements[2].Subcomponents).To(Equal([]string{"lodash@4.17.15"}))
+			Expect(doc.Statements[3].Vulnerability).To(Equal("CVE-2022-0001"))
+			Expect(doc.Statements[3].Status).To(Equal(StatusFixed))
+
+			r, err := a.Vulnerabilities()
+			Expect(err).To(BeNil())
+			Expect(doc.FilterResults(r, Component{ID: "pkg:oci/app"})).To(HaveLen(1))
+		})
+
+		g.It("should identify the product by the package URL of its repository", func() {
+			var a analyses.Analysis
+			Expect(json.Unmarshal([]byte(sampleAnalysis), &a)).To(Succeed())
+
+			a.Source, a.TriggerHash = "https://github.com/Ion-Channel/App.git", "4f2c9e1"
+			doc, err := Generate(&a, nil)
+			Expect(err).To(BeNil())
+			Expect(doc.Statements[0].Products).To(Equal([]string{"pkg:github/ion-channel/app@4f2c9e1"}))
+
+			a.Source, a.TriggerHash = "git@gitlab.com:ion-channel/app.git", ""
+			doc, err = Generate(&a, nil)
+			Expect(err).To(BeNil())
+			Expect(doc.Statements[0].Products).To(Equal([]string{"pkg:gitlab/ion-channel/app"}))
+
+			a.Source = "https://git.example.com/ion-channel/app.git"
+			doc, err = Generate(&a, nil)
+			Expect(err).To(BeNil())
+			Expect(doc.Statements[0].Products).To(Equal([]string{"p1"}))
+		})
+
+		g.It("should reject invalid triage files", func() {
+			_, err := ParseTriage(strings.NewReader(`{"triage": [{"vulnerability": "CVE-1", "status": "ignored"}]}`))
+			Expect(err).NotTo(BeNil())
+
+			_, err = ParseTriage(strings.NewReader(`{"triage": [{"status": "fixed"}]}`))
+			Expect(err).NotTo(BeNil())
+		})
+
+		g.It("should fail without vulnerability results", func() {
+			_, err := Generate(&analyses.Analysis{}, nil)
+			Expect(err).NotTo(BeNil())
+		})
+	})
+}
+
+const sampleOpenVEX = `{
+  "@context": "https://openvex.dev/ns/v0.2.0",
+  "@id": "https://example.com/vex/1",
+  "author": "Security Team",
+  "timestamp": "2023-01-09T00:00:00Z",
+  "version": 1,
+  "statements": [
+    {
+      "vulnerability": {"name": "CVE-2021-44228", "aliases": ["GHSA-jfh8-c2jp-5v3q"]},
+      "products": [
+        {"@id": "pkg:oci/app@sha256%3A1234", "subcomponents": [{"@id": "pkg:maven/org.apache.logging.log4j/log4j-core@2.14.1"}]}
+      ],
+      "status": "not_affected",
+      "justification": "vulnerable_code_not_in_execute_path",
+      "impact_statement": "JNDI lookups are disabled"
+    },
+    {
+      "vulnerability": {"name": "CVE-2020-8203"},
+      "products": [{"@id": "pkg:npm/lodash@4.17.15"}],
+      "status": "fixed"
+    }
+  ]
+}`
+
+const sampleCycloneDX = `{
+  "bomFormat": "CycloneDX",
+  "specVersion": "1.4",
+  "version": 1,
+  "metadata": {"timestamp": "2023-01-09T00:00:00Z", "authors": [{"name": "Security Team"}]},
+  "vulnerabilities": [
+    {
+      "id": "CVE-2021-44228",
+      "analysis": {"state": "not_affected", "justification": "code_not_present", "detail": "JndiLookup class removed"},
+      "affects": [{"ref": "pkg:maven/org.apache.logging.log4j/log4j-core@2.14.1"}]
+    },
+    {
+      "id": "CVE-2020-8203",
+      "analysis": {"state": "exploitable", "detail": "upgrade to 4.17.19"},
+      "affects": [{"ref": "lodash"}]
+    },
+    {
+      "id": "CVE-2023-0001"
+    }
+  ]
+}`
+
+const sampleAnalysis = `{
+  "id": "a1",
+  "project_id": "p1",
+  "scan_summaries": [
+    {"id": "s1", "results": {"type": "vulnerability", "data": {"vulnerabilities": [
+      {"org": "org.apache.logging.log4j", "name": "log4j-core", "version": "2.14.1", "query": {"type": "maven"}, "vulnerabilities": [
+        {"external_id": "CVE-2021-44228", "title": "Log4Shell"},
+        {"external_id": "CVE-2021-45046", "title": "Log4j DoS"}
+      ]},
+      {"name": "lodash", "version": "4.17.15", "vulnerabilities": [
+        {"external_id": "CVE-2020-8203", "title": "Prototype pollution"}
+      ]}
+    ], "meta": {"vulnerability_count": 3}}}}
+  ]
+}`
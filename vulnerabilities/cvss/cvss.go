@@ -0,0 +1,381 @@
+// Package cvss parses and validates Common Vulnerability Scoring System
+// vectors, versions 2.0, 3.0, 3.1, and 4.0, and calculates their scores and
+// severities as defined by each version's specification.
+package cvss
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Version is a string enum of the supported CVSS versions
+type Version string
+
+const (
+	// Version20 is CVSS version 2.0
+	Version20 Version = "2.0"
+	// Version30 is CVSS version 3.0
+	Version30 Version = "3.0"
+	// Version31 is CVSS version 3.1
+	Version31 Version = "3.1"
+	// Version40 is CVSS version 4.0
+	Version40 Version = "4.0"
+)
+
+// Severity is a string enum of the qualitative severity ratings of a score
+type Severity string
+
+const (
+	// SeverityNone is the severity of a score of 0.0
+	SeverityNone Severity = "none"
+	// SeverityLow is the severity of a score from 0.1 to 3.9
+	SeverityLow Severity = "low"
+	// SeverityMedium is the severity of a score from 4.0 to 6.9
+	SeverityMedium Severity = "medium"
+	// SeverityHigh is the severity of a score from 7.0 to 8.9
+	SeverityHigh Severity = "high"
+	// SeverityCritical is the severity of a score from 9.0 to 10.0
+	SeverityCritical Severity = "critical"
+)
+
+// metric is the definition of a metric of a CVSS version
+type metric struct {
+	key    string
+	values []string
+	// required is whether the metric must be in a vector. Optional metrics
+	// default to their first value.
+	required bool
+}
+
+// Vector is a parsed CVSS vector. The zero value is not valid; use Parse.
+type Vector struct {
+	version Version
+	metrics map[string]string
+}
+
+// Parse parses a CVSS vector string, such as
+// "CVSS:3.1/AV:N/AC:L/PR:N/UI:N/S:U/C:H/I:H/A:H". The version is read from
+// the "CVSS:" prefix. Version 2.0 vectors have no prefix, and may be wrapped
+// in parentheses. Metrics can be in any order and are matched without regard
+// to case. It returns an error if the version is not supported, a metric or
+// value is not defined by the version, a metric is repeated, or a base
+// metric is missing.
+func Parse(vector string) (*Vector, error) {
+	s := strings.TrimSpace(vector)
+	s = strings.TrimSuffix(strings.TrimPrefix(s, "("), ")")
+
+	var version Version
+	if strings.HasPrefix(strings.ToUpper(s), "CVSS:") {
+		i := strings.Index(s, "/")
+		if i < 0 {
+			return nil, fmt.Errorf("invalid CVSS vector: %v", vector)
+		}
+
+		version = Version(s[len("CVSS:"):i])
+		s = s[i+1:]
+	} else {
+		version = Version20
+	}
+
+	defs, ok := metricDefinitions[version]
+	if !ok {
+		return nil, fmt.Errorf("unsupported CVSS version: %v", version)
+	}
+
+	v := &Vector{version: version, metrics: make(map[string]string)}
+	for _, part := range strings.Split(s, "/") {
+		kv := strings.SplitN(part, ":", 2)
+		if len(kv) != 2 {
+			return nil, fmt.Errorf("invalid CVSS %v metric: %v", version, part)
+		}
+
+		def, value, err := lookupMetric(defs, version, kv[0], kv[1])
+		if err != nil {
+			return nil, err
+		}
+
+		if _, ok := v.metrics[def.key]; ok {
+			return nil, fmt.Errorf("repeated CVSS %v metric: %v", version, def.key)
+		}
+
+		v.metrics[def.key] = value
+	}
+
+	for _, def := range defs {
+		if def.required && v.metrics[def.key] == "" {
+			return nil, fmt.Errorf("missing CVSS %v metric: %v", version, def.key)
+		}
+	}
+
+	return v, nil
+}
+
+// MustParse is like Parse, but panics if the vector is invalid
+func MustParse(vector string) *Vector {
+	v, err := Parse(vector)
+	if err != nil {
+		panic(err.Error())
+	}
+
+	return v
+}
+
+// Version returns the CVSS version of the vector
+func (v *Vector) Version() Version {
+	return v.version
+}
+
+// Metric returns the value of the metric, or the metric's default value when
+// it is not set
+func (v *Vector) Metric(key string) string {
+	for _, def := range metricDefinitions[v.version] {
+		if strings.EqualFold(def.key, key) {
+			if value, ok := v.metrics[def.key]; ok {
+				return value
+			}
+
+			return def.values[0]
+		}
+	}
+
+	return ""
+}
+
+// Set sets the value of a metric, such as an environmental metric used to
+// rescore a vulnerability for a specific deployment. It returns an error if
+// the metric or value is not defined by the vector's version.
+func (v *Vector) Set(key, value string) error {
+	def, value, err := lookupMetric(metricDefinitions[v.version], v.version, key, value)
+	if err != nil {
+		return err
+	}
+
+	if !def.required && value == def.values[0] {
+		delete(v.metrics, def.key)
+		return nil
+	}
+
+	v.metrics[def.key] = value
+	return nil
+}
+
+// Apply sets each of the metrics of a partial vector, such as "CR:H/MAV:L".
+// No metrics are set if any of them are invalid.
+func (v *Vector) Apply(metrics string) error {
+	updated := v.Clone()
+	for _, part := range strings.Split(strings.Trim(metrics, "/"), "/") {
+		if part == "" {
+			continue
+		}
+
+		kv := strings.SplitN(part, ":", 2)
+		if len(kv) != 2 {
+			return fmt.Errorf("invalid CVSS %v metric: %v", v.version, part)
+		}
+
+		err := updated.Set(kv[0], kv[1])
+		if err != nil {
+			return err
+		}
+	}
+
+	v.metrics = updated.metrics
+	return nil
+}
+
+// Clone returns a copy of the vector
+func (v *Vector) Clone() *Vector {
+	c := &Vector{version: v.version, metrics: make(map[string]string, len(v.metrics))}
+	for k, val := range v.metrics {
+		c.metrics[k] = val
+	}
+
+	return c
+}
+
+// String returns the vector string, with the metrics in the order defined by
+// the specification. Optional metrics set to their default are left out.
+func (v *Vector) String() string {
+	var parts []string
+	if v.version != Version20 {
+		parts = append(parts, "CVSS:"+string(v.version))
+	}
+
+	for _, def := range metricDefinitions[v.version] {
+		value, ok := v.metrics[def.key]
+		if !ok || (!def.required && value == def.values[0]) {
+			continue
+		}
+
+		parts = append(parts, def.key+":"+value)
+	}
+
+	return strings.Join(parts, "/")
+}
+
+// BaseScore returns the score of the base metrics of the vector. For version
+// 4.0, this is the CVSS-B score.
+func (v *Vector) BaseScore() float64 {
+	switch v.version {
+	case Version20:
+		return v.v2Base()
+	case Version30, Version31:
+		return v.v3Base()
+	case Version40:
+		return v.baseOnly().v4Score()
+	default:
+		return 0
+	}
+}
+
+// TemporalScore returns the score of the base and temporal metrics of the
+// vector. For version 4.0, this is the CVSS-BT score of the base and threat
+// metrics.
+func (v *Vector) TemporalScore() float64 {
+	switch v.version {
+	case Version20:
+		return v.v2Temporal()
+	case Version30, Version31:
+		return v.v3Temporal()
+	case Version40:
+		return v.withoutEnvironmental().v4Score()
+	default:
+		return 0
+	}
+}
+
+// EnvironmentalScore returns the score of all of the metrics of the vector,
+// adjusted for the environment. For version 4.0, this is the CVSS-BTE score.
+func (v *Vector) EnvironmentalScore() float64 {
+	switch v.version {
+	case Version20:
+		return v.v2Environmental()
+	case Version30, Version31:
+		return v.v3Environmental()
+	case Version40:
+		return v.v4Score()
+	default:
+		return 0
+	}
+}
+
+// Score returns the most specific score of the vector: the environmental
+// score if any environmental metrics are set, the temporal score if any
+// temporal or threat metrics are set, and the base score otherwise
+func (v *Vector) Score() float64 {
+	switch {
+	case v.hasAny(environmentalMetrics[v.version]):
+		return v.EnvironmentalScore()
+	case v.hasAny(temporalMetrics[v.version]):
+		return v.TemporalScore()
+	default:
+		return v.BaseScore()
+	}
+}
+
+// BaseSeverity returns the severity of the base score
+func (v *Vector) BaseSeverity() Severity {
+	return v.severity(v.BaseScore())
+}
+
+// Severity returns the severity of the most specific score
+func (v *Vector) Severity() Severity {
+	return v.severity(v.Score())
+}
+
+func (v *Vector) severity(score float64) Severity {
+	if v.version == Version20 {
+		return SeverityV2(score)
+	}
+
+	return SeverityOf(score)
+}
+
+// SeverityOf returns the severity of a CVSS v3 or v4 score
+func SeverityOf(score float64) Severity {
+	switch {
+	case score >= 9.0:
+		return SeverityCritical
+	case score >= 7.0:
+		return SeverityHigh
+	case score >= 4.0:
+		return SeverityMedium
+	case score > 0:
+		return SeverityLow
+	default:
+		return SeverityNone
+	}
+}
+
+// SeverityV2 returns the severity of a CVSS v2 score, as rated by the NVD.
+// Version 2.0 has no critical or none ratings.
+func SeverityV2(score float64) Severity {
+	switch {
+	case score >= 7.0:
+		return SeverityHigh
+	case score >= 4.0:
+		return SeverityMedium
+	default:
+		return SeverityLow
+	}
+}
+
+func (v *Vector) hasAny(keys []string) bool {
+	for _, k := range keys {
+		if _, ok := v.metrics[k]; ok {
+			return true
+		}
+	}
+
+	return false
+}
+
+// baseOnly returns a copy of the vector with only its base metrics
+func (v *Vector) baseOnly() *Vector {
+	c := &Vector{version: v.version, metrics: make(map[string]string)}
+	for _, def := range metricDefinitions[v.version] {
+		if def.required {
+			c.metrics[def.key] = v.metrics[def.key]
+		}
+	}
+
+	return c
+}
+
+// withoutEnvironmental returns a copy of the vector without its environmental
+// metrics
+func (v *Vector) withoutEnvironmental() *Vector {
+	c := v.Clone()
+	for _, k := range environmentalMetrics[v.version] {
+		delete(c.metrics, k)
+	}
+
+	return c
+}
+
+func lookupMetric(defs []metric, version Version, key, value string) (metric, string, error) {
+	for _, def := range defs {
+		if !strings.EqualFold(def.key, key) {
+			continue
+		}
+
+		for _, allowed := range def.values {
+			if strings.EqualFold(allowed, value) {
+				return def, allowed, nil
+			}
+		}
+
+		return metric{}, "", fmt.Errorf("invalid CVSS %v value for %v: %v", version, def.key, value)
+	}
+
+	return metric{}, "", fmt.Errorf("unknown CVSS %v metric: %v", version, key)
+}
+
+// round1 rounds to one decimal place, half away from zero
+func round1(f float64) float64 {
+	if f < 0 {
+		return -round1(-f)
+	}
+
+	return float64(int64(f*10+0.5+1e-9)) / 10
+}
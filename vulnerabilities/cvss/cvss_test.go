@@ -0,0 +1,158 @@
+package cvss
+
+import (
+	"testing"
+
+	"github.com/franela/goblin"
+	. "github.com/onsi/gomega"
+)
+
+func TestCVSS(t *testing.T) {
+	g := goblin.Goblin(t)
+	RegisterFailHandler(func(m string, _ ...int) { g.Fail(m) })
+
+	g.Describe("Parsing", func() {
+		g.It("should detect the version of a vector", func() {
+			for vector, version := range map[string]Version{
+				"AV:N/AC:L/Au:N/C:P/I:P/A:P":                                      Version20,
+				"(AV:N/AC:L/Au:N/C:P/I:P/A:P)":                                    Version20,
+				"CVSS:3.0/AV:N/AC:L/PR:N/UI:R/S:U/C:N/I:N/A:L":                    Version30,
+				"CVSS:3.1/AV:N/AC:L/PR:N/UI:N/S:U/C:H/I:H/A:H":                    Version31,
+				"CVSS:4.0/AV:N/AC:L/AT:N/PR:N/UI:N/VC:H/VI:H/VA:H/SC:N/SI:N/SA:N": Version40,
+			} {
+				v, err := Parse(vector)
+				Expect(err).To(BeNil(), vector)
+				Expect(v.Version()).To(Equal(version))
+			}
+		})
+
+		g.It("should round trip to a normalized vector string", func() {
+			v, err := Parse("cvss:3.1/c:h/i:h/a:h/av:n/ac:l/pr:n/ui:n/s:u/E:X/RL:O")
+			Expect(err).To(BeNil())
+			Expect(v.String()).To(Equal("CVSS:3.1/AV:N/AC:L/PR:N/UI:N/S:U/C:H/I:H/A:H/RL:O"))
+
+			v, err = Parse("(AV:N/AC:L/Au:N/C:P/I:P/A:P/E:ND)")
+			Expect(err).To(BeNil())
+			Expect(v.String()).To(Equal("AV:N/AC:L/Au:N/C:P/I:P/A:P"))
+
+			v, err = Parse("CVSS:4.0/AV:N/AC:L/AT:N/PR:N/UI:N/VC:H/VI:H/VA:H/SC:N/SI:N/SA:N/U:red")
+			Expect(err).To(BeNil())
+			Expect(v.String()).To(Equal("CVSS:4.0/AV:N/AC:L/AT:N/PR:N/UI:N/VC:H/VI:H/VA:H/SC:N/SI:N/SA:N/U:Red"))
+			Expect(MustParse(v.String()).String()).To(Equal(v.String()))
+		})
+
+		g.It("should return errors for invalid vectors", func() {
+			for _, vector := range []string{
+				"",
+				"CVSS:3.1",
+				"CVSS:5.0/AV:N",
+				"CVSS:3.1/AV:N/AC:L/PR:N/UI:N/S:U/C:H/I:H",
+				"CVSS:3.1/AV:N/AC:L/PR:N/UI:N/S:U/C:H/I:H/A:H/A:L",
+				"CVSS:3.1/AV:X/AC:L/PR:N/UI:N/S:U/C:H/I:H/A:H",
+				"CVSS:3.1/AV:N/AC:L/PR:N/UI:N/S:U/C:H/I:H/A:H/AT:N",
+				"CVSS:3.1/AV:N/AC:L/PR:N/UI:N/S:U/C:H/I:H/A",
+				"AV:N/AC:L/C:P/I:P/A:P",
+			} {
+				_, err := Parse(vector)
+				Expect(err).NotTo(BeNil(), vector)
+			}
+		})
+	})
+
+	g.Describe("Scoring", func() {
+		g.It("should score CVSS v2 vectors", func() {
+			v := MustParse("AV:N/AC:L/Au:N/C:P/I:P/A:P")
+			Expect(v.BaseScore()).To(Equal(7.5))
+			Expect(v.BaseSeverity()).To(Equal(SeverityHigh))
+
+			// the examples from the CVSS v2 specification
+			v = MustParse("AV:N/AC:L/Au:N/C:N/I:N/A:C/E:F/RL:OF/RC:C/CDP:H/TD:H/CR:M/IR:M/AR:H")
+			Expect(v.BaseScore()).To(Equal(7.8))
+			Expect(v.TemporalScore()).To(Equal(6.4))
+			Expect(v.EnvironmentalScore()).To(Equal(9.2))
+
+			v = MustParse("AV:N/AC:L/Au:N/C:C/I:C/A:C/E:F/RL:OF/RC:C/CDP:H/TD:H/CR:M/IR:M/AR:L")
+			Expect(v.BaseScore()).To(Equal(10.0))
+			Expect(v.TemporalScore()).To(Equal(8.3))
+			Expect(v.EnvironmentalScore()).To(Equal(9.0))
+			Expect(v.Score()).To(Equal(9.0))
+		})
+
+		g.It("should score CVSS v3 vectors", func() {
+			table := []struct {
+				vector   string
+				score    float64
+				severity Severity
+			}{
+				{"CVSS:3.1/AV:N/AC:L/PR:N/UI:N/S:U/C:H/I:H/A:H", 9.8, SeverityCritical},
+				{"CVSS:3.1/AV:N/AC:L/PR:N/UI:N/S:C/C:H/I:H/A:H", 10.0, SeverityCritical},
+				{"CVSS:3.0/AV:N/AC:L/PR:N/UI:R/S:U/C:N/I:N/A:L", 4.3, SeverityMedium},
+				{"CVSS:3.1/AV:N/AC:L/PR:L/UI:N/S:C/C:L/I:L/A:N", 6.4, SeverityMedium},
+				{"CVSS:3.1/AV:L/AC:H/PR:H/UI:R/S:U/C:L/I:N/A:N", 1.8, SeverityLow},
+				{"CVSS:3.1/AV:N/AC:L/PR:N/UI:N/S:U/C:N/I:N/A:N", 0, SeverityNone},
+			}
+
+			for _, row := range table {
+				v := MustParse(row.vector)
+				Expect(v.BaseScore()).To(Equal(row.score), row.vector)
+				Expect(v.BaseSeverity()).To(Equal(row.severity), row.vector)
+			}
+		})
+
+		g.It("should score CVSS v3 temporal and environmental metrics", func() {
+			v := MustParse("CVSS:3.1/AV:N/AC:L/PR:N/UI:N/S:U/C:H/I:H/A:H/E:P/RL:O/RC:C")
+			Expect(v.BaseScore()).To(Equal(9.8))
+			Expect(v.TemporalScore()).To(Equal(8.8))
+			Expect(v.Score()).To(Equal(8.8))
+			Expect(v.Severity()).To(Equal(SeverityHigh))
+
+			Expect(v.Apply("CR:L/IR:L/AR:L")).To(Succeed())
+			Expect(v.EnvironmentalScore()).To(Equal(7.2))
+			Expect(v.Score()).To(Equal(7.2))
+
+			Expect(v.Apply("MAV:P/MAC:X")).To(Succeed())
+			Expect(v.Metric("MAV")).To(Equal("P"))
+			Expect(v.Score()).To(BeNumerically("<", 7.2))
+		})
+
+		g.It("should score CVSS v4 vectors", func() {
+			table := []struct {
+				vector string
+				score  float64
+			}{
+				{"CVSS:4.0/AV:N/AC:L/AT:N/PR:N/UI:N/VC:H/VI:H/VA:H/SC:N/SI:N/SA:N", 9.3},
+				{"CVSS:4.0/AV:N/AC:L/AT:N/PR:N/UI:N/VC:H/VI:H/VA:H/SC:H/SI:H/SA:H", 10.0},
+				{"CVSS:4.0/AV:N/AC:L/AT:N/PR:L/UI:N/VC:H/VI:H/VA:H/SC:N/SI:N/SA:N", 8.7},
+				{"CVSS:4.0/AV:L/AC:L/AT:N/PR:L/UI:N/VC:H/VI:H/VA:H/SC:N/SI:N/SA:N", 8.5},
+				{"CVSS:4.0/AV:N/AC:L/AT:N/PR:N/UI:N/VC:N/VI:N/VA:N/SC:N/SI:N/SA:N", 0},
+			}
+
+			for _, row := range table {
+				Expect(MustParse(row.vector).BaseScore()).To(Equal(row.score), row.vector)
+			}
+
+			v := MustParse("CVSS:4.0/AV:N/AC:L/AT:N/PR:N/UI:N/VC:H/VI:H/VA:H/SC:N/SI:N/SA:N/E:U/CR:L/IR:L/AR:L")
+			Expect(v.BaseScore()).To(Equal(9.3))
+			Expect(v.TemporalScore()).To(BeNumerically("<", 9.3))
+			Expect(v.EnvironmentalScore()).To(BeNumerically("<", v.TemporalScore()))
+			Expect(v.Score()).To(Equal(v.EnvironmentalScore()))
+		})
+
+		g.It("should not change a vector when applying invalid metrics", func() {
+			v := MustParse("CVSS:3.1/AV:N/AC:L/PR:N/UI:N/S:U/C:H/I:H/A:H")
+			Expect(v.Apply("CR:H/MAV:Q")).NotTo(Succeed())
+			Expect(v.Apply("CDP:H")).NotTo(Succeed())
+			Expect(v.String()).To(Equal("CVSS:3.1/AV:N/AC:L/PR:N/UI:N/S:U/C:H/I:H/A:H"))
+		})
+
+		g.It("should rate severities", func() {
+			Expect(SeverityOf(0)).To(Equal(SeverityNone))
+			Expect(SeverityOf(3.9)).To(Equal(SeverityLow))
+			Expect(SeverityOf(4.0)).To(Equal(SeverityMedium))
+			Expect(SeverityOf(8.9)).To(Equal(SeverityHigh))
+			Expect(SeverityOf(9.0)).To(Equal(SeverityCritical))
+			Expect(SeverityV2(10)).To(Equal(SeverityHigh))
+			Expect(SeverityV2(3.9)).To(Equal(SeverityLow))
+		})
+	})
+}
@@ -0,0 +1,101 @@
+package cvss
+
+var (
+	v2Metrics = []metric{
+		{key: "AV", values: []string{"L", "A", "N"}, required: true},
+		{key: "AC", values: []string{"H", "M", "L"}, required: true},
+		{key: "Au", values: []string{"M", "S", "N"}, required: true},
+		{key: "C", values: []string{"N", "P", "C"}, required: true},
+		{key: "I", values: []string{"N", "P", "C"}, required: true},
+		{key: "A", values: []string{"N", "P", "C"}, required: true},
+		{key: "E", values: []string{"ND", "U", "POC", "F", "H"}},
+		{key: "RL", values: []string{"ND", "OF", "TF", "W", "U"}},
+		{key: "RC", values: []string{"ND", "UC", "UR", "C"}},
+		{key: "CDP", values: []string{"ND", "N", "L", "LM", "MH", "H"}},
+		{key: "TD", values: []string{"ND", "N", "L", "M", "H"}},
+		{key: "CR", values: []string{"ND", "L", "M", "H"}},
+		{key: "IR", values: []string{"ND", "L", "M", "H"}},
+		{key: "AR", values: []string{"ND", "L", "M", "H"}},
+	}
+
+	v3Metrics = []metric{
+		{key: "AV", values: []string{"N", "A", "L", "P"}, required: true},
+		{key: "AC", values: []string{"L", "H"}, required: true},
+		{key: "PR", values: []string{"N", "L", "H"}, required: true},
+		{key: "UI", values: []string{"N", "R"}, required: true},
+		{key: "S", values: []string{"U", "C"}, required: true},
+		{key: "C", values: []string{"H", "L", "N"}, required: true},
+		{key: "I", values: []string{"H", "L", "N"}, required: true},
+		{key: "A", values: []string{"H", "L", "N"}, required: true},
+		{key: "E", values: []string{"X", "H", "F", "P", "U"}},
+		{key: "RL", values: []string{"X", "U", "W", "T", "O"}},
+		{key: "RC", values: []string{"X", "C", "R", "U"}},
+		{key: "CR", values: []string{"X", "H", "M", "L"}},
+		{key: "IR", values: []string{"X", "H", "M", "L"}},
+		{key: "AR", values: []string{"X", "H", "M", "L"}},
+		{key: "MAV", values: []string{"X", "N", "A", "L", "P"}},
+		{key: "MAC", values: []string{"X", "L", "H"}},
+		{key: "MPR", values: []string{"X", "N", "L", "H"}},
+		{key: "MUI", values: []string{"X", "N", "R"}},
+		{key: "MS", values: []string{"X", "U", "C"}},
+		{key: "MC", values: []string{"X", "H", "L", "N"}},
+		{key: "MI", values: []string{"X", "H", "L", "N"}},
+		{key: "MA", values: []string{"X", "H", "L", "N"}},
+	}
+
+	v4Metrics = []metric{
+		{key: "AV", values: []string{"N", "A", "L", "P"}, required: true},
+		{key: "AC", values: []string{"L", "H"}, required: true},
+		{key: "AT", values: []string{"N", "P"}, required: true},
+		{key: "PR", values: []string{"N", "L", "H"}, required: true},
+		{key: "UI", values: []string{"N", "P", "A"}, required: true},
+		{key: "VC", values: []string{"H", "L", "N"}, required: true},
+		{key: "VI", values: []string{"H", "L", "N"}, required: true},
+		{key: "VA", values: []string{"H", "L", "N"}, required: true},
+		{key: "SC", values: []string{"H", "L", "N"}, required: true},
+		{key: "SI", values: []string{"H", "L", "N"}, required: true},
+		{key: "SA", values: []string{"H", "L", "N"}, required: true},
+		{key: "E", values: []string{"X", "A", "P", "U"}},
+		{key: "CR", values: []string{"X", "H", "M", "L"}},
+		{key: "IR", values: []string{"X", "H", "M", "L"}},
+		{key: "AR", values: []string{"X", "H", "M", "L"}},
+		{key: "MAV", values: []string{"X", "N", "A", "L", "P"}},
+		{key: "MAC", values: []string{"X", "L", "H"}},
+		{key: "MAT", values: []string{"X", "N", "P"}},
+		{key: "MPR", values: []string{"X", "N", "L", "H"}},
+		{key: "MUI", values: []string{"X", "N", "P", "A"}},
+		{key: "MVC", values: []string{"X", "H", "L", "N"}},
+		{key: "MVI", values: []string{"X", "H", "L", "N"}},
+		{key: "MVA", values: []string{"X", "H", "L", "N"}},
+		{key: "MSC", values: []string{"X", "H", "L", "N"}},
+		{key: "MSI", values: []string{"X", "S", "H", "L", "N"}},
+		{key: "MSA", values: []string{"X", "S", "H", "L", "N"}},
+		{key: "S", values: []string{"X", "N", "P"}},
+		{key: "AU", values: []string{"X", "N", "Y"}},
+		{key: "R", values: []string{"X", "A", "U", "I"}},
+		{key: "V", values: []string{"X", "D", "C"}},
+		{key: "RE", values: []string{"X", "L", "M", "H"}},
+		{key: "U", values: []string{"X", "Clear", "Green", "Amber", "Red"}},
+	}
+
+	metricDefinitions = map[Version][]metric{
+		Version20: v2Metrics,
+		Version30: v3Metrics,
+		Version31: v3Metrics,
+		Version40: v4Metrics,
+	}
+
+	temporalMetrics = map[Version][]string{
+		Version20: {"E", "RL", "RC"},
+		Version30: {"E", "RL", "RC"},
+		Version31: {"E", "RL", "RC"},
+		Version40: {"E"},
+	}
+
+	environmentalMetrics = map[Version][]string{
+		Version20: {"CDP", "TD", "CR", "IR", "AR"},
+		Version30: {"CR", "IR", "AR", "MAV", "MAC", "MPR", "MUI", "MS", "MC", "MI", "MA"},
+		Version31: {"CR", "IR", "AR", "MAV", "MAC", "MPR", "MUI", "MS", "MC", "MI", "MA"},
+		Version40: {"CR", "IR", "AR", "MAV", "MAC", "MAT", "MPR", "MUI", "MVC", "MVI", "MVA", "MSC", "MSI", "MSA"},
+	}
+)
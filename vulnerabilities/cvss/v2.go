@@ -0,0 +1,60 @@
+package cvss
+
+import "math"
+
+var v2Weights = map[string]map[string]float64{
+	"AV":  {"L": 0.395, "A": 0.646, "N": 1.0},
+	"AC":  {"H": 0.35, "M": 0.61, "L": 0.71},
+	"Au":  {"M": 0.45, "S": 0.56, "N": 0.704},
+	"C":   {"N": 0, "P": 0.275, "C": 0.660},
+	"I":   {"N": 0, "P": 0.275, "C": 0.660},
+	"A":   {"N": 0, "P": 0.275, "C": 0.660},
+	"E":   {"U": 0.85, "POC": 0.9, "F": 0.95, "H": 1.0, "ND": 1.0},
+	"RL":  {"OF": 0.87, "TF": 0.90, "W": 0.95, "U": 1.0, "ND": 1.0},
+	"RC":  {"UC": 0.90, "UR": 0.95, "C": 1.0, "ND": 1.0},
+	"CDP": {"N": 0, "L": 0.1, "LM": 0.3, "MH": 0.4, "H": 0.5, "ND": 0},
+	"TD":  {"N": 0, "L": 0.25, "M": 0.75, "H": 1.0, "ND": 1.0},
+	"CR":  {"L": 0.5, "M": 1.0, "H": 1.51, "ND": 1.0},
+	"IR":  {"L": 0.5, "M": 1.0, "H": 1.51, "ND": 1.0},
+	"AR":  {"L": 0.5, "M": 1.0, "H": 1.51, "ND": 1.0},
+}
+
+func (v *Vector) v2Weight(key string) float64 {
+	return v2Weights[key][v.Metric(key)]
+}
+
+func (v *Vector) v2Base() float64 {
+	impact := 10.41 * (1 - (1-v.v2Weight("C"))*(1-v.v2Weight("I"))*(1-v.v2Weight("A")))
+	return v.v2BaseWithImpact(impact)
+}
+
+func (v *Vector) v2BaseWithImpact(impact float64) float64 {
+	exploitability := 20 * v.v2Weight("AV") * v.v2Weight("AC") * v.v2Weight("Au")
+
+	f := 0.0
+	if impact != 0 {
+		f = 1.176
+	}
+
+	return round1(((0.6 * impact) + (0.4 * exploitability) - 1.5) * f)
+}
+
+func (v *Vector) v2TemporalOf(base float64) float64 {
+	return round1(base * v.v2Weight("E") * v.v2Weight("RL") * v.v2Weight("RC"))
+}
+
+func (v *Vector) v2Temporal() float64 {
+	return v.v2TemporalOf(v.v2Base())
+}
+
+func (v *Vector) v2Environmental() float64 {
+	adjustedImpact := math.Min(10, 10.41*(1-
+		(1-v.v2Weight("C")*v.v2Weight("CR"))*
+			(1-v.v2Weight("I")*v.v2Weight("IR"))*
+			(1-v.v2Weight("A")*v.v2Weight("AR"))))
+
+	adjustedTemporal := v.v2TemporalOf(v.v2BaseWithImpact(adjustedImpact))
+	cdp := v.v2Weight("CDP")
+
+	return round1((adjustedTemporal + (10-adjustedTemporal)*cdp) * v.v2Weight("TD"))
+}
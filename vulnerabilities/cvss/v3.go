@@ -0,0 +1,131 @@
+package cvss
+
+import "math"
+
+var v3Weights = map[string]map[string]float64{
+	"AV": {"N": 0.85, "A": 0.62, "L": 0.55, "P": 0.2},
+	"AC": {"L": 0.77, "H": 0.44},
+	"UI": {"N": 0.85, "R": 0.62},
+	"C":  {"H": 0.56, "L": 0.22, "N": 0},
+	"I":  {"H": 0.56, "L": 0.22, "N": 0},
+	"A":  {"H": 0.56, "L": 0.22, "N": 0},
+	"E":  {"X": 1, "H": 1, "F": 0.97, "P": 0.94, "U": 0.91},
+	"RL": {"X": 1, "U": 1, "W": 0.97, "T": 0.96, "O": 0.95},
+	"RC": {"X": 1, "C": 1, "R": 0.96, "U": 0.92},
+	"CR": {"X": 1, "H": 1.5, "M": 1, "L": 0.5},
+	"IR": {"X": 1, "H": 1.5, "M": 1, "L": 0.5},
+	"AR": {"X": 1, "H": 1.5, "M": 1, "L": 0.5},
+}
+
+// v3PrivilegesRequired returns the weight of the privileges required, which
+// depends on whether the scope is changed
+func v3PrivilegesRequired(pr string, scopeChanged bool) float64 {
+	switch pr {
+	case "N":
+		return 0.85
+	case "L":
+		if scopeChanged {
+			return 0.68
+		}
+
+		return 0.62
+	default:
+		if scopeChanged {
+			return 0.5
+		}
+
+		return 0.27
+	}
+}
+
+// modified returns the value of the modified metric, or of the base metric
+// when the modified metric is not set
+func (v *Vector) modified(key string) string {
+	if m := v.Metric("M" + key); m != "" && m != "X" {
+		return m
+	}
+
+	return v.Metric(key)
+}
+
+func (v *Vector) v3Base() float64 {
+	scopeChanged := v.Metric("S") == "C"
+
+	iss := 1 - (1-v3Weights["C"][v.Metric("C")])*(1-v3Weights["I"][v.Metric("I")])*(1-v3Weights["A"][v.Metric("A")])
+
+	var impact float64
+	if scopeChanged {
+		impact = 7.52*(iss-0.029) - 3.25*math.Pow(iss-0.02, 15)
+	} else {
+		impact = 6.42 * iss
+	}
+
+	exploitability := 8.22 * v3Weights["AV"][v.Metric("AV")] * v3Weights["AC"][v.Metric("AC")] *
+		v3PrivilegesRequired(v.Metric("PR"), scopeChanged) * v3Weights["UI"][v.Metric("UI")]
+
+	if impact <= 0 {
+		return 0
+	}
+
+	if scopeChanged {
+		return v.roundUp(math.Min(1.08*(impact+exploitability), 10))
+	}
+
+	return v.roundUp(math.Min(impact+exploitability, 10))
+}
+
+func (v *Vector) v3TemporalMultiplier() float64 {
+	return v3Weights["E"][v.Metric("E")] * v3Weights["RL"][v.Metric("RL")] * v3Weights["RC"][v.Metric("RC")]
+}
+
+func (v *Vector) v3Temporal() float64 {
+	return v.roundUp(v.v3Base() * v.v3TemporalMultiplier())
+}
+
+func (v *Vector) v3Environmental() float64 {
+	scopeChanged := v.modified("S") == "C"
+
+	miss := math.Min(1-
+		(1-v3Weights["CR"][v.Metric("CR")]*v3Weights["C"][v.modified("C")])*
+			(1-v3Weights["IR"][v.Metric("IR")]*v3Weights["I"][v.modified("I")])*
+			(1-v3Weights["AR"][v.Metric("AR")]*v3Weights["A"][v.modified("A")]), 0.915)
+
+	var impact float64
+	switch {
+	case !scopeChanged:
+		impact = 6.42 * miss
+	case v.version == Version30:
+		impact = 7.52*(miss-0.029) - 3.25*math.Pow(miss-0.02, 15)
+	default:
+		impact = 7.52*(miss-0.029) - 3.25*math.Pow(miss*0.9731-0.02, 13)
+	}
+
+	exploitability := 8.22 * v3Weights["AV"][v.modified("AV")] * v3Weights["AC"][v.modified("AC")] *
+		v3PrivilegesRequired(v.modified("PR"), scopeChanged) * v3Weights["UI"][v.modified("UI")]
+
+	if impact <= 0 {
+		return 0
+	}
+
+	if scopeChanged {
+		return v.roundUp(v.roundUp(math.Min(1.08*(impact+exploitability), 10)) * v.v3TemporalMultiplier())
+	}
+
+	return v.roundUp(v.roundUp(math.Min(impact+exploitability, 10)) * v.v3TemporalMultiplier())
+}
+
+// roundUp returns the smallest number, to one decimal place, that is equal to
+// or higher than its input. Version 3.1 avoids floating point errors by
+// rounding to five decimal places first.
+func (v *Vector) roundUp(f float64) float64 {
+	if v.version == Version30 {
+		return math.Ceil(f*10) / 10
+	}
+
+	i := int64(math.Round(f * 100000))
+	if i%10000 == 0 {
+		return float64(i) / 100000
+	}
+
+	return float64(i/10000+1) / 10
+}
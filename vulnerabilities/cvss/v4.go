@@ -0,0 +1,306 @@
+package cvss
+
+import (
+	"fmt"
+	"math"
+	"strings"
+)
+
+// v4 scoring follows the reference implementation of the CVSS v4.0
+// specification: the vector is placed in a MacroVector of six equivalence
+// classes, whose score comes from a lookup table, and then interpolated
+// towards the next lower MacroVectors by its severity distance from the
+// highest severity vectors of its MacroVector.
+
+var v4Levels = map[string]map[string]float64{
+	"AV": {"N": 0.0, "A": 0.1, "L": 0.2, "P": 0.3},
+	"PR": {"N": 0.0, "L": 0.1, "H": 0.2},
+	"UI": {"N": 0.0, "P": 0.1, "A": 0.2},
+	"AC": {"L": 0.0, "H": 0.1},
+	"AT": {"N": 0.0, "P": 0.1},
+	"VC": {"H": 0.0, "L": 0.1, "N": 0.2},
+	"VI": {"H": 0.0, "L": 0.1, "N": 0.2},
+	"VA": {"H": 0.0, "L": 0.1, "N": 0.2},
+	"SC": {"H": 0.1, "L": 0.2, "N": 0.3},
+	"SI": {"S": 0.0, "H": 0.1, "L": 0.2, "N": 0.3},
+	"SA": {"S": 0.0, "H": 0.1, "L": 0.2, "N": 0.3},
+	"CR": {"H": 0.0, "M": 0.1, "L": 0.2},
+	"IR": {"H": 0.0, "M": 0.1, "L": 0.2},
+	"AR": {"H": 0.0, "M": 0.1, "L": 0.2},
+}
+
+// v4MaxComposed are the highest severity vectors of each equivalence class
+var v4MaxComposed = struct {
+	eq1, eq2, eq4, eq5 map[int][]string
+	eq3eq6             map[int]map[int][]string
+}{
+	eq1: map[int][]string{
+		0: {"AV:N/PR:N/UI:N/"},
+		1: {"AV:A/PR:N/UI:N/", "AV:N/PR:L/UI:N/", "AV:N/PR:N/UI:P/"},
+		2: {"AV:P/PR:N/UI:N/", "AV:A/PR:L/UI:P/"},
+	},
+	eq2: map[int][]string{
+		0: {"AC:L/AT:N/"},
+		1: {"AC:H/AT:N/", "AC:L/AT:P/"},
+	},
+	eq3eq6: map[int]map[int][]string{
+		0: {
+			0: {"VC:H/VI:H/VA:H/CR:H/IR:H/AR:H/"},
+			1: {"VC:H/VI:H/VA:L/CR:M/IR:M/AR:H/", "VC:H/VI:H/VA:H/CR:M/IR:M/AR:M/"},
+		},
+		1: {
+			0: {"VC:L/VI:H/VA:H/CR:H/IR:H/AR:H/", "VC:H/VI:L/VA:H/CR:H/IR:H/AR:H/"},
+			1: {"VC:L/VI:H/VA:L/CR:H/IR:M/AR:H/", "VC:L/VI:H/VA:H/CR:H/IR:M/AR:M/", "VC:H/VI:L/VA:H/CR:M/IR:H/AR:M/", "VC:H/VI:L/VA:L/CR:M/IR:H/AR:H/", "VC:L/VI:L/VA:H/CR:H/IR:H/AR:M/"},
+		},
+		2: {
+			1: {"VC:L/VI:L/VA:L/CR:H/IR:H/AR:H/"},
+		},
+	},
+	eq4: map[int][]string{
+		0: {"SC:H/SI:S/SA:S/"},
+		1: {"SC:H/SI:H/SA:H/"},
+		2: {"SC:L/SI:L/SA:L/"},
+	},
+	eq5: map[int][]string{
+		0: {"E:A/"},
+		1: {"E:P/"},
+		2: {"E:U/"},
+	},
+}
+
+// v4MaxSeverity are the number of severity steps within each equivalence
+// class
+var v4MaxSeverity = struct {
+	eq1, eq2, eq4 map[int]float64
+	eq3eq6        map[int]map[int]float64
+}{
+	eq1:    map[int]float64{0: 1, 1: 4, 2: 5},
+	eq2:    map[int]float64{0: 1, 1: 2},
+	eq3eq6: map[int]map[int]float64{0: {0: 7, 1: 6}, 1: {0: 8, 1: 8}, 2: {1: 10}},
+	eq4:    map[int]float64{0: 6, 1: 5, 2: 4},
+}
+
+// v4Effective returns the value of a metric used for scoring: the modified
+// metric when it is set, and the worst case for unset threat and security
+// requirement metrics
+func (v *Vector) v4Effective(key string) string {
+	switch key {
+	case "E":
+		if e := v.Metric("E"); e != "X" {
+			return e
+		}
+
+		return "A"
+	case "CR", "IR", "AR":
+		if r := v.Metric(key); r != "X" {
+			return r
+		}
+
+		return "H"
+	default:
+		return v.modified(key)
+	}
+}
+
+// v4MacroVector returns the equivalence classes of the vector
+func (v *Vector) v4MacroVector() [6]int {
+	m := v.v4Effective
+	var eq [6]int
+
+	switch {
+	case m("AV") == "N" && m("PR") == "N" && m("UI") == "N":
+		eq[0] = 0
+	case (m("AV") == "N" || m("PR") == "N" || m("UI") == "N") && m("AV") != "P":
+		eq[0] = 1
+	default:
+		eq[0] = 2
+	}
+
+	if m("AC") == "L" && m("AT") == "N" {
+		eq[1] = 0
+	} else {
+		eq[1] = 1
+	}
+
+	switch {
+	case m("VC") == "H" && m("VI") == "H":
+		eq[2] = 0
+	case m("VC") == "H" || m("VI") == "H" || m("VA") == "H":
+		eq[2] = 1
+	default:
+		eq[2] = 2
+	}
+
+	switch {
+	case m("SI") == "S" || m("SA") == "S":
+		eq[3] = 0
+	case m("SC") == "H" || m("SI") == "H" || m("SA") == "H":
+		eq[3] = 1
+	default:
+		eq[3] = 2
+	}
+
+	switch m("E") {
+	case "A":
+		eq[4] = 0
+	case "P":
+		eq[4] = 1
+	default:
+		eq[4] = 2
+	}
+
+	if (m("CR") == "H" && m("VC") == "H") || (m("IR") == "H" && m("VI") == "H") || (m("AR") == "H" && m("VA") == "H") {
+		eq[5] = 0
+	} else {
+		eq[5] = 1
+	}
+
+	return eq
+}
+
+func v4Lookup(eq [6]int) (float64, bool) {
+	score, ok := v4MacroVectorScores[fmt.Sprintf("%d%d%d%d%d%d", eq[0], eq[1], eq[2], eq[3], eq[4], eq[5])]
+	return score, ok
+}
+
+func (v *Vector) v4Score() float64 {
+	m := v.v4Effective
+
+	impact := []string{"VC", "VI", "VA", "SC", "SI", "SA"}
+	none := true
+	for _, k := range impact {
+		if m(k) != "N" {
+			none = false
+			break
+		}
+	}
+
+	if none {
+		return 0
+	}
+
+	eq := v.v4MacroVector()
+	value, ok := v4Lookup(eq)
+	if !ok {
+		return 0
+	}
+
+	lower := func(i int) (float64, bool) {
+		next := eq
+		next[i]++
+		return v4Lookup(next)
+	}
+
+	eq1Lower, eq1Ok := lower(0)
+	eq2Lower, eq2Ok := lower(1)
+	eq4Lower, eq4Ok := lower(3)
+
+	var eq3eq6Lower float64
+	var eq3eq6Ok bool
+	switch {
+	case eq[2] == 1 && eq[5] == 1, eq[2] == 0 && eq[5] == 1:
+		next := eq
+		next[2]++
+		eq3eq6Lower, eq3eq6Ok = v4Lookup(next)
+	case eq[2] == 1 && eq[5] == 0:
+		next := eq
+		next[5]++
+		eq3eq6Lower, eq3eq6Ok = v4Lookup(next)
+	case eq[2] == 0 && eq[5] == 0:
+		left, right := eq, eq
+		left[5]++
+		right[2]++
+		leftScore, leftOk := v4Lookup(left)
+		rightScore, rightOk := v4Lookup(right)
+		if leftOk && (!rightOk || leftScore > rightScore) {
+			eq3eq6Lower, eq3eq6Ok = leftScore, true
+		} else {
+			eq3eq6Lower, eq3eq6Ok = rightScore, rightOk
+		}
+	default:
+		next := eq
+		next[2]++
+		next[5]++
+		eq3eq6Lower, eq3eq6Ok = v4Lookup(next)
+	}
+
+	// find the first highest severity vector of the MacroVector that the
+	// vector is not more severe than
+	var maxVector string
+	for _, e1 := range v4MaxComposed.eq1[eq[0]] {
+		for _, e2 := range v4MaxComposed.eq2[eq[1]] {
+			for _, e36 := range v4MaxComposed.eq3eq6[eq[2]][eq[5]] {
+				for _, e4 := range v4MaxComposed.eq4[eq[3]] {
+					for _, e5 := range v4MaxComposed.eq5[eq[4]] {
+						candidate := e1 + e2 + e36 + e4 + e5
+						if maxVector == "" && v.v4NotMoreSevere(candidate) {
+							maxVector = candidate
+						}
+					}
+				}
+			}
+		}
+	}
+
+	distance := func(keys ...string) float64 {
+		d := 0.0
+		for _, k := range keys {
+			d += v4Levels[k][m(k)] - v4Levels[k][v4Extract(maxVector, k)]
+		}
+
+		return d
+	}
+
+	const step = 0.1
+	n := 0
+	total := 0.0
+
+	add := func(ok bool, lowerScore, current, maxSeverity float64) {
+		if !ok {
+			return
+		}
+
+		n++
+		total += (value - lowerScore) * (current / (maxSeverity * step))
+	}
+
+	add(eq1Ok, eq1Lower, distance("AV", "PR", "UI"), v4MaxSeverity.eq1[eq[0]])
+	add(eq2Ok, eq2Lower, distance("AC", "AT"), v4MaxSeverity.eq2[eq[1]])
+	add(eq3eq6Ok, eq3eq6Lower, distance("VC", "VI", "VA", "CR", "IR", "AR"), v4MaxSeverity.eq3eq6[eq[2]][eq[5]])
+	add(eq4Ok, eq4Lower, distance("SC", "SI", "SA"), v4MaxSeverity.eq4[eq[3]])
+	if _, ok := lower(4); ok {
+		// the threat metrics have no severity distance within their class,
+		// but still count towards the mean
+		n++
+	}
+
+	if n > 0 {
+		value -= total / float64(n)
+	}
+
+	value = math.Max(0, math.Min(10, value))
+	return math.Round(value*10) / 10
+}
+
+// v4NotMoreSevere returns whether every metric of the vector is at the same
+// or a lower severity than in the given highest severity vector
+func (v *Vector) v4NotMoreSevere(maxVector string) bool {
+	for _, k := range []string{"AV", "PR", "UI", "AC", "AT", "VC", "VI", "VA", "SC", "SI", "SA", "CR", "IR", "AR"} {
+		if v4Levels[k][v.v4Effective(k)]-v4Levels[k][v4Extract(maxVector, k)] < 0 {
+			return false
+		}
+	}
+
+	return true
+}
+
+// v4Extract returns the value of a metric in a partial vector
+func v4Extract(vector, key string) string {
+	for _, part := range strings.Split(vector, "/") {
+		if strings.HasPrefix(part, key+":") {
+			return strings.TrimPrefix(part, key+":")
+		}
+	}
+
+	return ""
+}
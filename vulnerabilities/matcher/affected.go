@@ -0,0 +1,264 @@
+package matcher
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/ion-channel/ionic/versioning"
+	"github.com/ion-channel/ionic/vulnerabilities/osv"
+)
+
+// affected is a package affected by a vulnerability of the database, with the
+// versions it is affected in
+type affected struct {
+	vulnerability int
+	identity      identity
+	// versions are the individually affected versions
+	versions []string
+	// ranges are OSV ranges of affected versions
+	ranges []osv.Range
+	// constraints are ranges in the syntax of the package's ecosystem, such
+	// as "< 2.0" or "[1.0,2.0)"
+	constraints []string
+}
+
+// interval is a contiguous range of affected versions, from an introduced
+// version up to a fixed or last affected version
+type interval struct {
+	introduced   string
+	fixed        string
+	lastAffected string
+}
+
+// check returns whether the version is affected, with the range it was found
+// in and the nearest version that fixes it. A package without any versions or
+// ranges is affected in every version.
+func (a *affected) check(version string) (bool, string, string) {
+	if len(a.versions) == 0 && len(a.ranges) == 0 && len(a.constraints) == 0 {
+		return true, "*", ""
+	}
+
+	scheme := a.scheme(version)
+
+	v, err := scheme.Parse(version)
+	if err != nil {
+		v = nil
+	}
+
+	for _, affectedVersion := range a.versions {
+		if affectedVersion == version {
+			return true, affectedVersion, a.nearestFixed(scheme, v)
+		}
+
+		if v != nil {
+			if av, err := scheme.Parse(affectedVersion); err == nil && av.Compare(v) == 0 {
+				return true, affectedVersion, a.nearestFixed(scheme, v)
+			}
+		}
+	}
+
+	if v == nil {
+		return false, "", ""
+	}
+
+	for _, text := range a.constraints {
+		r, err := versioning.ParseRange(scheme, text)
+		if err != nil {
+			continue
+		}
+
+		if r.Check(v) {
+			return true, text, a.nearestFixed(scheme, v)
+		}
+	}
+
+	for _, r := range a.ranges {
+		if r.Type == osv.RangeGit {
+			continue
+		}
+
+		rangeScheme := scheme
+		if r.Type == osv.RangeSemver {
+			rangeScheme = versioning.SemVer
+		}
+
+		rv := v
+		if rangeScheme != scheme {
+			if rv, err = rangeScheme.Parse(version); err != nil {
+				continue
+			}
+		}
+
+		for _, i := range intervals(rangeScheme, r.Events) {
+			if i.contains(rangeScheme, rv) {
+				return true, i.String(), a.nearestFixed(scheme, v)
+			}
+		}
+	}
+
+	return false, "", ""
+}
+
+// nearestFixed returns the oldest version newer than the version that is
+// fixed in any of the ranges, or is the exclusive upper bound of a constraint
+// containing the version, such as the "2.0" of "< 2.0" or "[1.0,2.0)". An
+// empty string is returned if there is none.
+func (a *affected) nearestFixed(s versioning.Scheme, v versioning.Version) string {
+	if v == nil {
+		return ""
+	}
+
+	var nearest versioning.Version
+	consider := func(fixed versioning.Version) {
+		if fixed == nil || fixed.Compare(v) <= 0 {
+			return
+		}
+
+		if nearest == nil || fixed.Compare(nearest) < 0 {
+			nearest = fixed
+		}
+	}
+
+	for _, r := range a.ranges {
+		if r.Type == osv.RangeGit {
+			continue
+		}
+
+		for _, e := range r.Events {
+			if e.Fixed == "" {
+				continue
+			}
+
+			if fixed, err := s.Parse(e.Fixed); err == nil {
+				consider(fixed)
+			}
+		}
+	}
+
+	for _, text := range a.constraints {
+		if r, err := versioning.ParseRange(s, text); err == nil {
+			consider(r.UpperBound(v))
+		}
+	}
+
+	if nearest == nil {
+		return ""
+	}
+
+	return nearest.String()
+}
+
+// scheme returns the versioning scheme of the affected package, or the
+// generic scheme if the version can not be parsed by it
+func (a *affected) scheme(version string) versioning.Scheme {
+	scheme := versioning.For(a.identity.ecosystem)
+	if _, err := scheme.Parse(version); err != nil {
+		return versioning.Generic
+	}
+
+	return scheme
+}
+
+// intervals sorts the events of an OSV range and returns the intervals of
+// affected versions they describe. Events whose versions can not be parsed
+// are ignored.
+func intervals(s versioning.Scheme, events []osv.Event) []interval {
+	type parsedEvent struct {
+		event   osv.Event
+		version versioning.Version
+	}
+
+	parsed := []parsedEvent{}
+	for _, e := range events {
+		text := e.Introduced + e.Fixed + e.LastAffected + e.Limit
+		if e.Introduced == "0" {
+			parsed = append(parsed, parsedEvent{event: e})
+			continue
+		}
+
+		v, err := s.Parse(text)
+		if err != nil {
+			continue
+		}
+
+		parsed = append(parsed, parsedEvent{event: e, version: v})
+	}
+
+	sort.SliceStable(parsed, func(i, j int) bool {
+		a, b := parsed[i].version, parsed[j].version
+		if a == nil || b == nil {
+			return a == nil && b != nil
+		}
+
+		return a.Compare(b) < 0
+	})
+
+	is := []interval{}
+	open := false
+	for _, p := range parsed {
+		switch {
+		case p.event.Introduced != "":
+			if !open {
+				is = append(is, interval{introduced: p.event.Introduced})
+				open = true
+			}
+		case p.event.Fixed != "" && open:
+			is[len(is)-1].fixed = p.event.Fixed
+			open = false
+		case p.event.LastAffected != "" && open:
+			is[len(is)-1].lastAffected = p.event.LastAffected
+			open = false
+		}
+	}
+
+	return is
+}
+
+// contains returns whether the version is within the interval
+func (i interval) contains(s versioning.Scheme, v versioning.Version) bool {
+	if i.introduced != "0" && !compares(s, v, i.introduced, func(c int) bool { return c >= 0 }) {
+		return false
+	}
+
+	switch {
+	case i.fixed != "":
+		return compares(s, v, i.fixed, func(c int) bool { return c < 0 })
+	case i.lastAffected != "":
+		return compares(s, v, i.lastAffected, func(c int) bool { return c <= 0 })
+	}
+
+	return true
+}
+
+// String returns the interval as a list of comparisons, such as
+// ">=1.0.0, <1.2.3"
+func (i interval) String() string {
+	parts := []string{}
+	if i.introduced != "0" {
+		parts = append(parts, ">="+i.introduced)
+	}
+
+	switch {
+	case i.fixed != "":
+		parts = append(parts, "<"+i.fixed)
+	case i.lastAffected != "":
+		parts = append(parts, "<="+i.lastAffected)
+	}
+
+	if len(parts) == 0 {
+		return "*"
+	}
+
+	return strings.Join(parts, ", ")
+}
+
+// compares parses the other version and returns whether the comparison of the
+// version to it is accepted
+func compares(s versioning.Scheme, v versioning.Version, other string, accept func(int) bool) bool {
+	o, err := s.Parse(other)
+	if err != nil {
+		return false
+	}
+
+	return accept(v.Compare(o))
+}
@@ -0,0 +1,402 @@
+// Package matcher matches packages against a local snapshot of vulnerability
+// data, such as exported vulnerabilities or an OSV dump, without sending them
+// to Ion Channel. Packages are matched by package URL, CPE, or org and name,
+// and their versions compared with the versioning scheme of their ecosystem.
+// Matches can be formatted the same way as the results of a vulnerability
+// scan.
+package matcher
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/ion-channel/ionic/dependencies"
+	"github.com/ion-channel/ionic/products"
+	"github.com/ion-channel/ionic/projects"
+	"github.com/ion-channel/ionic/vulnerabilities"
+	"github.com/ion-channel/ionic/vulnerabilities/osv"
+)
+
+// rangeCharacters are the characters that make a product version a range of
+// versions rather than a single version
+const rangeCharacters = "<>=~^[](),|*"
+
+// Database is a snapshot of vulnerability data that packages are matched
+// against
+type Database struct {
+	vulnerabilities []vulnerabilities.Vulnerability
+	// affected are the affected packages of the vulnerabilities, by
+	// normalized name
+	affected map[string][]*affected
+}
+
+// Match is a vulnerability affecting a version of a package
+type Match struct {
+	Package       Package                       `json:"package"`
+	Vulnerability vulnerabilities.Vulnerability `json:"vulnerability"`
+	// AffectedRange is the range of affected versions the version was found
+	// in, such as ">=1.0.0, <1.2.3", or the affected version itself
+	AffectedRange string `json:"affected_range"`
+	// FixedVersion is the oldest version newer than the package's version
+	// that fixes the vulnerability, if it is known
+	FixedVersion string `json:"fixed_version,omitempty"`
+}
+
+// NewDatabase returns an empty vulnerability database
+func NewDatabase() *Database {
+	return &Database{
+		vulnerabilities: []vulnerabilities.Vulnerability{},
+		affected:        map[string][]*affected{},
+	}
+}
+
+// Open returns a database of the vulnerabilities in the given JSON files, or
+// in the JSON files under the given directories, such as an extracted
+// osv.dev ecosystem export
+func Open(paths ...string) (*Database, error) {
+	db := NewDatabase()
+
+	for _, path := range paths {
+		files := []string{}
+		err := filepath.Walk(path, func(file string, info os.FileInfo, err error) error {
+			if err != nil {
+				return err
+			}
+
+			if !info.IsDir() && (file == path || strings.EqualFold(filepath.Ext(file), ".json")) {
+				files = append(files, file)
+			}
+
+			return nil
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to read vulnerability data: %v", err.Error())
+		}
+
+		sort.Strings(files)
+
+		for _, file := range files {
+			err = db.readFile(file)
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	return db, nil
+}
+
+func (db *Database) readFile(path string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return fmt.Errorf("failed to open vulnerability data: %v", err.Error())
+	}
+	defer f.Close()
+
+	err = db.Read(f)
+	if err != nil {
+		return fmt.Errorf("%v: %v", path, err.Error())
+	}
+
+	return nil
+}
+
+// Read adds the vulnerabilities in a JSON document to the database. The
+// document may be a single record or an array of them, where each record is
+// an OSV entry, an exported vulnerability with its affected products, or a
+// vulnerability input whose dependencies are package URLs. Withdrawn OSV
+// entries are skipped.
+func (db *Database) Read(r io.Reader) error {
+	b, err := io.ReadAll(r)
+	if err != nil {
+		return fmt.Errorf("failed to read vulnerability data: %v", err.Error())
+	}
+
+	b = bytes.TrimSpace(b)
+	if len(b) == 0 {
+		return nil
+	}
+
+	records := []json.RawMessage{}
+	if b[0] == '[' {
+		err = json.Unmarshal(b, &records)
+	} else {
+		records = append(records, b)
+	}
+
+	if err != nil {
+		return fmt.Errorf("failed to parse vulnerability data: %v", err.Error())
+	}
+
+	for _, record := range records {
+		err = db.readRecord(record)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (db *Database) readRecord(record json.RawMessage) error {
+	var fields map[string]json.RawMessage
+	err := json.Unmarshal(record, &fields)
+	if err != nil {
+		return fmt.Errorf("failed to parse vulnerability data: %v", err.Error())
+	}
+
+	_, hasAffected := fields["affected"]
+	_, hasSchema := fields["schema_version"]
+	if hasAffected || hasSchema {
+		var e osv.Entry
+		err = json.Unmarshal(record, &e)
+		if err != nil {
+			return fmt.Errorf("failed to parse osv entry: %v", err.Error())
+		}
+
+		if e.Withdrawn == nil {
+			db.AddOSV(e)
+		}
+
+		return nil
+	}
+
+	var names []string
+	if json.Unmarshal(fields["dependencies"], &names) == nil && len(names) > 0 {
+		var input vulnerabilities.VulnerabilityInput
+		err = json.Unmarshal(record, &input)
+		if err != nil {
+			return fmt.Errorf("failed to parse vulnerability: %v", err.Error())
+		}
+
+		db.AddInputs(input)
+		return nil
+	}
+
+	var v vulnerabilities.Vulnerability
+	err = json.Unmarshal(record, &v)
+	if err != nil {
+		return fmt.Errorf("failed to parse vulnerability: %v", err.Error())
+	}
+
+	db.AddVulnerabilities(v)
+	return nil
+}
+
+// Len returns the number of vulnerabilities in the database
+func (db *Database) Len() int {
+	return len(db.vulnerabilities)
+}
+
+// AddOSV adds OSV entries to the database, keeping the ranges of affected
+// versions of each package
+func (db *Database) AddOSV(entries ...osv.Entry) {
+	for _, e := range entries {
+		i := db.add(osv.ToVulnerability(e))
+
+		for _, a := range e.Affected {
+			id := identity{}
+			if eco, org, name, _, ok := parsePURL(a.Package.PURL); ok {
+				id = newIdentity(eco, org, name)
+			} else {
+				org, name := a.Package.OrgAndName()
+				id = nameIdentity(a.Package.Ecosystem, org, name)
+			}
+
+			db.index(&affected{
+				vulnerability: i,
+				identity:      id,
+				versions:      a.Versions,
+				ranges:        a.Ranges,
+			})
+		}
+	}
+}
+
+// AddVulnerabilities adds vulnerabilities to the database, such as those
+// returned by GetVulnerabilities. Each of their dependencies is an affected
+// product, identified by its external ID when it is a package URL or CPE, and
+// by its language, org, and name otherwise. The version of a product may be a
+// single version or a range, such as "< 2.0" or "[1.0,2.0)".
+func (db *Database) AddVulnerabilities(vulns ...vulnerabilities.Vulnerability) {
+	for _, v := range vulns {
+		i := db.add(v)
+
+		byIdentity := map[identity]*affected{}
+		for _, p := range v.Dependencies {
+			id, version := productIdentity(p)
+			if id.name == "" {
+				continue
+			}
+
+			a, ok := byIdentity[id]
+			if !ok {
+				a = &affected{vulnerability: i, identity: id}
+				byIdentity[id] = a
+				db.index(a)
+			}
+
+			switch {
+			case version == "" || version == "*" || version == "-":
+			case strings.ContainsAny(version, rangeCharacters) || strings.Contains(version, " - "):
+				a.constraints = append(a.constraints, version)
+			default:
+				a.versions = append(a.versions, version)
+			}
+		}
+	}
+}
+
+// AddInputs adds vulnerability inputs to the database, such as those returned
+// by osv.ReadDir, whose dependencies are package URLs, CPEs, or names in the
+// form "org/name@version"
+func (db *Database) AddInputs(inputs ...vulnerabilities.VulnerabilityInput) {
+	for _, input := range inputs {
+		v := input.Vulnerability
+		v.Dependencies = []products.Product{}
+
+		for _, dep := range input.Dependencies {
+			p := products.Product{ExternalID: dep}
+			if !strings.HasPrefix(dep, "pkg:") && !strings.HasPrefix(dep, "cpe:") {
+				p.ExternalID = ""
+				if i := strings.LastIndex(dep, "@"); i > 0 {
+					dep, p.Version = dep[:i], dep[i+1:]
+				}
+
+				if i := strings.LastIndex(dep, "/"); i >= 0 {
+					p.Org, dep = dep[:i], dep[i+1:]
+				}
+
+				p.Name = dep
+			}
+
+			v.Dependencies = append(v.Dependencies, p)
+		}
+
+		db.AddVulnerabilities(v)
+	}
+}
+
+func (db *Database) add(v vulnerabilities.Vulnerability) int {
+	db.vulnerabilities = append(db.vulnerabilities, v)
+	return len(db.vulnerabilities) - 1
+}
+
+func (db *Database) index(a *affected) {
+	db.affected[a.identity.name] = append(db.affected[a.identity.name], a)
+}
+
+// productIdentity returns the identity and version, or range of versions, of
+// an affected product
+func productIdentity(p products.Product) (identity, string) {
+	if eco, org, name, version, ok := parsePURL(p.ExternalID); ok {
+		if p.Version != "" {
+			version = p.Version
+		}
+
+		return newIdentity(eco, org, name), version
+	}
+
+	if vendor, product, version, ok := parseCPE(p.ExternalID); ok {
+		if p.Version != "" {
+			version = p.Version
+		}
+
+		return newIdentity(cpeEcosystem, vendor, product), version
+	}
+
+	return nameIdentity(p.Language, p.Org, p.Name), p.Version
+}
+
+// Match returns the vulnerabilities affecting each of the packages, ordered
+// by package and then by vulnerability ID. Packages without a version are
+// not matched.
+func (db *Database) Match(pkgs ...Package) []Match {
+	matches := []Match{}
+
+	for _, pkg := range pkgs {
+		version := pkg.version()
+		if version == "" {
+			continue
+		}
+
+		found := []Match{}
+		seen := map[string]bool{}
+		for _, id := range pkg.identities() {
+			for _, a := range db.affected[id.name] {
+				v := db.vulnerabilities[a.vulnerability]
+				key := v.ExternalID
+				if key == "" {
+					key = fmt.Sprintf("#%v", a.vulnerability)
+				}
+
+				if seen[key] || !id.matches(a.identity) {
+					continue
+				}
+
+				ok, affectedRange, fixed := a.check(version)
+				if !ok {
+					continue
+				}
+
+				seen[key] = true
+				found = append(found, Match{
+					Package:       pkg,
+					Vulnerability: v,
+					AffectedRange: affectedRange,
+					FixedVersion:  fixed,
+				})
+			}
+		}
+
+		sort.SliceStable(found, func(i, j int) bool {
+			return found[i].Vulnerability.ExternalID < found[j].Vulnerability.ExternalID
+		})
+
+		matches = append(matches, found...)
+	}
+
+	return matches
+}
+
+// MatchDependencies returns the vulnerabilities affecting the dependencies,
+// including their transitive dependencies. A dependency that appears more
+// than once is only matched once.
+func (db *Database) MatchDependencies(deps ...dependencies.Dependency) []Match {
+	pkgs := []Package{}
+	seen := map[Package]bool{}
+
+	var walk func(deps []dependencies.Dependency)
+	walk = func(deps []dependencies.Dependency) {
+		for _, dep := range deps {
+			pkg := FromDependency(dep)
+			if !seen[pkg] {
+				seen[pkg] = true
+				pkgs = append(pkgs, pkg)
+			}
+
+			walk(dep.Dependencies)
+		}
+	}
+	walk(deps)
+
+	return db.Match(pkgs...)
+}
+
+// MatchProjects returns the vulnerabilities affecting the projects, such as
+// the components of an SBOM imported with ProjectsFromCycloneDX or
+// ProjectsFromSPDX
+func (db *Database) MatchProjects(ps ...projects.Project) []Match {
+	pkgs := make([]Package, 0, len(ps))
+	for _, p := range ps {
+		pkgs = append(pkgs, FromProject(p))
+	}
+
+	return db.Match(pkgs...)
+}
@@ -0,0 +1,280 @@
+package matcher
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/franela/goblin"
+	. "github.com/onsi/gomega"
+
+	"github.com/ion-channel/ionic/aliases"
+	"github.com/ion-channel/ionic/dependencies"
+	"github.com/ion-channel/ionic/dependencies/lockfiles"
+	"github.com/ion-channel/ionic/products"
+	"github.com/ion-channel/ionic/projects"
+	"github.com/ion-channel/ionic/scans"
+	"github.com/ion-channel/ionic/vulnerabilities"
+	"github.com/ion-channel/ionic/vulnerabilities/osv"
+)
+
+const sampleOSV = `[
+	{
+		"schema_version": "1.6.0",
+		"id": "GHSA-35jh-r3h4-6jhm",
+		"modified": "2023-01-01T00:00:00Z",
+		"aliases": ["CVE-2021-23337"],
+		"summary": "Command injection in lodash",
+		"severity": [{"type": "CVSS_V3", "score": "CVSS:3.1/AV:N/AC:L/PR:H/UI:N/S:U/C:H/I:H/A:H"}],
+		"affected": [{
+			"package": {"ecosystem": "npm", "name": "lodash"},
+			"ranges": [{"type": "SEMVER", "events": [{"introduced": "0"}, {"fixed": "4.17.21"}]}]
+		}]
+	},
+	{
+		"id": "GHSA-jfh8-c2jp-5v3q",
+		"modified": "2023-01-01T00:00:00Z",
+		"summary": "Remote code injection in Log4j",
+		"affected": [{
+			"package": {"ecosystem": "Maven", "name": "org.apache.logging.log4j:log4j-core"},
+			"ranges": [{"type": "ECOSYSTEM", "events": [
+				{"introduced": "2.13.0"}, {"fixed": "2.15.0"},
+				{"introduced": "2.0-beta9"}, {"fixed": "2.3.1"},
+				{"introduced": "2.4"}, {"fixed": "2.12.2"}
+			]}]
+		}]
+	},
+	{
+		"id": "PYSEC-2022-1",
+		"modified": "2023-01-01T00:00:00Z",
+		"affected": [{
+			"package": {"ecosystem": "PyPI", "name": "Django"},
+			"ranges": [{"type": "ECOSYSTEM", "events": [{"introduced": "3.2"}, {"last_affected": "3.2.12"}]}]
+		}]
+	},
+	{
+		"id": "GHSA-withdrawn",
+		"modified": "2023-01-01T00:00:00Z",
+		"withdrawn": "2023-01-02T00:00:00Z",
+		"affected": [{"package": {"ecosystem": "npm", "name": "lodash"}, "versions": ["4.17.20"]}]
+	}
+]`
+
+const sampleGoOSV = `{
+	"id": "GO-2022-1059",
+	"modified": "2023-01-01T00:00:00Z",
+	"aliases": ["CVE-2022-32149"],
+	"summary": "Denial of service via crafted Accept-Language header in golang.org/x/text/language",
+	"affected": [{
+		"package": {"ecosystem": "Go", "name": "golang.org/x/text"},
+		"ranges": [{"type": "SEMVER", "events": [{"introduced": "0"}, {"fixed": "0.3.8"}]}]
+	}]
+}`
+
+func TestMatcher(t *testing.T) {
+	g := goblin.Goblin(t)
+	RegisterFailHandler(func(m string, _ ...int) { g.Fail(m) })
+
+	g.Describe("OSV data", func() {
+		db := NewDatabase()
+		g.BeforeEach(func() {
+			db = NewDatabase()
+			Expect(db.Read(strings.NewReader(sampleOSV))).To(BeNil())
+		})
+
+		g.It("should skip withdrawn entries", func() {
+			Expect(db.Len()).To(Equal(3))
+		})
+
+		g.It("should match dependencies within a range", func() {
+			matches := db.MatchDependencies(dependencies.Dependency{
+				Name: "my-app", Version: "1.0.0", Type: "npm",
+				Dependencies: []dependencies.Dependency{
+					{Name: "lodash", Version: "4.17.20", Type: "npm"},
+					{Name: "left-pad", Version: "1.3.0", Type: "npm"},
+				},
+			})
+
+			Expect(matches).To(HaveLen(1))
+			Expect(matches[0].Vulnerability.ExternalID).To(Equal("GHSA-35jh-r3h4-6jhm"))
+			Expect(matches[0].Vulnerability.Aliases).To(Equal([]string{"CVE-2021-23337"}))
+			Expect(matches[0].AffectedRange).To(Equal("<4.17.21"))
+			Expect(matches[0].FixedVersion).To(Equal("4.17.21"))
+
+			Expect(db.Match(Package{Ecosystem: "npm", Name: "lodash", Version: "4.17.21"})).To(BeEmpty())
+		})
+
+		g.It("should match go modules named by their full paths", func() {
+			Expect(db.Read(strings.NewReader(sampleGoOSV))).To(BeNil())
+
+			resp, err := lockfiles.ParseGoMod(strings.NewReader("module example.com/app\n\ngo 1.17\n\nrequire golang.org/x/text v0.3.7\n"))
+			Expect(err).To(BeNil())
+
+			matches := db.MatchDependencies(resp.Dependencies...)
+			Expect(matches).To(HaveLen(1))
+			Expect(matches[0].Vulnerability.ExternalID).To(Equal("GO-2022-1059"))
+			Expect(matches[0].FixedVersion).To(Equal("0.3.8"))
+
+			Expect(db.Match(Package{Ecosystem: "go", Name: "golang.org/x/text", Version: "v0.3.8"})).To(BeEmpty())
+			Expect(db.Match(Package{Ecosystem: "go", Org: "golang.org/x", Name: "text", Version: "v0.3.7"})).To(HaveLen(1))
+		})
+
+		g.It("should find the range and nearest fixed version", func() {
+			matches := db.Match(
+				Package{PURL: "pkg:maven/org.apache.logging.log4j/log4j-core@2.8.1"},
+				Package{Ecosystem: "maven", Org: "org.apache.logging.log4j", Name: "log4j-core", Version: "2.14.1"},
+				Package{Ecosystem: "maven", Org: "org.apache.logging.log4j", Name: "log4j-core", Version: "2.12.2"},
+				Package{Ecosystem: "maven", Org: "org.other", Name: "log4j-core", Version: "2.14.1"},
+			)
+
+			Expect(matches).To(HaveLen(2))
+			Expect(matches[0].AffectedRange).To(Equal(">=2.4, <2.12.2"))
+			Expect(matches[0].FixedVersion).To(Equal("2.12.2"))
+			Expect(matches[1].AffectedRange).To(Equal(">=2.13.0, <2.15.0"))
+			Expect(matches[1].FixedVersion).To(Equal("2.15.0"))
+		})
+
+		g.It("should compare versions and names by ecosystem", func() {
+			matches := db.Match(
+				Package{Ecosystem: "pypi", Name: "django", Version: "3.2.12"},
+				Package{Ecosystem: "pypi", Name: "django", Version: "3.2.13"},
+				Package{Ecosystem: "npm", Name: "django", Version: "3.2.1"},
+			)
+
+			Expect(matches).To(HaveLen(1))
+			Expect(matches[0].Package.Version).To(Equal("3.2.12"))
+			Expect(matches[0].AffectedRange).To(Equal(">=3.2, <=3.2.12"))
+			Expect(matches[0].FixedVersion).To(Equal(""))
+		})
+	})
+
+	g.Describe("Exported vulnerabilities", func() {
+		g.It("should match products by CPE, version, and range", func() {
+			db := NewDatabase()
+			db.AddVulnerabilities(vulnerabilities.Vulnerability{
+				ExternalID: "CVE-2017-5638",
+				Dependencies: []products.Product{
+					{Org: "apache", Name: "struts", Version: "2.5.10", ExternalID: "cpe:/a:apache:struts:2.5.10"},
+					{Org: "apache", Name: "struts", Version: "< 2.3.32", ExternalID: "cpe:2.3:a:apache:struts:*:*:*:*:*:*:*:*"},
+				},
+			}, vulnerabilities.Vulnerability{
+				ExternalID: "CVE-2020-0001",
+				Dependencies: []products.Product{
+					{Org: "acme", Name: "widgets", Version: "[1.0,2.0)", Language: "java"},
+				},
+			})
+
+			name := "struts"
+			matches := db.MatchProjects(
+				projects.Project{Name: &name, CPE: "cpe:2.3:a:apache:struts:2.5.10:*:*:*:*:*:*:*"},
+				projects.Project{Name: &name, CPE: "cpe:2.3:a:apache:struts:2.3.31:*:*:*:*:*:*:*"},
+				projects.Project{Name: &name, CPE: "cpe:2.3:a:apache:struts:2.5.12:*:*:*:*:*:*:*"},
+				projects.Project{Name: &name, Aliases: []aliases.Alias{{Org: "acme", Name: "widgets", Version: "1.4"}}},
+			)
+
+			Expect(matches).To(HaveLen(3))
+			Expect(matches[0].AffectedRange).To(Equal("2.5.10"))
+			Expect(matches[0].FixedVersion).To(Equal(""))
+			Expect(matches[1].AffectedRange).To(Equal("< 2.3.32"))
+			Expect(matches[1].FixedVersion).To(Equal("2.3.32"))
+			Expect(matches[2].Vulnerability.ExternalID).To(Equal("CVE-2020-0001"))
+			Expect(matches[2].AffectedRange).To(Equal("[1.0,2.0)"))
+			Expect(matches[2].FixedVersion).To(Equal("2.0"))
+		})
+
+		g.It("should not take inclusive or implied bounds as fixed versions", func() {
+			db := NewDatabase()
+			db.AddVulnerabilities(vulnerabilities.Vulnerability{
+				ExternalID: "CVE-2020-0002",
+				Dependencies: []products.Product{
+					{Org: "acme", Name: "widgets", Version: "[1.0,2.0]", Language: "java"},
+					{Org: "acme", Name: "gadgets", Version: "<= 3.0", Language: "java"},
+				},
+			})
+
+			name := "widgets"
+			matches := db.MatchProjects(
+				projects.Project{Name: &name, Aliases: []aliases.Alias{{Org: "acme", Name: "widgets", Version: "1.4"}}},
+				projects.Project{Name: &name, Aliases: []aliases.Alias{{Org: "acme", Name: "gadgets", Version: "2.9"}}},
+			)
+
+			Expect(matches).To(HaveLen(2))
+			Expect(matches[0].FixedVersion).To(Equal(""))
+			Expect(matches[1].FixedVersion).To(Equal(""))
+		})
+
+		g.It("should match inputs converted from OSV by their ranges", func() {
+			var entries []osv.Entry
+			Expect(json.Unmarshal([]byte(sampleOSV), &entries)).To(BeNil())
+
+			db := NewDatabase()
+			db.AddInputs(osv.ToVulnerabilityInput(entries[0]))
+
+			matches := db.Match(
+				Package{Ecosystem: "npm", Name: "lodash", Version: "4.17.20"},
+				Package{Ecosystem: "npm", Name: "lodash", Version: "4.17.21"},
+				Package{Ecosystem: "npm", Name: "lodash", Version: "5.0.0"},
+			)
+
+			Expect(matches).To(HaveLen(1))
+			Expect(matches[0].Package.Version).To(Equal("4.17.20"))
+			Expect(matches[0].AffectedRange).To(Equal("<4.17.21"))
+		})
+
+		g.It("should read vulnerability inputs from files and directories", func() {
+			dir, err := ioutil.TempDir("", "matcher")
+			Expect(err).To(BeNil())
+			defer os.RemoveAll(dir)
+
+			input := `{"external_id": "GHSA-xxxx", "title": "bad", "dependencies": ["pkg:gem/rails@6.1.0", "acme/widgets@1.0"], "source": ["OSV"]}`
+			Expect(ioutil.WriteFile(filepath.Join(dir, "input.json"), []byte(input), 0644)).To(BeNil())
+			Expect(ioutil.WriteFile(filepath.Join(dir, "notes.txt"), []byte("not json"), 0644)).To(BeNil())
+
+			db, err := Open(dir)
+			Expect(err).To(BeNil())
+			Expect(db.Len()).To(Equal(1))
+
+			matches := db.Match(
+				Package{PURL: "pkg:gem/rails@6.1.0"},
+				Package{Org: "acme", Name: "widgets", Version: "1.0.0"},
+				Package{Ecosystem: "gem", Name: "rails", Version: "6.1.1"},
+			)
+			Expect(matches).To(HaveLen(2))
+
+			_, err = Open(filepath.Join(dir, "notes.txt"))
+			Expect(err).NotTo(BeNil())
+		})
+	})
+
+	g.Describe("Results", func() {
+		g.It("should be formatted as vulnerability scan results", func() {
+			db := NewDatabase()
+			Expect(db.Read(strings.NewReader(sampleOSV))).To(BeNil())
+
+			results := Results(db.Match(
+				Package{PURL: "pkg:npm/lodash@4.17.15"},
+				Package{Ecosystem: "maven", Org: "org.apache.logging.log4j", Name: "log4j-core", Version: "2.14.1"},
+			))
+
+			Expect(results.Meta.VulnerabilityCount).To(Equal(2))
+			Expect(results.Vulnerabilities).To(HaveLen(2))
+			Expect(results.Vulnerabilities[0].Name).To(Equal("lodash"))
+			Expect(results.Vulnerabilities[0].Version).To(Equal("4.17.15"))
+			Expect(results.Vulnerabilities[0].ExternalID).To(Equal("pkg:npm/lodash@4.17.15"))
+			Expect(results.Vulnerabilities[1].Query.Type).To(Equal("maven"))
+
+			b, err := json.Marshal(results)
+			Expect(err).To(BeNil())
+
+			var decoded scans.VulnerabilityResults
+			Expect(json.Unmarshal(b, &decoded)).To(BeNil())
+			vuln := decoded.Vulnerabilities[0].Vulnerabilities[0]
+			Expect(vuln.ExternalID).To(Equal("GHSA-35jh-r3h4-6jhm"))
+			Expect(vuln.AffectedRange).To(Equal("<4.17.21"))
+			Expect(vuln.FixedVersion).To(Equal("4.17.21"))
+		})
+	})
+}
@@ -0,0 +1,210 @@
+package matcher
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/ion-channel/ionic/cpe"
+	"github.com/ion-channel/ionic/dependencies"
+	"github.com/ion-channel/ionic/projects"
+	"github.com/ion-channel/ionic/purl"
+)
+
+// cpeEcosystem is the ecosystem of packages identified by a CPE, whose org and
+// name are the vendor and product of the CPE
+const cpeEcosystem = "cpe"
+
+var pypiSeparators = regexp.MustCompile(`[-_.]+`)
+
+// Package identifies a version of a package to be matched against the
+// vulnerabilities of a database. It is matched by its package URL, its CPE,
+// and its org and name, whichever are given.
+type Package struct {
+	// Ecosystem is the ecosystem of the package, such as a dependency type
+	// like "maven" or "npm", which selects how its versions are compared
+	Ecosystem string `json:"ecosystem,omitempty"`
+	Org       string `json:"org,omitempty"`
+	Name      string `json:"name"`
+	Version   string `json:"version"`
+	PURL      string `json:"purl,omitempty"`
+	CPE       string `json:"cpe,omitempty"`
+}
+
+// identity is a normalized ecosystem, org, and name a package is known by
+type identity struct {
+	ecosystem string
+	org       string
+	name      string
+}
+
+// FromDependency returns the package of a resolved dependency, without its
+// own dependencies
+func FromDependency(dep dependencies.Dependency) Package {
+	return Package{
+		Ecosystem: dep.Type,
+		Org:       dep.Org,
+		Name:      dep.Name,
+		Version:   dep.Version,
+	}
+}
+
+// FromProject returns the package of a project, such as a component imported
+// from an SBOM with ProjectsFromCycloneDX or ProjectsFromSPDX, identified by
+// its package URL, CPE, and first alias
+func FromProject(p projects.Project) Package {
+	pkg := Package{
+		PURL: p.PURL,
+		CPE:  p.CPE,
+	}
+
+	if p.Name != nil {
+		pkg.Name = *p.Name
+	}
+
+	if len(p.Aliases) > 0 {
+		pkg.Org = p.Aliases[0].Org
+		pkg.Version = p.Aliases[0].Version
+		if p.Aliases[0].Name != "" {
+			pkg.Name = p.Aliases[0].Name
+		}
+	}
+
+	if pkg.Version == "" {
+		if _, _, _, version, ok := parsePURL(p.PURL); ok {
+			pkg.Version = version
+		} else if _, _, version, ok := parseCPE(p.CPE); ok {
+			pkg.Version = version
+		}
+	}
+
+	return pkg
+}
+
+// version returns the version of the package, falling back to the version of
+// its package URL or CPE
+func (p Package) version() string {
+	if p.Version != "" {
+		return p.Version
+	}
+
+	if _, _, _, version, ok := parsePURL(p.PURL); ok && version != "" {
+		return version
+	}
+
+	if _, _, version, ok := parseCPE(p.CPE); ok {
+		return version
+	}
+
+	return ""
+}
+
+// ecosystem returns the ecosystem of the package, preferring the type of its
+// package URL
+func (p Package) ecosystem() string {
+	if eco, _, _, _, ok := parsePURL(p.PURL); ok {
+		return eco
+	}
+
+	return normalizeEcosystem(p.Ecosystem)
+}
+
+// identities returns every identity the package is known by
+func (p Package) identities() []identity {
+	ids := []identity{}
+
+	if eco, org, name, _, ok := parsePURL(p.PURL); ok {
+		ids = append(ids, newIdentity(eco, org, name))
+	}
+
+	if vendor, product, _, ok := parseCPE(p.CPE); ok {
+		ids = append(ids, newIdentity(cpeEcosystem, vendor, product))
+	}
+
+	if p.Name != "" {
+		ids = append(ids, nameIdentity(p.ecosystem(), p.Org, p.Name))
+	}
+
+	return ids
+}
+
+// matches returns true if the identity of a package is the identity of an
+// affected package. Ecosystems and orgs are only compared when both are known,
+// except for CPEs, which only match other CPEs.
+func (id identity) matches(affected identity) bool {
+	if id.name != affected.name {
+		return false
+	}
+
+	if (id.ecosystem == cpeEcosystem) != (affected.ecosystem == cpeEcosystem) {
+		return false
+	}
+
+	if id.ecosystem != "" && affected.ecosystem != "" && id.ecosystem != affected.ecosystem {
+		return false
+	}
+
+	return id.org == "" || affected.org == "" || affected.org == "*" || id.org == affected.org
+}
+
+func newIdentity(ecosystem, org, name string) identity {
+	ecosystem = normalizeEcosystem(ecosystem)
+	return identity{
+		ecosystem: ecosystem,
+		org:       normalizeName(ecosystem, strings.TrimPrefix(org, "@")),
+		name:      normalizeName(ecosystem, name),
+	}
+}
+
+// nameIdentity returns the identity of a package named by an org and name,
+// split into a namespace and name the way its package URL would be, so that
+// a Go module named by its full path or a Maven package named
+// "group:artifact" has the same identity as one given its org separately
+func nameIdentity(ecosystem, org, name string) identity {
+	p, err := purl.FromOrgName(ecosystem, org, name, "")
+	if err != nil {
+		return newIdentity(ecosystem, org, name)
+	}
+
+	return newIdentity(p.Type, p.Namespace, p.Name)
+}
+
+// normalizeEcosystem returns the package URL type of an ecosystem, ignoring
+// release suffixes such as the "11" of "Debian:11"
+func normalizeEcosystem(ecosystem string) string {
+	return purl.TypeFor(ecosystem)
+}
+
+// normalizeName lower cases a name, and normalizes the separators of Python
+// package names as PEP 503 does
+func normalizeName(ecosystem, name string) string {
+	name = strings.ToLower(strings.TrimSpace(name))
+	if ecosystem == "pypi" {
+		name = pypiSeparators.ReplaceAllString(name, "-")
+	}
+
+	return name
+}
+
+// parsePURL returns the type, namespace, name, and version of a package URL
+// such as pkg:maven/org.apache.commons/commons-text@1.9
+func parsePURL(s string) (string, string, string, string, bool) {
+	p, err := purl.Parse(s)
+	if err != nil {
+		return "", "", "", "", false
+	}
+
+	return p.Type, p.Namespace, p.Name, p.Version, true
+}
+
+// parseCPE returns the vendor, product, and version of a CPE 2.2 URI such as
+// cpe:/a:apache:struts:2.5.10 or a CPE 2.3 formatted string such as
+// cpe:2.3:a:apache:struts:2.5.10:*:*:*:*:*:*:*. Any and not applicable
+// versions are returned empty.
+func parseCPE(s string) (string, string, string, bool) {
+	name, err := cpe.Parse(s)
+	if err != nil {
+		return "", "", "", false
+	}
+
+	return name.Vendor.Text(), name.Product.Text(), name.Version.Text(), name.Product.Text() != ""
+}
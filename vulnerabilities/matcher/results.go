@@ -0,0 +1,69 @@
+package matcher
+
+import (
+	"github.com/ion-channel/ionic/scans"
+)
+
+// Results returns the matches as the results of a vulnerability scan, with a
+// product for each matched package and the affected range and fixed version
+// of each of its vulnerabilities
+func Results(matches []Match) *scans.VulnerabilityResults {
+	results := &scans.VulnerabilityResults{
+		Vulnerabilities: []scans.VulnerabilityResultsProduct{},
+	}
+
+	index := map[Package]int{}
+	for _, m := range matches {
+		i, ok := index[m.Package]
+		if !ok {
+			i = len(results.Vulnerabilities)
+			index[m.Package] = i
+			results.Vulnerabilities = append(results.Vulnerabilities, resultsProduct(m.Package))
+		}
+
+		product := &results.Vulnerabilities[i]
+		product.Vulnerabilities = append(product.Vulnerabilities, scans.VulnerabilityResultsVulnerability{
+			Vulnerability: m.Vulnerability,
+			Dependencies:  []scans.VulnerabilityResultsProduct{},
+			AffectedRange: m.AffectedRange,
+			FixedVersion:  m.FixedVersion,
+		})
+	}
+
+	results.Meta.VulnerabilityCount = len(matches)
+	return results
+}
+
+func resultsProduct(pkg Package) scans.VulnerabilityResultsProduct {
+	version := pkg.version()
+
+	externalID := pkg.PURL
+	if externalID == "" {
+		externalID = pkg.CPE
+	}
+
+	org, name := pkg.Org, pkg.Name
+	if name == "" {
+		if _, purlOrg, purlName, _, ok := parsePURL(pkg.PURL); ok {
+			org, name = purlOrg, purlName
+		} else if vendor, product, _, ok := parseCPE(pkg.CPE); ok {
+			org, name = vendor, product
+		}
+	}
+
+	return scans.VulnerabilityResultsProduct{
+		ExternalID:      externalID,
+		Name:            name,
+		Org:             org,
+		Version:         version,
+		Aliases:         []string{},
+		Vulnerabilities: []scans.VulnerabilityResultsVulnerability{},
+		Query: scans.Dependency{
+			Org:          org,
+			Name:         name,
+			Type:         pkg.Ecosystem,
+			Version:      version,
+			Dependencies: []scans.Dependency{},
+		},
+	}
+}
@@ -0,0 +1,481 @@
+package osv
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/ion-channel/ionic/products"
+	"github.com/ion-channel/ionic/purl"
+	"github.com/ion-channel/ionic/vulnerabilities"
+	"github.com/ion-channel/ionic/vulnerabilities/cvss"
+)
+
+// SourceName is the vulnerability source given to entries imported from OSV
+const SourceName = "OSV"
+
+var referenceTypes = map[string]string{
+	ReferenceAdvisory:        ReferenceAdvisory,
+	ReferenceArticle:         ReferenceArticle,
+	ReferenceDetection:       ReferenceDetection,
+	ReferenceDiscussion:      ReferenceDiscussion,
+	ReferenceReport:          ReferenceReport,
+	ReferenceFix:             ReferenceFix,
+	ReferenceIntroduced:      ReferenceIntroduced,
+	ReferencePackage:         ReferencePackage,
+	ReferenceEvidence:        ReferenceEvidence,
+	ReferenceWeb:             ReferenceWeb,
+	"PATCH":                  ReferenceFix,
+	"COMMIT":                 ReferenceFix,
+	"VENDOR_ADVISORY":        ReferenceAdvisory,
+	"THIRD_PARTY_ADVISORY":   ReferenceAdvisory,
+	"US_GOVERNMENT_RESOURCE": ReferenceAdvisory,
+	"CERT":                   ReferenceAdvisory,
+	"EXPLOIT":                ReferenceEvidence,
+	"ISSUE_TRACKING":         ReferenceReport,
+	"BUG":                    ReferenceReport,
+	"MAILING_LIST":           ReferenceDiscussion,
+	"PRODUCT":                ReferencePackage,
+}
+
+// FromVulnerability converts a vulnerability into an OSV entry. The external
+// ID and aliases become the ID and aliases of the entry, the title and summary
+// become its summary and details, and the score details become its severity.
+// Dependencies are grouped by package into affected entries, with the
+// ecosystem taken from the product language or package URL and one range for
+// each affected version.
+func FromVulnerability(v vulnerabilities.Vulnerability) Entry {
+	e := Entry{
+		SchemaVersion: SchemaVersion,
+		ID:            v.ExternalID,
+		Summary:       v.Title,
+		Details:       v.Summary,
+		Modified:      v.ModifiedAt,
+		Severity:      severities(v),
+		Affected:      affected(v.Dependencies),
+	}
+
+	if e.Modified.IsZero() {
+		e.Modified = v.UpdatedAt
+	}
+
+	if !v.PublishedAt.IsZero() {
+		published := v.PublishedAt
+		e.Published = &published
+	}
+
+	for _, alias := range v.Aliases {
+		if alias != "" && alias != e.ID && !contains(e.Aliases, alias) {
+			e.Aliases = append(e.Aliases, alias)
+		}
+	}
+
+	seen := map[string]bool{}
+	for _, ref := range v.References {
+		if ref.URL == "" || seen[ref.URL] {
+			continue
+		}
+
+		seen[ref.URL] = true
+		e.References = append(e.References, Reference{
+			Type: referenceType(ref.Type),
+			URL:  ref.URL,
+		})
+	}
+
+	return e
+}
+
+// ToVulnerability converts an OSV entry into a vulnerability. The most recent
+// CVSS version among its severities becomes the vector and score, and each
+// affected version becomes a dependency identified by its package URL.
+// Affected packages that only give ranges without enumerated versions become a
+// dependency for each interval of the ranges, with a version constraint such
+// as ">=1.0.0, <1.2.3" as its version. Packages only affected within git
+// ranges are left out, and packages without any versions or ranges become a
+// single dependency without a version.
+func ToVulnerability(e Entry) vulnerabilities.Vulnerability {
+	v := vulnerabilities.Vulnerability{
+		ExternalID:   e.ID,
+		Title:        e.Summary,
+		Summary:      e.Details,
+		ModifiedAt:   e.Modified,
+		UpdatedAt:    e.Modified,
+		Dependencies: []products.Product{},
+		References:   []vulnerabilities.Reference{},
+	}
+
+	if v.Summary == "" {
+		v.Summary = e.Summary
+	}
+
+	if e.Published != nil {
+		v.PublishedAt = *e.Published
+	}
+
+	for _, alias := range e.Aliases {
+		if alias != e.ID && !contains(v.Aliases, alias) {
+			v.Aliases = append(v.Aliases, alias)
+		}
+	}
+
+	for _, ref := range e.References {
+		v.References = append(v.References, vulnerabilities.Reference{
+			Type:   ref.Type,
+			Source: SourceName,
+			URL:    ref.URL,
+		})
+	}
+
+	sevs := append([]Severity{}, e.Severity...)
+	for _, a := range e.Affected {
+		sevs = append(sevs, a.Severity...)
+
+		language := ""
+		if eco, ok := lookupEcosystem(a.Package.Ecosystem); ok {
+			language = eco.languages[0]
+		}
+
+		org, name := splitName(a.Package.Ecosystem, a.Package.Name)
+		versions, constraints := affectedVersions(a)
+		if len(versions) == 0 && len(constraints) == 0 {
+			if len(a.Ranges) > 0 {
+				continue
+			}
+
+			versions = []string{""}
+		}
+
+		for _, version := range append(versions, constraints...) {
+			// the package URLs of constraints do not have a version
+			exact := !contains(constraints, version)
+			purl := purlFor(a.Package.Ecosystem, a.Package.Name, "")
+			if exact {
+				purl = purlFor(a.Package.Ecosystem, a.Package.Name, version)
+			}
+
+			if purl == "" && a.Package.PURL != "" && (version == "" || !exact) {
+				purl = a.Package.PURL
+			}
+
+			v.Dependencies = append(v.Dependencies, products.Product{
+				Name:       name,
+				Org:        org,
+				Version:    version,
+				Language:   language,
+				ExternalID: purl,
+			})
+		}
+	}
+
+	applySeverities(&v, sevs)
+	return v
+}
+
+// ToVulnerabilityInput converts an OSV entry into an input ready to be added
+// with AddVulnerability, whose dependencies are the package URLs of the
+// affected versions. Version constraints are kept as the versions of the
+// package URLs.
+func ToVulnerabilityInput(e Entry) vulnerabilities.VulnerabilityInput {
+	v := ToVulnerability(e)
+
+	deps := []string{}
+	for _, p := range v.Dependencies {
+		id := p.ExternalID
+		if pu, err := purl.Parse(id); err == nil && p.Version != "" {
+			pu.Version = p.Version
+			id = pu.String()
+		}
+
+		if id == "" {
+			id = strings.Trim(p.Org+"/"+p.Name, "/")
+			if p.Version != "" {
+				id += "@" + p.Version
+			}
+		}
+
+		if !contains(deps, id) {
+			deps = append(deps, id)
+		}
+	}
+
+	modified := e.Modified
+	return vulnerabilities.VulnerabilityInput{
+		Vulnerability: v,
+		Dependencies:  deps,
+		Source:        []string{SourceName},
+		UpdatedAt:     &modified,
+	}
+}
+
+// severities returns the CVSS vectors of the vulnerability as OSV severities,
+// one per CVSS version
+func severities(v vulnerabilities.Vulnerability) []Severity {
+	candidates := []string{v.Vector}
+	if v.ScoreDetails.CVSSv4 != nil {
+		candidates = append(candidates, v.ScoreDetails.CVSSv4.VectorString)
+	}
+
+	if v.ScoreDetails.CVSSv3 != nil && v.ScoreDetails.CVSSv3.VectorString != "" {
+		vector := v.ScoreDetails.CVSSv3.VectorString
+		if !strings.HasPrefix(strings.ToUpper(vector), "CVSS:") {
+			vector = "CVSS:3.0/" + vector
+		}
+
+		candidates = append(candidates, vector)
+	}
+
+	if v.ScoreDetails.CVSSv2 != nil {
+		candidates = append(candidates, v.ScoreDetails.CVSSv2.VectorString)
+	}
+
+	byType := map[string]string{}
+	for _, c := range candidates {
+		vector, err := cvss.Parse(c)
+		if err != nil {
+			continue
+		}
+
+		t := severityType(vector.Version())
+		if _, ok := byType[t]; !ok {
+			byType[t] = vector.String()
+		}
+	}
+
+	sevs := []Severity{}
+	for _, t := range []string{SeverityCVSSv2, SeverityCVSSv3, SeverityCVSSv4} {
+		if score, ok := byType[t]; ok {
+			sevs = append(sevs, Severity{Type: t, Score: score})
+		}
+	}
+
+	return sevs
+}
+
+// applySeverities fills in the score details of the vulnerability from the
+// given OSV severities, and takes its vector and score from the most recent
+// CVSS version among them
+func applySeverities(v *vulnerabilities.Vulnerability, sevs []Severity) {
+	var best *cvss.Vector
+	for _, s := range sevs {
+		vector, err := cvss.Parse(s.Score)
+		if err != nil {
+			continue
+		}
+
+		switch vector.Version() {
+		case cvss.Version20:
+			if v.ScoreDetails.CVSSv2 == nil {
+				v.ScoreDetails.CVSSv2 = vulnerabilities.NewV2FromShorthand(s.Score)
+			}
+		case cvss.Version30, cvss.Version31:
+			if v.ScoreDetails.CVSSv3 == nil {
+				v.ScoreDetails.CVSSv3 = vulnerabilities.NewV3FromShorthand(s.Score)
+			}
+		case cvss.Version40:
+			if v.ScoreDetails.CVSSv4 == nil {
+				v.ScoreDetails.CVSSv4 = vulnerabilities.NewV4FromShorthand(s.Score)
+			}
+		}
+
+		if best == nil || vector.Version() > best.Version() {
+			best = vector
+		}
+	}
+
+	if best == nil {
+		return
+	}
+
+	v.Vector = best.String()
+	v.Score = fmt.Sprintf("%.1f", best.BaseScore())
+	v.ScoreVersion = string(best.Version())
+	v.ScoreSystem = "CVSS"
+}
+
+// affected groups the given products by package into OSV affected entries
+func affected(deps []products.Product) []Affected {
+	index := map[string]int{}
+	affs := []Affected{}
+
+	for _, p := range deps {
+		eco := ecosystemForLanguage(p.Language)
+		if eco == "" {
+			eco = ecosystemForPURL(p.ExternalID)
+		}
+
+		name := joinName(eco, p.Org, p.Name)
+		key := eco + "|" + name
+
+		i, ok := index[key]
+		if !ok {
+			i = len(affs)
+			index[key] = i
+			affs = append(affs, Affected{
+				Package: Package{
+					Ecosystem: eco,
+					Name:      name,
+					PURL:      purlFor(eco, name, ""),
+				},
+			})
+		}
+
+		if events, ok := constraintEvents(p.Version); ok {
+			affs[i].Ranges = append(affs[i].Ranges, Range{Type: RangeEcosystem, Events: events})
+		} else if p.Version != "" && !contains(affs[i].Versions, p.Version) {
+			affs[i].Versions = append(affs[i].Versions, p.Version)
+		}
+	}
+
+	for ii := range affs {
+		sort.Strings(affs[ii].Versions)
+		for _, version := range affs[ii].Versions {
+			affs[ii].Ranges = append(affs[ii].Ranges, Range{
+				Type: RangeEcosystem,
+				Events: []Event{
+					{Introduced: version},
+					{LastAffected: version},
+				},
+			})
+		}
+	}
+
+	return affs
+}
+
+// constraintEvents returns the events of a version constraint in the form
+// written by ToVulnerability, such as ">=1.0.0, <1.2.3", and whether the
+// version is such a constraint
+func constraintEvents(version string) ([]Event, bool) {
+	if version == "*" {
+		return []Event{{Introduced: "0"}}, true
+	}
+
+	if !strings.ContainsAny(version, "<>=") {
+		return nil, false
+	}
+
+	introduced := Event{Introduced: "0"}
+	var upper *Event
+	for _, part := range strings.Split(version, ",") {
+		part = strings.TrimSpace(part)
+		switch {
+		case strings.HasPrefix(part, ">="):
+			introduced.Introduced = strings.TrimSpace(part[2:])
+		case strings.HasPrefix(part, "<="):
+			upper = &Event{LastAffected: strings.TrimSpace(part[2:])}
+		case strings.HasPrefix(part, "<"):
+			upper = &Event{Fixed: strings.TrimSpace(part[1:])}
+		default:
+			return nil, false
+		}
+	}
+
+	events := []Event{introduced}
+	if upper != nil {
+		events = append(events, *upper)
+	}
+
+	return events, true
+}
+
+// affectedVersions returns the enumerated versions of an affected package,
+// including intervals that cover exactly one version, and the other intervals
+// of its ranges as version constraints when it does not enumerate its versions
+func affectedVersions(a Affected) ([]string, []string) {
+	versions := append([]string{}, a.Versions...)
+	constraints := []string{}
+	for _, r := range a.Ranges {
+		if r.Type == RangeGit {
+			continue
+		}
+
+		introduced, open := "", false
+		for _, e := range r.Events {
+			switch {
+			case e.Introduced != "":
+				if !open {
+					introduced, open = e.Introduced, true
+				}
+			case e.Fixed != "" && open:
+				constraints = append(constraints, versionConstraint(introduced, "<"+e.Fixed))
+				open = false
+			case e.LastAffected != "" && open:
+				if e.LastAffected == introduced {
+					if !contains(versions, introduced) {
+						versions = append(versions, introduced)
+					}
+				} else {
+					constraints = append(constraints, versionConstraint(introduced, "<="+e.LastAffected))
+				}
+
+				open = false
+			}
+		}
+
+		if open {
+			constraints = append(constraints, versionConstraint(introduced, ""))
+		}
+	}
+
+	// enumerated versions are complete, so the ranges they were taken from
+	// are not needed
+	if len(a.Versions) > 0 {
+		constraints = constraints[:0]
+	}
+
+	return versions, constraints
+}
+
+// versionConstraint returns the constraint of an interval from the introduced
+// version up to the given upper bound, such as ">=1.0.0, <1.2.3"
+func versionConstraint(introduced, upper string) string {
+	parts := []string{}
+	if introduced != "0" {
+		parts = append(parts, ">="+introduced)
+	}
+
+	if upper != "" {
+		parts = append(parts, upper)
+	}
+
+	if len(parts) == 0 {
+		return "*"
+	}
+
+	return strings.Join(parts, ", ")
+}
+
+// severityType returns the OSV severity type of a CVSS version
+func severityType(version cvss.Version) string {
+	switch version {
+	case cvss.Version20:
+		return SeverityCVSSv2
+	case cvss.Version40:
+		return SeverityCVSSv4
+	}
+
+	return SeverityCVSSv3
+}
+
+// referenceType returns the OSV reference type of an Ion Channel reference
+// type, defaulting to a plain web page
+func referenceType(t string) string {
+	t = strings.ToUpper(strings.TrimSpace(t))
+	t = strings.NewReplacer("-", "_", " ", "_").Replace(t)
+	if osvType, ok := referenceTypes[t]; ok {
+		return osvType
+	}
+
+	return ReferenceWeb
+}
+
+func contains(list []string, s string) bool {
+	for _, l := range list {
+		if l == s {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,136 @@
+package osv
+
+import (
+	"strings"
+
+	"github.com/ion-channel/ionic/purl"
+)
+
+// ecosystem relates an OSV ecosystem to the languages of Ion Channel products
+// and the package URL type of its packages
+type ecosystem struct {
+	name      string
+	languages []string
+	purlType  string
+}
+
+var ecosystems = []ecosystem{
+	{"Maven", []string{"java", "kotlin", "scala"}, "maven"},
+	{"npm", []string{"javascript", "typescript", "node"}, "npm"},
+	{"PyPI", []string{"python"}, "pypi"},
+	{"RubyGems", []string{"ruby"}, "gem"},
+	{"Go", []string{"go", "golang"}, "golang"},
+	{"Packagist", []string{"php"}, "composer"},
+	{"crates.io", []string{"rust"}, "cargo"},
+	{"NuGet", []string{"c#", "csharp", ".net", "dotnet"}, "nuget"},
+	{"Hex", []string{"elixir", "erlang"}, "hex"},
+	{"Pub", []string{"dart"}, "pub"},
+	{"Hackage", []string{"haskell"}, "hackage"},
+	{"CRAN", []string{"r"}, "cran"},
+	{"SwiftURL", []string{"swift"}, "swift"},
+}
+
+// ecosystemForLanguage returns the OSV ecosystem of the given product
+// language, or an empty string if it has none
+func ecosystemForLanguage(language string) string {
+	language = strings.ToLower(strings.TrimSpace(language))
+	for _, e := range ecosystems {
+		for _, l := range e.languages {
+			if l == language {
+				return e.name
+			}
+		}
+	}
+
+	return ""
+}
+
+// ecosystemForPURL returns the OSV ecosystem of the given package URL, or an
+// empty string if it has none
+func ecosystemForPURL(purl string) string {
+	if !strings.HasPrefix(purl, "pkg:") {
+		return ""
+	}
+
+	t := strings.ToLower(strings.SplitN(strings.TrimPrefix(purl, "pkg:"), "/", 2)[0])
+	for _, e := range ecosystems {
+		if e.purlType == t {
+			return e.name
+		}
+	}
+
+	return ""
+}
+
+// lookupEcosystem returns the details of the given OSV ecosystem. Ecosystems
+// may carry a release suffix, such as "Debian:11", which is ignored.
+func lookupEcosystem(name string) (ecosystem, bool) {
+	name = strings.SplitN(name, ":", 2)[0]
+	for _, e := range ecosystems {
+		if strings.EqualFold(e.name, name) {
+			return e, true
+		}
+	}
+
+	return ecosystem{}, false
+}
+
+// splitName splits a package name of the given ecosystem into the org and
+// name used by Ion Channel products
+func splitName(eco, name string) (string, string) {
+	switch strings.SplitN(eco, ":", 2)[0] {
+	case "Maven":
+		if i := strings.Index(name, ":"); i >= 0 {
+			return name[:i], name[i+1:]
+		}
+	case "npm":
+		if strings.HasPrefix(name, "@") {
+			if i := strings.Index(name, "/"); i >= 0 {
+				return name[1:i], name[i+1:]
+			}
+		}
+	case "Go", "Packagist":
+		if i := strings.LastIndex(name, "/"); i >= 0 {
+			return name[:i], name[i+1:]
+		}
+	}
+
+	return "", name
+}
+
+// joinName joins the org and name of an Ion Channel product into a package
+// name of the given ecosystem
+func joinName(eco, org, name string) string {
+	if org == "" {
+		return name
+	}
+
+	switch eco {
+	case "Maven":
+		return org + ":" + name
+	case "npm":
+		return "@" + strings.TrimPrefix(org, "@") + "/" + name
+	case "Go", "Packagist":
+		return org + "/" + name
+	}
+
+	return name
+}
+
+// purlFor returns the package URL of a package in the given ecosystem, or an
+// empty string if the ecosystem has no package URL type or the package can not
+// be named by one. The version is omitted when empty.
+func purlFor(eco, name, version string) string {
+	e, ok := lookupEcosystem(eco)
+	if !ok || name == "" {
+		return ""
+	}
+
+	org, name := splitName(e.name, name)
+	p, err := purl.FromOrgName(e.purlType, org, name, version)
+	if err != nil {
+		return ""
+	}
+
+	return p.String()
+}
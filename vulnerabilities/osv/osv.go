@@ -0,0 +1,239 @@
+// Package osv converts vulnerabilities to and from the Open Source
+// Vulnerability (OSV) format, as published by osv.dev and the GitHub Advisory
+// Database. See https://ossf.github.io/osv-schema/ for the schema.
+package osv
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/ion-channel/ionic/vulnerabilities"
+)
+
+const (
+	// SchemaVersion is the version of the OSV schema written by this package
+	SchemaVersion = "1.6.0"
+
+	// SeverityCVSSv2 is the severity type of a CVSS v2 vector
+	SeverityCVSSv2 = "CVSS_V2"
+	// SeverityCVSSv3 is the severity type of a CVSS v3.0 or v3.1 vector
+	SeverityCVSSv3 = "CVSS_V3"
+	// SeverityCVSSv4 is the severity type of a CVSS v4.0 vector
+	SeverityCVSSv4 = "CVSS_V4"
+
+	// RangeSemver is a range of semantic versions
+	RangeSemver = "SEMVER"
+	// RangeEcosystem is a range of versions ordered by the package ecosystem
+	RangeEcosystem = "ECOSYSTEM"
+	// RangeGit is a range of git commits
+	RangeGit = "GIT"
+
+	// ReferenceAdvisory is a published security advisory
+	ReferenceAdvisory = "ADVISORY"
+	// ReferenceArticle is an article or blog post describing the vulnerability
+	ReferenceArticle = "ARTICLE"
+	// ReferenceDetection is a tool or script that detects the vulnerability
+	ReferenceDetection = "DETECTION"
+	// ReferenceDiscussion is a mailing list or forum discussion
+	ReferenceDiscussion = "DISCUSSION"
+	// ReferenceReport is a report, such as an issue tracker entry
+	ReferenceReport = "REPORT"
+	// ReferenceFix is a commit or patch fixing the vulnerability
+	ReferenceFix = "FIX"
+	// ReferenceIntroduced is a commit introducing the vulnerability
+	ReferenceIntroduced = "INTRODUCED"
+	// ReferencePackage is the home page of the affected package
+	ReferencePackage = "PACKAGE"
+	// ReferenceEvidence is a proof of concept or exploit
+	ReferenceEvidence = "EVIDENCE"
+	// ReferenceWeb is any other web page
+	ReferenceWeb = "WEB"
+)
+
+// Entry represents a single vulnerability in the OSV format
+type Entry struct {
+	SchemaVersion    string          `json:"schema_version,omitempty"`
+	ID               string          `json:"id"`
+	Modified         time.Time       `json:"modified"`
+	Published        *time.Time      `json:"published,omitempty"`
+	Withdrawn        *time.Time      `json:"withdrawn,omitempty"`
+	Aliases          []string        `json:"aliases,omitempty"`
+	Related          []string        `json:"related,omitempty"`
+	Summary          string          `json:"summary,omitempty"`
+	Details          string          `json:"details,omitempty"`
+	Severity         []Severity      `json:"severity,omitempty"`
+	Affected         []Affected      `json:"affected,omitempty"`
+	References       []Reference     `json:"references,omitempty"`
+	DatabaseSpecific json.RawMessage `json:"database_specific,omitempty"`
+}
+
+// Severity represents a scored severity of an OSV entry, where the score is
+// the vector of the scoring system given by the type
+type Severity struct {
+	Type  string `json:"type"`
+	Score string `json:"score"`
+}
+
+// Affected represents a package affected by an OSV entry, and the versions of
+// it that are affected
+type Affected struct {
+	Package           Package         `json:"package"`
+	Severity          []Severity      `json:"severity,omitempty"`
+	Ranges            []Range         `json:"ranges,omitempty"`
+	Versions          []string        `json:"versions,omitempty"`
+	EcosystemSpecific json.RawMessage `json:"ecosystem_specific,omitempty"`
+	DatabaseSpecific  json.RawMessage `json:"database_specific,omitempty"`
+}
+
+// Package represents a package within an ecosystem, such as "npm" or "PyPI"
+type Package struct {
+	Ecosystem string `json:"ecosystem"`
+	Name      string `json:"name"`
+	PURL      string `json:"purl,omitempty"`
+}
+
+// OrgAndName returns the org and name of the package, splitting its name the
+// way its ecosystem namespaces packages, such as the group and artifact of a
+// Maven package
+func (p Package) OrgAndName() (string, string) {
+	return splitName(p.Ecosystem, p.Name)
+}
+
+// Range represents a range of affected versions as a list of events
+type Range struct {
+	Type   string  `json:"type"`
+	Repo   string  `json:"repo,omitempty"`
+	Events []Event `json:"events"`
+}
+
+// Event represents a point in a range at which a version becomes affected or
+// unaffected. Only one of its fields is set.
+type Event struct {
+	Introduced   string `json:"introduced,omitempty"`
+	Fixed        string `json:"fixed,omitempty"`
+	LastAffected string `json:"last_affected,omitempty"`
+	Limit        string `json:"limit,omitempty"`
+}
+
+// Reference represents a typed link to more information about an OSV entry
+type Reference struct {
+	Type string `json:"type"`
+	URL  string `json:"url"`
+}
+
+// Read decodes the OSV entries of the reader, which holds either a single
+// entry, as published by osv.dev, or a JSON array of entries, as written by
+// Write
+func Read(r io.Reader) ([]Entry, error) {
+	b, err := io.ReadAll(r)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read osv entries: %v", err.Error())
+	}
+
+	b = bytes.TrimSpace(b)
+
+	entries := []Entry{}
+	if len(b) > 0 && b[0] == '[' {
+		err = json.Unmarshal(b, &entries)
+	} else {
+		var e Entry
+		err = json.Unmarshal(b, &e)
+		entries = append(entries, e)
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode osv entry: %v", err.Error())
+	}
+
+	for ii := range entries {
+		if entries[ii].ID == "" {
+			return nil, fmt.Errorf("osv entry has no id")
+		}
+	}
+
+	return entries, nil
+}
+
+// ReadFile decodes the OSV entries in the given file, which holds either a
+// single entry or a JSON array of entries
+func ReadFile(path string) ([]Entry, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open osv file: %v", err.Error())
+	}
+	defer f.Close()
+
+	entries, err := Read(f)
+	if err != nil {
+		return nil, fmt.Errorf("%v: %v", path, err.Error())
+	}
+
+	return entries, nil
+}
+
+// ReadDir decodes every OSV entry in the JSON files under the given
+// directory, such as an extracted osv.dev ecosystem export or files written
+// by Write, and returns them as inputs ready to be added with
+// AddVulnerability. Withdrawn entries are skipped.
+func ReadDir(dir string) ([]vulnerabilities.VulnerabilityInput, error) {
+	paths := []string{}
+	err := filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if !info.IsDir() && strings.EqualFold(filepath.Ext(path), ".json") {
+			paths = append(paths, path)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to read osv directory: %v", err.Error())
+	}
+
+	sort.Strings(paths)
+
+	inputs := []vulnerabilities.VulnerabilityInput{}
+	for _, path := range paths {
+		entries, err := ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, e := range entries {
+			if e.Withdrawn != nil {
+				continue
+			}
+
+			inputs = append(inputs, ToVulnerabilityInput(e))
+		}
+	}
+
+	return inputs, nil
+}
+
+// Write encodes the given vulnerabilities as a JSON array of OSV entries
+func Write(w io.Writer, vulns ...vulnerabilities.Vulnerability) error {
+	entries := make([]Entry, 0, len(vulns))
+	for ii := range vulns {
+		entries = append(entries, FromVulnerability(vulns[ii]))
+	}
+
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+
+	err := enc.Encode(entries)
+	if err != nil {
+		return fmt.Errorf("failed to encode osv entries: %v", err.Error())
+	}
+
+	return nil
+}
@@ -0,0 +1,285 @@
+package osv
+
+import (
+	"bytes"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/franela/goblin"
+	"github.com/ion-channel/ionic/products"
+	"github.com/ion-channel/ionic/vulnerabilities"
+	. "github.com/onsi/gomega"
+)
+
+func TestOSV(t *testing.T) {
+	g := goblin.Goblin(t)
+	RegisterFailHandler(func(m string, _ ...int) { g.Fail(m) })
+
+	g.Describe("Import", func() {
+		g.It("should convert an entry into a vulnerability", func() {
+			entries, err := Read(strings.NewReader(sampleEntry))
+			Expect(err).To(BeNil())
+			Expect(entries).To(HaveLen(1))
+
+			v := ToVulnerability(entries[0])
+			Expect(v.ExternalID).To(Equal("GHSA-jfh8-c2jp-5v3q"))
+			Expect(v.Aliases).To(Equal([]string{"CVE-2021-44228"}))
+			Expect(v.Title).To(Equal("Remote code injection in Log4j"))
+			Expect(v.Summary).To(HavePrefix("Apache Log4j2"))
+			Expect(v.PublishedAt.Year()).To(Equal(2021))
+
+			Expect(v.Vector).To(Equal("CVSS:3.1/AV:N/AC:L/PR:N/UI:N/S:C/C:H/I:H/A:H"))
+			Expect(v.Score).To(Equal("10.0"))
+			Expect(v.ScoreVersion).To(Equal("3.1"))
+			Expect(v.ScoreDetails.CVSSv3).NotTo(BeNil())
+			Expect(v.ScoreDetails.CVSSv3.BaseSeverity).To(Equal("CRITICAL"))
+
+			Expect(v.References).To(HaveLen(2))
+			Expect(v.References[0].Type).To(Equal(ReferenceAdvisory))
+
+			Expect(v.Dependencies).To(HaveLen(3))
+			Expect(v.Dependencies[0].Org).To(Equal("org.apache.logging.log4j"))
+			Expect(v.Dependencies[0].Name).To(Equal("log4j-core"))
+			Expect(v.Dependencies[0].Version).To(Equal("2.14.0"))
+			Expect(v.Dependencies[0].Language).To(Equal("java"))
+			Expect(v.Dependencies[0].ExternalID).To(Equal("pkg:maven/org.apache.logging.log4j/log4j-core@2.14.0"))
+			Expect(v.Dependencies[2].Version).To(Equal("<1.0.0"))
+			Expect(v.Dependencies[2].ExternalID).To(Equal("pkg:npm/%40scope/log4js"))
+		})
+
+		g.It("should convert ranges into version constraints", func() {
+			entries, err := Read(strings.NewReader(sampleRangeEntry))
+			Expect(err).To(BeNil())
+			Expect(entries).To(HaveLen(1))
+
+			v := ToVulnerability(entries[0])
+			versions := []string{}
+			for _, p := range v.Dependencies {
+				versions = append(versions, p.Name+" "+p.Version)
+			}
+
+			Expect(versions).To(Equal([]string{
+				"lodash 5.0.1",
+				"lodash <4.17.21",
+				"lodash >=5.0.0-rc.1, <=5.0.0-rc.3",
+				"lodash >=6.0.0",
+			}))
+
+			Expect(ToVulnerabilityInput(entries[0]).Dependencies).To(ContainElement("pkg:npm/lodash@%3C4.17.21"))
+
+			exported := FromVulnerability(v)
+			Expect(exported.Affected).To(HaveLen(1))
+			Expect(exported.Affected[0].Versions).To(Equal([]string{"5.0.1"}))
+			Expect(exported.Affected[0].Ranges).To(HaveLen(4))
+			Expect(exported.Affected[0].Ranges[0].Events).To(Equal([]Event{{Introduced: "0"}, {Fixed: "4.17.21"}}))
+			Expect(exported.Affected[0].Ranges[2].Events).To(Equal([]Event{{Introduced: "6.0.0"}}))
+		})
+
+		g.It("should leave out packages only affected within git ranges", func() {
+			entry := `{"id": "OSV-2", "modified": "2023-01-01T00:00:00Z", "affected": [{
+			  "package": {"ecosystem": "npm", "name": "lodash"},
+			  "ranges": [{"type": "GIT", "repo": "https://github.com/lodash/lodash", "events": [{"introduced": "0"}, {"fixed": "abc"}]}]
+			}]}`
+
+			entries, err := Read(strings.NewReader(entry))
+			Expect(err).To(BeNil())
+			Expect(ToVulnerability(entries[0]).Dependencies).To(BeEmpty())
+		})
+
+		g.It("should build inputs from a directory of entries", func() {
+			dir, err := ioutil.TempDir("", "osv")
+			Expect(err).To(BeNil())
+			defer os.RemoveAll(dir)
+
+			Expect(os.MkdirAll(filepath.Join(dir, "Maven"), 0755)).To(BeNil())
+			Expect(ioutil.WriteFile(filepath.Join(dir, "Maven", "GHSA-jfh8-c2jp-5v3q.json"), []byte(sampleEntry), 0644)).To(BeNil())
+			Expect(ioutil.WriteFile(filepath.Join(dir, "withdrawn.json"), []byte(`{"id":"OSV-1","modified":"2022-01-01T00:00:00Z","withdrawn":"2022-01-01T00:00:00Z"}`), 0644)).To(BeNil())
+			Expect(ioutil.WriteFile(filepath.Join(dir, "README.md"), []byte("not an entry"), 0644)).To(BeNil())
+
+			inputs, err := ReadDir(dir)
+			Expect(err).To(BeNil())
+			Expect(inputs).To(HaveLen(1))
+			Expect(inputs[0].ExternalID).To(Equal("GHSA-jfh8-c2jp-5v3q"))
+			Expect(inputs[0].Source).To(Equal([]string{SourceName}))
+			Expect(inputs[0].Dependencies).To(Equal([]string{
+				"pkg:maven/org.apache.logging.log4j/log4j-core@2.14.0",
+				"pkg:maven/org.apache.logging.log4j/log4j-core@2.14.1",
+				"pkg:npm/%40scope/log4js@%3C1.0.0",
+			}))
+
+			b, err := json.Marshal(inputs[0])
+			Expect(err).To(BeNil())
+			Expect(string(b)).To(ContainSubstring(`"aliases":["CVE-2021-44228"]`))
+		})
+
+		g.It("should fail on an entry without an id", func() {
+			_, err := Read(strings.NewReader(`{"summary":"nothing"}`))
+			Expect(err).NotTo(BeNil())
+
+			_, err = Read(strings.NewReader(`[{"id":"OSV-1"},{"summary":"nothing"}]`))
+			Expect(err).NotTo(BeNil())
+		})
+	})
+
+	g.Describe("Export", func() {
+		g.It("should convert a vulnerability into an entry", func() {
+			published := time.Date(2021, 12, 10, 0, 0, 0, 0, time.UTC)
+			v := vulnerabilities.Vulnerability{
+				ExternalID:  "CVE-2021-44228",
+				Aliases:     []string{"GHSA-jfh8-c2jp-5v3q", "CVE-2021-44228"},
+				Title:       "Log4Shell",
+				Summary:     "JNDI lookups allow remote code execution",
+				Vector:      "AV:N/AC:M/Au:N/C:C/I:C/A:C",
+				PublishedAt: published,
+				ModifiedAt:  published,
+				ScoreDetails: vulnerabilities.ScoreDetails{
+					CVSSv3: &vulnerabilities.CVSSv3{VectorString: "AV:N/AC:L/PR:N/UI:N/S:C/C:H/I:H/A:H"},
+				},
+				References: []vulnerabilities.Reference{
+					{Type: "Patch", URL: "https://github.com/apache/logging-log4j2/pull/608"},
+					{Type: "Vendor Advisory", URL: "https://logging.apache.org/log4j/2.x/security.html"},
+					{Type: "Vendor Advisory", URL: "https://logging.apache.org/log4j/2.x/security.html"},
+					{Type: "", URL: "https://example.com"},
+				},
+				Dependencies: []products.Product{
+					{Org: "org.apache.logging.log4j", Name: "log4j-core", Version: "2.14.1", Language: "Java"},
+					{Org: "org.apache.logging.log4j", Name: "log4j-core", Version: "2.14.0", Language: "Java"},
+					{Name: "requests", Version: "2.0.0", ExternalID: "pkg:pypi/requests@2.0.0"},
+				},
+			}
+
+			e := FromVulnerability(v)
+			Expect(e.ID).To(Equal("CVE-2021-44228"))
+			Expect(e.Aliases).To(Equal([]string{"GHSA-jfh8-c2jp-5v3q"}))
+			Expect(e.Summary).To(Equal("Log4Shell"))
+			Expect(e.Details).To(Equal("JNDI lookups allow remote code execution"))
+			Expect(*e.Published).To(Equal(published))
+
+			Expect(e.Severity).To(Equal([]Severity{
+				{Type: SeverityCVSSv2, Score: "AV:N/AC:M/Au:N/C:C/I:C/A:C"},
+				{Type: SeverityCVSSv3, Score: "CVSS:3.0/AV:N/AC:L/PR:N/UI:N/S:C/C:H/I:H/A:H"},
+			}))
+
+			Expect(e.References).To(Equal([]Reference{
+				{Type: ReferenceFix, URL: "https://github.com/apache/logging-log4j2/pull/608"},
+				{Type: ReferenceAdvisory, URL: "https://logging.apache.org/log4j/2.x/security.html"},
+				{Type: ReferenceWeb, URL: "https://example.com"},
+			}))
+
+			Expect(e.Affected).To(HaveLen(2))
+			Expect(e.Affected[0].Package).To(Equal(Package{
+				Ecosystem: "Maven",
+				Name:      "org.apache.logging.log4j:log4j-core",
+				PURL:      "pkg:maven/org.apache.logging.log4j/log4j-core",
+			}))
+			Expect(e.Affected[0].Versions).To(Equal([]string{"2.14.0", "2.14.1"}))
+			Expect(e.Affected[0].Ranges).To(HaveLen(2))
+			Expect(e.Affected[0].Ranges[0].Events).To(Equal([]Event{{Introduced: "2.14.0"}, {LastAffected: "2.14.0"}}))
+			Expect(e.Affected[1].Package.Ecosystem).To(Equal("PyPI"))
+			Expect(e.Affected[1].Package.PURL).To(Equal("pkg:pypi/requests"))
+		})
+
+		g.It("should round trip through the osv format", func() {
+			entries, err := Read(strings.NewReader(sampleEntry))
+			Expect(err).To(BeNil())
+
+			buf := &bytes.Buffer{}
+			Expect(Write(buf, ToVulnerability(entries[0]))).To(BeNil())
+
+			entries, err = Read(buf)
+			Expect(err).To(BeNil())
+			Expect(entries).To(HaveLen(1))
+
+			v := ToVulnerability(entries[0])
+			Expect(v.ExternalID).To(Equal("GHSA-jfh8-c2jp-5v3q"))
+			Expect(v.Aliases).To(Equal([]string{"CVE-2021-44228"}))
+			Expect(v.Vector).To(Equal("CVSS:3.1/AV:N/AC:L/PR:N/UI:N/S:C/C:H/I:H/A:H"))
+			Expect(v.Dependencies).To(HaveLen(3))
+			Expect(v.Dependencies[1].ExternalID).To(Equal("pkg:maven/org.apache.logging.log4j/log4j-core@2.14.1"))
+		})
+
+		g.It("should read back the files it writes", func() {
+			entries, err := Read(strings.NewReader(sampleEntry))
+			Expect(err).To(BeNil())
+
+			ranged, err := Read(strings.NewReader(sampleRangeEntry))
+			Expect(err).To(BeNil())
+
+			dir, err := ioutil.TempDir("", "osv")
+			Expect(err).To(BeNil())
+			defer os.RemoveAll(dir)
+
+			f, err := os.Create(filepath.Join(dir, "export.json"))
+			Expect(err).To(BeNil())
+			Expect(Write(f, ToVulnerability(entries[0]), ToVulnerability(ranged[0]))).To(BeNil())
+			Expect(f.Close()).To(BeNil())
+
+			read, err := ReadFile(filepath.Join(dir, "export.json"))
+			Expect(err).To(BeNil())
+			Expect(read).To(HaveLen(2))
+			Expect(read[0].ID).To(Equal("GHSA-jfh8-c2jp-5v3q"))
+			Expect(read[1].ID).To(Equal(ranged[0].ID))
+
+			inputs, err := ReadDir(dir)
+			Expect(err).To(BeNil())
+			Expect(inputs).To(HaveLen(2))
+			Expect(inputs[0].ExternalID).To(Equal("GHSA-jfh8-c2jp-5v3q"))
+			Expect(inputs[0].Dependencies).To(ContainElement("pkg:maven/org.apache.logging.log4j/log4j-core@2.14.1"))
+		})
+	})
+}
+
+const sampleEntry = `{
+  "schema_version": "1.4.0",
+  "id": "GHSA-jfh8-c2jp-5v3q",
+  "modified": "2023-01-09T05:01:36Z",
+  "published": "2021-12-10T00:40:56Z",
+  "aliases": ["CVE-2021-44228"],
+  "summary": "Remote code injection in Log4j",
+  "details": "Apache Log4j2 JNDI features do not protect against attacker controlled LDAP endpoints.",
+  "severity": [
+    {"type": "CVSS_V3", "score": "CVSS:3.1/AV:N/AC:L/PR:N/UI:N/S:C/C:H/I:H/A:H"}
+  ],
+  "affected": [
+    {
+      "package": {"ecosystem": "Maven", "name": "org.apache.logging.log4j:log4j-core"},
+      "ranges": [
+        {"type": "ECOSYSTEM", "events": [{"introduced": "2.13.0"}, {"fixed": "2.15.0"}]}
+      ],
+      "versions": ["2.14.0", "2.14.1"]
+    },
+    {
+      "package": {"ecosystem": "npm", "name": "@scope/log4js"},
+      "ranges": [
+        {"type": "SEMVER", "events": [{"introduced": "0"}, {"fixed": "1.0.0"}]}
+      ]
+    }
+  ],
+  "references": [
+    {"type": "ADVISORY", "url": "https://nvd.nist.gov/vuln/detail/CVE-2021-44228"},
+    {"type": "WEB", "url": "https://logging.apache.org/log4j/2.x/security.html"}
+  ]
+}`
+
+const sampleRangeEntry = `{
+  "id": "GHSA-35jh-r3h4-6jhm",
+  "modified": "2023-01-01T00:00:00Z",
+  "affected": [
+    {
+      "package": {"ecosystem": "npm", "name": "lodash"},
+      "ranges": [
+        {"type": "SEMVER", "events": [{"introduced": "0"}, {"fixed": "4.17.21"}]},
+        {"type": "ECOSYSTEM", "events": [
+          {"introduced": "5.0.0-rc.1"}, {"last_affected": "5.0.0-rc.3"},
+          {"introduced": "5.0.1"}, {"last_affected": "5.0.1"},
+          {"introduced": "6.0.0"}
+        ]}
+      ]
+    }
+  ]
+}`
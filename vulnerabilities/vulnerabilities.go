@@ -2,10 +2,12 @@ package vulnerabilities
 
 import (
 	"encoding/json"
+	"fmt"
 	"strings"
 	"time"
 
 	"github.com/ion-channel/ionic/products"
+	"github.com/ion-channel/ionic/vulnerabilities/cvss"
 )
 
 const (
@@ -24,6 +26,7 @@ const (
 type Vulnerability struct {
 	ID                          int                `json:"id" xml:"id"`
 	ExternalID                  string             `json:"external_id" xml:"exteral_id"`
+	Aliases                     []string           `json:"aliases,omitempty" xml:"aliases,omitempty"`
 	Source                      []Source           `json:"source" xml:"source"`
 	Title                       string             `json:"title" xml:"title"`
 	Summary                     string             `json:"summary" xml:"summary"`
@@ -75,6 +78,7 @@ type Source struct {
 type ScoreDetails struct {
 	CVSSv2 *CVSSv2 `json:"cvssv2,omitempty" xml:"cvssv2"`
 	CVSSv3 *CVSSv3 `json:"cvssv3,omitempty" xml:"cvssv3"`
+	CVSSv4 *CVSSv4 `json:"cvssv4,omitempty" xml:"cvssv4"`
 	NPM    *NPM    `json:"npm,omitempty" xml:"npm"`
 }
 
@@ -115,6 +119,14 @@ type CVSSv3 struct {
 	BaseSeverity          string  `json:"baseSeverity" xml:"baseSeverity"`
 }
 
+// CVSSv4 represents the vector and base score of a CVSS v4 score for a given
+// vulnerability
+type CVSSv4 struct {
+	VectorString string  `json:"vectorString" xml:"vectorString"`
+	BaseScore    float64 `json:"baseScore" xml:"baseScore"`
+	BaseSeverity string  `json:"baseSeverity" xml:"baseSeverity"`
+}
+
 // Reference represents a location where a CVE may have been referenced
 type Reference struct {
 	Type   string `json:"type" xml:"type"`
@@ -124,16 +136,35 @@ type Reference struct {
 }
 
 // NewV3FromShorthand takes a shorthand representation of a CVSSv3 and returns
-// an expanded struct representation
+// an expanded struct representation. The base score and severity are
+// calculated when the shorthand is a valid CVSS v3.0 or v3.1 vector; a
+// shorthand without a version prefix is treated as v3.0.
 func NewV3FromShorthand(shorthand string) *CVSSv3 {
-	shorthand = strings.ToUpper(shorthand)
-	shorthand = strings.TrimPrefix(shorthand, "CVSS:3.0/")
-	metrics := strings.Split(shorthand, "/")
+	shorthand = strings.ToUpper(strings.TrimSpace(shorthand))
 
 	sv := &CVSSv3{}
 
+	vector := shorthand
+	if !strings.HasPrefix(vector, "CVSS:") {
+		vector = "CVSS:3.0/" + vector
+	}
+
+	if v, err := cvss.Parse(vector); err == nil && (v.Version() == cvss.Version30 || v.Version() == cvss.Version31) {
+		sv.VectorString = v.String()
+		sv.BaseScore = v.BaseScore()
+		sv.BaseSeverity = strings.ToUpper(string(v.BaseSeverity()))
+	}
+
+	shorthand = strings.TrimPrefix(shorthand, "CVSS:3.0/")
+	shorthand = strings.TrimPrefix(shorthand, "CVSS:3.1/")
+	metrics := strings.Split(shorthand, "/")
+
 	for _, metric := range metrics {
 		parts := strings.Split(metric, ":")
+		if len(parts) != 2 {
+			continue
+		}
+
 		switch parts[0] {
 		case "AV":
 			switch parts[1] {
@@ -176,6 +207,188 @@ func NewV3FromShorthand(shorthand string) *CVSSv3 {
 	return sv
 }
 
+// NewV2FromShorthand takes a shorthand representation of a CVSSv2, such as
+// "AV:N/AC:L/Au:N/C:P/I:P/A:P", and returns an expanded struct representation
+// with its base score. It returns nil if the shorthand is not a valid CVSS v2
+// vector.
+func NewV2FromShorthand(shorthand string) *CVSSv2 {
+	v, err := cvss.Parse(shorthand)
+	if err != nil || v.Version() != cvss.Version20 {
+		return nil
+	}
+
+	return &CVSSv2{
+		VectorString:          v.String(),
+		AccessVector:          v2Names["AV"][v.Metric("AV")],
+		AccessComplexity:      v2Names["AC"][v.Metric("AC")],
+		Authentication:        v2Names["Au"][v.Metric("Au")],
+		ConfidentialityImpact: v2Names["CIA"][v.Metric("C")],
+		IntegrityImpact:       v2Names["CIA"][v.Metric("I")],
+		AvailabilityImpact:    v2Names["CIA"][v.Metric("A")],
+		BaseScore:             v.BaseScore(),
+	}
+}
+
+// NewV4FromShorthand takes a CVSS v4 vector, such as
+// "CVSS:4.0/AV:N/AC:L/AT:N/PR:N/UI:N/VC:H/VI:H/VA:H/SC:N/SI:N/SA:N", and
+// returns its base score and severity. It returns nil if the shorthand is not
+// a valid CVSS v4 vector.
+func NewV4FromShorthand(shorthand string) *CVSSv4 {
+	v, err := cvss.Parse(shorthand)
+	if err != nil || v.Version() != cvss.Version40 {
+		return nil
+	}
+
+	return &CVSSv4{
+		VectorString: v.String(),
+		BaseScore:    v.BaseScore(),
+		BaseSeverity: strings.ToUpper(string(v.BaseSeverity())),
+	}
+}
+
+var v2Names = map[string]map[string]string{
+	"AV":  {"L": "LOCAL", "A": "ADJACENT_NETWORK", "N": "NETWORK"},
+	"AC":  {"H": "HIGH", "M": "MEDIUM", "L": "LOW"},
+	"Au":  {"M": "MULTIPLE", "S": "SINGLE", "N": "NONE"},
+	"CIA": {"N": "NONE", "P": "PARTIAL", "C": "COMPLETE"},
+}
+
+// UnmarshalJSON reads a vulnerability and fills in the score details of its
+// vector when they were not given
+func (v *Vulnerability) UnmarshalJSON(b []byte) error {
+	type vulnerability Vulnerability
+
+	var vuln vulnerability
+	err := json.Unmarshal(b, &vuln)
+	if err != nil {
+		return err
+	}
+
+	*v = Vulnerability(vuln)
+	v.PopulateScoreDetails()
+	return nil
+}
+
+// UnmarshalJSON reads a vulnerability input, whose dependencies, sources, and
+// timestamps replace those of the embedded vulnerability
+func (vi *VulnerabilityInput) UnmarshalJSON(b []byte) error {
+	var fields map[string]json.RawMessage
+	err := json.Unmarshal(b, &fields)
+	if err != nil {
+		return err
+	}
+
+	var input struct {
+		Dependencies []string   `json:"dependencies"`
+		Source       []string   `json:"source"`
+		UpdatedAt    *time.Time `json:"updated_at"`
+		CreatedAt    *time.Time `json:"created_at"`
+	}
+
+	err = json.Unmarshal(b, &input)
+	if err != nil {
+		return err
+	}
+
+	for _, k := range []string{"dependencies", "source", "updated_at", "created_at"} {
+		delete(fields, k)
+	}
+
+	rest, err := json.Marshal(fields)
+	if err != nil {
+		return err
+	}
+
+	err = json.Unmarshal(rest, &vi.Vulnerability)
+	if err != nil {
+		return err
+	}
+
+	vi.Dependencies = input.Dependencies
+	vi.Source = input.Source
+	vi.UpdatedAt = input.UpdatedAt
+	vi.CreatedAt = input.CreatedAt
+	return nil
+}
+
+// PopulateScoreDetails fills in the CVSS score details of the vulnerability
+// from its vector when they are missing. Details already present are left
+// unchanged.
+func (v *Vulnerability) PopulateScoreDetails() {
+	vector, err := cvss.Parse(v.Vector)
+	if err != nil {
+		return
+	}
+
+	switch vector.Version() {
+	case cvss.Version20:
+		if v.ScoreDetails.CVSSv2 == nil {
+			v.ScoreDetails.CVSSv2 = NewV2FromShorthand(v.Vector)
+		}
+	case cvss.Version30, cvss.Version31:
+		if v.ScoreDetails.CVSSv3 == nil {
+			v.ScoreDetails.CVSSv3 = NewV3FromShorthand(v.Vector)
+		}
+	case cvss.Version40:
+		if v.ScoreDetails.CVSSv4 == nil {
+			v.ScoreDetails.CVSSv4 = NewV4FromShorthand(v.Vector)
+		}
+	}
+}
+
+// CVSS returns the parsed CVSS vector of the vulnerability, from its vector or
+// the most recent version of its score details
+func (v *Vulnerability) CVSS() (*cvss.Vector, error) {
+	candidates := []string{v.Vector}
+	if v.ScoreDetails.CVSSv4 != nil {
+		candidates = append(candidates, v.ScoreDetails.CVSSv4.VectorString)
+	}
+
+	if v.ScoreDetails.CVSSv3 != nil && v.ScoreDetails.CVSSv3.VectorString != "" {
+		vector := v.ScoreDetails.CVSSv3.VectorString
+		if !strings.HasPrefix(strings.ToUpper(vector), "CVSS:") {
+			vector = "CVSS:3.0/" + vector
+		}
+
+		candidates = append(candidates, vector)
+	}
+
+	if v.ScoreDetails.CVSSv2 != nil {
+		candidates = append(candidates, v.ScoreDetails.CVSSv2.VectorString)
+	}
+
+	for _, c := range candidates {
+		if c == "" {
+			continue
+		}
+
+		if vector, err := cvss.Parse(c); err == nil {
+			return vector, nil
+		}
+	}
+
+	return nil, fmt.Errorf("vulnerability %v has no valid CVSS vector", v.ExternalID)
+}
+
+// Rescore returns the score and severity of the vulnerability with the given
+// environmental metrics, such as "CR:H/IR:H/MAV:L", applied to its CVSS
+// vector. This lets a vulnerability be scored for a specific deployment. It
+// returns an error if the vulnerability has no CVSS vector or the metrics are
+// not valid for its version.
+func (v *Vulnerability) Rescore(environmental string) (float64, cvss.Severity, error) {
+	vector, err := v.CVSS()
+	if err != nil {
+		return 0, "", err
+	}
+
+	err = vector.Apply(environmental)
+	if err != nil {
+		return 0, "", fmt.Errorf("failed to rescore %v: %v", v.ExternalID, err.Error())
+	}
+
+	return vector.Score(), vector.Severity(), nil
+}
+
 func parseLowHighNone(lhn string) string {
 	switch lhn {
 	case "N":
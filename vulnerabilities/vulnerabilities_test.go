@@ -1,6 +1,7 @@
 package vulnerabilities
 
 import (
+	"encoding/json"
 	"testing"
 
 	"github.com/franela/goblin"
@@ -58,5 +59,74 @@ func TestVulnerabilities(t *testing.T) {
 				Expect(parseLowHighNone(row.Shorthand)).To(Equal(row.Longhand))
 			}
 		})
+
+		g.It("should calculate the base score of a cvss version 3", func() {
+			cvssv3 := NewV3FromShorthand("CVSS:3.1/AV:N/AC:L/PR:N/UI:N/S:U/C:H/I:H/A:H")
+			Expect(cvssv3.VectorString).To(Equal("CVSS:3.1/AV:N/AC:L/PR:N/UI:N/S:U/C:H/I:H/A:H"))
+			Expect(cvssv3.AttackVector).To(Equal("network"))
+			Expect(cvssv3.BaseScore).To(Equal(9.8))
+			Expect(cvssv3.BaseSeverity).To(Equal("CRITICAL"))
+
+			cvssv3 = NewV3FromShorthand("AV:N/AC:L/PR:N/UI:R/S:U/C:N/I:N/A:L")
+			Expect(cvssv3.BaseScore).To(Equal(4.3))
+
+			cvssv3 = NewV3FromShorthand("not a vector")
+			Expect(cvssv3.BaseScore).To(Equal(0.0))
+		})
+
+		g.It("should return a new cvss version 2 and 4", func() {
+			cvssv2 := NewV2FromShorthand("AV:N/AC:M/Au:N/C:N/I:P/A:N")
+			Expect(cvssv2).NotTo(BeNil())
+			Expect(cvssv2.AccessVector).To(Equal("NETWORK"))
+			Expect(cvssv2.AccessComplexity).To(Equal("MEDIUM"))
+			Expect(cvssv2.IntegrityImpact).To(Equal("PARTIAL"))
+			Expect(cvssv2.BaseScore).To(Equal(4.3))
+			Expect(NewV2FromShorthand("CVSS:3.1/AV:N/AC:L/PR:N/UI:N/S:U/C:H/I:H/A:H")).To(BeNil())
+
+			cvssv4 := NewV4FromShorthand("CVSS:4.0/AV:N/AC:L/AT:N/PR:N/UI:N/VC:H/VI:H/VA:H/SC:N/SI:N/SA:N")
+			Expect(cvssv4).NotTo(BeNil())
+			Expect(cvssv4.BaseScore).To(Equal(9.3))
+			Expect(cvssv4.BaseSeverity).To(Equal("CRITICAL"))
+		})
+	})
+
+	g.Describe("Score details", func() {
+		g.It("should populate missing score details from the vector", func() {
+			var v Vulnerability
+			err := json.Unmarshal([]byte(`{"external_id":"CVE-1","vector":"AV:N/AC:L/Au:N/C:P/I:P/A:P","score_details":{}}`), &v)
+			Expect(err).To(BeNil())
+			Expect(v.ScoreDetails.CVSSv2).NotTo(BeNil())
+			Expect(v.ScoreDetails.CVSSv2.BaseScore).To(Equal(7.5))
+			Expect(v.ScoreDetails.CVSSv3).To(BeNil())
+
+			err = json.Unmarshal([]byte(`{"external_id":"CVE-2","vector":"CVSS:3.1/AV:N/AC:L/PR:N/UI:N/S:U/C:H/I:H/A:H","score_details":{"cvssv3":{"baseScore":9.0,"baseSeverity":"CRITICAL"}}}`), &v)
+			Expect(err).To(BeNil())
+			Expect(v.ScoreDetails.CVSSv3.BaseScore).To(Equal(9.0))
+		})
+
+		g.It("should rescore with environmental metrics", func() {
+			v := Vulnerability{ExternalID: "CVE-3", Vector: "CVSS:3.1/AV:N/AC:L/PR:N/UI:N/S:U/C:H/I:H/A:H"}
+
+			score, severity, err := v.Rescore("CR:L/IR:L/AR:L")
+			Expect(err).To(BeNil())
+			Expect(score).To(Equal(8.0))
+			Expect(string(severity)).To(Equal("high"))
+
+			_, _, err = v.Rescore("CDP:H")
+			Expect(err).NotTo(BeNil())
+
+			_, _, err = (&Vulnerability{}).Rescore("CR:L")
+			Expect(err).NotTo(BeNil())
+		})
+
+		g.It("should unmarshal a vulnerability input", func() {
+			var vi VulnerabilityInput
+			err := json.Unmarshal([]byte(`{"external_id":"CVE-4","title":"input","dependencies":["dep-1"],"source":["nvd"]}`), &vi)
+			Expect(err).To(BeNil())
+			Expect(vi.ExternalID).To(Equal("CVE-4"))
+			Expect(vi.Title).To(Equal("input"))
+			Expect(vi.Dependencies).To(Equal([]string{"dep-1"}))
+			Expect(vi.Source).To(Equal([]string{"nvd"}))
+		})
 	})
 }